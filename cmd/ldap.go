@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/config"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/auth"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/user"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/directory"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// ldapCmd represents the ldap command
+var ldapCmd = &cobra.Command{
+	Use:   "ldap",
+	Short: "Manage the LDAP directory integration",
+	Run:   runLdap,
+}
+
+var ldapSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync users with the LDAP directory",
+	Long: `Disable users that are no longer in the LDAP directory, or no longer in an allowed group,
+and apply the directory groups of the remaining users.`,
+	Run: runLdapSync,
+}
+
+func init() {
+	RootCmd.AddCommand(ldapCmd)
+	ldapCmd.AddCommand(ldapSyncCmd)
+}
+
+func runLdap(cmd *cobra.Command, args []string) {
+	cmd.Help()
+}
+
+func runLdapSync(cmd *cobra.Command, args []string) {
+	version := RootCmd.Version
+	zlog, _ := zap.NewProduction(
+		zap.Fields(
+			zap.String("version", version),
+		),
+	)
+	defer func() {
+		_ = zlog.Sync()
+	}()
+	logger := otelzap.New(zlog)
+
+	c := config.InitConfig(logger)
+	if c.Auth.Method != "ldap" {
+		slog.Error("LDAP sync requires auth.method=ldap")
+		os.Exit(1)
+	}
+
+	d := db.New(c.Admin.Email, &db.Config{
+		Host:                   c.Db.Host,
+		Port:                   c.Db.Port,
+		User:                   c.Db.User,
+		Password:               c.Db.Pass,
+		Name:                   c.Db.Name,
+		SSLMode:                c.Db.Sslmode,
+		AESHashkey:             c.Config.AesHashkey,
+		MaxIdleConns:           c.Db.MaxIdleConns,
+		MaxOpenConns:           c.Db.MaxOpenConns,
+		ConnMaxLifetime:        c.Db.ConnMaxLifetime,
+		DefaultEstimationScale: c.Config.AllowedPointValues,
+	}, logger, false)
+
+	userService := &user.Service{DB: d.DB, Logger: logger}
+	authService := &auth.Service{DB: d.DB, Logger: logger, AESHashkey: d.Config.AESHashkey}
+
+	result, err := newLdapDirectory(c, logger, userService, authService).Sync(context.Background())
+	if err != nil {
+		slog.Error("Failed to sync LDAP directory", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	if result.Failed > 0 {
+		slog.Error("LDAP directory sync completed with failed users",
+			slog.Int("directory_users", result.DirectoryUsers),
+			slog.Int("disabled", result.Disabled),
+			slog.Int("groups_synced", result.GroupsSynced),
+			slog.Int("failed", result.Failed),
+		)
+		os.Exit(1)
+	}
+
+	slog.Info("LDAP directory sync completed successfully",
+		slog.Int("directory_users", result.DirectoryUsers),
+		slog.Int("disabled", result.Disabled),
+		slog.Int("groups_synced", result.GroupsSynced),
+	)
+}
+
+// newLdapDirectory creates the LDAP directory service from the application configuration
+func newLdapDirectory(c config.Config, logger *otelzap.Logger, userDataSvc directory.UserDataSvc, authDataSvc directory.AuthDataSvc) *directory.Service {
+	groupMappings := make([]thunderdome.GroupMapping, 0, len(c.Auth.Ldap.GroupMappings))
+	for _, m := range c.Auth.Ldap.GroupMappings {
+		groupMappings = append(groupMappings, thunderdome.GroupMapping(m))
+	}
+
+	return directory.New(directory.Config{
+		URL:           c.Auth.Ldap.Url,
+		UseTLS:        c.Auth.Ldap.UseTls,
+		Bindname:      c.Auth.Ldap.Bindname,
+		Bindpass:      c.Auth.Ldap.Bindpass,
+		BaseDN:        c.Auth.Ldap.Basedn,
+		Filter:        c.Auth.Ldap.Filter,
+		MailAttr:      c.Auth.Ldap.MailAttr,
+		CnAttr:        c.Auth.Ldap.CnAttr,
+		GroupAttr:     c.Auth.Ldap.GroupAttr,
+		AllowedGroups: c.Auth.Ldap.AllowedGroups,
+		AdminGroups:   c.Auth.Ldap.AdminGroups,
+		GroupMappings: groupMappings,
+		SyncFilter:    c.Auth.Ldap.SyncFilter,
+	}, logger, userDataSvc, authDataSvc)
+}
@@ -3,6 +3,8 @@ package cmd
 import (
 	"context"
 	_ "embed"
+	"strings"
+	"time"
 
 	jiraData "github.com/StevenWeathers/thunderdome-planning-poker/internal/db/jira"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/project"
@@ -15,16 +17,22 @@ import (
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/admin"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/alert"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/apikey"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/audit"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/auth"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/broadcast"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/poker"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/ratelimit"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/retro"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/retrotemplate"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/scim"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/storyboard"
 	subscriptionData "github.com/StevenWeathers/thunderdome-planning-poker/internal/db/subscription"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/team"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db/user"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/directory"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/http"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
 	"github.com/StevenWeathers/thunderdome-planning-poker/ui"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/config"
@@ -110,6 +118,31 @@ func serve(embedUseOS bool) {
 	ldapEnabled := c.Auth.Method == "ldap"
 	headerAuthEnabled := c.Auth.Method == "header"
 	oidcAuthEnabled := c.Auth.Method == "oidc"
+	oidcProviders := make([]thunderdome.AuthProviderConfig, 0)
+	oidcProviderNames := make([]string, 0)
+	for _, p := range c.Auth.OIDC.AllProviders() {
+		groupMappings := make([]thunderdome.GroupMapping, 0, len(p.GroupMappings))
+		for _, m := range p.GroupMappings {
+			groupMappings = append(groupMappings, thunderdome.GroupMapping(m))
+		}
+		requestedScopes := p.RequestedScopes
+		if len(requestedScopes) == 0 {
+			requestedScopes = []string{"openid", "profile", "email"}
+		}
+		oidcProviders = append(oidcProviders, thunderdome.AuthProviderConfig{
+			ProviderName:           p.ProviderName,
+			ProviderURL:            p.ProviderURL,
+			ClientID:               p.ClientID,
+			ClientSecret:           p.ClientSecret,
+			RequestedScopes:        requestedScopes,
+			RequestedIDTokenClaims: p.RequestedIDTokenClaims,
+			GroupsClaim:            p.GroupsClaim,
+			GroupMappings:          groupMappings,
+			AdminGroups:            p.AdminGroups,
+			AllowedEmailDomains:    p.AllowedEmailDomains,
+		})
+		oidcProviderNames = append(oidcProviderNames, p.ProviderName)
+	}
 
 	d := db.New(c.Admin.Email, &db.Config{
 		Host:                   c.Db.Host,
@@ -127,6 +160,9 @@ func serve(embedUseOS bool) {
 
 	userService := &user.Service{DB: d.DB, Logger: logger}
 	apkService := &apikey.Service{DB: d.DB, Logger: logger}
+	auditService := &audit.Service{DB: d.DB, Logger: logger}
+	rateLimitService := &ratelimit.Service{DB: d.DB, Logger: logger}
+	scimService := &scim.Service{DB: d.DB, Logger: logger}
 	alertService := &alert.Service{DB: d.DB, Logger: logger}
 	authService := &auth.Service{DB: d.DB, Logger: logger, AESHashkey: d.Config.AESHashkey}
 	battleService := &poker.Service{
@@ -142,7 +178,31 @@ func serve(embedUseOS bool) {
 	subscriptionDataSvc := &subscriptionData.Service{DB: d.DB, Logger: logger}
 	jiraDataSvc := &jiraData.Service{DB: d.DB, Logger: logger, AESHashKey: d.Config.AESHashkey}
 	retroTemplateDataSvc := &retrotemplate.Service{DB: d.DB, Logger: logger}
-	projectDataSvc := &project.Service{DB: d.DB, Logger: logger}
+	projectDataSvc := &project.Service{DB: d.DB, Logger: logger, HTMLSanitizerPolicy: d.HTMLSanitizerPolicy}
+
+	var broadcastBackend wshub.Backend
+	if strings.EqualFold(c.Http.WebsocketBroadcastBackend, "postgres") {
+		broadcastSvc := broadcast.New(d.DB, logger, d.Config.ConnString())
+		broadcastSvc.Start(context.Background())
+		broadcastBackend = broadcastSvc
+	}
+
+	if c.RateLimit.Enabled {
+		rateLimitService.Start(context.Background())
+	}
+
+	trustedProxies, err := config.ParseTrustedProxies(c.Http.TrustedProxies)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	var ldapDirectory *directory.Service
+	if ldapEnabled {
+		ldapDirectory = newLdapDirectory(c, logger, userService, authService)
+		if c.Auth.Ldap.SyncIntervalMinutes > 0 {
+			ldapDirectory.Start(context.Background(), time.Duration(c.Auth.Ldap.SyncIntervalMinutes)*time.Minute)
+		}
+	}
 
 	cook := cookie.New(cookie.Config{
 		AppDomain:           c.Http.Domain,
@@ -185,6 +245,7 @@ func serve(embedUseOS bool) {
 			AppDomain:                 c.Http.Domain,
 			SecureProtocol:            c.Http.SecureProtocol,
 			PathPrefix:                c.Http.PathPrefix,
+			TrustedProxies:            trustedProxies,
 			ExternalAPIEnabled:        c.Config.AllowExternalApi,
 			ExternalAPIVerifyRequired: c.Config.ExternalApiVerifyRequired,
 			UserAPIKeyLimit:           c.Config.UserApikeyLimit,
@@ -203,20 +264,22 @@ func serve(embedUseOS bool) {
 			CleanupGuestsDaysOld:      c.Config.CleanupGuestsDaysOld,
 			RequireTeams:              c.Config.RequireTeams,
 			RetroDefaultTemplateID:    c.Config.RetroDefaultTemplateID,
-			AuthLdapUrl:               c.Auth.Ldap.Url,
-			AuthLdapUseTls:            c.Auth.Ldap.UseTls,
-			AuthLdapBindname:          c.Auth.Ldap.Bindname,
-			AuthLdapBindpass:          c.Auth.Ldap.Bindpass,
-			AuthLdapBasedn:            c.Auth.Ldap.Basedn,
-			AuthLdapFilter:            c.Auth.Ldap.Filter,
-			AuthLdapMailAttr:          c.Auth.Ldap.MailAttr,
-			AuthLdapCnAttr:            c.Auth.Ldap.CnAttr,
 			AuthHeaderUsernameHeader:  c.Auth.Header.UsernameHeader,
 			AuthHeaderEmailHeader:     c.Auth.Header.EmailHeader,
 			AllowGuests:               c.Config.AllowGuests,
 			AllowRegistration:         c.Config.AllowRegistration,
 			ShowActiveCountries:       c.Config.ShowActiveCountries,
 			SubscriptionsEnabled:      c.Config.SubscriptionsEnabled,
+			RateLimit: http.RateLimitConfig{
+				Enabled:             c.RateLimit.Enabled,
+				AuthIPRequests:      c.RateLimit.AuthIPRequests,
+				AuthAccountRequests: c.RateLimit.AuthAccountRequests,
+				AuthWindow:          time.Duration(c.RateLimit.AuthWindowSeconds) * time.Second,
+				LockoutThreshold:    c.RateLimit.LockoutThreshold,
+				LockoutDuration:     time.Duration(c.RateLimit.LockoutMinutes) * time.Minute,
+				APIKeyRequests:      c.RateLimit.APIKeyRequests,
+				APIKeyWindow:        time.Duration(c.RateLimit.APIKeyWindowSeconds) * time.Second,
+			},
 			GoogleAuth: http.AuthProvider{
 				Enabled: c.Auth.Google.Enabled,
 				AuthProviderConfig: thunderdome.AuthProviderConfig{
@@ -228,22 +291,16 @@ func serve(embedUseOS bool) {
 					RequestedIDTokenClaims: c.Auth.OIDC.RequestedIDTokenClaims,
 				},
 			},
-			OIDCAuth: http.AuthProvider{
-				Enabled: oidcAuthEnabled,
-				AuthProviderConfig: thunderdome.AuthProviderConfig{
-					ProviderName:           c.Auth.OIDC.ProviderName,
-					ProviderURL:            c.Auth.OIDC.ProviderURL,
-					ClientID:               c.Auth.OIDC.ClientID,
-					ClientSecret:           c.Auth.OIDC.ClientSecret,
-					RequestedScopes:        c.Auth.OIDC.RequestedScopes,
-					RequestedIDTokenClaims: c.Auth.OIDC.RequestedIDTokenClaims,
-				},
+			OIDCAuth: http.OIDCAuth{
+				Enabled:   oidcAuthEnabled,
+				Providers: oidcProviders,
 			},
 			WebsocketConfig: http.WebsocketConfig{
 				WriteWaitSec:       c.Http.WebsocketWriteWaitSec,
 				PingPeriodSec:      c.Http.WebsocketPingPeriodSec,
 				PongWaitSec:        c.Http.WebsocketPongWaitSec,
 				WebsocketSubdomain: c.Http.WebsocketSubdomain,
+				BroadcastBackend:   broadcastBackend,
 			},
 		},
 		Email:                      emailSvc,
@@ -266,6 +323,10 @@ func serve(embedUseOS bool) {
 		ColorLegendTemplateDataSvc: storyboardService,
 		SubscriptionSvc:            subscriptionService,
 		ProjectDataSvc:             projectDataSvc,
+		AuditDataSvc:               auditService,
+		RateLimitDataSvc:           rateLimitService,
+		SCIMDataSvc:                scimService,
+		LdapDirectory:              ldapDirectory,
 		UIConfig: thunderdome.UIConfig{
 			AppConfig: thunderdome.AppConfig{
 				AllowedPointValues:          c.Config.AllowedPointValues,
@@ -293,7 +354,7 @@ func serve(embedUseOS bool) {
 				HeaderAuthEnabled:           headerAuthEnabled,
 				GoogleAuthEnabled:           c.Auth.Google.Enabled,
 				OIDCAuthEnabled:             oidcAuthEnabled,
-				OIDCProviderName:            c.Auth.OIDC.ProviderName,
+				OIDCProviders:               oidcProviderNames,
 				FeaturePoker:                c.Feature.Poker,
 				FeatureRetro:                c.Feature.Retro,
 				FeatureStoryboard:           c.Feature.Storyboard,
@@ -309,7 +370,7 @@ func serve(embedUseOS bool) {
 		},
 	}, uiFilesystem, uiHTTPFilesystem)
 
-	err := h.ListenAndServe()
+	err = h.ListenAndServe()
 	if err != nil {
 		logger.Fatal(err.Error())
 	}
@@ -0,0 +1,30 @@
+package jira
+
+import (
+	"context"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// GetFields gets the issue fields available on the Jira instance
+func (c *Client) GetFields(ctx context.Context) ([]thunderdome.JiraField, error) {
+	fields, _, err := c.instance.Issue.Field.Gets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]thunderdome.JiraField, 0, len(fields))
+	for _, field := range fields {
+		f := thunderdome.JiraField{
+			ID:     field.ID,
+			Name:   field.Name,
+			Custom: field.Custom,
+		}
+		if field.Schema != nil {
+			f.Type = field.Schema.Type
+		}
+		result = append(result, f)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,19 @@
+package jira
+
+import (
+	"context"
+
+	"github.com/ctreminiom/go-atlassian/v2/pkg/infra/models"
+)
+
+// UpdateIssueStoryPoints sets the story points field of the issue to points
+func (c *Client) UpdateIssueStoryPoints(ctx context.Context, issueKey string, storyPointsField string, points float64) error {
+	customFields := &models.CustomFields{}
+	if err := customFields.Number(storyPointsField, points); err != nil {
+		return err
+	}
+
+	_, err := c.instance.Issue.Update(ctx, issueKey, false, &models.IssueScheme{}, customFields, nil)
+
+	return err
+}
@@ -0,0 +1,18 @@
+package jira
+
+import "strings"
+
+// DefaultLinkTemplate is the issue link used when a Jira instance has no link template configured
+const DefaultLinkTemplate = "{host}/browse/{key}"
+
+// IssueLink builds the link to the issue by replacing the {host} and {key} placeholders of the link template
+func IssueLink(host string, linkTemplate string, issueKey string) string {
+	if linkTemplate == "" {
+		linkTemplate = DefaultLinkTemplate
+	}
+
+	return strings.NewReplacer(
+		"{host}", strings.TrimSuffix(host, "/"),
+		"{key}", issueKey,
+	).Replace(linkTemplate)
+}
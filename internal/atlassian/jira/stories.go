@@ -2,13 +2,14 @@ package jira
 
 import (
 	"context"
+	"encoding/json"
 )
 
 // StoriesJQLSearch searches for stories in Jira using JQL
 func (c *Client) StoriesJQLSearch(ctx context.Context, jql string, fields []string, startAt int, maxResults int) (*IssuesSearchResult, error) {
 	iss := IssuesSearchResult{}
 
-	issues, _, err := c.instance.Issue.Search.SearchJQL(ctx, jql, fields, nil, maxResults, "")
+	issues, response, err := c.instance.Issue.Search.SearchJQL(ctx, jql, fields, nil, maxResults, "")
 	if err != nil {
 		return nil, err
 	}
@@ -16,5 +17,27 @@ func (c *Client) StoriesJQLSearch(ctx context.Context, jql string, fields []stri
 	iss.Total = issues.Total
 	iss.Issues = issues.Issues
 
+	// custom fields aren't part of the issue scheme, so read the requested fields from the raw response
+	var raw struct {
+		Issues []struct {
+			Key    string                     `json:"key"`
+			Fields map[string]json.RawMessage `json:"fields"`
+		} `json:"issues"`
+	}
+	if err := json.Unmarshal(response.Bytes.Bytes(), &raw); err != nil {
+		return nil, err
+	}
+	iss.FieldValues = make(map[string]map[string]any, len(raw.Issues))
+	for _, issue := range raw.Issues {
+		values := make(map[string]any)
+		for _, field := range fields {
+			var value any
+			if rawValue, ok := issue.Fields[field]; ok && json.Unmarshal(rawValue, &value) == nil {
+				values[field] = value
+			}
+		}
+		iss.FieldValues[issue.Key] = values
+	}
+
 	return &iss, err
 }
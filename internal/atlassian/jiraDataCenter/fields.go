@@ -0,0 +1,27 @@
+package jiradatacenter
+
+import (
+	"context"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// GetFields gets the issue fields available on the Jira instance
+func (c *Client) GetFields(ctx context.Context) ([]thunderdome.JiraField, error) {
+	fields, _, err := c.instance.Field.GetList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]thunderdome.JiraField, 0, len(fields))
+	for _, field := range fields {
+		result = append(result, thunderdome.JiraField{
+			ID:     field.ID,
+			Name:   field.Name,
+			Custom: field.Custom,
+			Type:   field.Schema.Type,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,16 @@
+package jiradatacenter
+
+import (
+	"context"
+)
+
+// UpdateIssueStoryPoints sets the story points field of the issue to points
+func (c *Client) UpdateIssueStoryPoints(ctx context.Context, issueKey string, storyPointsField string, points float64) error {
+	_, err := c.instance.Issue.UpdateIssue(ctx, issueKey, map[string]any{
+		"fields": map[string]any{
+			storyPointsField: points,
+		},
+	})
+
+	return err
+}
@@ -21,6 +21,18 @@ func (c *Client) StoriesJQLSearch(ctx context.Context, jql string, fields []stri
 
 	iss.Total = respo.Total
 	iss.Issues = issues
+	iss.FieldValues = make(map[string]map[string]any, len(issues))
+	for _, issue := range issues {
+		values := make(map[string]any)
+		if issue.Fields != nil {
+			for _, field := range fields {
+				if value, ok := issue.Fields.Unknowns[field]; ok {
+					values[field] = value
+				}
+			}
+		}
+		iss.FieldValues[issue.Key] = values
+	}
 
 	return &iss, err
 }
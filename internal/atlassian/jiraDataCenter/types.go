@@ -21,4 +21,6 @@ type Client struct {
 type IssuesSearchResult struct {
 	Total  int                      `json:"total"`
 	Issues []jira_data_center.Issue `json:"issues"`
+	// FieldValues are the requested field values keyed by issue key then field ID
+	FieldValues map[string]map[string]any `json:"-"`
 }
@@ -2,6 +2,8 @@ package config
 
 import (
 	"context"
+	"fmt"
+	"net/netip"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -37,6 +39,8 @@ func InitConfig(logger *otelzap.Logger) Config {
 	viper.SetDefault("http.websocket_pong_wait_sec", 60)
 	viper.SetDefault("http.websocket_ping_period_sec", 54)
 	viper.SetDefault("http.websocket_subdomain", "")
+	viper.SetDefault("http.websocket_broadcast_backend", "memory")
+	viper.SetDefault("http.trusted_proxies", []string{})
 
 	viper.SetDefault("otel.enabled", false)
 	viper.SetDefault("otel.service_name", "thunderdome")
@@ -127,6 +131,11 @@ func InitConfig(logger *otelzap.Logger) Config {
 	viper.SetDefault("auth.ldap.filter", "(&(objectClass=posixAccount)(mail=%s))")
 	viper.SetDefault("auth.ldap.mail_attr", "mail")
 	viper.SetDefault("auth.ldap.cn_attr", "cn")
+	viper.SetDefault("auth.ldap.group_attr", "memberOf")
+	viper.SetDefault("auth.ldap.allowed_groups", []string{})
+	viper.SetDefault("auth.ldap.admin_groups", []string{})
+	viper.SetDefault("auth.ldap.sync_filter", "(&(objectClass=posixAccount)(mail=*))")
+	viper.SetDefault("auth.ldap.sync_interval_minutes", 0)
 	viper.SetDefault("auth.header.usernameHeader", "Remote-User")
 	viper.SetDefault("auth.header.emailHeader", "Remote-Email")
 	viper.SetDefault("auth.google.enabled", false)
@@ -140,6 +149,18 @@ func InitConfig(logger *otelzap.Logger) Config {
 	viper.SetDefault("auth.oidc.client_secret", "")
 	viper.SetDefault("auth.oidc.requested_scopes", []string{"openid", "profile", "email"})
 	viper.SetDefault("auth.oidc.requested_id_token_claims", []string{})
+	viper.SetDefault("auth.oidc.groups_claim", "")
+	viper.SetDefault("auth.oidc.admin_groups", []string{})
+	viper.SetDefault("auth.oidc.allowed_email_domains", []string{})
+
+	viper.SetDefault("ratelimit.enabled", true)
+	viper.SetDefault("ratelimit.auth_ip_requests", 30)
+	viper.SetDefault("ratelimit.auth_account_requests", 10)
+	viper.SetDefault("ratelimit.auth_window_seconds", 300)
+	viper.SetDefault("ratelimit.lockout_threshold", 10)
+	viper.SetDefault("ratelimit.lockout_minutes", 15)
+	viper.SetDefault("ratelimit.apikey_requests", 600)
+	viper.SetDefault("ratelimit.apikey_window_seconds", 60)
 
 	// automatically load matching envs
 	viper.SetEnvKeyReplacer(strings.NewReplacer(`.`, `_`))
@@ -175,3 +196,27 @@ func InitConfig(logger *otelzap.Logger) Config {
 
 	return c
 }
+
+// ParseTrustedProxies parses the trusted proxy IP addresses and CIDR ranges,
+// a single IP address is trusted as a range holding only that address
+func ParseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if prefix, err := netip.ParsePrefix(value); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", value)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return prefixes, nil
+}
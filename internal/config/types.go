@@ -12,29 +12,33 @@ type Config struct {
 	Config AppConfig
 	Feature
 	Auth
+	RateLimit
 	Subscription thunderdome.SubscriptionConfig
 }
 
 // Http is the application HTTP server configuration
 type Http struct {
-	Port                   string
-	SecureCookie           bool   `mapstructure:"secure_cookie"`
-	BackendCookieName      string `mapstructure:"backend_cookie_name"`
-	SessionCookieName      string `mapstructure:"session_cookie_name"`
-	FrontendCookieName     string `mapstructure:"frontend_cookie_name"`
-	AuthStateCookieName    string `mapstructure:"auth_state_cookie_name"`
-	Domain                 string
-	PathPrefix             string `mapstructure:"path_prefix"`
-	SecureProtocol         bool   `mapstructure:"secure_protocol"`
-	WriteTimeout           int    `mapstructure:"write_timeout"`
-	ReadTimeout            int    `mapstructure:"read_timeout"`
-	IdleTimeout            int    `mapstructure:"idle_timeout"`
-	ReadHeaderTimeout      int    `mapstructure:"read_header_timeout"`
-	CookieHashkey          string `mapstructure:"cookie_hashkey"`
-	WebsocketWriteWaitSec  int    `mapstructure:"websocket_write_wait_sec"`
-	WebsocketPingPeriodSec int    `mapstructure:"websocket_ping_period_sec"`
-	WebsocketPongWaitSec   int    `mapstructure:"websocket_pong_wait_sec"`
-	WebsocketSubdomain     string `mapstructure:"websocket_subdomain"`
+	Port                      string
+	SecureCookie              bool   `mapstructure:"secure_cookie"`
+	BackendCookieName         string `mapstructure:"backend_cookie_name"`
+	SessionCookieName         string `mapstructure:"session_cookie_name"`
+	FrontendCookieName        string `mapstructure:"frontend_cookie_name"`
+	AuthStateCookieName       string `mapstructure:"auth_state_cookie_name"`
+	Domain                    string
+	PathPrefix                string `mapstructure:"path_prefix"`
+	SecureProtocol            bool   `mapstructure:"secure_protocol"`
+	WriteTimeout              int    `mapstructure:"write_timeout"`
+	ReadTimeout               int    `mapstructure:"read_timeout"`
+	IdleTimeout               int    `mapstructure:"idle_timeout"`
+	ReadHeaderTimeout         int    `mapstructure:"read_header_timeout"`
+	CookieHashkey             string `mapstructure:"cookie_hashkey"`
+	WebsocketWriteWaitSec     int    `mapstructure:"websocket_write_wait_sec"`
+	WebsocketPingPeriodSec    int    `mapstructure:"websocket_ping_period_sec"`
+	WebsocketPongWaitSec      int    `mapstructure:"websocket_pong_wait_sec"`
+	WebsocketSubdomain        string `mapstructure:"websocket_subdomain"`
+	WebsocketBroadcastBackend string `mapstructure:"websocket_broadcast_backend"`
+	// TrustedProxies are the reverse proxy IP addresses or CIDR ranges whose forwarded client IP headers are trusted
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 // Admin is the application admin configuration
@@ -112,6 +116,18 @@ type Feature struct {
 	Project    bool
 }
 
+// RateLimit is the application rate limiting and account lockout configuration
+type RateLimit struct {
+	Enabled             bool
+	AuthIPRequests      int `mapstructure:"auth_ip_requests"`
+	AuthAccountRequests int `mapstructure:"auth_account_requests"`
+	AuthWindowSeconds   int `mapstructure:"auth_window_seconds"`
+	LockoutThreshold    int `mapstructure:"lockout_threshold"`
+	LockoutMinutes      int `mapstructure:"lockout_minutes"`
+	APIKeyRequests      int `mapstructure:"apikey_requests"`
+	APIKeyWindowSeconds int `mapstructure:"apikey_window_seconds"`
+}
+
 // Google is the application Google OAuth2 configuration
 type Google struct {
 	Enabled      bool   `mapstructure:"enabled"`
@@ -119,14 +135,44 @@ type Google struct {
 	ClientSecret string `mapstructure:"client_secret"`
 }
 
-// OIDC is the application OpenID Connect OAuth2 configuration
+// OIDC is the application OpenID Connect OAuth2 configuration, a single provider can be configured
+// inline and additional providers in Providers
 type OIDC struct {
-	ProviderName           string   `mapstructure:"provider_name"`
-	ProviderURL            string   `mapstructure:"provider_url"`
-	ClientID               string   `mapstructure:"client_id"`
-	ClientSecret           string   `mapstructure:"client_secret"`
-	RequestedScopes        []string `mapstructure:"requested_scopes"`
-	RequestedIDTokenClaims []string `mapstructure:"requested_id_token_claims"`
+	OIDCProvider `mapstructure:",squash"`
+	Providers    []OIDCProvider `mapstructure:"providers"`
+}
+
+// OIDCProvider is the application configuration of an OpenID Connect OAuth2 provider
+type OIDCProvider struct {
+	ProviderName           string         `mapstructure:"provider_name"`
+	ProviderURL            string         `mapstructure:"provider_url"`
+	ClientID               string         `mapstructure:"client_id"`
+	ClientSecret           string         `mapstructure:"client_secret"`
+	RequestedScopes        []string       `mapstructure:"requested_scopes"`
+	RequestedIDTokenClaims []string       `mapstructure:"requested_id_token_claims"`
+	GroupsClaim            string         `mapstructure:"groups_claim"`
+	GroupMappings          []GroupMapping `mapstructure:"group_mappings"`
+	AdminGroups            []string       `mapstructure:"admin_groups"`
+	AllowedEmailDomains    []string       `mapstructure:"allowed_email_domains"`
+}
+
+// AllProviders gets the inline provider, when configured, followed by the additional providers
+func (o OIDC) AllProviders() []OIDCProvider {
+	providers := make([]OIDCProvider, 0, len(o.Providers)+1)
+	if o.ProviderName != "" || len(o.Providers) == 0 {
+		providers = append(providers, o.OIDCProvider)
+	}
+
+	return append(providers, o.Providers...)
+}
+
+// GroupMapping is the application mapping of an identity provider group to organization, department or team membership
+type GroupMapping struct {
+	Group          string `mapstructure:"group"`
+	OrganizationID string `mapstructure:"organization_id"`
+	DepartmentID   string `mapstructure:"department_id"`
+	TeamID         string `mapstructure:"team_id"`
+	Role           string `mapstructure:"role"`
 }
 
 // Auth is the application authentication configuration
@@ -154,4 +200,11 @@ type AuthLdap struct {
 	Filter   string
 	MailAttr string `mapstructure:"mail_attr"`
 	CnAttr   string `mapstructure:"cn_attr"`
+	// GroupAttr is the user attribute listing the user's group DNs
+	GroupAttr           string         `mapstructure:"group_attr"`
+	AllowedGroups       []string       `mapstructure:"allowed_groups"`
+	AdminGroups         []string       `mapstructure:"admin_groups"`
+	GroupMappings       []GroupMapping `mapstructure:"group_mappings"`
+	SyncFilter          string         `mapstructure:"sync_filter"`
+	SyncIntervalMinutes int            `mapstructure:"sync_interval_minutes"`
 }
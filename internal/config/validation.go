@@ -52,20 +52,49 @@ func (c Config) Validate() []ValidationIssue {
 		issues = appendIfInvalid(issues, "auth.ldap.filter", !strings.Contains(c.Auth.Ldap.Filter, "%s"), "must include %s when auth.method=ldap")
 		issues = appendIfInvalid(issues, "auth.ldap.mail_attr", strings.TrimSpace(c.Auth.Ldap.MailAttr) == "", "must be configured when auth.method=ldap")
 		issues = appendIfInvalid(issues, "auth.ldap.cn_attr", strings.TrimSpace(c.Auth.Ldap.CnAttr) == "", "must be configured when auth.method=ldap")
+		issues = appendIfInvalid(issues, "auth.ldap.group_attr", strings.TrimSpace(c.Auth.Ldap.GroupAttr) == "" && (len(c.Auth.Ldap.AllowedGroups) > 0 || len(c.Auth.Ldap.AdminGroups) > 0 || len(c.Auth.Ldap.GroupMappings) > 0), "must be configured when allowed_groups, admin_groups or group_mappings are configured")
+		issues = appendIfInvalid(issues, "auth.ldap.sync_filter", strings.TrimSpace(c.Auth.Ldap.SyncFilter) == "" && c.Auth.Ldap.SyncIntervalMinutes > 0, "must be configured when auth.ldap.sync_interval_minutes is configured")
+		issues = append(issues, validateGroupMappings("auth.ldap", c.Auth.Ldap.GroupMappings)...)
 	case "oidc":
-		issues = appendIfInvalid(issues, "auth.oidc.provider_name", strings.TrimSpace(c.Auth.OIDC.ProviderName) == "", "must be configured when auth.method=oidc")
-		issues = appendIfInvalid(issues, "auth.oidc.provider_url", strings.TrimSpace(c.Auth.OIDC.ProviderURL) == "", "must be configured when auth.method=oidc")
-		issues = appendIfInvalid(issues, "auth.oidc.client_id", strings.TrimSpace(c.Auth.OIDC.ClientID) == "", "must be configured when auth.method=oidc")
-		issues = appendIfInvalid(issues, "auth.oidc.client_secret", strings.TrimSpace(c.Auth.OIDC.ClientSecret) == "", "must be configured when auth.method=oidc")
+		providerNames := make(map[string]bool)
+		validateProvider := func(key string, p OIDCProvider) {
+			issues = append(issues, validateOIDCProvider(key, p)...)
+
+			name := strings.ToLower(strings.TrimSpace(p.ProviderName))
+			issues = appendIfInvalid(issues, key+".provider_name", name != "" && providerNames[name], "must be unique")
+			providerNames[name] = true
+		}
+		if c.Auth.OIDC.ProviderName != "" || len(c.Auth.OIDC.Providers) == 0 {
+			validateProvider("auth.oidc", c.Auth.OIDC.OIDCProvider)
+		}
+		for i, p := range c.Auth.OIDC.Providers {
+			validateProvider(fmt.Sprintf("auth.oidc.providers[%d]", i), p)
+		}
 	default:
 		issues = append(issues, ValidationIssue{Key: "auth.method", Message: "must be one of normal, header, ldap, oidc"})
 	}
 
+	switch strings.ToLower(strings.TrimSpace(c.Http.WebsocketBroadcastBackend)) {
+	case "", "memory", "postgres":
+	default:
+		issues = append(issues, ValidationIssue{Key: "http.websocket_broadcast_backend", Message: "must be one of memory, postgres"})
+	}
+
+	if _, err := ParseTrustedProxies(c.Http.TrustedProxies); err != nil {
+		issues = append(issues, ValidationIssue{Key: "http.trusted_proxies", Message: "must be IP addresses or CIDR ranges"})
+	}
+
 	if c.Auth.Google.Enabled {
 		issues = appendIfInvalid(issues, "auth.google.client_id", strings.TrimSpace(c.Auth.Google.ClientID) == "", "must be configured when auth.google.enabled=true")
 		issues = appendIfInvalid(issues, "auth.google.client_secret", strings.TrimSpace(c.Auth.Google.ClientSecret) == "", "must be configured when auth.google.enabled=true")
 	}
 
+	if c.RateLimit.Enabled {
+		issues = appendIfInvalid(issues, "ratelimit.auth_window_seconds", c.RateLimit.AuthWindowSeconds <= 0 && (c.RateLimit.AuthIPRequests > 0 || c.RateLimit.AuthAccountRequests > 0), "must be greater than 0 when auth rate limits are configured")
+		issues = appendIfInvalid(issues, "ratelimit.lockout_minutes", c.RateLimit.LockoutMinutes <= 0 && c.RateLimit.LockoutThreshold > 0, "must be greater than 0 when ratelimit.lockout_threshold is configured")
+		issues = appendIfInvalid(issues, "ratelimit.apikey_window_seconds", c.RateLimit.APIKeyWindowSeconds <= 0 && c.RateLimit.APIKeyRequests > 0, "must be greater than 0 when ratelimit.apikey_requests is configured")
+	}
+
 	if c.Config.SubscriptionsEnabled {
 		issues = appendIfInvalid(issues, "subscription.account_secret", strings.TrimSpace(c.Subscription.AccountSecret) == "", "must be configured when config.subscriptions_enabled=true")
 		issues = appendIfInvalid(issues, "subscription.webhook_secret", strings.TrimSpace(c.Subscription.WebhookSecret) == "", "must be configured when config.subscriptions_enabled=true")
@@ -74,6 +103,38 @@ func (c Config) Validate() []ValidationIssue {
 	return issues
 }
 
+// validateOIDCProvider validates an OpenID Connect provider configured at the key
+func validateOIDCProvider(key string, p OIDCProvider) []ValidationIssue {
+	issues := make([]ValidationIssue, 0)
+
+	issues = appendIfInvalid(issues, key+".provider_name", strings.TrimSpace(p.ProviderName) == "", "must be configured when auth.method=oidc")
+	issues = appendIfInvalid(issues, key+".provider_url", strings.TrimSpace(p.ProviderURL) == "", "must be configured when auth.method=oidc")
+	issues = appendIfInvalid(issues, key+".client_id", strings.TrimSpace(p.ClientID) == "", "must be configured when auth.method=oidc")
+	issues = appendIfInvalid(issues, key+".client_secret", strings.TrimSpace(p.ClientSecret) == "", "must be configured when auth.method=oidc")
+	issues = appendIfInvalid(issues, key+".groups_claim", strings.TrimSpace(p.GroupsClaim) == "" && (len(p.GroupMappings) > 0 || len(p.AdminGroups) > 0), "must be configured when group_mappings or admin_groups are configured")
+	issues = append(issues, validateGroupMappings(key, p.GroupMappings)...)
+
+	return issues
+}
+
+// validateGroupMappings validates the group mappings configured at the key
+func validateGroupMappings(key string, mappings []GroupMapping) []ValidationIssue {
+	issues := make([]ValidationIssue, 0)
+
+	for i, m := range mappings {
+		mappingKey := fmt.Sprintf("%s.group_mappings[%d]", key, i)
+		issues = appendIfInvalid(issues, mappingKey+".group", strings.TrimSpace(m.Group) == "", "must be configured")
+		issues = appendIfInvalid(issues, mappingKey, m.OrganizationID == "" && m.DepartmentID == "" && m.TeamID == "", "must configure one of organization_id, department_id, team_id")
+		switch strings.ToUpper(m.Role) {
+		case "", "MEMBER", "ADMIN":
+		default:
+			issues = append(issues, ValidationIssue{Key: mappingKey + ".role", Message: "must be one of member, admin"})
+		}
+	}
+
+	return issues
+}
+
 func appendIfInvalid(issues []ValidationIssue, key string, invalid bool, message string) []ValidationIssue {
 	if invalid {
 		issues = append(issues, ValidationIssue{Key: key, Message: message})
@@ -146,6 +146,155 @@ func TestConfigValidateRejectsUnknownAuthMethod(t *testing.T) {
 	assertHasIssue(t, issues, "auth.method", "must be one of")
 }
 
+func TestConfigValidateRejectsUnknownWebsocketBroadcastBackend(t *testing.T) {
+	c := Config{
+		Http: Http{
+			Domain:                    "planning.example.com",
+			CookieHashkey:             "cookie-secret",
+			WebsocketBroadcastBackend: "redis",
+		},
+		Db:     Db{User: "planner", Pass: "db-secret"},
+		Config: AppConfig{AesHashkey: "aes-secret"},
+		Auth:   Auth{Method: "normal"},
+	}
+
+	issues := c.Validate()
+	assertHasIssue(t, issues, "http.websocket_broadcast_backend", "must be one of")
+}
+
+func TestConfigValidateRejectsInvalidTrustedProxies(t *testing.T) {
+	c := Config{
+		Http: Http{
+			Domain:         "planning.example.com",
+			CookieHashkey:  "cookie-secret",
+			TrustedProxies: []string{"10.0.0.0/8", "proxy.internal"},
+		},
+		Db:     Db{User: "planner", Pass: "db-secret"},
+		Config: AppConfig{AesHashkey: "aes-secret"},
+		Auth:   Auth{Method: "normal"},
+	}
+
+	issues := c.Validate()
+	assertHasIssue(t, issues, "http.trusted_proxies", "IP addresses or CIDR ranges")
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	prefixes, err := ParseTrustedProxies([]string{"10.0.0.0/8", " 192.168.1.10 ", "", "2001:db8::/32"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error = %v", err)
+	}
+
+	want := []string{"10.0.0.0/8", "192.168.1.10/32", "2001:db8::/32"}
+	if len(prefixes) != len(want) {
+		t.Fatalf("ParseTrustedProxies() = %v, want %v", prefixes, want)
+	}
+	for i, prefix := range prefixes {
+		if prefix.String() != want[i] {
+			t.Errorf("ParseTrustedProxies()[%d] = %s, want %s", i, prefix, want[i])
+		}
+	}
+}
+
+func TestConfigValidateFlagsRateLimitWindows(t *testing.T) {
+	c := Config{
+		Http:   Http{Domain: "planning.example.com", CookieHashkey: "cookie-secret"},
+		Db:     Db{User: "planner", Pass: "db-secret"},
+		Config: AppConfig{AesHashkey: "aes-secret"},
+		Auth:   Auth{Method: "normal"},
+		RateLimit: RateLimit{
+			Enabled:          true,
+			AuthIPRequests:   30,
+			LockoutThreshold: 10,
+			APIKeyRequests:   600,
+		},
+	}
+
+	issues := c.Validate()
+	assertHasIssue(t, issues, "ratelimit.auth_window_seconds", "greater than 0")
+	assertHasIssue(t, issues, "ratelimit.lockout_minutes", "greater than 0")
+	assertHasIssue(t, issues, "ratelimit.apikey_window_seconds", "greater than 0")
+}
+
+func TestConfigValidateFlagsOIDCGroupMappings(t *testing.T) {
+	c := Config{
+		Http:   Http{Domain: "planning.example.com", CookieHashkey: "cookie-secret"},
+		Db:     Db{User: "planner", Pass: "db-secret"},
+		Config: AppConfig{AesHashkey: "aes-secret"},
+		Auth: Auth{
+			Method: "oidc",
+			OIDC: OIDC{
+				OIDCProvider: OIDCProvider{
+					GroupMappings: []GroupMapping{
+						{Group: "engineering", TeamID: "2b8f3c2e-3c4e-4a8e-9d8b-0e6f1a2b3c4d", Role: "admin"},
+						{Group: "", Role: "owner"},
+					},
+				},
+			},
+		},
+	}
+
+	issues := c.Validate()
+	assertHasIssue(t, issues, "auth.oidc.groups_claim", "must be configured")
+	assertHasIssue(t, issues, "auth.oidc.group_mappings[1].group", "must be configured")
+	assertHasIssue(t, issues, "auth.oidc.group_mappings[1]", "one of organization_id")
+	assertHasIssue(t, issues, "auth.oidc.group_mappings[1].role", "member, admin")
+	for _, issue := range issues {
+		if strings.HasPrefix(issue.Key, "auth.oidc.group_mappings[0]") {
+			t.Fatalf("unexpected issue for valid group mapping: %v", issue)
+		}
+	}
+}
+
+func TestConfigValidateFlagsOIDCProviders(t *testing.T) {
+	provider := OIDCProvider{
+		ProviderName: "Acme",
+		ProviderURL:  "https://sso.acme.example.com",
+		ClientID:     "thunderdome",
+		ClientSecret: "client-secret",
+	}
+	c := Config{
+		Http:   Http{Domain: "planning.example.com", CookieHashkey: "cookie-secret"},
+		Db:     Db{User: "planner", Pass: "db-secret"},
+		Config: AppConfig{AesHashkey: "aes-secret"},
+		Auth: Auth{
+			Method: "oidc",
+			OIDC: OIDC{
+				Providers: []OIDCProvider{provider, {ProviderName: "acme"}},
+			},
+		},
+	}
+
+	issues := c.Validate()
+	assertHasIssue(t, issues, "auth.oidc.providers[1].provider_name", "must be unique")
+	assertHasIssue(t, issues, "auth.oidc.providers[1].client_id", "auth.method=oidc")
+	for _, issue := range issues {
+		if strings.HasPrefix(issue.Key, "auth.oidc.provider_name") || strings.HasPrefix(issue.Key, "auth.oidc.providers[0]") {
+			t.Fatalf("unexpected issue for configured provider: %v", issue)
+		}
+	}
+}
+
+func TestConfigValidateFlagsLdapGroupsAndSync(t *testing.T) {
+	c := Config{
+		Http:   Http{Domain: "planning.example.com", CookieHashkey: "cookie-secret"},
+		Db:     Db{User: "planner", Pass: "db-secret"},
+		Config: AppConfig{AesHashkey: "aes-secret"},
+		Auth: Auth{
+			Method: "ldap",
+			Ldap: AuthLdap{
+				AllowedGroups:       []string{"thunderdome-users"},
+				GroupMappings:       []GroupMapping{{Group: "platform"}},
+				SyncIntervalMinutes: 60,
+			},
+		},
+	}
+
+	issues := c.Validate()
+	assertHasIssue(t, issues, "auth.ldap.group_attr", "must be configured")
+	assertHasIssue(t, issues, "auth.ldap.sync_filter", "sync_interval_minutes")
+	assertHasIssue(t, issues, "auth.ldap.group_mappings[0]", "one of organization_id")
+}
+
 func assertHasIssue(t *testing.T, issues []ValidationIssue, key string, messagePart string) {
 	t.Helper()
 
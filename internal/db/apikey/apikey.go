@@ -3,11 +3,13 @@ package apikey
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
@@ -22,7 +24,7 @@ type Service struct {
 }
 
 // GenerateAPIKey generates a new API key for a User
-func (d *Service) GenerateAPIKey(ctx context.Context, userID string, keyName string) (*thunderdome.APIKey, error) {
+func (d *Service) GenerateAPIKey(ctx context.Context, userID string, keyName string, restrictions thunderdome.APIKeyRestrictions) (*thunderdome.APIKey, error) {
 	apiPrefix, prefixErr := db.RandomString(8)
 	if prefixErr != nil {
 		return nil, fmt.Errorf("error generating api prefix: %v", prefixErr)
@@ -46,12 +48,21 @@ func (d *Service) GenerateAPIKey(ctx context.Context, userID string, keyName str
 		Active:      true,
 		CreatedDate: time.Now(),
 	}
+	if restrictions.Scopes == nil {
+		restrictions.Scopes = make([]string, 0)
+	}
+	apiKey.APIKeyRestrictions = restrictions
 
 	err := d.DB.QueryRowContext(ctx,
-		`INSERT INTO thunderdome.api_key (id, name, user_id) VALUES ($1, $2, $3) RETURNING created_date;`,
+		`INSERT INTO thunderdome.api_key (id, name, user_id, scopes, expires_at, team_id, organization_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING created_date;`,
 		keyID,
 		keyName,
 		userID,
+		restrictions.Scopes,
+		restrictions.ExpiresAt,
+		restrictions.TeamID,
+		restrictions.OrganizationID,
 	).Scan(&apiKey.CreatedDate)
 	if err != nil {
 		return nil, fmt.Errorf("error creating api key: %v", err)
@@ -64,7 +75,8 @@ func (d *Service) GenerateAPIKey(ctx context.Context, userID string, keyName str
 func (d *Service) GetUserAPIKeys(ctx context.Context, userID string) ([]*thunderdome.APIKey, error) {
 	var keys = make([]*thunderdome.APIKey, 0)
 	rows, err := d.DB.QueryContext(ctx,
-		"SELECT id, name, user_id, active, created_date, updated_date FROM thunderdome.api_key WHERE user_id = $1 ORDER BY created_date",
+		`SELECT id, name, user_id, active, scopes, expires_at, team_id, organization_id, last_used, created_date, updated_date
+		FROM thunderdome.api_key WHERE user_id = $1 ORDER BY created_date`,
 		userID,
 	)
 	if err == nil {
@@ -72,12 +84,19 @@ func (d *Service) GetUserAPIKeys(ctx context.Context, userID string) ([]*thunder
 		for rows.Next() {
 			var ak thunderdome.APIKey
 			var key string
+			var scopes pgtype.Array[string]
+			m := pgtype.NewMap()
 
 			if err := rows.Scan(
 				&key,
 				&ak.Name,
 				&ak.UserID,
 				&ak.Active,
+				m.SQLScanner(&scopes),
+				&ak.ExpiresAt,
+				&ak.TeamID,
+				&ak.OrganizationID,
+				&ak.LastUsed,
 				&ak.CreatedDate,
 				&ak.UpdatedDate,
 			); err != nil {
@@ -86,6 +105,7 @@ func (d *Service) GetUserAPIKeys(ctx context.Context, userID string) ([]*thunder
 				splitKey := strings.Split(key, ".")
 				ak.Prefix = splitKey[0]
 				ak.ID = key
+				ak.Scopes = apiKeyScopes(scopes)
 				keys = append(keys, &ak)
 			}
 		}
@@ -126,16 +146,21 @@ func (d *Service) DeleteUserAPIKey(ctx context.Context, userID string, keyID str
 	return keys, nil
 }
 
-// GetAPIKeyUser checks to see if the API key exists and returns the User
-func (d *Service) GetAPIKeyUser(ctx context.Context, apiKey string) (*thunderdome.User, error) {
+// GetAPIKeyUser checks to see if the API key exists and is unexpired, returning the User and the key
+// with its restrictions, the key last used timestamp is updated at most once a minute
+func (d *Service) GetAPIKeyUser(ctx context.Context, apiKey string) (*thunderdome.User, *thunderdome.APIKey, error) {
 	user := &thunderdome.User{}
+	key := &thunderdome.APIKey{}
+	var scopes pgtype.Array[string]
+	m := pgtype.NewMap()
 
 	splitKey := strings.Split(apiKey, ".")
 	hashedKey := db.HashString(apiKey)
 	keyID := splitKey[0] + "." + hashedKey
 
 	err := d.DB.QueryRowContext(ctx, `
-		SELECT u.id, u.name, u.email, u.type, u.avatar, u.verified, u.notifications_enabled, COALESCE(u.country, ''), COALESCE(u.locale, ''), COALESCE(u.company, ''), COALESCE(u.job_title, ''), u.created_date, u.updated_date, u.last_active
+		SELECT u.id, u.name, u.email, u.type, u.avatar, u.verified, u.notifications_enabled, COALESCE(u.country, ''), COALESCE(u.locale, ''), COALESCE(u.company, ''), COALESCE(u.job_title, ''), u.created_date, u.updated_date, u.last_active,
+		ak.name, ak.active, ak.scopes, ak.expires_at, ak.team_id, ak.organization_id, ak.last_used, ak.created_date, ak.updated_date
 		FROM thunderdome.api_key ak
 		LEFT JOIN thunderdome.users u ON u.id = ak.user_id
 		WHERE ak.id = $1 AND ak.active = true
@@ -155,21 +180,57 @@ func (d *Service) GetAPIKeyUser(ctx context.Context, apiKey string) (*thunderdom
 		&user.JobTitle,
 		&user.CreatedDate,
 		&user.UpdatedDate,
-		&user.LastActive)
+		&user.LastActive,
+		&key.Name,
+		&key.Active,
+		m.SQLScanner(&scopes),
+		&key.ExpiresAt,
+		&key.TeamID,
+		&key.OrganizationID,
+		&key.LastUsed,
+		&key.CreatedDate,
+		&key.UpdatedDate,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("active API Key match not found: %v", err)
+		return nil, nil, fmt.Errorf("active API Key match not found: %v", err)
+	}
+
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return nil, nil, errors.New("APIKEY_EXPIRED")
 	}
 
+	key.ID = keyID
+	key.Prefix = splitKey[0]
+	key.UserID = user.ID
+	key.Scopes = apiKeyScopes(scopes)
 	user.GravatarHash = db.CreateGravatarHash(user.Email)
 
-	return user, nil
+	if _, err := d.DB.ExecContext(ctx,
+		`UPDATE thunderdome.api_key SET last_used = NOW()
+		WHERE id = $1 AND (last_used IS NULL OR last_used < NOW() - INTERVAL '1 minute');`,
+		keyID,
+	); err != nil {
+		d.Logger.Ctx(ctx).Error("GetAPIKeyUser last used update error", zap.Error(err))
+	}
+
+	return user, key, nil
+}
+
+// apiKeyScopes converts the scanned scopes array to a non nil slice
+func apiKeyScopes(scopes pgtype.Array[string]) []string {
+	if scopes.Elements == nil {
+		return make([]string, 0)
+	}
+
+	return scopes.Elements
 }
 
 // GetAPIKeys gets a list of api keys
 func (d *Service) GetAPIKeys(ctx context.Context, limit int, offset int) []*thunderdome.UserAPIKey {
 	var keys = make([]*thunderdome.UserAPIKey, 0)
 	rows, err := d.DB.QueryContext(ctx,
-		`SELECT apk.id, apk.name, u.id, u.name, u.email, apk.active, apk.created_date, apk.updated_date
+		`SELECT apk.id, apk.name, u.id, u.name, COALESCE(u.email, ''), u.type, apk.active, apk.scopes, apk.expires_at, apk.team_id,
+		apk.organization_id, apk.last_used, apk.created_date, apk.updated_date
 		FROM thunderdome.api_key apk
 		LEFT JOIN thunderdome.users u ON apk.user_id = u.id
 		ORDER BY apk.created_date
@@ -183,6 +244,8 @@ func (d *Service) GetAPIKeys(ctx context.Context, limit int, offset int) []*thun
 		for rows.Next() {
 			var ak thunderdome.UserAPIKey
 			var key string
+			var scopes pgtype.Array[string]
+			m := pgtype.NewMap()
 
 			if err := rows.Scan(
 				&key,
@@ -190,7 +253,13 @@ func (d *Service) GetAPIKeys(ctx context.Context, limit int, offset int) []*thun
 				&ak.UserID,
 				&ak.UserName,
 				&ak.UserEmail,
+				&ak.UserType,
 				&ak.Active,
+				m.SQLScanner(&scopes),
+				&ak.ExpiresAt,
+				&ak.TeamID,
+				&ak.OrganizationID,
+				&ak.LastUsed,
 				&ak.CreatedDate,
 				&ak.UpdatedDate,
 			); err != nil {
@@ -199,6 +268,7 @@ func (d *Service) GetAPIKeys(ctx context.Context, limit int, offset int) []*thun
 				splitKey := strings.Split(key, ".")
 				ak.Prefix = splitKey[0]
 				ak.ID = key
+				ak.Scopes = apiKeyScopes(scopes)
 				keys = append(keys, &ak)
 			}
 		}
@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+)
+
+// Service represents the audit event database service
+type Service struct {
+	DB     *sql.DB
+	Logger *otelzap.Logger
+}
+
+// CreateEvent appends an audit event
+func (d *Service) CreateEvent(ctx context.Context, event thunderdome.AuditEvent) error {
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return fmt.Errorf("audit event metadata marshal error: %v", err)
+	}
+	if event.Metadata == nil {
+		metadata = []byte("{}")
+	}
+
+	if _, err := d.DB.ExecContext(ctx,
+		`INSERT INTO thunderdome.audit_event
+		(action, actor_id, actor_type, target_type, target_id, organization_id, metadata, ip_address, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`,
+		event.Action,
+		event.ActorID,
+		event.ActorType,
+		event.TargetType,
+		event.TargetID,
+		event.OrganizationID,
+		metadata,
+		truncate(event.IPAddress, 64),
+		truncate(event.UserAgent, 512),
+	); err != nil {
+		return fmt.Errorf("audit event create query error: %v", err)
+	}
+
+	return nil
+}
+
+// ListEvents gets the audit events matching the filter newest first along with the total count
+func (d *Service) ListEvents(ctx context.Context, filter thunderdome.AuditEventFilter, limit int, offset int) ([]*thunderdome.AuditEvent, int, error) {
+	events := make([]*thunderdome.AuditEvent, 0)
+	where, args := eventFilterWhere(filter)
+
+	var count int
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM thunderdome.audit_event ae`+where+`;`,
+		args...,
+	).Scan(&count)
+	if err != nil {
+		return nil, 0, fmt.Errorf("audit event count query error: %v", err)
+	}
+
+	args = append(args, limit, offset)
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT ae.id, ae.action, ae.actor_id, COALESCE(u.name, ''), ae.actor_type, ae.target_type, ae.target_id,
+		ae.organization_id, ae.metadata, ae.ip_address, ae.user_agent, ae.created_date
+		FROM thunderdome.audit_event ae
+		LEFT JOIN thunderdome.users u ON u.id = ae.actor_id`+where+`
+		ORDER BY ae.created_date DESC
+		LIMIT $`+fmt.Sprint(len(args)-1)+` OFFSET $`+fmt.Sprint(len(args))+`;`,
+		args...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("audit event list query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e thunderdome.AuditEvent
+		var metadata []byte
+		if err := rows.Scan(
+			&e.ID,
+			&e.Action,
+			&e.ActorID,
+			&e.ActorName,
+			&e.ActorType,
+			&e.TargetType,
+			&e.TargetID,
+			&e.OrganizationID,
+			&metadata,
+			&e.IPAddress,
+			&e.UserAgent,
+			&e.CreatedDate,
+		); err != nil {
+			return nil, 0, fmt.Errorf("audit event list scan error: %v", err)
+		}
+		if err := json.Unmarshal(metadata, &e.Metadata); err != nil {
+			return nil, 0, fmt.Errorf("audit event metadata unmarshal error: %v", err)
+		}
+		events = append(events, &e)
+	}
+
+	return events, count, nil
+}
+
+// eventFilterWhere builds the WHERE clause and its arguments for an audit event filter
+func eventFilterWhere(filter thunderdome.AuditEventFilter) (string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Action != "" {
+		add("ae.action = $%d", filter.Action)
+	}
+	if filter.ActorID != "" {
+		add("ae.actor_id = $%d", filter.ActorID)
+	}
+	if filter.TargetType != "" {
+		add("ae.target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		add("ae.target_id = $%d", filter.TargetID)
+	}
+	if filter.OrganizationID != "" {
+		add("ae.organization_id = $%d", filter.OrganizationID)
+	}
+	if filter.From != nil {
+		add("ae.created_date >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("ae.created_date < $%d", *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// truncate limits a string to the column size
+func truncate(value string, size int) string {
+	if len(value) <= size {
+		return value
+	}
+
+	return value[:size]
+}
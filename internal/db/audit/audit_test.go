@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func TestEventFilterWhere(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    thunderdome.AuditEventFilter
+		wantWhere string
+		wantArgs  int
+	}{
+		{name: "no filter", wantWhere: ""},
+		{
+			name:      "organization and action",
+			filter:    thunderdome.AuditEventFilter{OrganizationID: "org", Action: thunderdome.AuditActionTeamUserAdd},
+			wantWhere: " WHERE ae.action = $1 AND ae.organization_id = $2",
+			wantArgs:  2,
+		},
+		{
+			name:      "actor since",
+			filter:    thunderdome.AuditEventFilter{ActorID: "user", From: &from},
+			wantWhere: " WHERE ae.actor_id = $1 AND ae.created_date >= $2",
+			wantArgs:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := eventFilterWhere(tt.filter)
+			if where != tt.wantWhere {
+				t.Errorf("eventFilterWhere() where = %q, want %q", where, tt.wantWhere)
+			}
+			if len(args) != tt.wantArgs {
+				t.Errorf("eventFilterWhere() args = %d, want %d", len(args), tt.wantArgs)
+			}
+		})
+	}
+}
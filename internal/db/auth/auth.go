@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"image/png"
+	"time"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
 
@@ -30,11 +31,13 @@ func (d *Service) AuthUser(ctx context.Context, userEmail string, userPassword s
 	var user thunderdome.User
 	var cred thunderdome.Credential
 	var passHash string
+	var locked, hasFailedLogins bool
 	sanitizedEmail := db.SanitizeEmail(userEmail)
 
 	err := d.DB.QueryRowContext(ctx,
 		`SELECT u.id, u.name, c.email, u.type, c.password, u.avatar, c.verified, u.verified, u.notifications_enabled,
- 			COALESCE(u.locale, ''), u.disabled, c.mfa_enabled, u.theme, COALESCE(u.picture, '')
+ 			COALESCE(u.locale, ''), u.disabled, c.mfa_enabled, u.theme, COALESCE(u.picture, ''),
+			COALESCE(c.locked_until > NOW(), false), c.failed_login_attempts > 0 OR c.locked_until IS NOT NULL
 			FROM thunderdome.auth_credential c
 			JOIN thunderdome.users u ON c.user_id = u.id
 			WHERE c.email = $1`,
@@ -54,6 +57,8 @@ func (d *Service) AuthUser(ctx context.Context, userEmail string, userPassword s
 		&cred.MFAEnabled,
 		&user.Theme,
 		&user.Picture,
+		&locked,
+		&hasFailedLogins,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -64,6 +69,11 @@ func (d *Service) AuthUser(ctx context.Context, userEmail string, userPassword s
 		}
 	}
 
+	// a locked account doesn't get its password checked so guessing can't continue while locked
+	if locked {
+		return nil, nil, "", errors.New("USER_LOCKED")
+	}
+
 	if !db.ComparePasswords(passHash, userPassword) {
 		return nil, nil, "", errors.New("INVALID_PASSWORD")
 	}
@@ -72,6 +82,16 @@ func (d *Service) AuthUser(ctx context.Context, userEmail string, userPassword s
 		return nil, nil, "", errors.New("USER_DISABLED")
 	}
 
+	if hasFailedLogins {
+		if _, resetErr := d.DB.ExecContext(ctx,
+			`UPDATE thunderdome.auth_credential SET failed_login_attempts = 0, last_failed_login = NULL, locked_until = NULL
+			WHERE user_id = $1;`,
+			user.ID,
+		); resetErr != nil {
+			d.Logger.Ctx(ctx).Error("Unable to reset failed login attempts", zap.Error(resetErr), zap.String("email", sanitizedEmail))
+		}
+	}
+
 	// check to see if the bcrypt cost has been updated, if not do so
 	if db.CheckPasswordCost(passHash) {
 		hashedPassword, hashErr := db.HashSaltPassword(userPassword)
@@ -91,6 +111,51 @@ func (d *Service) AuthUser(ctx context.Context, userEmail string, userPassword s
 	return &user, &cred, sessionID, nil
 }
 
+// UserLoginFailed records a failed password login for the account, locking it for the lockout duration
+// once threshold failures happen without a gap longer than the lockout duration, returns the account user
+// along with the lock expiry when this failure locked the account
+func (d *Service) UserLoginFailed(
+	ctx context.Context, userEmail string, threshold int, lockout time.Duration,
+) (*thunderdome.User, *time.Time, error) {
+	var user thunderdome.User
+	var justLocked bool
+	var lockedUntil sql.NullTime
+	sanitizedEmail := db.SanitizeEmail(userEmail)
+
+	err := d.DB.QueryRowContext(ctx, `
+		WITH attempt AS (
+			SELECT c.user_id, CASE
+				WHEN c.last_failed_login IS NULL OR c.last_failed_login < NOW() - make_interval(secs => $3) THEN 1
+				ELSE c.failed_login_attempts + 1
+			END AS attempts
+			FROM thunderdome.auth_credential c
+			WHERE c.email = $1
+		)
+		UPDATE thunderdome.auth_credential c SET
+			failed_login_attempts = CASE WHEN a.attempts >= $2 THEN 0 ELSE a.attempts END,
+			last_failed_login = CASE WHEN a.attempts >= $2 THEN NULL ELSE NOW() END,
+			locked_until = CASE WHEN a.attempts >= $2 THEN NOW() + make_interval(secs => $3) ELSE c.locked_until END
+		FROM attempt a, thunderdome.users u
+		WHERE c.user_id = a.user_id AND u.id = c.user_id
+		RETURNING u.id, u.name, c.email, a.attempts >= $2, c.locked_until;
+		`,
+		sanitizedEmail,
+		threshold,
+		lockout.Seconds(),
+	).Scan(&user.ID, &user.Name, &user.Email, &justLocked, &lockedUntil)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, errors.New("USER_NOT_FOUND")
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("record failed login query error: %v", err)
+	}
+
+	if justLocked && lockedUntil.Valid {
+		return &user, &lockedUntil.Time, nil
+	}
+
+	return &user, nil, nil
+}
+
 // UserResetRequest inserts a new user reset request
 func (d *Service) UserResetRequest(ctx context.Context, userEmail string) (resetID string, userName string, resetErr error) {
 	var resetIDVal sql.NullString
@@ -163,7 +228,7 @@ func (d *Service) UserResetPassword(ctx context.Context, resetID string, userPas
 	// Update auth_credential
 	_, err = tx.Exec(`
         UPDATE thunderdome.auth_credential
-        SET password = $1, updated_date = NOW()
+        SET password = $1, updated_date = NOW(), failed_login_attempts = 0, last_failed_login = NULL, locked_until = NULL
         WHERE user_id = $2
     `, hashedPassword, matchedUserID)
 	if err != nil {
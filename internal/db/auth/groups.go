@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// SyncGroupMemberships applies the memberships resolved from a user's identity provider groups, granting or updating
+// the role of each membership with a role and removing the user from mapped entities without one. Removal from an
+// organization or department is skipped when a granted membership is within it. A non-empty userType promotes or
+// demotes a registered or admin user. The sync is applied in a single transaction so a failure leaves the
+// user's memberships unchanged
+func (d *Service) SyncGroupMemberships(ctx context.Context, userID string, memberships []thunderdome.GroupMembership, userType string) error {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	grantedOrgs := make(map[string]bool)
+	grantedDepartments := make(map[string]bool)
+
+	for _, m := range memberships {
+		if m.Role == "" {
+			continue
+		}
+
+		var err error
+		switch {
+		case m.TeamID != "":
+			var orgID, departmentID string
+			err = tx.QueryRowContext(ctx,
+				`SELECT COALESCE(t.organization_id::text, od.organization_id::text, ''), COALESCE(t.department_id::text, '')
+				FROM thunderdome.team t
+				LEFT JOIN thunderdome.organization_department od ON od.id = t.department_id
+				WHERE t.id = $1;`,
+				m.TeamID,
+			).Scan(&orgID, &departmentID)
+			if err != nil && errors.Is(err, sql.ErrNoRows) {
+				d.Logger.Ctx(ctx).Warn("oidc group mapping team not found", zap.String("team_id", m.TeamID))
+				continue
+			} else if err != nil {
+				return fmt.Errorf("oauth sync groups team query error: %v", err)
+			}
+			if orgID != "" {
+				grantedOrgs[orgID] = true
+				if err = d.upsertMembership(ctx, tx, "organization_user", "organization_id", orgID, userID, ""); err != nil {
+					return err
+				}
+			}
+			if departmentID != "" {
+				grantedDepartments[departmentID] = true
+				if err = d.upsertMembership(ctx, tx, "department_user", "department_id", departmentID, userID, ""); err != nil {
+					return err
+				}
+			}
+			err = d.upsertMembership(ctx, tx, "team_user", "team_id", m.TeamID, userID, m.Role)
+		case m.DepartmentID != "":
+			var orgID string
+			err = tx.QueryRowContext(ctx,
+				`SELECT organization_id FROM thunderdome.organization_department WHERE id = $1;`,
+				m.DepartmentID,
+			).Scan(&orgID)
+			if err != nil && errors.Is(err, sql.ErrNoRows) {
+				d.Logger.Ctx(ctx).Warn("oidc group mapping department not found", zap.String("department_id", m.DepartmentID))
+				continue
+			} else if err != nil {
+				return fmt.Errorf("oauth sync groups department query error: %v", err)
+			}
+			grantedOrgs[orgID] = true
+			grantedDepartments[m.DepartmentID] = true
+			if err = d.upsertMembership(ctx, tx, "organization_user", "organization_id", orgID, userID, ""); err != nil {
+				return err
+			}
+			err = d.upsertMembership(ctx, tx, "department_user", "department_id", m.DepartmentID, userID, m.Role)
+		case m.OrganizationID != "":
+			grantedOrgs[m.OrganizationID] = true
+			err = d.upsertMembership(ctx, tx, "organization_user", "organization_id", m.OrganizationID, userID, m.Role)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, m := range memberships {
+		if m.Role != "" {
+			continue
+		}
+
+		var err error
+		switch {
+		case m.TeamID != "":
+			_, err = tx.ExecContext(ctx,
+				`DELETE FROM thunderdome.team_user WHERE team_id = $1 AND user_id = $2;`,
+				m.TeamID, userID,
+			)
+		// the department_user_remove and organization_user_remove procedures commit,
+		// which isn't allowed within the transaction, so their deletes are run directly
+		case m.DepartmentID != "" && !grantedDepartments[m.DepartmentID]:
+			_, err = tx.ExecContext(ctx,
+				`WITH tu AS (
+					DELETE FROM thunderdome.team_user WHERE user_id = $2
+					AND team_id IN (SELECT id FROM thunderdome.team WHERE department_id = $1)
+				)
+				DELETE FROM thunderdome.department_user WHERE department_id = $1 AND user_id = $2;`,
+				m.DepartmentID, userID,
+			)
+		case m.DepartmentID == "" && m.OrganizationID != "" && !grantedOrgs[m.OrganizationID]:
+			_, err = tx.ExecContext(ctx,
+				`WITH od AS (
+					SELECT id FROM thunderdome.organization_department WHERE organization_id = $1
+				), tu AS (
+					DELETE FROM thunderdome.team_user WHERE user_id = $2
+					AND team_id IN (
+						SELECT id FROM thunderdome.team
+						WHERE organization_id = $1 OR department_id IN (SELECT id FROM od)
+					)
+				), du AS (
+					DELETE FROM thunderdome.department_user WHERE user_id = $2 AND department_id IN (SELECT id FROM od)
+				)
+				DELETE FROM thunderdome.organization_user WHERE organization_id = $1 AND user_id = $2;`,
+				m.OrganizationID, userID,
+			)
+		}
+		if err != nil {
+			return fmt.Errorf("oauth sync groups remove membership query error: %v", err)
+		}
+	}
+
+	if userType != "" {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE thunderdome.users SET type = $2, updated_date = NOW()
+			WHERE id = $1 AND type IN ('REGISTERED', 'ADMIN') AND type <> $2;`,
+			userID, userType,
+		); err != nil {
+			return fmt.Errorf("oauth sync groups user type query error: %v", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// upsertMembership adds a user to an organization, department or team membership table,
+// an empty role keeps an existing membership's role and adds new members with the member role
+func (d *Service) upsertMembership(ctx context.Context, tx *sql.Tx, table string, column string, entityID string, userID string, role string) error {
+	onConflict := `DO NOTHING`
+	if role != "" {
+		onConflict = `DO UPDATE SET role = EXCLUDED.role, updated_date = NOW()`
+	} else {
+		role = thunderdome.EntityMemberUserType
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO thunderdome.`+table+` (`+column+`, user_id, role) VALUES ($1, $2, $3)
+		ON CONFLICT (`+column+`, user_id) `+onConflict+`;`,
+		entityID, userID, role,
+	); err != nil {
+		return fmt.Errorf("oauth sync groups %s upsert query error: %v", table, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+const (
+	testOrgID        = "c3d4e5f6-a7b8-4c9d-8e1f-2a3b4c5d6e7f"
+	testOtherOrgID   = "d4e5f6a7-b8c9-4d0e-9f1a-2b3c4d5e6f7a"
+	testDepartmentID = "e5f6a7b8-c9d0-4e1f-8a2b-3c4d5e6f7a8b"
+	testTeamID       = "b2c3d4e5-f6a7-4b8c-9d0e-1f2a3b4c5d6e"
+)
+
+func TestSyncGroupMemberships(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO thunderdome.organization_user`).
+		WithArgs(testOrgID, testUserID, thunderdome.AdminUserType).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM thunderdome.team_user WHERE team_id = \$1 AND user_id = \$2`).
+		WithArgs(testTeamID, testUserID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE thunderdome.users SET type = \$2`).
+		WithArgs(testUserID, thunderdome.RegisteredUserType).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := s.SyncGroupMemberships(context.Background(), testUserID, []thunderdome.GroupMembership{
+		{OrganizationID: testOrgID, Role: thunderdome.AdminUserType},
+		{TeamID: testTeamID},
+	}, thunderdome.RegisteredUserType)
+	if err != nil {
+		t.Fatalf("SyncGroupMemberships() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSyncGroupMembershipsRollsBack(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO thunderdome.organization_user`).
+		WithArgs(testOrgID, testUserID, thunderdome.AdminUserType).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM thunderdome.team_user`).
+		WithArgs(testTeamID, testUserID).
+		WillReturnError(errors.New("connection reset"))
+	// the granted organization membership is rolled back with the failed removal
+	mock.ExpectRollback()
+
+	err := s.SyncGroupMemberships(context.Background(), testUserID, []thunderdome.GroupMembership{
+		{OrganizationID: testOrgID, Role: thunderdome.AdminUserType},
+		{TeamID: testTeamID},
+	}, "")
+	if err == nil {
+		t.Fatal("SyncGroupMemberships() error = nil, want the removal error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSyncGroupMembershipsRemovesOrganizationAndDepartment(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	// memberships are removed with plain deletes, the removal procedures can't commit within the transaction
+	mock.ExpectExec(`WITH tu AS \(\s*DELETE FROM thunderdome.team_user[\s\S]*DELETE FROM thunderdome.department_user WHERE department_id = \$1 AND user_id = \$2`).
+		WithArgs(testDepartmentID, testUserID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`WITH od AS \([\s\S]*DELETE FROM thunderdome.department_user[\s\S]*DELETE FROM thunderdome.organization_user WHERE organization_id = \$1 AND user_id = \$2`).
+		WithArgs(testOtherOrgID, testUserID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := s.SyncGroupMemberships(context.Background(), testUserID, []thunderdome.GroupMembership{
+		{OrganizationID: testOrgID, DepartmentID: testDepartmentID},
+		{OrganizationID: testOtherOrgID},
+	}, "")
+	if err != nil {
+		t.Fatalf("SyncGroupMemberships() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,340 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+const (
+	recoveryCodeCount    = 10
+	recoveryCodeLength   = 10
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// generateRecoveryCode generates a random recovery code formatted as xxxxx-xxxxx,
+// the alphabet leaves out characters that are easily confused when written down
+func generateRecoveryCode() (string, error) {
+	var sb strings.Builder
+	alphabetSize := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := 0; i < recoveryCodeLength; i++ {
+		if i == recoveryCodeLength/2 {
+			sb.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(recoveryCodeAlphabet[n.Int64()])
+	}
+
+	return sb.String(), nil
+}
+
+// normalizeRecoveryCode strips the formatting a user may have added when typing in a recovery code
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, code)
+}
+
+// MFAStatus gets the second factors configured for the user
+func (d *Service) MFAStatus(ctx context.Context, userID string) (*thunderdome.MFAStatus, error) {
+	status := &thunderdome.MFAStatus{}
+
+	err := d.DB.QueryRowContext(ctx, `
+		SELECT
+			EXISTS(SELECT 1 FROM thunderdome.user_mfa WHERE user_id = $1),
+			(SELECT COUNT(*) FROM thunderdome.user_webauthn_credential WHERE user_id = $1),
+			(SELECT COUNT(*) FROM thunderdome.user_mfa_recovery_code WHERE user_id = $1 AND used_date IS NULL);
+		`,
+		userID,
+	).Scan(
+		&status.TOTPEnabled,
+		&status.WebAuthnCredentials,
+		&status.RecoveryCodesRemaining,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get user mfa status query error: %v", err)
+	}
+
+	return status, nil
+}
+
+// MFARecoveryCodesGenerate replaces the user's recovery codes with a new set,
+// the codes are only returned here as just their hash is stored
+func (d *Service) MFARecoveryCodesGenerate(ctx context.Context, userID string) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, fmt.Errorf("error generating recovery code: %w", err)
+		}
+		codes = append(codes, code)
+	}
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx,
+		`DELETE FROM thunderdome.user_mfa_recovery_code WHERE user_id = $1;`, userID); err != nil {
+		return nil, fmt.Errorf("delete user recovery codes query error: %v", err)
+	}
+
+	for _, code := range codes {
+		if _, err = tx.ExecContext(ctx, `
+			INSERT INTO thunderdome.user_mfa_recovery_code (user_id, code_hash) VALUES ($1, $2);
+			`,
+			userID,
+			db.HashString(normalizeRecoveryCode(code)),
+		); err != nil {
+			return nil, fmt.Errorf("create user recovery code query error: %v", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return codes, nil
+}
+
+// MFARecoveryCodeValidate uses up one of the user's recovery codes in place of a second factor for auth login
+func (d *Service) MFARecoveryCodeValidate(ctx context.Context, sessionID string, code string) error {
+	result, err := d.DB.ExecContext(ctx, `
+		UPDATE thunderdome.user_mfa_recovery_code rc SET used_date = NOW()
+		FROM thunderdome.user_session us
+		WHERE us.session_id = $1 AND us.disabled = true AND NOW() < us.expire_date
+			AND rc.user_id = us.user_id AND rc.code_hash = $2 AND rc.used_date IS NULL;
+		`,
+		sessionID,
+		db.HashString(normalizeRecoveryCode(code)),
+	)
+	if err != nil {
+		return fmt.Errorf("use user recovery code query error: %v", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("INVALID_RECOVERY_CODE")
+	}
+
+	err = d.EnableSession(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("unable to enable user session: %v", err)
+	}
+
+	return nil
+}
+
+// MFASessionUserID gets the user ID of a session still awaiting its second factor
+func (d *Service) MFASessionUserID(ctx context.Context, sessionID string) (string, error) {
+	var userID string
+
+	err := d.DB.QueryRowContext(ctx, `
+		SELECT user_id FROM thunderdome.user_session
+		WHERE session_id = $1 AND disabled = true AND NOW() < expire_date;
+		`,
+		sessionID,
+	).Scan(&userID)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return "", errors.New("SESSION_NOT_FOUND")
+	} else if err != nil {
+		return "", fmt.Errorf("get mfa session user query error: %v", err)
+	}
+
+	return userID, nil
+}
+
+// WebAuthnCredentialList gets the security keys registered by the user
+func (d *Service) WebAuthnCredentialList(ctx context.Context, userID string) ([]*thunderdome.WebAuthnCredential, error) {
+	credentials := make([]*thunderdome.WebAuthnCredential, 0)
+
+	rows, err := d.DB.QueryContext(ctx, `
+		SELECT id, name, credential, created_date, last_used
+		FROM thunderdome.user_webauthn_credential
+		WHERE user_id = $1
+		ORDER BY created_date;
+		`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get user webauthn credentials query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c thunderdome.WebAuthnCredential
+		if err := rows.Scan(
+			&c.ID,
+			&c.Name,
+			&c.Credential,
+			&c.CreatedDate,
+			&c.LastUsed,
+		); err != nil {
+			return nil, fmt.Errorf("get user webauthn credentials scan error: %v", err)
+		}
+		credentials = append(credentials, &c)
+	}
+
+	return credentials, nil
+}
+
+// WebAuthnCredentialCreate stores a newly registered security key and enables MFA for the user
+func (d *Service) WebAuthnCredentialCreate(
+	ctx context.Context, userID string, name string, credentialID []byte, credential []byte,
+) (*thunderdome.WebAuthnCredential, error) {
+	c := &thunderdome.WebAuthnCredential{
+		Name:       name,
+		Credential: credential,
+	}
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO thunderdome.user_webauthn_credential (user_id, name, credential_id, credential)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_date;
+		`,
+		userID,
+		name,
+		credentialID,
+		credential,
+	).Scan(&c.ID, &c.CreatedDate)
+	if err != nil {
+		return nil, fmt.Errorf("create user webauthn credential query error: %v", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE thunderdome.users SET mfa_enabled = true, updated_date = NOW() WHERE id = $1;
+		`,
+		userID,
+	); err != nil {
+		return nil, fmt.Errorf("enable user mfa query error: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return c, nil
+}
+
+// WebAuthnCredentialUse stores the updated state (such as the sign count) of a security key after a login
+func (d *Service) WebAuthnCredentialUse(ctx context.Context, userID string, credentialID []byte, credential []byte) error {
+	if _, err := d.DB.ExecContext(ctx, `
+		UPDATE thunderdome.user_webauthn_credential SET credential = $3, last_used = NOW()
+		WHERE user_id = $1 AND credential_id = $2;
+		`,
+		userID,
+		credentialID,
+		credential,
+	); err != nil {
+		return fmt.Errorf("update user webauthn credential query error: %v", err)
+	}
+
+	return nil
+}
+
+// WebAuthnCredentialDelete removes a security key from the user,
+// disabling MFA when it was the user's last second factor
+func (d *Service) WebAuthnCredentialDelete(ctx context.Context, userID string, id string) error {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM thunderdome.user_webauthn_credential WHERE user_id = $1 AND id = $2;
+		`,
+		userID,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("delete user webauthn credential query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("WEBAUTHN_CREDENTIAL_NOT_FOUND")
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		WITH remaining AS (
+			SELECT EXISTS(SELECT 1 FROM thunderdome.user_mfa WHERE user_id = $1)
+				OR EXISTS(SELECT 1 FROM thunderdome.user_webauthn_credential WHERE user_id = $1) AS has_factor
+		), codes AS (
+			DELETE FROM thunderdome.user_mfa_recovery_code
+			WHERE user_id = $1 AND NOT (SELECT has_factor FROM remaining)
+		)
+		UPDATE thunderdome.users SET mfa_enabled = false, updated_date = NOW()
+		WHERE id = $1 AND NOT (SELECT has_factor FROM remaining);
+		`,
+		userID,
+	); err != nil {
+		return fmt.Errorf("disable user mfa query error: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// WebAuthnChallengeSave stores the pending challenge of a registration or login ceremony,
+// replacing any earlier challenge for the same ceremony
+func (d *Service) WebAuthnChallengeSave(ctx context.Context, userID string, ceremony string, sessionData []byte) error {
+	if _, err := d.DB.ExecContext(ctx, `
+		INSERT INTO thunderdome.user_webauthn_challenge (user_id, ceremony, session_data)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, ceremony) DO UPDATE
+		SET session_data = EXCLUDED.session_data, expire_date = NOW() + INTERVAL '5 minutes';
+		`,
+		userID,
+		ceremony,
+		sessionData,
+	); err != nil {
+		return fmt.Errorf("save user webauthn challenge query error: %v", err)
+	}
+
+	return nil
+}
+
+// WebAuthnChallengeConsume gets and removes the pending challenge of a ceremony so it can only be answered once
+func (d *Service) WebAuthnChallengeConsume(ctx context.Context, userID string, ceremony string) ([]byte, error) {
+	var sessionData []byte
+
+	err := d.DB.QueryRowContext(ctx, `
+		DELETE FROM thunderdome.user_webauthn_challenge
+		WHERE user_id = $1 AND ceremony = $2
+		RETURNING CASE WHEN NOW() < expire_date THEN session_data END;
+		`,
+		userID,
+		ceremony,
+	).Scan(&sessionData)
+	if (err != nil && errors.Is(err, sql.ErrNoRows)) || (err == nil && sessionData == nil) {
+		return nil, errors.New("WEBAUTHN_CHALLENGE_NOT_FOUND")
+	} else if err != nil {
+		return nil, fmt.Errorf("consume user webauthn challenge query error: %v", err)
+	}
+
+	return sessionData, nil
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateRecoveryCode(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			t.Fatalf("generateRecoveryCode() error = %v", err)
+		}
+		if len(code) != recoveryCodeLength+1 || code[recoveryCodeLength/2] != '-' {
+			t.Fatalf("generateRecoveryCode() = %q, want xxxxx-xxxxx", code)
+		}
+		for _, r := range strings.ReplaceAll(code, "-", "") {
+			if !strings.ContainsRune(recoveryCodeAlphabet, r) {
+				t.Fatalf("generateRecoveryCode() = %q, contains %q outside the alphabet", code, r)
+			}
+		}
+		if seen[code] {
+			t.Fatalf("generateRecoveryCode() repeated %q", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{name: "formatted", code: "abcde-fghjk", want: "abcdefghjk"},
+		{name: "uppercase", code: "ABCDE-FGHJK", want: "abcdefghjk"},
+		{name: "spaces", code: " abcde fghjk ", want: "abcdefghjk"},
+		{name: "unformatted", code: "abcdefghjk", want: "abcdefghjk"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeRecoveryCode(tt.code); got != tt.want {
+				t.Errorf("normalizeRecoveryCode(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
@@ -124,7 +124,8 @@ func (d *Service) OauthAuthUser(ctx context.Context, provider string, sub string
 	return &user, sessionID, nil
 }
 
-// OauthUpsertUser checks if an existing user with the same email exists, if not it creates a new user
+// OauthUpsertUser checks if an existing user with the same email exists, if not it creates a new user.
+// An existing user is only linked to the provider identity when the provider has verified the email
 func (d *Service) OauthUpsertUser(ctx context.Context, provider string, sub string, email string, emailVerified bool, name string, pictureUrl string) (*thunderdome.User, string, error) {
 	var user thunderdome.User
 
@@ -153,25 +154,32 @@ func (d *Service) OauthUpsertUser(ctx context.Context, provider string, sub stri
 			return nil, "", txErr
 		}
 
-		// Check if user with the same email exists
-		existingUserErr := d.DB.QueryRowContext(ctx,
-			`SELECT u.id, u.name, u.email, u.type, u.notifications_enabled,
+		// Check if user with the same email exists, e.g. from another provider
+		existingUserErr := tx.QueryRowContext(ctx,
+			`SELECT u.id, u.name, u.email, u.type, u.verified, u.notifications_enabled,
  				 COALESCE(u.locale, ''), u.disabled, u.theme, COALESCE(u.picture, '')
  				 FROM thunderdome.users u
- 				 WHERE u.email = $1;`,
+ 				 WHERE lower(u.email) = lower($1);`,
 			email,
 		).Scan(
 			&user.ID,
 			&user.Name,
 			&user.Email,
 			&user.Type,
+			&user.Verified,
 			&user.NotificationsEnabled,
 			&user.Locale,
 			&user.Disabled,
 			&user.Theme,
 			&user.Picture,
 		)
-		if existingUserErr != nil && errors.Is(existingUserErr, sql.ErrNoRows) {
+		if existingUserErr == nil && !emailVerified {
+			// only link an existing account when the provider has verified the user owns the email
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				return nil, "", fmt.Errorf("upsert user failed: EMAIL_NOT_VERIFIED, unable to rollback: %v", rollbackErr)
+			}
+			return nil, "", errors.New("EMAIL_NOT_VERIFIED")
+		} else if existingUserErr != nil && errors.Is(existingUserErr, sql.ErrNoRows) {
 			// Create a new user if no user with the same email exists
 			userInsertErr := tx.QueryRowContext(ctx,
 				`INSERT INTO thunderdome.users (name, email, type, verified, picture)
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
@@ -105,3 +106,99 @@ func (d *Service) DeleteSession(ctx context.Context, sessionID string) error {
 
 	return nil
 }
+
+// TouchSession records the last seen time, IP address and user agent of a user authenticated session,
+// the last seen time is only written once a minute unless the client changed
+func (d *Service) TouchSession(ctx context.Context, sessionID string, ipAddress string, userAgent string) error {
+	if _, sessionErr := d.DB.ExecContext(ctx, `
+		UPDATE thunderdome.user_session
+		SET last_seen = NOW(), ip_address = LEFT($2, 64), user_agent = LEFT($3, 512)
+		WHERE session_id = $1 AND (
+			last_seen < NOW() - INTERVAL '1 minute'
+			OR ip_address <> LEFT($2, 64)
+			OR user_agent <> LEFT($3, 512)
+		);
+		`,
+		sessionID,
+		ipAddress,
+		userAgent,
+	); sessionErr != nil {
+		return fmt.Errorf("touch user session query error: %v", sessionErr)
+	}
+
+	return nil
+}
+
+// GetUserSessions gets the active authenticated sessions of a user, marking the current session
+func (d *Service) GetUserSessions(ctx context.Context, userID string, currentSessionID string) ([]*thunderdome.UserSession, error) {
+	sessions := make([]*thunderdome.UserSession, 0)
+
+	rows, err := d.DB.QueryContext(ctx, `
+		SELECT id, ip_address, user_agent, session_id = $2, created_date, last_seen, expire_date
+		FROM thunderdome.user_session
+		WHERE user_id = $1 AND disabled = false AND NOW() < expire_date
+		ORDER BY last_seen DESC;
+		`,
+		userID,
+		currentSessionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get user sessions query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s thunderdome.UserSession
+		if err := rows.Scan(
+			&s.ID,
+			&s.IPAddress,
+			&s.UserAgent,
+			&s.Current,
+			&s.CreatedDate,
+			&s.LastSeen,
+			&s.ExpireDate,
+		); err != nil {
+			return nil, fmt.Errorf("get user sessions scan error: %v", err)
+		}
+		sessions = append(sessions, &s)
+	}
+
+	return sessions, nil
+}
+
+// DeleteUserSession deletes an authenticated session of a user by its ID
+func (d *Service) DeleteUserSession(ctx context.Context, userID string, id string) error {
+	result, err := d.DB.ExecContext(ctx, `
+		DELETE FROM thunderdome.user_session WHERE user_id = $1 AND id = $2;
+		`,
+		userID,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("delete user session query error: %v", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("SESSION_NOT_FOUND")
+	}
+
+	return nil
+}
+
+// DeleteUserSessions deletes all authenticated sessions of a user other than the excepted session,
+// returning the number of sessions deleted
+func (d *Service) DeleteUserSessions(ctx context.Context, userID string, exceptSessionID string) (int, error) {
+	result, err := d.DB.ExecContext(ctx, `
+		DELETE FROM thunderdome.user_session WHERE user_id = $1 AND session_id <> $2;
+		`,
+		userID,
+		exceptSessionID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("delete user sessions query error: %v", err)
+	}
+
+	rows, _ := result.RowsAffected()
+
+	return int(rows), nil
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const (
+	testUserID      = "6d2c1f8e-0a4b-4c1e-9d7f-2b3a4c5d6e7f"
+	testOtherUserID = "1f2e3d4c-5b6a-4978-8a9b-0c1d2e3f4a5b"
+	testSessionRow  = "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"
+)
+
+func newTestService(t *testing.T) (*Service, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return &Service{
+		DB:     db,
+		Logger: otelzap.New(zap.NewNop()),
+	}, mock
+}
+
+func TestGetUserSessions(t *testing.T) {
+	s, mock := newTestService(t)
+	now := time.Now()
+	mock.ExpectQuery(`FROM thunderdome.user_session\s+WHERE user_id = \$1 AND disabled = false`).
+		WithArgs(testUserID, "current-session").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "ip_address", "user_agent", "current", "created_date", "last_seen", "expire_date",
+		}).
+			AddRow(testSessionRow, "203.0.113.7", "Firefox", true, now, now, now.Add(time.Hour)).
+			AddRow("b2c3d4e5-f6a7-4b8c-9d0e-1f2a3b4c5d6e", "198.51.100.2", "Safari", false, now, now, now.Add(time.Hour)))
+
+	sessions, err := s.GetUserSessions(context.Background(), testUserID, "current-session")
+	if err != nil {
+		t.Fatalf("GetUserSessions() error = %v", err)
+	}
+	if len(sessions) != 2 || !sessions[0].Current || sessions[1].Current {
+		t.Fatalf("GetUserSessions() = %+v, want two sessions with the first current", sessions)
+	}
+	if sessions[0].IPAddress != "203.0.113.7" || sessions[0].UserAgent != "Firefox" {
+		t.Errorf("GetUserSessions()[0] client = %s %s, want 203.0.113.7 Firefox", sessions[0].IPAddress, sessions[0].UserAgent)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDeleteUserSession(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		rowsAffected int64
+		wantErr      string
+	}{
+		{name: "own session", userID: testUserID, rowsAffected: 1},
+		{name: "another user's session", userID: testOtherUserID, rowsAffected: 0, wantErr: "SESSION_NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newTestService(t)
+			// the session is only deleted when it belongs to the user
+			mock.ExpectExec(`DELETE FROM thunderdome.user_session WHERE user_id = \$1 AND id = \$2`).
+				WithArgs(tt.userID, testSessionRow).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			err := s.DeleteUserSession(context.Background(), tt.userID, testSessionRow)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("DeleteUserSession() error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("DeleteUserSession() error = %v, want %s", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestDeleteUserSessions(t *testing.T) {
+	s, mock := newTestService(t)
+	mock.ExpectExec(`DELETE FROM thunderdome.user_session WHERE user_id = \$1 AND session_id <> \$2`).
+		WithArgs(testUserID, "current-session").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	revoked, err := s.DeleteUserSessions(context.Background(), testUserID, "current-session")
+	if err != nil {
+		t.Fatalf("DeleteUserSessions() error = %v", err)
+	}
+	if revoked != 3 {
+		t.Errorf("DeleteUserSessions() = %d, want 3", revoked)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,209 @@
+// Package broadcast provides a Postgres LISTEN/NOTIFY backend for the websocket hubs
+// so that broadcasts reach room members connected to any application instance
+package broadcast
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const (
+	// notifyChannel is the Postgres channel all hub broadcasts are published on
+	notifyChannel = "thunderdome_wshub"
+	// maxNotifyPayload keeps notifications under the Postgres 8000 byte payload limit,
+	// larger messages are stored in the wshub_broadcast table and referenced by ID
+	maxNotifyPayload = 7900
+	// storedMessageTTL is how long stored messages are kept for listeners to read
+	storedMessageTTL = time.Minute
+	// reconnectDelay is how long to wait before re-establishing a lost listen connection
+	reconnectDelay = 5 * time.Second
+)
+
+type envelope struct {
+	Instance string `json:"instance"`
+	Channel  string `json:"channel"`
+	Room     string `json:"room,omitempty"`
+	UserID   string `json:"userId,omitempty"`
+	Data     string `json:"data,omitempty"`
+	StoredID string `json:"storedId,omitempty"`
+}
+
+// Service is a Postgres LISTEN/NOTIFY implementation of wshub.Backend
+type Service struct {
+	DB         *sql.DB
+	Logger     *otelzap.Logger
+	ConnString string
+
+	instanceID  string
+	mu          sync.RWMutex
+	subscribers map[string][]func(wshub.Message)
+}
+
+// New returns a new Postgres broadcast backend, Start must be called to receive messages from other instances
+func New(db *sql.DB, logger *otelzap.Logger, connString string) *Service {
+	return &Service{
+		DB:          db,
+		Logger:      logger,
+		ConnString:  connString,
+		instanceID:  uuid.NewString(),
+		subscribers: make(map[string][]func(wshub.Message)),
+	}
+}
+
+// Start listens for broadcasts from other instances until the context is cancelled
+func (s *Service) Start(ctx context.Context) {
+	go s.listen(ctx)
+	go s.cleanup(ctx)
+}
+
+// Publish sends the message to the hubs subscribed to the channel on other instances
+func (s *Service) Publish(ctx context.Context, channel string, msg wshub.Message) error {
+	payload, err := json.Marshal(envelope{
+		Instance: s.instanceID,
+		Channel:  channel,
+		Room:     msg.Room,
+		UserID:   msg.UserID,
+		Data:     string(msg.Data),
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding broadcast message: %v", err)
+	}
+
+	if len(payload) > maxNotifyPayload {
+		var storedID string
+		if err := s.DB.QueryRowContext(ctx,
+			`INSERT INTO thunderdome.wshub_broadcast (payload) VALUES ($1) RETURNING id;`,
+			string(payload),
+		).Scan(&storedID); err != nil {
+			return fmt.Errorf("error storing broadcast message: %v", err)
+		}
+
+		payload, err = json.Marshal(envelope{
+			Instance: s.instanceID,
+			Channel:  channel,
+			StoredID: storedID,
+		})
+		if err != nil {
+			return fmt.Errorf("error encoding broadcast message reference: %v", err)
+		}
+	}
+
+	if _, err := s.DB.ExecContext(ctx, `SELECT pg_notify($1, $2);`, notifyChannel, string(payload)); err != nil {
+		return fmt.Errorf("error publishing broadcast message: %v", err)
+	}
+
+	return nil
+}
+
+// Subscribe registers the deliver func to receive messages published to the channel by other instances
+func (s *Service) Subscribe(channel string, deliver func(wshub.Message)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscribers[channel] = append(s.subscribers[channel], deliver)
+}
+
+// listen keeps a dedicated connection listening for notifications, reconnecting when it is lost
+func (s *Service) listen(ctx context.Context) {
+	for {
+		if err := s.listenConn(ctx); err != nil && ctx.Err() == nil {
+			s.Logger.Ctx(ctx).Error("broadcast listen error, reconnecting", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (s *Service) listenConn(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, s.ConnString)
+	if err != nil {
+		return fmt.Errorf("error connecting broadcast listener: %v", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		return fmt.Errorf("error listening for broadcasts: %v", err)
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("error waiting for broadcast: %v", err)
+		}
+
+		s.handleNotification(ctx, notification.Payload)
+	}
+}
+
+func (s *Service) handleNotification(ctx context.Context, payload string) {
+	var env envelope
+	if err := json.Unmarshal([]byte(payload), &env); err != nil {
+		s.Logger.Ctx(ctx).Error("broadcast notification decode error", zap.Error(err))
+		return
+	}
+
+	// the publishing hub has already delivered to its own connections
+	if env.Instance == s.instanceID {
+		return
+	}
+
+	if env.StoredID != "" {
+		var stored string
+		if err := s.DB.QueryRowContext(ctx,
+			`SELECT payload FROM thunderdome.wshub_broadcast WHERE id = $1;`,
+			env.StoredID,
+		).Scan(&stored); err != nil {
+			s.Logger.Ctx(ctx).Error("broadcast stored message query error", zap.Error(err),
+				zap.String("stored_id", env.StoredID))
+			return
+		}
+		if err := json.Unmarshal([]byte(stored), &env); err != nil {
+			s.Logger.Ctx(ctx).Error("broadcast stored message decode error", zap.Error(err),
+				zap.String("stored_id", env.StoredID))
+			return
+		}
+	}
+
+	s.mu.RLock()
+	subscribers := s.subscribers[env.Channel]
+	s.mu.RUnlock()
+
+	msg := wshub.Message{Room: env.Room, UserID: env.UserID, Data: []byte(env.Data)}
+	for _, deliver := range subscribers {
+		deliver(msg)
+	}
+}
+
+// cleanup periodically removes stored messages every listener has had the chance to read
+func (s *Service) cleanup(ctx context.Context) {
+	ticker := time.NewTicker(storedMessageTTL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.DB.ExecContext(ctx,
+				`DELETE FROM thunderdome.wshub_broadcast WHERE created_at < NOW() - make_interval(secs => $1);`,
+				storedMessageTTL.Seconds(),
+			); err != nil {
+				s.Logger.Ctx(ctx).Error("broadcast stored message cleanup error", zap.Error(err))
+			}
+		}
+	}
+}
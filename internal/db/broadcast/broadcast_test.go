@@ -0,0 +1,94 @@
+package broadcast
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// capturePayload matches any argument, keeping the notification payload published
+type capturePayload struct {
+	payload *string
+}
+
+func (c capturePayload) Match(v driver.Value) bool {
+	*c.payload, _ = v.(string)
+	return true
+}
+
+func newTestService(t *testing.T) (*Service, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return New(db, otelzap.New(zap.NewNop()), ""), mock
+}
+
+func TestPublishKeepsMessageUser(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "notification payload", data: `{"type":"jira_estimate_push_failed"}`},
+		{name: "stored payload", data: strings.Repeat("x", maxNotifyPayload)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher, publisherMock := newTestService(t)
+			listener, listenerMock := newTestService(t)
+
+			var stored, payload string
+			if len(tt.data) >= maxNotifyPayload {
+				publisherMock.ExpectQuery(`INSERT INTO thunderdome.wshub_broadcast`).
+					WithArgs(capturePayload{payload: &stored}).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("stored-1"))
+			}
+			publisherMock.ExpectExec(`SELECT pg_notify`).
+				WithArgs(notifyChannel, capturePayload{payload: &payload}).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			err := publisher.Publish(context.Background(), "poker", wshub.Message{
+				Room:   "room-1",
+				UserID: "user-1",
+				Data:   []byte(tt.data),
+			})
+			if err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+			if stored != "" {
+				listenerMock.ExpectQuery(`SELECT payload FROM thunderdome.wshub_broadcast`).
+					WithArgs("stored-1").
+					WillReturnRows(sqlmock.NewRows([]string{"payload"}).AddRow(stored))
+			}
+
+			var delivered []wshub.Message
+			listener.Subscribe("poker", func(msg wshub.Message) {
+				delivered = append(delivered, msg)
+			})
+			listener.handleNotification(context.Background(), payload)
+
+			if len(delivered) != 1 {
+				t.Fatalf("delivered %d messages, want 1", len(delivered))
+			}
+			if delivered[0].UserID != "user-1" || delivered[0].Room != "room-1" || string(delivered[0].Data) != tt.data {
+				t.Errorf("delivered message room %q user %q, want room-1 user-1", delivered[0].Room, delivered[0].UserID)
+			}
+			if err := publisherMock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+			if err := listenerMock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"database/sql"
 	"embed"
-	"fmt"
 	"time"
 
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
@@ -41,17 +40,7 @@ func New(adminEmail string, config *Config, logger *otelzap.Logger, instantiate
 		Logger:              logger,
 	}
 
-	psqlInfo := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		d.Config.Host,
-		d.Config.Port,
-		d.Config.User,
-		d.Config.Password,
-		d.Config.Name,
-		d.Config.SSLMode,
-	)
-
-	pdb := waitForDB(ctx, logger, psqlInfo)
+	pdb := waitForDB(ctx, logger, d.Config.ConnString())
 
 	d.DB = pdb
 	d.DB.SetMaxOpenConns(d.Config.MaxOpenConns)
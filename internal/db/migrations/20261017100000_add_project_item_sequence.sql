@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Per project counter used to number items (e.g. PROJ-42)
+ALTER TABLE thunderdome.project ADD COLUMN item_sequence INTEGER NOT NULL DEFAULT 0;
+
+-- Items without a project can't be reached through any project so they're removed before project_id is required,
+-- items elsewhere that have one of them as their parent are detached first
+UPDATE thunderdome.project_item SET parent_id = NULL
+    WHERE parent_id IN (SELECT id FROM thunderdome.project_item WHERE project_id IS NULL);
+DELETE FROM thunderdome.project_item WHERE project_id IS NULL;
+
+-- Items should go away with their project, child items are detached when their parent is removed
+ALTER TABLE thunderdome.project_item DROP CONSTRAINT IF EXISTS project_item_project_id_fkey;
+ALTER TABLE thunderdome.project_item
+    ADD CONSTRAINT project_item_project_id_fkey FOREIGN KEY (project_id)
+    REFERENCES thunderdome.project(id) ON DELETE CASCADE;
+ALTER TABLE thunderdome.project_item ALTER COLUMN project_id SET NOT NULL;
+ALTER TABLE thunderdome.project_item DROP CONSTRAINT IF EXISTS project_item_parent_id_fkey;
+ALTER TABLE thunderdome.project_item
+    ADD CONSTRAINT project_item_parent_id_fkey FOREIGN KEY (parent_id)
+    REFERENCES thunderdome.project_item(id) ON DELETE SET NULL;
+ALTER TABLE thunderdome.project_item ALTER COLUMN rank TYPE VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_project_item_parent_id ON thunderdome.project_item(parent_id);
+CREATE INDEX IF NOT EXISTS idx_project_item_status_id ON thunderdome.project_item(status_id);
+
+-- Bring existing counters in line with any items created before the sequence existed
+UPDATE thunderdome.project p SET item_sequence = COALESCE((
+    SELECT MAX(NULLIF(SPLIT_PART(pi.item_key, '-', 2), '')::INTEGER)
+    FROM thunderdome.project_item pi
+    WHERE pi.project_id = p.id AND SPLIT_PART(pi.item_key, '-', 2) ~ '^[0-9]+$'
+), 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS thunderdome.idx_project_item_status_id;
+DROP INDEX IF EXISTS thunderdome.idx_project_item_parent_id;
+ALTER TABLE thunderdome.project_item ALTER COLUMN rank TYPE VARCHAR(16);
+ALTER TABLE thunderdome.project_item DROP CONSTRAINT IF EXISTS project_item_parent_id_fkey;
+ALTER TABLE thunderdome.project_item
+    ADD CONSTRAINT project_item_parent_id_fkey FOREIGN KEY (parent_id)
+    REFERENCES thunderdome.project_item(id);
+ALTER TABLE thunderdome.project_item ALTER COLUMN project_id DROP NOT NULL;
+ALTER TABLE thunderdome.project_item DROP CONSTRAINT IF EXISTS project_item_project_id_fkey;
+ALTER TABLE thunderdome.project_item
+    ADD CONSTRAINT project_item_project_id_fkey FOREIGN KEY (project_id)
+    REFERENCES thunderdome.project(id);
+ALTER TABLE thunderdome.project DROP COLUMN IF EXISTS item_sequence;
+-- +goose StatementEnd
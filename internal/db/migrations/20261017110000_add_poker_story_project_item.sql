@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker_story ADD COLUMN project_item_id UUID
+    REFERENCES thunderdome.project_item(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_poker_story_project_item_id ON thunderdome.poker_story(project_item_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS thunderdome.idx_poker_story_project_item_id;
+ALTER TABLE thunderdome.poker_story DROP COLUMN IF EXISTS project_item_id;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Websocket broadcasts too large for a NOTIFY payload, read by other instances then cleaned up
+CREATE UNLOGGED TABLE thunderdome.wshub_broadcast (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payload TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_wshub_broadcast_created_at ON thunderdome.wshub_broadcast(created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS thunderdome.wshub_broadcast;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.poker ADD COLUMN voting_time_limit_sec INT NOT NULL DEFAULT 0;
+ALTER TABLE thunderdome.poker_settings ADD COLUMN voting_time_limit_sec INT NOT NULL DEFAULT 0;
+ALTER TABLE thunderdome.poker_story ADD COLUMN vote_deadline TIMESTAMPTZ;
+ALTER TABLE thunderdome.poker_story ADD COLUMN voteend_reason VARCHAR(16); -- Possible values: 'timeout', 'all_voted', 'manual'
+CREATE INDEX IF NOT EXISTS idx_poker_story_vote_deadline ON thunderdome.poker_story(vote_deadline)
+    WHERE vote_deadline IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS thunderdome.idx_poker_story_vote_deadline;
+ALTER TABLE thunderdome.poker_story DROP COLUMN IF EXISTS voteend_reason;
+ALTER TABLE thunderdome.poker_story DROP COLUMN IF EXISTS vote_deadline;
+ALTER TABLE thunderdome.poker_settings DROP COLUMN IF EXISTS voting_time_limit_sec;
+ALTER TABLE thunderdome.poker DROP COLUMN IF EXISTS voting_time_limit_sec;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS thunderdome.poker_story_vote_round (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    story_id UUID NOT NULL REFERENCES thunderdome.poker_story(id) ON DELETE CASCADE,
+    round_number INT NOT NULL,
+    votes JSONB NOT NULL DEFAULT '[]'::jsonb,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ended_by UUID REFERENCES thunderdome.users(id) ON DELETE SET NULL,
+    end_reason VARCHAR(16) NOT NULL, -- Possible values: 'timeout', 'all_voted', 'manual', 'revote', 'switched', 'skipped', 'finalized'
+    UNIQUE (story_id, round_number)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS thunderdome.poker_story_vote_round;
+-- +goose StatementEnd
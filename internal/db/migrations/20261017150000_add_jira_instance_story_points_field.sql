@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.jira_instance ADD COLUMN story_points_field VARCHAR(128) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.jira_instance DROP COLUMN IF EXISTS story_points_field;
+-- +goose StatementEnd
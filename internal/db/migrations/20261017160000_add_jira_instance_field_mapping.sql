@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.jira_instance
+    ADD COLUMN acceptance_criteria_field VARCHAR(128) NOT NULL DEFAULT '',
+    ADD COLUMN link_template VARCHAR(512) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.jira_instance
+    DROP COLUMN IF EXISTS acceptance_criteria_field,
+    DROP COLUMN IF EXISTS link_template;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.api_key
+    ADD COLUMN scopes TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN expires_at TIMESTAMPTZ,
+    ADD COLUMN team_id UUID REFERENCES thunderdome.team(id) ON DELETE CASCADE,
+    ADD COLUMN organization_id UUID REFERENCES thunderdome.organization(id) ON DELETE CASCADE,
+    ADD COLUMN last_used TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.api_key
+    DROP COLUMN IF EXISTS scopes,
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS team_id,
+    DROP COLUMN IF EXISTS organization_id,
+    DROP COLUMN IF EXISTS last_used;
+-- +goose StatementEnd
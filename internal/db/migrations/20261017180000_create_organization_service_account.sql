@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.organization_service_account (
+    user_id UUID PRIMARY KEY REFERENCES thunderdome.users(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES thunderdome.organization(id) ON DELETE CASCADE,
+    description VARCHAR(256) NOT NULL DEFAULT '',
+    created_by UUID REFERENCES thunderdome.users(id) ON DELETE SET NULL,
+    created_date TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_date TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX organization_service_account_organization_id_idx
+    ON thunderdome.organization_service_account (organization_id);
+
+-- service account users only exist for their organization, remove the user along with the service account
+CREATE OR REPLACE FUNCTION thunderdome.organization_service_account_delete_user() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM thunderdome.users WHERE id = OLD.user_id AND type = 'SERVICE';
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER organization_service_account_delete_user
+    AFTER DELETE ON thunderdome.organization_service_account
+    FOR EACH ROW EXECUTE FUNCTION thunderdome.organization_service_account_delete_user();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS organization_service_account_delete_user ON thunderdome.organization_service_account;
+DROP FUNCTION IF EXISTS thunderdome.organization_service_account_delete_user();
+DROP TABLE IF EXISTS thunderdome.organization_service_account;
+DELETE FROM thunderdome.users WHERE type = 'SERVICE';
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.audit_event (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    action VARCHAR(64) NOT NULL,
+    actor_id UUID,
+    actor_type VARCHAR(128) NOT NULL DEFAULT '',
+    target_type VARCHAR(64) NOT NULL DEFAULT '',
+    target_id VARCHAR(256) NOT NULL DEFAULT '',
+    organization_id UUID,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    created_date TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX audit_event_created_date_idx ON thunderdome.audit_event (created_date DESC);
+CREATE INDEX audit_event_organization_id_idx ON thunderdome.audit_event (organization_id, created_date DESC);
+CREATE INDEX audit_event_actor_id_idx ON thunderdome.audit_event (actor_id);
+CREATE INDEX audit_event_target_id_idx ON thunderdome.audit_event (target_id);
+
+-- audit events are append only, actor and organization ids are intentionally not foreign keys
+-- so the history outlives the users and organizations it refers to
+CREATE OR REPLACE FUNCTION thunderdome.audit_event_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit events are append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_event_append_only
+    BEFORE UPDATE OR DELETE ON thunderdome.audit_event
+    FOR EACH ROW EXECUTE FUNCTION thunderdome.audit_event_append_only();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS audit_event_append_only ON thunderdome.audit_event;
+DROP FUNCTION IF EXISTS thunderdome.audit_event_append_only();
+DROP TABLE IF EXISTS thunderdome.audit_event;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.user_session
+    ADD COLUMN id UUID NOT NULL DEFAULT gen_random_uuid(),
+    ADD COLUMN last_seen TIMESTAMPTZ,
+    ADD COLUMN ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN user_agent VARCHAR(512) NOT NULL DEFAULT '';
+UPDATE thunderdome.user_session SET last_seen = created_date;
+ALTER TABLE thunderdome.user_session
+    ALTER COLUMN last_seen SET DEFAULT NOW(),
+    ALTER COLUMN last_seen SET NOT NULL;
+CREATE UNIQUE INDEX user_session_id_idx ON thunderdome.user_session (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS thunderdome.user_session_id_idx;
+ALTER TABLE thunderdome.user_session
+    DROP COLUMN IF EXISTS id,
+    DROP COLUMN IF EXISTS last_seen,
+    DROP COLUMN IF EXISTS ip_address,
+    DROP COLUMN IF EXISTS user_agent;
+-- +goose StatementEnd
@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.user_mfa_recovery_code (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES thunderdome.users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(128) NOT NULL,
+    used_date TIMESTAMPTZ,
+    created_date TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX user_mfa_recovery_code_user_id_idx ON thunderdome.user_mfa_recovery_code (user_id);
+
+CREATE TABLE thunderdome.user_webauthn_credential (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES thunderdome.users(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL DEFAULT '',
+    credential_id BYTEA NOT NULL UNIQUE,
+    credential JSONB NOT NULL,
+    last_used TIMESTAMPTZ,
+    created_date TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX user_webauthn_credential_user_id_idx ON thunderdome.user_webauthn_credential (user_id);
+
+CREATE TABLE thunderdome.user_webauthn_challenge (
+    user_id UUID NOT NULL REFERENCES thunderdome.users(id) ON DELETE CASCADE,
+    ceremony VARCHAR(16) NOT NULL,
+    session_data JSONB NOT NULL,
+    expire_date TIMESTAMPTZ NOT NULL DEFAULT NOW() + INTERVAL '5 minutes',
+    PRIMARY KEY (user_id, ceremony)
+);
+
+CREATE OR REPLACE PROCEDURE thunderdome.user_mfa_remove(IN userid uuid)
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    DELETE FROM thunderdome.user_mfa WHERE user_id = userId;
+    DELETE FROM thunderdome.user_webauthn_credential WHERE user_id = userId;
+    DELETE FROM thunderdome.user_webauthn_challenge WHERE user_id = userId;
+    DELETE FROM thunderdome.user_mfa_recovery_code WHERE user_id = userId;
+    UPDATE thunderdome.users SET mfa_enabled = false, updated_date = NOW() WHERE id = userId;
+
+    COMMIT;
+END;
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE PROCEDURE thunderdome.user_mfa_remove(IN userid uuid)
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    DELETE FROM thunderdome.user_mfa WHERE user_id = userId;
+    UPDATE thunderdome.users SET mfa_enabled = false, updated_date = NOW() WHERE id = userId;
+
+    COMMIT;
+END;
+$$;
+DROP TABLE IF EXISTS thunderdome.user_webauthn_challenge;
+DROP TABLE IF EXISTS thunderdome.user_webauthn_credential;
+DROP TABLE IF EXISTS thunderdome.user_mfa_recovery_code;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE UNLOGGED TABLE thunderdome.rate_limit (
+    key VARCHAR(320) PRIMARY KEY,
+    hits INTEGER NOT NULL DEFAULT 1,
+    window_end TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX rate_limit_window_end_idx ON thunderdome.rate_limit (window_end);
+
+ALTER TABLE thunderdome.auth_credential
+    ADD COLUMN failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN last_failed_login TIMESTAMPTZ,
+    ADD COLUMN locked_until TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.auth_credential
+    DROP COLUMN IF EXISTS failed_login_attempts,
+    DROP COLUMN IF EXISTS last_failed_login,
+    DROP COLUMN IF EXISTS locked_until;
+DROP TABLE IF EXISTS thunderdome.rate_limit;
+-- +goose StatementEnd
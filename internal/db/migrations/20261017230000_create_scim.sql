@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE thunderdome.organization_scim_token (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES thunderdome.organization(id) ON DELETE CASCADE,
+    name VARCHAR(256) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_by UUID REFERENCES thunderdome.users(id) ON DELETE SET NULL,
+    last_used TIMESTAMPTZ,
+    created_date TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX organization_scim_token_organization_id_idx
+    ON thunderdome.organization_scim_token (organization_id);
+
+CREATE TABLE thunderdome.scim_user (
+    organization_id UUID NOT NULL REFERENCES thunderdome.organization(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES thunderdome.users(id) ON DELETE CASCADE,
+    external_id VARCHAR(256) NOT NULL DEFAULT '',
+    created_date TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_date TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id)
+);
+CREATE INDEX scim_user_user_id_idx ON thunderdome.scim_user (user_id);
+
+CREATE TABLE thunderdome.scim_group (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES thunderdome.organization(id) ON DELETE CASCADE,
+    display_name VARCHAR(256) NOT NULL,
+    external_id VARCHAR(256) NOT NULL DEFAULT '',
+    team_id UUID NOT NULL REFERENCES thunderdome.team(id) ON DELETE CASCADE,
+    department_id UUID REFERENCES thunderdome.organization_department(id) ON DELETE CASCADE,
+    created_date TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_date TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (organization_id, display_name),
+    UNIQUE (team_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS thunderdome.scim_group;
+DROP TABLE IF EXISTS thunderdome.scim_user;
+DROP TABLE IF EXISTS thunderdome.organization_scim_token;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.retro_action
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'open',
+    ADD COLUMN due_date DATE;
+ALTER TABLE thunderdome.retro_action
+    ADD CONSTRAINT retro_action_status_check CHECK (status IN ('open', 'in_progress', 'done', 'dropped'));
+UPDATE thunderdome.retro_action SET status = 'done' WHERE completed = true;
+
+CREATE TABLE thunderdome.retro_action_review (
+    retro_id UUID NOT NULL REFERENCES thunderdome.retro(id) ON DELETE CASCADE,
+    action_id UUID NOT NULL REFERENCES thunderdome.retro_action(id) ON DELETE CASCADE,
+    decision VARCHAR(16) NOT NULL CHECK (decision IN ('done', 'kept', 'dropped')),
+    created_date TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (retro_id, action_id)
+);
+CREATE INDEX retro_action_review_action_id_idx ON thunderdome.retro_action_review (action_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS thunderdome.retro_action_review;
+ALTER TABLE thunderdome.retro_action DROP CONSTRAINT IF EXISTS retro_action_status_check;
+ALTER TABLE thunderdome.retro_action DROP COLUMN IF EXISTS due_date;
+ALTER TABLE thunderdome.retro_action DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.retro ADD COLUMN anonymity VARCHAR(16) NOT NULL DEFAULT 'none';
+ALTER TABLE thunderdome.retro
+    ADD CONSTRAINT retro_anonymity_check CHECK (anonymity IN ('none', 'anonymous', 'untracked'));
+ALTER TABLE thunderdome.retro_item
+    ADD COLUMN author_tag VARCHAR(64),
+    ADD COLUMN author_secret_hash VARCHAR(64);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.retro_item DROP COLUMN IF EXISTS author_secret_hash;
+ALTER TABLE thunderdome.retro_item DROP COLUMN IF EXISTS author_tag;
+ALTER TABLE thunderdome.retro DROP CONSTRAINT IF EXISTS retro_anonymity_check;
+ALTER TABLE thunderdome.retro DROP COLUMN IF EXISTS anonymity;
+-- +goose StatementEnd
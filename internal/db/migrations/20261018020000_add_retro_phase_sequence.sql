@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.retro ADD COLUMN phases JSONB NOT NULL DEFAULT '[]'::jsonb;
+UPDATE thunderdome.retro r SET phases = (
+    SELECT jsonb_agg(jsonb_build_object(
+        'name', p.name,
+        'timeLimitMin', CASE WHEN p.name = 'brainstorm' THEN r.phase_time_limit_min ELSE 0 END
+    ) ORDER BY p.ord)
+    FROM (VALUES ('review', 1), ('intro', 2), ('brainstorm', 3), ('group', 4), ('vote', 5), ('action', 6), ('completed', 7)) AS p(name, ord)
+    WHERE p.name <> 'review' OR r.phase = 'review'
+        OR EXISTS (SELECT 1 FROM thunderdome.retro_action_review rar WHERE rar.retro_id = r.id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.retro DROP COLUMN IF EXISTS phases;
+-- +goose StatementEnd
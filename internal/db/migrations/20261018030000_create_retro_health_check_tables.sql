@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS thunderdome.retro_health_check (
+    retro_id uuid NOT NULL REFERENCES thunderdome.retro(id) ON DELETE CASCADE,
+    voter_hash character varying(64) NOT NULL,
+    dimension character varying(64) NOT NULL,
+    score smallint NOT NULL CHECK (score BETWEEN 1 AND 5),
+    created_date timestamp with time zone DEFAULT now() NOT NULL,
+    updated_date timestamp with time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (retro_id, voter_hash, dimension)
+);
+
+CREATE TABLE IF NOT EXISTS thunderdome.team_health_check (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    team_id uuid NOT NULL REFERENCES thunderdome.team(id) ON DELETE CASCADE,
+    retro_id uuid REFERENCES thunderdome.retro(id) ON DELETE SET NULL,
+    retro_name character varying(256) NOT NULL,
+    dimension character varying(64) NOT NULL,
+    average_score numeric(3,2) NOT NULL,
+    response_count integer NOT NULL,
+    created_date timestamp with time zone DEFAULT now() NOT NULL,
+    updated_date timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS team_health_check_team_id_idx ON thunderdome.team_health_check USING btree (team_id, created_date);
+CREATE UNIQUE INDEX IF NOT EXISTS team_health_check_retro_dimension_idx
+    ON thunderdome.team_health_check USING btree (retro_id, dimension);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS thunderdome.team_health_check;
+DROP TABLE IF EXISTS thunderdome.retro_health_check;
+-- +goose StatementEnd
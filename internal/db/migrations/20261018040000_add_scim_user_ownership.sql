@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE thunderdome.scim_user
+    ADD COLUMN created_user BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN active BOOLEAN NOT NULL DEFAULT true;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE thunderdome.scim_user DROP COLUMN IF EXISTS active;
+ALTER TABLE thunderdome.scim_user DROP COLUMN IF EXISTS created_user;
+-- +goose StatementEnd
@@ -27,7 +27,7 @@ type Service struct {
 }
 
 // CreateGame creates a new story pointing session
-func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, hideVoterIdentity bool) (*thunderdome.Poker, error) {
+func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, hideVoterIdentity bool, votingTimeLimitSec int) (*thunderdome.Poker, error) {
 	var encryptedJoinCode string
 	var encryptedLeaderCode string
 
@@ -56,6 +56,7 @@ func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name str
 		AutoFinishVoting:     autoFinishVoting,
 		PointAverageRounding: pointAverageRounding,
 		HideVoterIdentity:    hideVoterIdentity,
+		VotingTimeLimitSec:   votingTimeLimitSec,
 		Facilitators:         make([]string, 0),
 		JoinCode:             joinCode,
 		FacilitatorCode:      facilitatorCode,
@@ -74,11 +75,11 @@ func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name str
 	err = tx.QueryRowContext(ctx, `
             INSERT INTO thunderdome.poker
             (owner_id, name, estimation_scale_id, point_values_allowed, auto_finish_voting, point_average_rounding,
-             hide_voter_identity, join_code, leader_code)
-            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+             hide_voter_identity, join_code, leader_code, voting_time_limit_sec)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
             RETURNING id, created_date, updated_date
         `, facilitatorID, name, estimationScaleID, pointValuesAllowed, autoFinishVoting,
-		pointAverageRounding, hideVoterIdentity, encryptedJoinCode, encryptedLeaderCode,
+		pointAverageRounding, hideVoterIdentity, encryptedJoinCode, encryptedLeaderCode, votingTimeLimitSec,
 	).Scan(&b.ID, &b.CreatedDate, &b.UpdatedDate)
 	if err != nil {
 		d.Logger.Error("create poker error", zap.Error(err))
@@ -119,8 +120,8 @@ func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name str
 		}
 
 		e := d.DB.QueryRowContext(ctx,
-			`INSERT INTO thunderdome.poker_story (poker_id, name, type, reference_id, link, description, acceptance_criteria, priority, position)
-					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, (
+			`INSERT INTO thunderdome.poker_story (poker_id, name, type, reference_id, link, description, acceptance_criteria, priority, project_item_id, position)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, (
 					  coalesce(
 						(select max(position) from thunderdome.poker_story where poker_id = $1),
 						-1
@@ -134,6 +135,7 @@ func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name str
 			story.Description,
 			story.AcceptanceCriteria,
 			priority,
+			story.ProjectItemID,
 		).Scan(&story.ID)
 		if e != nil {
 			d.Logger.Error("insert stories error", zap.Error(e))
@@ -146,7 +148,7 @@ func (d *Service) CreateGame(ctx context.Context, facilitatorID string, name str
 }
 
 // TeamCreateGame creates a new story pointing session associated to a team
-func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, hideVoterIdentity bool) (*thunderdome.Poker, error) {
+func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, hideVoterIdentity bool, votingTimeLimitSec int) (*thunderdome.Poker, error) {
 	var encryptedJoinCode string
 	var encryptedLeaderCode string
 
@@ -175,6 +177,7 @@ func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitator
 		AutoFinishVoting:     autoFinishVoting,
 		PointAverageRounding: pointAverageRounding,
 		HideVoterIdentity:    hideVoterIdentity,
+		VotingTimeLimitSec:   votingTimeLimitSec,
 		Facilitators:         make([]string, 0),
 		JoinCode:             joinCode,
 		FacilitatorCode:      facilitatorCode,
@@ -192,11 +195,11 @@ func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitator
 	err = tx.QueryRowContext(ctx, `
             INSERT INTO thunderdome.poker
             (owner_id, name, estimation_scale_id, point_values_allowed, auto_finish_voting, point_average_rounding,
-             hide_voter_identity, join_code, leader_code, team_id)
-            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+             hide_voter_identity, join_code, leader_code, team_id, voting_time_limit_sec)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
             RETURNING id, created_date, updated_date
         `, facilitatorID, name, estimationScaleID, pointValuesAllowed, autoFinishVoting,
-		pointAverageRounding, hideVoterIdentity, encryptedJoinCode, encryptedLeaderCode, teamID,
+		pointAverageRounding, hideVoterIdentity, encryptedJoinCode, encryptedLeaderCode, teamID, votingTimeLimitSec,
 	).Scan(&b.ID, &b.CreatedDate, &b.UpdatedDate)
 	if err != nil {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
@@ -243,8 +246,8 @@ func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitator
 		}
 
 		e := d.DB.QueryRowContext(ctx,
-			`INSERT INTO thunderdome.poker_story (poker_id, name, type, reference_id, link, description, acceptance_criteria, priority, position)
-					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, (
+			`INSERT INTO thunderdome.poker_story (poker_id, name, type, reference_id, link, description, acceptance_criteria, priority, project_item_id, position)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, (
 					  coalesce(
 						(select max(position) from thunderdome.poker_story where poker_id = $1),
 						-1
@@ -258,6 +261,7 @@ func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitator
 			story.Description,
 			story.AcceptanceCriteria,
 			priority,
+			story.ProjectItemID,
 		).Scan(&story.ID)
 		if e != nil {
 			d.Logger.Error("insert stories error", zap.Error(e))
@@ -269,8 +273,8 @@ func (d *Service) TeamCreateGame(ctx context.Context, teamID string, facilitator
 	return b, nil
 }
 
-// UpdateGame updates the game by ID
-func (d *Service) UpdateGame(pokerID string, name string, pointValuesAllowed []string, autoFinishVoting bool, pointAverageRounding string, hideVoterIdentity bool, joinCode string, facilitatorCode string, teamID string) error {
+// UpdateGame updates the game by ID, a nil votingTimeLimitSec keeps the current voting time limit
+func (d *Service) UpdateGame(pokerID string, name string, pointValuesAllowed []string, autoFinishVoting bool, pointAverageRounding string, hideVoterIdentity bool, joinCode string, facilitatorCode string, teamID string, votingTimeLimitSec *int) error {
 	var encryptedJoinCode string
 	var encryptedLeaderCode string
 
@@ -293,10 +297,11 @@ func (d *Service) UpdateGame(pokerID string, name string, pointValuesAllowed []s
 	if _, err := d.DB.Exec(`
 		UPDATE thunderdome.poker
 		SET name = $2, point_values_allowed = $3, auto_finish_voting = $4, point_average_rounding = $5,
-		 hide_voter_identity = $6, join_code = $7, leader_code = $8, updated_date = NOW(), team_id = NULLIF($9, '')::uuid
+		 hide_voter_identity = $6, join_code = $7, leader_code = $8, updated_date = NOW(), team_id = NULLIF($9, '')::uuid,
+		 voting_time_limit_sec = COALESCE($10, voting_time_limit_sec)
 		WHERE id = $1`,
 		pokerID, name, pointValuesAllowed, autoFinishVoting, pointAverageRounding,
-		hideVoterIdentity, encryptedJoinCode, encryptedLeaderCode, teamID,
+		hideVoterIdentity, encryptedJoinCode, encryptedLeaderCode, teamID, votingTimeLimitSec,
 	); err != nil {
 		return fmt.Errorf("update poker query error: %v", err)
 	}
@@ -324,7 +329,7 @@ func (d *Service) GetGameByID(pokerID string, userID string) (*thunderdome.Poker
 	e := d.DB.QueryRow(
 		`
 		SELECT b.id, b.name, b.voting_locked, COALESCE(b.active_story_id::text, ''), b.auto_finish_voting,
-		b.point_average_rounding, b.hide_voter_identity, COALESCE(b.join_code, ''), COALESCE(b.leader_code, ''),
+		b.point_average_rounding, b.hide_voter_identity, b.voting_time_limit_sec, COALESCE(b.join_code, ''), COALESCE(b.leader_code, ''),
 		b.estimation_scale_id, b.point_values_allowed, COALESCE(b.team_id::text, ''), b.created_date, b.updated_date,
 		CASE WHEN COUNT(bl) = 0 THEN '[]'::json ELSE array_to_json(array_agg(bl.user_id)) END AS leaders,
 		COALESCE(
@@ -358,6 +363,7 @@ func (d *Service) GetGameByID(pokerID string, userID string) (*thunderdome.Poker
 		&b.AutoFinishVoting,
 		&b.PointAverageRounding,
 		&b.HideVoterIdentity,
+		&b.VotingTimeLimitSec,
 		&joinCode,
 		&facilitatorCode,
 		&b.EstimationScaleID,
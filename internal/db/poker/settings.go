@@ -18,12 +18,12 @@ func (d *Service) GetSettingsByOrganization(ctx context.Context, orgID string) (
 	var facilitatorCode string
 
 	err := d.DB.QueryRowContext(ctx, `
-		SELECT id, organization_id, auto_finish_voting, point_average_rounding, hide_voter_identity, 
-		       estimation_scale_id, join_code, facilitator_code, created_at, updated_at
+		SELECT id, organization_id, auto_finish_voting, point_average_rounding, hide_voter_identity,
+		       voting_time_limit_sec, estimation_scale_id, join_code, facilitator_code, created_at, updated_at
 		FROM thunderdome.poker_settings
 		WHERE organization_id = $1`, orgID).Scan(
 		&settings.ID, &settings.OrganizationID, &settings.AutoFinishVoting, &settings.PointAverageRounding,
-		&settings.HideVoterIdentity, &settings.EstimationScaleID, &joinCode, &facilitatorCode,
+		&settings.HideVoterIdentity, &settings.VotingTimeLimitSec, &settings.EstimationScaleID, &joinCode, &facilitatorCode,
 		&settings.CreatedAt, &settings.UpdatedAt)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -59,12 +59,12 @@ func (d *Service) GetSettingsByDepartment(ctx context.Context, deptID string) (*
 	var facilitatorCode string
 
 	err := d.DB.QueryRowContext(ctx, `
-		SELECT id, department_id, auto_finish_voting, point_average_rounding, hide_voter_identity, 
-		       estimation_scale_id, join_code, facilitator_code, created_at, updated_at
+		SELECT id, department_id, auto_finish_voting, point_average_rounding, hide_voter_identity,
+		       voting_time_limit_sec, estimation_scale_id, join_code, facilitator_code, created_at, updated_at
 		FROM thunderdome.poker_settings
 		WHERE department_id = $1`, deptID).Scan(
 		&settings.ID, &settings.DepartmentID, &settings.AutoFinishVoting, &settings.PointAverageRounding,
-		&settings.HideVoterIdentity, &settings.EstimationScaleID, &joinCode, &facilitatorCode,
+		&settings.HideVoterIdentity, &settings.VotingTimeLimitSec, &settings.EstimationScaleID, &joinCode, &facilitatorCode,
 		&settings.CreatedAt, &settings.UpdatedAt)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -100,12 +100,12 @@ func (d *Service) GetSettingsByTeam(ctx context.Context, teamID string) (*thunde
 	var facilitatorCode string
 
 	err := d.DB.QueryRowContext(ctx, `
-		SELECT id, team_id, auto_finish_voting, point_average_rounding, hide_voter_identity, 
-		       estimation_scale_id, join_code, facilitator_code, created_at, updated_at
+		SELECT id, team_id, auto_finish_voting, point_average_rounding, hide_voter_identity,
+		       voting_time_limit_sec, estimation_scale_id, join_code, facilitator_code, created_at, updated_at
 		FROM thunderdome.poker_settings
 		WHERE team_id = $1`, teamID).Scan(
 		&settings.ID, &settings.TeamID, &settings.AutoFinishVoting, &settings.PointAverageRounding,
-		&settings.HideVoterIdentity, &settings.EstimationScaleID, &joinCode, &facilitatorCode,
+		&settings.HideVoterIdentity, &settings.VotingTimeLimitSec, &settings.EstimationScaleID, &joinCode, &facilitatorCode,
 		&settings.CreatedAt, &settings.UpdatedAt)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -158,12 +158,12 @@ func (d *Service) CreateSettings(ctx context.Context, settings *thunderdome.Poke
 	err := d.DB.QueryRowContext(ctx, `
 		INSERT INTO thunderdome.poker_settings (
 			organization_id, department_id, team_id, auto_finish_voting, point_average_rounding,
-			hide_voter_identity, estimation_scale_id, join_code, facilitator_code
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at, updated_at`,
+			hide_voter_identity, estimation_scale_id, join_code, facilitator_code, voting_time_limit_sec
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10, 0)) RETURNING id, created_at, updated_at, voting_time_limit_sec`,
 		settings.OrganizationID, settings.DepartmentID, settings.TeamID, settings.AutoFinishVoting,
 		settings.PointAverageRounding, settings.HideVoterIdentity, settings.EstimationScaleID,
-		encryptedJoinCode, encryptedFacilitatorCode).Scan(
-		&settings.ID, &settings.CreatedAt, &settings.UpdatedAt,
+		encryptedJoinCode, encryptedFacilitatorCode, settings.VotingTimeLimitSec).Scan(
+		&settings.ID, &settings.CreatedAt, &settings.UpdatedAt, &settings.VotingTimeLimitSec,
 	)
 	if err != nil {
 		return nil, err
@@ -195,11 +195,13 @@ func (d *Service) UpdateSettings(ctx context.Context, settings *thunderdome.Poke
 	err := d.DB.QueryRowContext(ctx, `
 		UPDATE thunderdome.poker_settings
 		SET auto_finish_voting = $1, point_average_rounding = $2, hide_voter_identity = $3,
-		    estimation_scale_id = $4, join_code = $5, facilitator_code = $6, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $7 RETURNING created_at, updated_at, organization_id, department_id, team_id`,
+		    estimation_scale_id = $4, join_code = $5, facilitator_code = $6,
+		    voting_time_limit_sec = COALESCE($8, voting_time_limit_sec), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $7 RETURNING created_at, updated_at, organization_id, department_id, team_id, voting_time_limit_sec`,
 		settings.AutoFinishVoting, settings.PointAverageRounding, settings.HideVoterIdentity,
-		settings.EstimationScaleID, encryptedJoinCode, encryptedFacilitatorCode, settings.ID).Scan(
+		settings.EstimationScaleID, encryptedJoinCode, encryptedFacilitatorCode, settings.ID, settings.VotingTimeLimitSec).Scan(
 		&settings.CreatedAt, &settings.UpdatedAt, &settings.OrganizationID, &settings.DepartmentID, &settings.TeamID,
+		&settings.VotingTimeLimitSec,
 	)
 	if err != nil {
 		return nil, err
@@ -231,11 +233,12 @@ func (d *Service) UpdateOrganizationSettings(ctx context.Context, settings *thun
 	err := d.DB.QueryRowContext(ctx, `
 		UPDATE thunderdome.poker_settings
 		SET auto_finish_voting = $1, point_average_rounding = $2, hide_voter_identity = $3,
-		    estimation_scale_id = $4, join_code = $5, facilitator_code = $6, updated_at = CURRENT_TIMESTAMP
-		WHERE organization_id = $7 RETURNING id, created_at, updated_at`,
+		    estimation_scale_id = $4, join_code = $5, facilitator_code = $6,
+		    voting_time_limit_sec = COALESCE($8, voting_time_limit_sec), updated_at = CURRENT_TIMESTAMP
+		WHERE organization_id = $7 RETURNING id, created_at, updated_at, voting_time_limit_sec`,
 		settings.AutoFinishVoting, settings.PointAverageRounding, settings.HideVoterIdentity,
-		settings.EstimationScaleID, encryptedJoinCode, encryptedFacilitatorCode, settings.OrganizationID).Scan(
-		&settings.ID, &settings.CreatedAt, &settings.UpdatedAt,
+		settings.EstimationScaleID, encryptedJoinCode, encryptedFacilitatorCode, settings.OrganizationID, settings.VotingTimeLimitSec).Scan(
+		&settings.ID, &settings.CreatedAt, &settings.UpdatedAt, &settings.VotingTimeLimitSec,
 	)
 	if err != nil {
 		return nil, err
@@ -267,11 +270,12 @@ func (d *Service) UpdateDepartmentSettings(ctx context.Context, settings *thunde
 	err := d.DB.QueryRowContext(ctx, `
 		UPDATE thunderdome.poker_settings
 		SET auto_finish_voting = $1, point_average_rounding = $2, hide_voter_identity = $3,
-		    estimation_scale_id = $4, join_code = $5, facilitator_code = $6, updated_at = CURRENT_TIMESTAMP
-		WHERE department_id = $7 RETURNING id, created_at, updated_at`,
+		    estimation_scale_id = $4, join_code = $5, facilitator_code = $6,
+		    voting_time_limit_sec = COALESCE($8, voting_time_limit_sec), updated_at = CURRENT_TIMESTAMP
+		WHERE department_id = $7 RETURNING id, created_at, updated_at, voting_time_limit_sec`,
 		settings.AutoFinishVoting, settings.PointAverageRounding, settings.HideVoterIdentity,
-		settings.EstimationScaleID, encryptedJoinCode, encryptedFacilitatorCode, settings.DepartmentID).Scan(
-		&settings.ID, &settings.CreatedAt, &settings.UpdatedAt,
+		settings.EstimationScaleID, encryptedJoinCode, encryptedFacilitatorCode, settings.DepartmentID, settings.VotingTimeLimitSec).Scan(
+		&settings.ID, &settings.CreatedAt, &settings.UpdatedAt, &settings.VotingTimeLimitSec,
 	)
 	if err != nil {
 		return nil, err
@@ -303,11 +307,12 @@ func (d *Service) UpdateTeamSettings(ctx context.Context, settings *thunderdome.
 	err := d.DB.QueryRowContext(ctx, `
 		UPDATE thunderdome.poker_settings
 		SET auto_finish_voting = $1, point_average_rounding = $2, hide_voter_identity = $3,
-		    estimation_scale_id = $4, join_code = $5, facilitator_code = $6, updated_at = CURRENT_TIMESTAMP
-		WHERE team_id = $7 RETURNING id, created_at, updated_at`,
+		    estimation_scale_id = $4, join_code = $5, facilitator_code = $6,
+		    voting_time_limit_sec = COALESCE($8, voting_time_limit_sec), updated_at = CURRENT_TIMESTAMP
+		WHERE team_id = $7 RETURNING id, created_at, updated_at, voting_time_limit_sec`,
 		settings.AutoFinishVoting, settings.PointAverageRounding, settings.HideVoterIdentity,
-		settings.EstimationScaleID, encryptedJoinCode, encryptedFacilitatorCode, settings.TeamID).Scan(
-		&settings.ID, &settings.CreatedAt, &settings.UpdatedAt,
+		settings.EstimationScaleID, encryptedJoinCode, encryptedFacilitatorCode, settings.TeamID, settings.VotingTimeLimitSec).Scan(
+		&settings.ID, &settings.CreatedAt, &settings.UpdatedAt, &settings.VotingTimeLimitSec,
 	)
 	if err != nil {
 		return nil, err
@@ -329,12 +334,12 @@ func (d *Service) GetSettingsByID(ctx context.Context, id string) (*thunderdome.
 
 	err := d.DB.QueryRowContext(ctx, `
 		SELECT id, organization_id, department_id, team_id, auto_finish_voting, point_average_rounding,
-		       hide_voter_identity, estimation_scale_id, join_code, facilitator_code, created_at, updated_at
+		       hide_voter_identity, voting_time_limit_sec, estimation_scale_id, join_code, facilitator_code, created_at, updated_at
 		FROM thunderdome.poker_settings
 		WHERE id = $1`, id).Scan(
 		&settings.ID, &settings.OrganizationID, &settings.DepartmentID, &settings.TeamID,
 		&settings.AutoFinishVoting, &settings.PointAverageRounding, &settings.HideVoterIdentity,
-		&settings.EstimationScaleID, &joinCode, &facilitatorCode,
+		&settings.VotingTimeLimitSec, &settings.EstimationScaleID, &joinCode, &facilitatorCode,
 		&settings.CreatedAt, &settings.UpdatedAt)
 
 	if errors.Is(err, sql.ErrNoRows) {
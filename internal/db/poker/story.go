@@ -16,8 +16,8 @@ func (d *Service) GetStories(pokerID string, userID string) []*thunderdome.Story
 	storyRows, storiesErr := d.DB.Query(
 		`SELECT
 			id, name, type, reference_id, link, description, acceptance_criteria, priority,
-			points, active, skipped, votestart_time, voteend_time, votes,
-			row_number() OVER (ORDER BY position ASC) as position
+			points, active, skipped, votestart_time, voteend_time, vote_deadline, voteend_reason, votes,
+			row_number() OVER (ORDER BY position ASC) as position, project_item_id
 			FROM thunderdome.poker_story WHERE poker_id = $1 ORDER BY position
 		`,
 		pokerID,
@@ -37,7 +37,7 @@ func (d *Service) GetStories(pokerID string, userID string) []*thunderdome.Story
 			}
 			if err := storyRows.Scan(
 				&p.ID, &p.Name, &p.Type, &referenceID, &link, &description, &acceptanceCriteria, &p.Priority,
-				&p.Points, &p.Active, &p.Skipped, &p.VoteStartTime, &p.VoteEndTime, &v, &p.Position,
+				&p.Points, &p.Active, &p.Skipped, &p.VoteStartTime, &p.VoteEndTime, &p.VoteDeadline, &p.VoteEndReason, &v, &p.Position, &p.ProjectItemID,
 			); err != nil {
 				d.Logger.Error("get poker stories query error", zap.Error(err),
 					zap.String("PokerID", pokerID), zap.String("UserID", userID))
@@ -95,12 +95,11 @@ func (d *Service) CreateStory(pokerID string, name string, storyType string, ref
 	return stories, nil
 }
 
-// ActivateStoryVoting sets the story by ID to active, wipes any previous votes/points, and disables votingLock
+// ActivateStoryVoting sets the story by ID to active, wipes any previous votes/points, and disables votingLock,
+// games with a voting time limit also get the story's voting deadline set
 func (d *Service) ActivateStoryVoting(pokerID string, storyID string) ([]*thunderdome.Story, error) {
-	if _, err := d.DB.Exec(
-		`CALL thunderdome.poker_story_activate($1, $2);`, pokerID, storyID,
-	); err != nil {
-		d.Logger.Error("CALL thunderdome.poker_story_activate error", zap.Error(err),
+	if err := d.activateStoryVoting(pokerID, storyID, ""); err != nil {
+		d.Logger.Error("poker ActivateStoryVoting error", zap.Error(err),
 			zap.String("PokerID", pokerID), zap.String("StoryID", storyID))
 	}
 
@@ -109,6 +108,66 @@ func (d *Service) ActivateStoryVoting(pokerID string, storyID string) ([]*thunde
 	return stories, nil
 }
 
+// activateStoryVoting starts a voting round on the story, a round still in progress
+// is kept in its story's voting round history before the votes are wiped,
+// ending as a revote when it's the same story and as switched otherwise
+func (d *Service) activateStoryVoting(pokerID string, storyID string, userID string) error {
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// locking the game keeps concurrent story changes from archiving the same round twice
+	var activeStoryID sql.NullString
+	if err = tx.QueryRow(
+		`SELECT active_story_id FROM thunderdome.poker WHERE id = $1 FOR UPDATE;`, pokerID,
+	).Scan(&activeStoryID); err != nil {
+		return fmt.Errorf("get poker active story query error: %v", err)
+	}
+
+	if activeStoryID.Valid {
+		endReason := thunderdome.StoryVoteEndSwitched
+		if activeStoryID.String == storyID {
+			endReason = thunderdome.StoryVoteEndRevote
+		}
+		if err = archiveVoteRound(tx, pokerID, activeStoryID.String, userID, endReason); err != nil {
+			return err
+		}
+	}
+
+	if _, err = tx.Exec(
+		`UPDATE thunderdome.poker_story SET updated_date = NOW(), active = false
+		WHERE poker_id = $1 AND active = true;`, pokerID,
+	); err != nil {
+		return fmt.Errorf("deactivate poker stories query error: %v", err)
+	}
+	if _, err = tx.Exec(
+		`UPDATE thunderdome.poker_story ps SET updated_date = NOW(), active = true, skipped = false,
+			points = '', votestart_time = NOW(), votes = '[]'::jsonb, voteend_reason = NULL,
+			vote_deadline = CASE WHEN p.voting_time_limit_sec > 0
+				THEN NOW() + make_interval(secs => p.voting_time_limit_sec) END
+		FROM thunderdome.poker p
+		WHERE ps.id = $2 AND ps.poker_id = $1 AND p.id = ps.poker_id;`,
+		pokerID, storyID,
+	); err != nil {
+		return fmt.Errorf("activate poker story query error: %v", err)
+	}
+	if _, err = tx.Exec(
+		`UPDATE thunderdome.poker SET last_active = NOW(), updated_date = NOW(),
+			voting_locked = false, active_story_id = $2
+		WHERE id = $1;`, pokerID, storyID,
+	); err != nil {
+		return fmt.Errorf("set poker active story query error: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 // SetVote sets a users vote for the story
 func (d *Service) SetVote(pokerID string, userID string, storyID string, voteValue string) (Stories []*thunderdome.Story, allUsersVoted bool) {
 	if _, err := d.DB.Exec(
@@ -180,11 +239,11 @@ func (d *Service) RetractVote(pokerID string, userID string, storyID string) ([]
 	return stories, nil
 }
 
-// EndStoryVoting sets story to active: false
-func (d *Service) EndStoryVoting(pokerID string, storyID string) ([]*thunderdome.Story, error) {
-	if _, err := d.DB.Exec(
-		`CALL thunderdome.poker_plan_voting_stop($1, $2);`, pokerID, storyID); err != nil {
-		d.Logger.Error("CALL thunderdome.poker_plan_voting_stop error", zap.Error(err),
+// EndStoryVoting sets story to active: false, records why voting ended,
+// and keeps the round's votes in the story's voting round history
+func (d *Service) EndStoryVoting(pokerID string, storyID string, endReason string, endedBy string) ([]*thunderdome.Story, error) {
+	if err := d.endStoryVoting(pokerID, storyID, endReason, endedBy); err != nil {
+		d.Logger.Error("poker EndStoryVoting error", zap.Error(err),
 			zap.String("PokerID", pokerID), zap.String("StoryID", storyID))
 	}
 
@@ -193,11 +252,53 @@ func (d *Service) EndStoryVoting(pokerID string, storyID string) ([]*thunderdome
 	return stories, nil
 }
 
-// SkipStory sets story to active: false and unsets games activeStoryId
+func (d *Service) endStoryVoting(pokerID string, storyID string, endReason string, endedBy string) error {
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = stopStoryVoting(tx, pokerID, storyID, endReason, endedBy); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// stopStoryVoting archives the story's round in progress, deactivates the story
+// with why voting ended, and locks voting for the game
+func stopStoryVoting(tx *sql.Tx, pokerID string, storyID string, endReason string, endedBy string) error {
+	if err := archiveVoteRound(tx, pokerID, storyID, endedBy, endReason); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(
+		`UPDATE thunderdome.poker_story SET updated_date = NOW(), active = false, voteend_time = NOW(),
+			voteend_reason = $3, vote_deadline = NULL
+		WHERE id = $2 AND poker_id = $1;`,
+		pokerID, storyID, endReason,
+	); err != nil {
+		return fmt.Errorf("stop poker story voting query error: %v", err)
+	}
+	if _, err := tx.Exec(
+		`UPDATE thunderdome.poker SET updated_date = NOW(), last_active = NOW(), voting_locked = true
+		WHERE id = $1;`, pokerID,
+	); err != nil {
+		return fmt.Errorf("lock poker voting query error: %v", err)
+	}
+
+	return nil
+}
+
+// SkipStory sets story to active: false and unsets games activeStoryId,
+// a round still in progress is kept in the story's voting round history as skipped
 func (d *Service) SkipStory(pokerID string, storyID string) ([]*thunderdome.Story, error) {
-	if _, err := d.DB.Exec(
-		`CALL thunderdome.poker_vote_skip($1, $2);`, pokerID, storyID); err != nil {
-		d.Logger.Error("CALL thunderdome.poker_vote_skip error", zap.Error(err),
+	if err := d.skipStory(pokerID, storyID); err != nil {
+		d.Logger.Error("poker SkipStory error", zap.Error(err),
 			zap.String("PokerID", pokerID), zap.String("StoryID", storyID))
 	}
 
@@ -206,6 +307,38 @@ func (d *Service) SkipStory(pokerID string, storyID string) ([]*thunderdome.Stor
 	return stories, nil
 }
 
+func (d *Service) skipStory(pokerID string, storyID string) error {
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = archiveVoteRound(tx, pokerID, storyID, "", thunderdome.StoryVoteEndSkipped); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(
+		`UPDATE thunderdome.poker_story SET updated_date = NOW(), active = false, skipped = true,
+			voteend_time = NOW()
+		WHERE poker_id = $1;`, pokerID,
+	); err != nil {
+		return fmt.Errorf("skip poker story query error: %v", err)
+	}
+	if _, err = tx.Exec(
+		`UPDATE thunderdome.poker SET updated_date = NOW(), last_active = NOW(),
+			voting_locked = true, active_story_id = NULL
+		WHERE id = $1;`, pokerID,
+	); err != nil {
+		return fmt.Errorf("unset poker active story query error: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateStory updates the story by ID
 func (d *Service) UpdateStory(pokerID string, storyID string, name string, storyType string, referenceID string, link string, description string, acceptanceCriteria string, priority int32) ([]*thunderdome.Story, error) {
 	sanitizedDescription := d.HTMLSanitizerPolicy.Sanitize(description)
@@ -308,11 +441,12 @@ func (d *Service) ArrangeStory(pokerID string, storyID string, beforeStoryID str
 	return stories, nil
 }
 
-// FinalizeStory sets story to active: false and updates the points
+// FinalizeStory sets story to active: false and updates the points,
+// stories sourced from a project item also write the points back to that item,
+// and a round still in progress is kept in the story's voting round history as finalized
 func (d *Service) FinalizeStory(pokerID string, storyID string, points string) ([]*thunderdome.Story, error) {
-	if _, err := d.DB.Exec(
-		`CALL thunderdome.poker_story_finalize($1, $2, $3);`, pokerID, storyID, points); err != nil {
-		d.Logger.Error("CALL thunderdome.poker_story_finalize error", zap.Error(err),
+	if err := d.finalizeStory(pokerID, storyID, points); err != nil {
+		d.Logger.Error("poker FinalizeStory error", zap.Error(err),
 			zap.String("PokerID", pokerID),
 			zap.String("StoryID", storyID),
 			zap.String("Points", points))
@@ -322,3 +456,41 @@ func (d *Service) FinalizeStory(pokerID string, storyID string, points string) (
 
 	return stories, nil
 }
+
+func (d *Service) finalizeStory(pokerID string, storyID string, points string) error {
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = archiveVoteRound(tx, pokerID, storyID, "", thunderdome.StoryVoteEndFinalized); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(
+		`UPDATE thunderdome.poker_story SET updated_date = NOW(), active = false, points = $3
+		WHERE id = $2 AND poker_id = $1;`, pokerID, storyID, points,
+	); err != nil {
+		return fmt.Errorf("finalize poker story query error: %v", err)
+	}
+	if _, err = tx.Exec(
+		`UPDATE thunderdome.poker SET updated_date = NOW(), last_active = NOW(), active_story_id = NULL
+		WHERE id = $1;`, pokerID,
+	); err != nil {
+		return fmt.Errorf("unset poker active story query error: %v", err)
+	}
+	if _, err = tx.Exec(
+		`UPDATE thunderdome.project_item pi SET story_points = $3, updated_at = NOW()
+		FROM thunderdome.poker_story ps
+		WHERE ps.id = $2 AND ps.poker_id = $1 AND pi.id = ps.project_item_id;`,
+		pokerID, storyID, points,
+	); err != nil {
+		return fmt.Errorf("update project item points query error: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package poker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const (
+	testPokerID = "6d2c1f8e-0a4b-4c1e-9d7f-2b3a4c5d6e7f"
+	testStoryID = "1f2e3d4c-5b6a-4978-8a9b-0c1d2e3f4a5b"
+)
+
+func newTestService(t *testing.T) (*Service, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return &Service{DB: db, Logger: otelzap.New(zap.NewNop())}, mock
+}
+
+func TestFinalizeStoryWritesPointsToProjectItem(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	// finalizing mid-vote keeps the round's votes before the story is deactivated
+	mock.ExpectExec(`INSERT INTO thunderdome.poker_story_vote_round(.|\n)+AND ps.active = true`).
+		WithArgs(testPokerID, testStoryID, "", thunderdome.StoryVoteEndFinalized).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE thunderdome.poker_story SET updated_date = NOW\(\), active = false, points = \$3`).
+		WithArgs(testPokerID, testStoryID, "5").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE thunderdome.poker SET (.|\n)+active_story_id = NULL`).
+		WithArgs(testPokerID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE thunderdome.project_item pi SET story_points = \$3`).
+		WithArgs(testPokerID, testStoryID, "5").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`FROM thunderdome.poker_story WHERE poker_id = \$1`).
+		WithArgs(testPokerID).
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	if _, err := s.FinalizeStory(testPokerID, testStoryID, "5"); err != nil {
+		t.Fatalf("FinalizeStory() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFinalizeStoryRollsBackWhenArchiveFails(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO thunderdome.poker_story_vote_round`).
+		WithArgs(testPokerID, testStoryID, "", thunderdome.StoryVoteEndFinalized).
+		WillReturnError(errors.New("connection reset"))
+	// the story and its project item are left as they are, the stories are reloaded as they are
+	mock.ExpectRollback()
+	mock.ExpectQuery(`FROM thunderdome.poker_story WHERE poker_id = \$1`).
+		WithArgs(testPokerID).
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	if _, err := s.FinalizeStory(testPokerID, testStoryID, "5"); err != nil {
+		t.Fatalf("FinalizeStory() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSkipStoryArchivesVoteRound(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO thunderdome.poker_story_vote_round(.|\n)+AND ps.active = true`).
+		WithArgs(testPokerID, testStoryID, "", thunderdome.StoryVoteEndSkipped).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE thunderdome.poker_story SET (.|\n)+skipped = true`).
+		WithArgs(testPokerID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE thunderdome.poker SET (.|\n)+voting_locked = true, active_story_id = NULL`).
+		WithArgs(testPokerID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`FROM thunderdome.poker_story WHERE poker_id = \$1`).
+		WithArgs(testPokerID).
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	if _, err := s.SkipStory(testPokerID, testStoryID); err != nil {
+		t.Fatalf("SkipStory() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestActivateStoryVotingArchivesActiveStoryRound(t *testing.T) {
+	s, mock := newTestService(t)
+	const activeStoryID = "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT active_story_id FROM thunderdome.poker WHERE id = \$1 FOR UPDATE`).
+		WithArgs(testPokerID).
+		WillReturnRows(sqlmock.NewRows([]string{"active_story_id"}).AddRow(activeStoryID))
+	// the round of the story being voted on is kept before another story is activated
+	mock.ExpectExec(`INSERT INTO thunderdome.poker_story_vote_round(.|\n)+AND ps.active = true`).
+		WithArgs(testPokerID, activeStoryID, "", thunderdome.StoryVoteEndSwitched).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE thunderdome.poker_story SET updated_date = NOW\(\), active = false`).
+		WithArgs(testPokerID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE thunderdome.poker_story ps SET (.|\n)+votes = '\[\]'::jsonb`).
+		WithArgs(testPokerID, testStoryID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE thunderdome.poker SET (.|\n)+active_story_id = \$2`).
+		WithArgs(testPokerID, testStoryID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`FROM thunderdome.poker_story WHERE poker_id = \$1`).
+		WithArgs(testPokerID).
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	if _, err := s.ActivateStoryVoting(testPokerID, testStoryID); err != nil {
+		t.Fatalf("ActivateStoryVoting() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
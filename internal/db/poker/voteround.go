@@ -0,0 +1,86 @@
+package poker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+
+	"go.uber.org/zap"
+)
+
+// insertVoteRoundSQL copies a story's current votes into its voting round history,
+// params are poker ID, story ID, ended by user ID (empty for none), and end reason
+const insertVoteRoundSQL = `INSERT INTO thunderdome.poker_story_vote_round
+		(story_id, round_number, votes, started_at, ended_by, end_reason)
+	SELECT ps.id,
+		COALESCE((SELECT MAX(vr.round_number) FROM thunderdome.poker_story_vote_round vr WHERE vr.story_id = ps.id), 0) + 1,
+		ps.votes, ps.votestart_time, NULLIF($3, '')::uuid, $4
+	FROM thunderdome.poker_story ps
+	WHERE ps.id = $2 AND ps.poker_id = $1`
+
+// GetStoryVoteRounds gets the voting round history of a poker game's stories ordered by round
+func (d *Service) GetStoryVoteRounds(ctx context.Context, pokerID string) ([]*thunderdome.StoryVoteRound, error) {
+	rounds := make([]*thunderdome.StoryVoteRound, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT vr.id, vr.story_id, vr.round_number, vr.votes, vr.started_at, vr.ended_at,
+			vr.ended_by, vr.end_reason
+		FROM thunderdome.poker_story_vote_round vr
+		JOIN thunderdome.poker_story ps ON ps.id = vr.story_id
+		WHERE ps.poker_id = $1
+		ORDER BY vr.story_id, vr.round_number;`,
+		pokerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get story vote rounds query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var votes []byte
+		var vr = thunderdome.StoryVoteRound{
+			Votes: make([]*thunderdome.Vote, 0),
+		}
+		if err := rows.Scan(
+			&vr.ID, &vr.StoryID, &vr.RoundNumber, &votes, &vr.StartTime, &vr.EndTime,
+			&vr.EndedBy, &vr.EndReason,
+		); err != nil {
+			return nil, fmt.Errorf("get story vote rounds scan error: %v", err)
+		}
+		if err := json.Unmarshal(votes, &vr.Votes); err != nil {
+			return nil, fmt.Errorf("get story vote rounds votes unmarshal error: %v", err)
+		}
+		rounds = append(rounds, &vr)
+	}
+
+	return rounds, nil
+}
+
+// RevoteStory starts a fresh voting round on the story, a round still in progress
+// is kept in the story's voting round history as ended by the user for a revote
+func (d *Service) RevoteStory(pokerID string, storyID string, userID string) ([]*thunderdome.Story, error) {
+	if err := d.activateStoryVoting(pokerID, storyID, userID); err != nil {
+		d.Logger.Error("poker RevoteStory error", zap.Error(err),
+			zap.String("PokerID", pokerID), zap.String("StoryID", storyID))
+		return nil, fmt.Errorf("poker revote story error: %v", err)
+	}
+
+	stories := d.GetStories(pokerID, "")
+
+	return stories, nil
+}
+
+// archiveVoteRound keeps the story's round in progress in its voting round history
+// before its votes are cleared, stories that aren't being voted on are left as they are
+func archiveVoteRound(tx *sql.Tx, pokerID string, storyID string, endedBy string, endReason string) error {
+	if _, err := tx.Exec(
+		insertVoteRoundSQL+` AND ps.active = true;`, pokerID, storyID, endedBy, endReason,
+	); err != nil {
+		return fmt.Errorf("insert vote round query error: %v", err)
+	}
+
+	return nil
+}
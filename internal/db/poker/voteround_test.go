@@ -0,0 +1,66 @@
+package poker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+const testUserID = "c3d4e5f6-a7b8-4c9d-8e1f-2a3b4c5d6e7f"
+
+func TestRevoteStory(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT active_story_id FROM thunderdome.poker WHERE id = \$1 FOR UPDATE`).
+		WithArgs(testPokerID).
+		WillReturnRows(sqlmock.NewRows([]string{"active_story_id"}).AddRow(testStoryID))
+	// the round in progress is archived before its votes are cleared
+	mock.ExpectExec(`INSERT INTO thunderdome.poker_story_vote_round(.|\n)+AND ps.active = true`).
+		WithArgs(testPokerID, testStoryID, testUserID, thunderdome.StoryVoteEndRevote).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE thunderdome.poker_story SET updated_date = NOW\(\), active = false`).
+		WithArgs(testPokerID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// the new round starts with votes = '[]'
+	mock.ExpectExec(`UPDATE thunderdome.poker_story ps SET (.|\n)+votes = '\[\]'::jsonb`).
+		WithArgs(testPokerID, testStoryID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE thunderdome.poker SET (.|\n)+active_story_id = \$2`).
+		WithArgs(testPokerID, testStoryID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`FROM thunderdome.poker_story WHERE poker_id = \$1`).
+		WithArgs(testPokerID).
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	if _, err := s.RevoteStory(testPokerID, testStoryID, testUserID); err != nil {
+		t.Fatalf("RevoteStory() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRevoteStoryKeepsVotesWhenArchiveFails(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT active_story_id FROM thunderdome.poker WHERE id = \$1 FOR UPDATE`).
+		WithArgs(testPokerID).
+		WillReturnRows(sqlmock.NewRows([]string{"active_story_id"}).AddRow(testStoryID))
+	mock.ExpectExec(`INSERT INTO thunderdome.poker_story_vote_round`).
+		WithArgs(testPokerID, testStoryID, testUserID, thunderdome.StoryVoteEndRevote).
+		WillReturnError(errors.New("connection reset"))
+	// the votes are only cleared once the round they belong to is archived
+	mock.ExpectRollback()
+
+	if _, err := s.RevoteStory(testPokerID, testStoryID, testUserID); err == nil {
+		t.Fatal("RevoteStory() error = nil, want the archive error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
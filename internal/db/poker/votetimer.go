@@ -0,0 +1,73 @@
+package poker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// GetStoryVotingDeadlines gets the voting deadlines of stories being voted on with a time limit,
+// deadlines that ran out longer than lookback ago are skipped as abandoned games
+func (d *Service) GetStoryVotingDeadlines(ctx context.Context, lookback time.Duration) ([]*thunderdome.StoryVotingDeadline, error) {
+	deadlines := make([]*thunderdome.StoryVotingDeadline, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT poker_id, id, vote_deadline
+		FROM thunderdome.poker_story
+		WHERE active = true AND vote_deadline IS NOT NULL
+		AND vote_deadline > NOW() - make_interval(secs => $1);`,
+		lookback.Seconds(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get story voting deadlines query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var vd thunderdome.StoryVotingDeadline
+		if err := rows.Scan(&vd.PokerID, &vd.StoryID, &vd.Deadline); err != nil {
+			return nil, fmt.Errorf("get story voting deadlines scan error: %v", err)
+		}
+		deadlines = append(deadlines, &vd)
+	}
+
+	return deadlines, nil
+}
+
+// TimeoutStoryVoting ends voting for a story whose voting deadline has run out,
+// the deadline is cleared only while it's run out so only one caller ends the voting,
+// and clearing it commits together with ending the voting so a failure leaves the deadline to retry
+func (d *Service) TimeoutStoryVoting(ctx context.Context, pokerID string, storyID string) ([]*thunderdome.Story, error) {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		`UPDATE thunderdome.poker_story SET vote_deadline = NULL
+		WHERE id = $2 AND poker_id = $1 AND active = true AND vote_deadline <= NOW()
+		RETURNING id;`,
+		pokerID, storyID,
+	).Scan(&storyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("STORY_VOTING_NOT_EXPIRED")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("timeout story voting query error: %v", err)
+	}
+
+	if err = stopStoryVoting(tx, pokerID, storyID, thunderdome.StoryVoteEndTimeout, ""); err != nil {
+		return nil, fmt.Errorf("timeout story voting error: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return d.GetStories(pokerID, ""), nil
+}
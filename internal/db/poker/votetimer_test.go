@@ -0,0 +1,83 @@
+package poker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func TestTimeoutStoryVoting(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE thunderdome.poker_story SET vote_deadline = NULL`).
+		WithArgs(testPokerID, testStoryID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testStoryID))
+	mock.ExpectExec(`INSERT INTO thunderdome.poker_story_vote_round`).
+		WithArgs(testPokerID, testStoryID, "", thunderdome.StoryVoteEndTimeout).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// voting is stopped the same way as when the facilitator ends it
+	mock.ExpectExec(`UPDATE thunderdome.poker_story SET (.|\n)+voteend_reason = \$3`).
+		WithArgs(testPokerID, testStoryID, thunderdome.StoryVoteEndTimeout).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE thunderdome.poker SET (.|\n)+voting_locked = true`).
+		WithArgs(testPokerID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`FROM thunderdome.poker_story WHERE poker_id = \$1`).
+		WithArgs(testPokerID).
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	if _, err := s.TimeoutStoryVoting(context.Background(), testPokerID, testStoryID); err != nil {
+		t.Fatalf("TimeoutStoryVoting() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTimeoutStoryVotingKeepsDeadlineWhenStopFails(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE thunderdome.poker_story SET vote_deadline = NULL`).
+		WithArgs(testPokerID, testStoryID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testStoryID))
+	mock.ExpectExec(`INSERT INTO thunderdome.poker_story_vote_round`).
+		WithArgs(testPokerID, testStoryID, "", thunderdome.StoryVoteEndTimeout).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE thunderdome.poker_story SET (.|\n)+voteend_reason = \$3`).
+		WithArgs(testPokerID, testStoryID, thunderdome.StoryVoteEndTimeout).
+		WillReturnError(errors.New("connection reset"))
+	// the cleared deadline is rolled back so the vote timer ends the voting on its next reload
+	mock.ExpectRollback()
+
+	if _, err := s.TimeoutStoryVoting(context.Background(), testPokerID, testStoryID); err == nil {
+		t.Fatal("TimeoutStoryVoting() error = nil, want the stop voting error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTimeoutStoryVotingNotExpired(t *testing.T) {
+	s, mock := newTestService(t)
+
+	// the facilitator ended voting, or another instance timed it out, so nothing is updated
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE thunderdome.poker_story SET vote_deadline = NULL`).
+		WithArgs(testPokerID, testStoryID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	_, err := s.TimeoutStoryVoting(context.Background(), testPokerID, testStoryID)
+	if err == nil || err.Error() != "STORY_VOTING_NOT_EXPIRED" {
+		t.Fatalf("TimeoutStoryVoting() error = %v, want STORY_VOTING_NOT_EXPIRED", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
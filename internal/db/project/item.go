@@ -0,0 +1,458 @@
+package project
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/fracindex"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+const projectItemSelect = `SELECT pi.id, pi.project_id, pi.parent_id, pi.item_key, pi.title, COALESCE(pi.description, ''),
+		pi.type_id, it.type_key, pi.status_id, ist.status_key, COALESCE(ist.is_final, false),
+		pi.priority_id, ip.priority_key, pi.story_points, pi.rank, pi.start_date, pi.end_date, pi.created_by,
+		pi.external_reference_id, pi.external_reference_link, pi.created_at, pi.updated_at
+	FROM thunderdome.project_item pi
+	JOIN thunderdome.item_type it ON it.id = pi.type_id
+	JOIN thunderdome.item_status ist ON ist.id = pi.status_id
+	LEFT JOIN thunderdome.item_priority ip ON ip.id = pi.priority_id`
+
+func scanProjectItem(scanner rowScanner) (*thunderdome.ProjectItem, error) {
+	var item thunderdome.ProjectItem
+	err := scanner.Scan(
+		&item.ID,
+		&item.ProjectID,
+		&item.ParentID,
+		&item.ItemKey,
+		&item.Title,
+		&item.Description,
+		&item.TypeID,
+		&item.TypeKey,
+		&item.StatusID,
+		&item.StatusKey,
+		&item.StatusIsFinal,
+		&item.PriorityID,
+		&item.PriorityKey,
+		&item.StoryPoints,
+		&item.Rank,
+		&item.StartDate,
+		&item.EndDate,
+		&item.CreatedBy,
+		&item.ExternalReferenceID,
+		&item.ExternalReferenceLink,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// GetProjectItems retrieves a paginated list of a project's items in rank order
+func (s *Service) GetProjectItems(ctx context.Context, projectID string, filter thunderdome.ProjectItemFilter, limit int, offset int) ([]*thunderdome.ProjectItem, int, error) {
+	items := make([]*thunderdome.ProjectItem, 0)
+	var count int
+
+	const filterWhere = `WHERE pi.project_id = $1
+		AND ($2 = '' OR pi.type_id::text = $2)
+		AND ($3 = '' OR pi.status_id::text = $3)
+		AND ($4 = '' OR pi.priority_id::text = $4)
+		AND ($5 = '' OR pi.parent_id::text = $5)
+		AND (NOT $6 OR NOT COALESCE(ist.is_final, false))
+		AND (COALESCE(CARDINALITY($7::text[]), 0) = 0 OR pi.id::text = ANY($7::text[]))`
+	args := []any{projectID, filter.TypeID, filter.StatusID, filter.PriorityID, filter.ParentID, filter.Open, filter.IDs}
+
+	err := s.DB.QueryRowContext(ctx,
+		`SELECT COUNT(*)
+		FROM thunderdome.project_item pi
+		JOIN thunderdome.item_status ist ON ist.id = pi.status_id
+		`+filterWhere+`;`,
+		args...,
+	).Scan(&count)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting project items: %v", err)
+	}
+
+	rows, err := s.DB.QueryContext(ctx,
+		projectItemSelect+`
+		`+filterWhere+`
+		ORDER BY pi.rank
+		LIMIT $8 OFFSET $9;`,
+		append(args, limit, offset)...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error querying project items: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanProjectItem(rows)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("GetProjectItems row scan error", zap.Error(err))
+		} else {
+			items = append(items, item)
+		}
+	}
+
+	return items, count, nil
+}
+
+// GetProjectItemByID retrieves a project item by its ID
+func (s *Service) GetProjectItemByID(ctx context.Context, projectID string, itemID string) (*thunderdome.ProjectItem, error) {
+	item, err := scanProjectItem(s.DB.QueryRowContext(ctx,
+		projectItemSelect+`
+		WHERE pi.project_id = $1 AND pi.id = $2;`,
+		projectID, itemID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("PROJECT_ITEM_NOT_FOUND")
+	} else if err != nil {
+		return nil, fmt.Errorf("error querying project item: %v", err)
+	}
+
+	return item, nil
+}
+
+// CreateProjectItem creates a new project item in the project's initial status,
+// numbering it with the project key (e.g. PROJ-42) and ranking it last
+func (s *Service) CreateProjectItem(ctx context.Context, item *thunderdome.ProjectItem) (*thunderdome.ProjectItem, error) {
+	logger := s.Logger.With(
+		zap.String("project_id", item.ProjectID),
+		zap.String("user_id", item.CreatedBy),
+	)
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error beginning create project item transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var projectKey string
+	var itemNumber int
+	err = tx.QueryRowContext(ctx,
+		`UPDATE thunderdome.project SET item_sequence = item_sequence + 1
+		WHERE id = $1
+		RETURNING project_key, item_sequence;`,
+		item.ProjectID,
+	).Scan(&projectKey, &itemNumber)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("PROJECT_NOT_FOUND")
+	} else if err != nil {
+		return nil, fmt.Errorf("error incrementing project item sequence: %v", err)
+	}
+
+	if err := validateProjectItemRefs(ctx, tx, item); err != nil {
+		return nil, err
+	}
+
+	var statusID string
+	err = tx.QueryRowContext(ctx,
+		`WITH `+projectScopeCTE+`
+		SELECT x.id
+		FROM thunderdome.item_status x
+		JOIN project_scope ps ON `+projectScopeAvailable+`
+		WHERE x.is_initial AND COALESCE(x.is_active, true)
+		ORDER BY `+projectScopeSpecificity+` DESC
+		LIMIT 1;`,
+		item.ProjectID,
+	).Scan(&statusID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("ITEM_STATUS_INITIAL_NOT_FOUND")
+	} else if err != nil {
+		return nil, fmt.Errorf("error querying initial item status: %v", err)
+	}
+
+	var lastRank *string
+	if err := tx.QueryRowContext(ctx,
+		`SELECT MAX(rank) FROM thunderdome.project_item WHERE project_id = $1;`,
+		item.ProjectID,
+	).Scan(&lastRank); err != nil {
+		return nil, fmt.Errorf("error querying project item rank: %v", err)
+	}
+
+	rank, err := fracindex.KeyBetween(lastRank, nil)
+	if err != nil {
+		logger.Error("get project item rank between error", zap.Error(err), zap.Stringp("rank_a", lastRank))
+		return nil, err
+	}
+	if rank == nil {
+		return nil, errors.New("project item rank is nil")
+	}
+
+	var itemID string
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.project_item (
+			project_id, parent_id, item_key, title, description, type_id, status_id, priority_id,
+			story_points, rank, start_date, end_date, created_by, external_reference_id, external_reference_link
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING id;`,
+		item.ProjectID,
+		item.ParentID,
+		fmt.Sprintf("%s-%d", projectKey, itemNumber),
+		item.Title,
+		s.HTMLSanitizerPolicy.Sanitize(item.Description),
+		item.TypeID,
+		statusID,
+		item.PriorityID,
+		item.StoryPoints,
+		*rank,
+		item.StartDate,
+		item.EndDate,
+		item.CreatedBy,
+		item.ExternalReferenceID,
+		item.ExternalReferenceLink,
+	).Scan(&itemID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating project item: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing create project item transaction: %v", err)
+	}
+
+	return s.GetProjectItemByID(ctx, item.ProjectID, itemID)
+}
+
+// UpdateProjectItem updates a project item's details, status changes go through TransitionProjectItem
+func (s *Service) UpdateProjectItem(ctx context.Context, item *thunderdome.ProjectItem) (*thunderdome.ProjectItem, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error beginning update project item transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := validateProjectItemRefs(ctx, tx, item); err != nil {
+		return nil, err
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE thunderdome.project_item
+		SET parent_id = $3, title = $4, description = $5, type_id = $6, priority_id = $7, story_points = $8,
+			start_date = $9, end_date = $10, external_reference_id = $11, external_reference_link = $12,
+			updated_at = NOW()
+		WHERE project_id = $1 AND id = $2;`,
+		item.ProjectID,
+		item.ID,
+		item.ParentID,
+		item.Title,
+		s.HTMLSanitizerPolicy.Sanitize(item.Description),
+		item.TypeID,
+		item.PriorityID,
+		item.StoryPoints,
+		item.StartDate,
+		item.EndDate,
+		item.ExternalReferenceID,
+		item.ExternalReferenceLink,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error updating project item: %v", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return nil, errors.New("PROJECT_ITEM_NOT_FOUND")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing update project item transaction: %v", err)
+	}
+
+	return s.GetProjectItemByID(ctx, item.ProjectID, item.ID)
+}
+
+// TransitionProjectItem moves a project item to another status available to the project.
+// Leaving the initial status stamps the start date, entering a final status stamps the end date
+// and reopening a finished item clears it, items can not be finished while they have open children.
+func (s *Service) TransitionProjectItem(ctx context.Context, projectID string, itemID string, statusID string) (*thunderdome.ProjectItem, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error beginning transition project item transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var isInitial, isFinal bool
+	err = tx.QueryRowContext(ctx,
+		`WITH `+projectScopeCTE+`
+		SELECT COALESCE(x.is_initial, false), COALESCE(x.is_final, false)
+		FROM thunderdome.item_status x
+		JOIN project_scope ps ON `+projectScopeAvailable+`
+		WHERE x.id = $2 AND COALESCE(x.is_active, true);`,
+		projectID, statusID,
+	).Scan(&isInitial, &isFinal)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("ITEM_STATUS_INVALID")
+	} else if err != nil {
+		return nil, fmt.Errorf("error querying item status: %v", err)
+	}
+
+	if isFinal {
+		var openChildren int
+		if err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*)
+			FROM thunderdome.project_item c
+			JOIN thunderdome.item_status cs ON cs.id = c.status_id
+			WHERE c.parent_id = $1 AND NOT COALESCE(cs.is_final, false);`,
+			itemID,
+		).Scan(&openChildren); err != nil {
+			return nil, fmt.Errorf("error querying project item children: %v", err)
+		}
+		if openChildren > 0 {
+			return nil, errors.New("ITEM_HAS_OPEN_CHILDREN")
+		}
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE thunderdome.project_item pi
+		SET status_id = $3,
+			start_date = CASE WHEN $4 THEN pi.start_date ELSE COALESCE(pi.start_date, NOW()) END,
+			end_date = CASE
+				WHEN NOT $5 THEN NULL
+				WHEN COALESCE(cs.is_final, false) THEN pi.end_date
+				ELSE NOW()
+			END,
+			updated_at = NOW()
+		FROM thunderdome.item_status cs
+		WHERE pi.project_id = $1 AND pi.id = $2 AND cs.id = pi.status_id;`,
+		projectID, itemID, statusID, isInitial, isFinal,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error transitioning project item: %v", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return nil, errors.New("PROJECT_ITEM_NOT_FOUND")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transition project item transaction: %v", err)
+	}
+
+	return s.GetProjectItemByID(ctx, projectID, itemID)
+}
+
+// MoveProjectItem re-ranks a project item before another item, or last when placeBefore is empty
+func (s *Service) MoveProjectItem(ctx context.Context, projectID string, itemID string, placeBefore string) (*thunderdome.ProjectItem, error) {
+	var betweenAkey *string
+	var betweenBkey *string
+	logger := s.Logger.With(
+		zap.String("project_id", projectID),
+		zap.String("item_id", itemID),
+		zap.String("place_before", placeBefore),
+	)
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error beginning move project item transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if placeBefore == "" {
+		if err := tx.QueryRowContext(ctx,
+			`SELECT MAX(rank) FROM thunderdome.project_item WHERE project_id = $1 AND id <> $2;`,
+			projectID, itemID,
+		).Scan(&betweenAkey); err != nil {
+			return nil, fmt.Errorf("error querying project item rank: %v", err)
+		}
+	} else {
+		err := tx.QueryRowContext(ctx,
+			`SELECT b.rank,
+				(SELECT MAX(a.rank) FROM thunderdome.project_item a
+				 WHERE a.project_id = b.project_id AND a.rank < b.rank AND a.id <> $3)
+			FROM thunderdome.project_item b
+			WHERE b.project_id = $1 AND b.id = $2;`,
+			projectID, placeBefore, itemID,
+		).Scan(&betweenBkey, &betweenAkey)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("PROJECT_ITEM_NOT_FOUND")
+		} else if err != nil {
+			return nil, fmt.Errorf("error querying project item rank: %v", err)
+		}
+	}
+
+	rank, err := fracindex.KeyBetween(betweenAkey, betweenBkey)
+	if err != nil {
+		logger.Error("get project item rank between error", zap.Error(err),
+			zap.Stringp("rank_a", betweenAkey), zap.Stringp("rank_b", betweenBkey))
+		return nil, err
+	}
+	if rank == nil {
+		return nil, errors.New("project item rank is nil")
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE thunderdome.project_item SET rank = $3, updated_at = NOW() WHERE project_id = $1 AND id = $2;`,
+		projectID, itemID, *rank,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error moving project item: %v", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return nil, errors.New("PROJECT_ITEM_NOT_FOUND")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing move project item transaction: %v", err)
+	}
+
+	return s.GetProjectItemByID(ctx, projectID, itemID)
+}
+
+// DeleteProjectItem deletes a project item, its child items are detached rather than deleted
+func (s *Service) DeleteProjectItem(ctx context.Context, projectID string, itemID string) error {
+	result, err := s.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.project_item WHERE project_id = $1 AND id = $2;`,
+		projectID, itemID,
+	)
+	if err != nil {
+		return fmt.Errorf("error deleting project item: %v", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return errors.New("PROJECT_ITEM_NOT_FOUND")
+	}
+
+	return nil
+}
+
+// validateProjectItemRefs ensures the item's type and priority are available to its project
+// and that its parent belongs to the same project without creating a cycle
+func validateProjectItemRefs(ctx context.Context, tx *sql.Tx, item *thunderdome.ProjectItem) error {
+	var typeValid, priorityValid, parentValid bool
+	err := tx.QueryRowContext(ctx,
+		`WITH RECURSIVE `+projectScopeCTE+`,
+		ancestors AS (
+			SELECT id, parent_id FROM thunderdome.project_item WHERE id = $4::uuid AND project_id = $1
+			UNION
+			SELECT pi.id, pi.parent_id FROM thunderdome.project_item pi JOIN ancestors a ON pi.id = a.parent_id
+		)
+		SELECT
+			EXISTS (
+				SELECT 1 FROM thunderdome.item_type x JOIN project_scope ps ON `+projectScopeAvailable+`
+				WHERE x.id = $2 AND COALESCE(x.is_active, true)
+			),
+			$3::uuid IS NULL OR EXISTS (
+				SELECT 1 FROM thunderdome.item_priority x JOIN project_scope ps ON `+projectScopeAvailable+`
+				WHERE x.id = $3 AND COALESCE(x.is_active, true)
+			),
+			$4::uuid IS NULL OR (
+				EXISTS (SELECT 1 FROM ancestors WHERE id = $4::uuid)
+				AND NOT EXISTS (SELECT 1 FROM ancestors WHERE id::text = $5)
+			);`,
+		item.ProjectID, item.TypeID, item.PriorityID, item.ParentID, item.ID,
+	).Scan(&typeValid, &priorityValid, &parentValid)
+	if err != nil {
+		return fmt.Errorf("error validating project item: %v", err)
+	}
+
+	switch {
+	case !typeValid:
+		return errors.New("ITEM_TYPE_INVALID")
+	case !priorityValid:
+		return errors.New("ITEM_PRIORITY_INVALID")
+	case !parentValid:
+		return errors.New("ITEM_PARENT_INVALID")
+	}
+
+	return nil
+}
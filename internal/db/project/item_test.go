@@ -0,0 +1,238 @@
+package project
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const (
+	testProjectID = "6d2c1f8e-0a4b-4c1e-9d7f-2b3a4c5d6e7f"
+	testItemID    = "1f2e3d4c-5b6a-4978-8a9b-0c1d2e3f4a5b"
+	testTypeID    = "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"
+	testStatusID  = "b2c3d4e5-f6a7-4b8c-9d0e-1f2a3b4c5d6e"
+	testUserID    = "c3d4e5f6-a7b8-4c9d-0e1f-2a3b4c5d6e7f"
+)
+
+func newTestService(t *testing.T) (*Service, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return &Service{
+		DB:                  db,
+		Logger:              otelzap.New(zap.NewNop()),
+		HTMLSanitizerPolicy: bluemonday.UGCPolicy(),
+	}, mock
+}
+
+func projectItemRow(itemKey string, rank string) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{
+		"id", "project_id", "parent_id", "item_key", "title", "description", "type_id", "type_key",
+		"status_id", "status_key", "is_final", "priority_id", "priority_key", "story_points", "rank",
+		"start_date", "end_date", "created_by", "external_reference_id", "external_reference_link",
+		"created_at", "updated_at",
+	}).AddRow(
+		testItemID, testProjectID, nil, itemKey, "Item", "", testTypeID, "story",
+		testStatusID, "not-started", false, nil, nil, nil, rank,
+		nil, nil, testUserID, nil, nil,
+		now, now,
+	)
+}
+
+func TestCreateProjectItem(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE thunderdome.project SET item_sequence = item_sequence \+ 1`).
+		WithArgs(testProjectID).
+		WillReturnRows(sqlmock.NewRows([]string{"project_key", "item_sequence"}).AddRow("PROJ", 42))
+	mock.ExpectQuery(`ancestors AS`).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "priority", "parent"}).AddRow(true, true, true))
+	mock.ExpectQuery(`WHERE x.is_initial`).
+		WithArgs(testProjectID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testStatusID))
+	mock.ExpectQuery(`SELECT MAX\(rank\) FROM thunderdome.project_item`).
+		WithArgs(testProjectID).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow("a0"))
+	mock.ExpectQuery(`INSERT INTO thunderdome.project_item`).
+		WithArgs(testProjectID, nil, "PROJ-42", "Item", "", testTypeID, testStatusID, nil,
+			nil, "a1", nil, nil, testUserID, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testItemID))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`WHERE pi.project_id = \$1 AND pi.id = \$2`).
+		WithArgs(testProjectID, testItemID).
+		WillReturnRows(projectItemRow("PROJ-42", "a1"))
+
+	item, err := s.CreateProjectItem(context.Background(), &thunderdome.ProjectItem{
+		ProjectID: testProjectID,
+		Title:     "Item",
+		TypeID:    testTypeID,
+		CreatedBy: testUserID,
+	})
+	if err != nil {
+		t.Fatalf("CreateProjectItem() error = %v", err)
+	}
+	if item.ItemKey != "PROJ-42" || item.Rank != "a1" {
+		t.Errorf("CreateProjectItem() = %s ranked %s, want PROJ-42 ranked a1", item.ItemKey, item.Rank)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCreateProjectItemErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(mock sqlmock.Sqlmock)
+		wantErr string
+	}{
+		{
+			name: "project not found",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`UPDATE thunderdome.project SET item_sequence`).WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: "PROJECT_NOT_FOUND",
+		},
+		{
+			name: "type not available to project",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`UPDATE thunderdome.project SET item_sequence`).
+					WillReturnRows(sqlmock.NewRows([]string{"project_key", "item_sequence"}).AddRow("PROJ", 1))
+				mock.ExpectQuery(`ancestors AS`).
+					WillReturnRows(sqlmock.NewRows([]string{"type", "priority", "parent"}).AddRow(false, true, true))
+			},
+			wantErr: "ITEM_TYPE_INVALID",
+		},
+		{
+			name: "parent in another project or a cycle",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`UPDATE thunderdome.project SET item_sequence`).
+					WillReturnRows(sqlmock.NewRows([]string{"project_key", "item_sequence"}).AddRow("PROJ", 1))
+				mock.ExpectQuery(`ancestors AS`).
+					WillReturnRows(sqlmock.NewRows([]string{"type", "priority", "parent"}).AddRow(true, true, false))
+			},
+			wantErr: "ITEM_PARENT_INVALID",
+		},
+		{
+			name: "no initial status",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`UPDATE thunderdome.project SET item_sequence`).
+					WillReturnRows(sqlmock.NewRows([]string{"project_key", "item_sequence"}).AddRow("PROJ", 1))
+				mock.ExpectQuery(`ancestors AS`).
+					WillReturnRows(sqlmock.NewRows([]string{"type", "priority", "parent"}).AddRow(true, true, true))
+				mock.ExpectQuery(`WHERE x.is_initial`).WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: "ITEM_STATUS_INITIAL_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newTestService(t)
+			mock.ExpectBegin()
+			tt.setup(mock)
+			// the sequence increment is rolled back with the rest of the transaction
+			mock.ExpectRollback()
+
+			_, err := s.CreateProjectItem(context.Background(), &thunderdome.ProjectItem{
+				ProjectID: testProjectID,
+				Title:     "Item",
+				TypeID:    testTypeID,
+				CreatedBy: testUserID,
+			})
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("CreateProjectItem() error = %v, want %s", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestUpdateProjectItemNotFound(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`ancestors AS`).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "priority", "parent"}).AddRow(true, true, true))
+	mock.ExpectExec(`UPDATE thunderdome.project_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err := s.UpdateProjectItem(context.Background(), &thunderdome.ProjectItem{
+		ID:        testItemID,
+		ProjectID: testProjectID,
+		Title:     "Item",
+		TypeID:    testTypeID,
+	})
+	if err == nil || err.Error() != "PROJECT_ITEM_NOT_FOUND" {
+		t.Fatalf("UpdateProjectItem() error = %v, want PROJECT_ITEM_NOT_FOUND", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTransitionProjectItemOpenChildren(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT COALESCE\(x.is_initial, false\), COALESCE\(x.is_final, false\)`).
+		WithArgs(testProjectID, testStatusID).
+		WillReturnRows(sqlmock.NewRows([]string{"is_initial", "is_final"}).AddRow(false, true))
+	mock.ExpectQuery(`WHERE c.parent_id = \$1`).
+		WithArgs(testItemID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectRollback()
+
+	_, err := s.TransitionProjectItem(context.Background(), testProjectID, testItemID, testStatusID)
+	if err == nil || err.Error() != "ITEM_HAS_OPEN_CHILDREN" {
+		t.Fatalf("TransitionProjectItem() error = %v, want ITEM_HAS_OPEN_CHILDREN", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDeleteProjectItem(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		wantErr      string
+	}{
+		{name: "deleted", rowsAffected: 1},
+		{name: "not in project", rowsAffected: 0, wantErr: "PROJECT_ITEM_NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newTestService(t)
+			mock.ExpectExec(`DELETE FROM thunderdome.project_item WHERE project_id = \$1 AND id = \$2`).
+				WithArgs(testProjectID, testItemID).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			err := s.DeleteProjectItem(context.Background(), testProjectID, testItemID)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("DeleteProjectItem() error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("DeleteProjectItem() error = %v, want %s", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package project
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+const itemPriorityColumns = `x.id, x.name, x.priority_key, COALESCE(x.description, ''), x.color::text, x.priority_level,
+	COALESCE(x.is_active, true), x.organization_id, x.department_id, x.team_id, x.created_at, x.updated_at`
+
+func scanItemPriority(scanner rowScanner) (*thunderdome.ItemPriority, error) {
+	var p thunderdome.ItemPriority
+	err := scanner.Scan(
+		&p.ID,
+		&p.Name,
+		&p.PriorityKey,
+		&p.Description,
+		&p.Color,
+		&p.PriorityLevel,
+		&p.IsActive,
+		&p.OrganizationID,
+		&p.DepartmentID,
+		&p.TeamID,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// GetItemPriorities retrieves the item priorities defined directly in the given scope
+func (s *Service) GetItemPriorities(ctx context.Context, scope thunderdome.ItemScope) ([]*thunderdome.ItemPriority, error) {
+	priorities := make([]*thunderdome.ItemPriority, 0)
+
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT `+itemPriorityColumns+`
+		FROM thunderdome.item_priority x
+		WHERE x.organization_id IS NOT DISTINCT FROM $1
+		AND x.department_id IS NOT DISTINCT FROM $2
+		AND x.team_id IS NOT DISTINCT FROM $3
+		ORDER BY x.priority_level, x.name;`,
+		scope.OrganizationID, scope.DepartmentID, scope.TeamID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error querying item priorities: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p, err := scanItemPriority(rows)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("GetItemPriorities row scan error", zap.Error(err))
+		} else {
+			priorities = append(priorities, p)
+		}
+	}
+
+	return priorities, nil
+}
+
+// GetProjectItemPriorities retrieves the active item priorities available to a project,
+// global priorities plus those of the project's organization, department and team
+func (s *Service) GetProjectItemPriorities(ctx context.Context, projectID string) ([]*thunderdome.ItemPriority, error) {
+	priorities := make([]*thunderdome.ItemPriority, 0)
+
+	rows, err := s.DB.QueryContext(ctx,
+		`WITH `+projectScopeCTE+`
+		SELECT `+itemPriorityColumns+`
+		FROM thunderdome.item_priority x
+		JOIN project_scope ps ON `+projectScopeAvailable+`
+		WHERE COALESCE(x.is_active, true)
+		ORDER BY x.priority_level, `+projectScopeSpecificity+` DESC, x.name;`,
+		projectID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error querying project item priorities: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p, err := scanItemPriority(rows)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("GetProjectItemPriorities row scan error", zap.Error(err))
+		} else {
+			priorities = append(priorities, p)
+		}
+	}
+
+	return priorities, nil
+}
+
+// CreateItemPriority creates an item priority within the scope set on the item priority
+func (s *Service) CreateItemPriority(ctx context.Context, priority *thunderdome.ItemPriority) (*thunderdome.ItemPriority, error) {
+	if err := s.validateItemScope(ctx, priority.ItemScope); err != nil {
+		return nil, err
+	}
+
+	created, err := scanItemPriority(s.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.item_priority AS x
+			(name, priority_key, description, color, priority_level, is_active, organization_id, department_id, team_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+itemPriorityColumns+`;`,
+		priority.Name,
+		priority.PriorityKey,
+		priority.Description,
+		priority.Color,
+		priority.PriorityLevel,
+		priority.IsActive,
+		priority.OrganizationID,
+		priority.DepartmentID,
+		priority.TeamID,
+	))
+	if err != nil {
+		return nil, itemConfigError(err, "ITEM_PRIORITY", "error creating item priority")
+	}
+
+	return created, nil
+}
+
+// UpdateItemPriority updates an item priority, only matching within the scope set on the item priority
+func (s *Service) UpdateItemPriority(ctx context.Context, priority *thunderdome.ItemPriority) (*thunderdome.ItemPriority, error) {
+	updated, err := scanItemPriority(s.DB.QueryRowContext(ctx,
+		`UPDATE thunderdome.item_priority AS x
+		SET name = $2, priority_key = $3, description = $4, color = $5, priority_level = $6, is_active = $7,
+			updated_at = NOW()
+		WHERE x.id = $1
+		AND x.organization_id IS NOT DISTINCT FROM $8
+		AND x.department_id IS NOT DISTINCT FROM $9
+		AND x.team_id IS NOT DISTINCT FROM $10
+		RETURNING `+itemPriorityColumns+`;`,
+		priority.ID,
+		priority.Name,
+		priority.PriorityKey,
+		priority.Description,
+		priority.Color,
+		priority.PriorityLevel,
+		priority.IsActive,
+		priority.OrganizationID,
+		priority.DepartmentID,
+		priority.TeamID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("ITEM_PRIORITY_NOT_FOUND")
+	} else if err != nil {
+		return nil, itemConfigError(err, "ITEM_PRIORITY", "error updating item priority")
+	}
+
+	return updated, nil
+}
+
+// DeleteItemPriority deletes an item priority from the given scope, priorities still used by items can not be deleted
+func (s *Service) DeleteItemPriority(ctx context.Context, scope thunderdome.ItemScope, priorityID string) error {
+	result, err := s.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.item_priority
+		WHERE id = $1
+		AND organization_id IS NOT DISTINCT FROM $2
+		AND department_id IS NOT DISTINCT FROM $3
+		AND team_id IS NOT DISTINCT FROM $4;`,
+		priorityID, scope.OrganizationID, scope.DepartmentID, scope.TeamID,
+	)
+	if err != nil {
+		return itemConfigError(err, "ITEM_PRIORITY", "error deleting item priority")
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return errors.New("ITEM_PRIORITY_NOT_FOUND")
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+const errItemScopeInvalid = "ITEM_SCOPE_INVALID"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// projectScopeCTE resolves the effective organization, department and team of the project in $1,
+// so that a team project inside a department still inherits department and organization item settings
+const projectScopeCTE = `project_scope AS (
+	SELECT p.id,
+		COALESCE(p.organization_id, pd.organization_id, t.organization_id) AS organization_id,
+		COALESCE(p.department_id, t.department_id) AS department_id,
+		p.team_id
+	FROM thunderdome.project p
+	LEFT JOIN thunderdome.team t ON t.id = p.team_id
+	LEFT JOIN thunderdome.organization_department pd ON pd.id = p.department_id
+	WHERE p.id = $1
+)`
+
+// projectScopeAvailable matches item type/status/priority rows (aliased x) usable by the project_scope (aliased ps)
+const projectScopeAvailable = `(
+	(x.organization_id IS NULL AND x.department_id IS NULL AND x.team_id IS NULL)
+	OR (x.organization_id = ps.organization_id AND x.department_id IS NULL AND x.team_id IS NULL)
+	OR (x.department_id = ps.department_id AND x.team_id IS NULL)
+	OR (x.team_id = ps.team_id)
+)`
+
+// projectScopeSpecificity ranks rows (aliased x) so that team settings win over department, organization and global
+const projectScopeSpecificity = `CASE
+	WHEN x.team_id IS NOT NULL THEN 3
+	WHEN x.department_id IS NOT NULL THEN 2
+	WHEN x.organization_id IS NOT NULL THEN 1
+	ELSE 0
+END`
+
+// validateItemScope verifies the scope follows the organization > department > team hierarchy
+// required by the item type, status and priority tables
+func (s *Service) validateItemScope(ctx context.Context, scope thunderdome.ItemScope) error {
+	hasOrg := scope.OrganizationID != nil && *scope.OrganizationID != ""
+	hasDept := scope.DepartmentID != nil && *scope.DepartmentID != ""
+	hasTeam := scope.TeamID != nil && *scope.TeamID != ""
+
+	switch {
+	case hasTeam && (!hasDept || !hasOrg):
+		return errors.New(errItemScopeInvalid)
+	case hasDept && !hasOrg:
+		return errors.New(errItemScopeInvalid)
+	case !hasDept:
+		return nil
+	}
+
+	var valid bool
+	err := s.DB.QueryRowContext(ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM thunderdome.organization_department d
+			WHERE d.id = $2 AND d.organization_id = $1
+			AND ($3::uuid IS NULL OR EXISTS (
+				SELECT 1 FROM thunderdome.team t WHERE t.id = $3 AND t.department_id = d.id
+			))
+		);`,
+		scope.OrganizationID, scope.DepartmentID, scope.TeamID,
+	).Scan(&valid)
+	if err != nil {
+		return fmt.Errorf("error validating item scope: %v", err)
+	}
+	if !valid {
+		return errors.New(errItemScopeInvalid)
+	}
+
+	return nil
+}
+
+// itemConfigError translates constraint violations for item types, statuses and priorities
+// into application error codes prefixed with the entity code (e.g. ITEM_TYPE_ALREADY_EXISTS)
+func itemConfigError(err error, prefix string, message string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return errors.New(prefix + "_ALREADY_EXISTS")
+		case "23503":
+			return errors.New(prefix + "_IN_USE")
+		case "23514", "22P02":
+			return errors.New(prefix + "_INVALID")
+		}
+	}
+
+	return fmt.Errorf("%s: %v", message, err)
+}
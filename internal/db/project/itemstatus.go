@@ -0,0 +1,237 @@
+package project
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+const itemStatusColumns = `x.id, x.name, x.status_key, COALESCE(x.description, ''), x.color::text,
+	COALESCE(x.is_initial, false), COALESCE(x.is_final, false), COALESCE(x.is_active, true), COALESCE(x.sort_order, 0),
+	x.organization_id, x.department_id, x.team_id, x.created_at, x.updated_at`
+
+func scanItemStatus(scanner rowScanner) (*thunderdome.ItemStatus, error) {
+	var st thunderdome.ItemStatus
+	err := scanner.Scan(
+		&st.ID,
+		&st.Name,
+		&st.StatusKey,
+		&st.Description,
+		&st.Color,
+		&st.IsInitial,
+		&st.IsFinal,
+		&st.IsActive,
+		&st.SortOrder,
+		&st.OrganizationID,
+		&st.DepartmentID,
+		&st.TeamID,
+		&st.CreatedAt,
+		&st.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &st, nil
+}
+
+// GetItemStatuses retrieves the item statuses defined directly in the given scope
+func (s *Service) GetItemStatuses(ctx context.Context, scope thunderdome.ItemScope) ([]*thunderdome.ItemStatus, error) {
+	statuses := make([]*thunderdome.ItemStatus, 0)
+
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT `+itemStatusColumns+`
+		FROM thunderdome.item_status x
+		WHERE x.organization_id IS NOT DISTINCT FROM $1
+		AND x.department_id IS NOT DISTINCT FROM $2
+		AND x.team_id IS NOT DISTINCT FROM $3
+		ORDER BY x.sort_order, x.name;`,
+		scope.OrganizationID, scope.DepartmentID, scope.TeamID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error querying item statuses: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		st, err := scanItemStatus(rows)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("GetItemStatuses row scan error", zap.Error(err))
+		} else {
+			statuses = append(statuses, st)
+		}
+	}
+
+	return statuses, nil
+}
+
+// GetProjectItemStatuses retrieves the active item statuses available to a project,
+// global statuses plus those of the project's organization, department and team
+func (s *Service) GetProjectItemStatuses(ctx context.Context, projectID string) ([]*thunderdome.ItemStatus, error) {
+	statuses := make([]*thunderdome.ItemStatus, 0)
+
+	rows, err := s.DB.QueryContext(ctx,
+		`WITH `+projectScopeCTE+`
+		SELECT `+itemStatusColumns+`
+		FROM thunderdome.item_status x
+		JOIN project_scope ps ON `+projectScopeAvailable+`
+		WHERE COALESCE(x.is_active, true)
+		ORDER BY x.sort_order, `+projectScopeSpecificity+` DESC, x.name;`,
+		projectID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error querying project item statuses: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		st, err := scanItemStatus(rows)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("GetProjectItemStatuses row scan error", zap.Error(err))
+		} else {
+			statuses = append(statuses, st)
+		}
+	}
+
+	return statuses, nil
+}
+
+// CreateItemStatus creates an item status within the scope set on the status,
+// when marked initial any other initial status in the same scope is unmarked
+func (s *Service) CreateItemStatus(ctx context.Context, status *thunderdome.ItemStatus) (*thunderdome.ItemStatus, error) {
+	if err := s.validateItemScope(ctx, status.ItemScope); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error beginning create item status transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if status.IsInitial {
+		if err := clearInitialItemStatus(ctx, tx, status.ItemScope, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := scanItemStatus(tx.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.item_status AS x
+			(name, status_key, description, color, is_initial, is_final, is_active, sort_order,
+			organization_id, department_id, team_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING `+itemStatusColumns+`;`,
+		status.Name,
+		status.StatusKey,
+		status.Description,
+		status.Color,
+		status.IsInitial,
+		status.IsFinal,
+		status.IsActive,
+		status.SortOrder,
+		status.OrganizationID,
+		status.DepartmentID,
+		status.TeamID,
+	))
+	if err != nil {
+		return nil, itemConfigError(err, "ITEM_STATUS", "error creating item status")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing create item status transaction: %v", err)
+	}
+
+	return created, nil
+}
+
+// UpdateItemStatus updates an item status, only matching within the scope set on the status,
+// when marked initial any other initial status in the same scope is unmarked
+func (s *Service) UpdateItemStatus(ctx context.Context, status *thunderdome.ItemStatus) (*thunderdome.ItemStatus, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error beginning update item status transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if status.IsInitial {
+		if err := clearInitialItemStatus(ctx, tx, status.ItemScope, status.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := scanItemStatus(tx.QueryRowContext(ctx,
+		`UPDATE thunderdome.item_status AS x
+		SET name = $2, status_key = $3, description = $4, color = $5, is_initial = $6, is_final = $7,
+			is_active = $8, sort_order = $9, updated_at = NOW()
+		WHERE x.id = $1
+		AND x.organization_id IS NOT DISTINCT FROM $10
+		AND x.department_id IS NOT DISTINCT FROM $11
+		AND x.team_id IS NOT DISTINCT FROM $12
+		RETURNING `+itemStatusColumns+`;`,
+		status.ID,
+		status.Name,
+		status.StatusKey,
+		status.Description,
+		status.Color,
+		status.IsInitial,
+		status.IsFinal,
+		status.IsActive,
+		status.SortOrder,
+		status.OrganizationID,
+		status.DepartmentID,
+		status.TeamID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("ITEM_STATUS_NOT_FOUND")
+	} else if err != nil {
+		return nil, itemConfigError(err, "ITEM_STATUS", "error updating item status")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing update item status transaction: %v", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteItemStatus deletes an item status from the given scope, statuses still used by items can not be deleted
+func (s *Service) DeleteItemStatus(ctx context.Context, scope thunderdome.ItemScope, statusID string) error {
+	result, err := s.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.item_status
+		WHERE id = $1
+		AND organization_id IS NOT DISTINCT FROM $2
+		AND department_id IS NOT DISTINCT FROM $3
+		AND team_id IS NOT DISTINCT FROM $4;`,
+		statusID, scope.OrganizationID, scope.DepartmentID, scope.TeamID,
+	)
+	if err != nil {
+		return itemConfigError(err, "ITEM_STATUS", "error deleting item status")
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return errors.New("ITEM_STATUS_NOT_FOUND")
+	}
+
+	return nil
+}
+
+// clearInitialItemStatus unmarks the initial status of a scope so only one initial status exists per scope
+func clearInitialItemStatus(ctx context.Context, tx *sql.Tx, scope thunderdome.ItemScope, exceptID string) error {
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE thunderdome.item_status SET is_initial = false, updated_at = NOW()
+		WHERE is_initial
+		AND organization_id IS NOT DISTINCT FROM $1
+		AND department_id IS NOT DISTINCT FROM $2
+		AND team_id IS NOT DISTINCT FROM $3
+		AND id::text <> $4;`,
+		scope.OrganizationID, scope.DepartmentID, scope.TeamID, exceptID,
+	); err != nil {
+		return fmt.Errorf("error clearing initial item status: %v", err)
+	}
+
+	return nil
+}
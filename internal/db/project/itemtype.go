@@ -0,0 +1,174 @@
+package project
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+const itemTypeColumns = `x.id, x.name, x.type_key, COALESCE(x.description, ''), x.color::text, COALESCE(x.is_active, true),
+	x.organization_id, x.department_id, x.team_id, x.created_at, x.updated_at`
+
+func scanItemType(scanner rowScanner) (*thunderdome.ItemType, error) {
+	var t thunderdome.ItemType
+	err := scanner.Scan(
+		&t.ID,
+		&t.Name,
+		&t.TypeKey,
+		&t.Description,
+		&t.Color,
+		&t.IsActive,
+		&t.OrganizationID,
+		&t.DepartmentID,
+		&t.TeamID,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// GetItemTypes retrieves the item types defined directly in the given scope
+func (s *Service) GetItemTypes(ctx context.Context, scope thunderdome.ItemScope) ([]*thunderdome.ItemType, error) {
+	itemTypes := make([]*thunderdome.ItemType, 0)
+
+	rows, err := s.DB.QueryContext(ctx,
+		`SELECT `+itemTypeColumns+`
+		FROM thunderdome.item_type x
+		WHERE x.organization_id IS NOT DISTINCT FROM $1
+		AND x.department_id IS NOT DISTINCT FROM $2
+		AND x.team_id IS NOT DISTINCT FROM $3
+		ORDER BY x.name;`,
+		scope.OrganizationID, scope.DepartmentID, scope.TeamID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error querying item types: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanItemType(rows)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("GetItemTypes row scan error", zap.Error(err))
+		} else {
+			itemTypes = append(itemTypes, t)
+		}
+	}
+
+	return itemTypes, nil
+}
+
+// GetProjectItemTypes retrieves the active item types available to a project,
+// global types plus those of the project's organization, department and team
+func (s *Service) GetProjectItemTypes(ctx context.Context, projectID string) ([]*thunderdome.ItemType, error) {
+	itemTypes := make([]*thunderdome.ItemType, 0)
+
+	rows, err := s.DB.QueryContext(ctx,
+		`WITH `+projectScopeCTE+`
+		SELECT `+itemTypeColumns+`
+		FROM thunderdome.item_type x
+		JOIN project_scope ps ON `+projectScopeAvailable+`
+		WHERE COALESCE(x.is_active, true)
+		ORDER BY `+projectScopeSpecificity+` DESC, x.name;`,
+		projectID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error querying project item types: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanItemType(rows)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("GetProjectItemTypes row scan error", zap.Error(err))
+		} else {
+			itemTypes = append(itemTypes, t)
+		}
+	}
+
+	return itemTypes, nil
+}
+
+// CreateItemType creates an item type within the scope set on the item type
+func (s *Service) CreateItemType(ctx context.Context, itemType *thunderdome.ItemType) (*thunderdome.ItemType, error) {
+	if err := s.validateItemScope(ctx, itemType.ItemScope); err != nil {
+		return nil, err
+	}
+
+	created, err := scanItemType(s.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.item_type AS x
+			(name, type_key, description, color, is_active, organization_id, department_id, team_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+itemTypeColumns+`;`,
+		itemType.Name,
+		itemType.TypeKey,
+		itemType.Description,
+		itemType.Color,
+		itemType.IsActive,
+		itemType.OrganizationID,
+		itemType.DepartmentID,
+		itemType.TeamID,
+	))
+	if err != nil {
+		return nil, itemConfigError(err, "ITEM_TYPE", "error creating item type")
+	}
+
+	return created, nil
+}
+
+// UpdateItemType updates an item type, only matching within the scope set on the item type
+func (s *Service) UpdateItemType(ctx context.Context, itemType *thunderdome.ItemType) (*thunderdome.ItemType, error) {
+	updated, err := scanItemType(s.DB.QueryRowContext(ctx,
+		`UPDATE thunderdome.item_type AS x
+		SET name = $2, type_key = $3, description = $4, color = $5, is_active = $6, updated_at = NOW()
+		WHERE x.id = $1
+		AND x.organization_id IS NOT DISTINCT FROM $7
+		AND x.department_id IS NOT DISTINCT FROM $8
+		AND x.team_id IS NOT DISTINCT FROM $9
+		RETURNING `+itemTypeColumns+`;`,
+		itemType.ID,
+		itemType.Name,
+		itemType.TypeKey,
+		itemType.Description,
+		itemType.Color,
+		itemType.IsActive,
+		itemType.OrganizationID,
+		itemType.DepartmentID,
+		itemType.TeamID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("ITEM_TYPE_NOT_FOUND")
+	} else if err != nil {
+		return nil, itemConfigError(err, "ITEM_TYPE", "error updating item type")
+	}
+
+	return updated, nil
+}
+
+// DeleteItemType deletes an item type from the given scope, types still used by items can not be deleted
+func (s *Service) DeleteItemType(ctx context.Context, scope thunderdome.ItemScope, typeID string) error {
+	result, err := s.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.item_type
+		WHERE id = $1
+		AND organization_id IS NOT DISTINCT FROM $2
+		AND department_id IS NOT DISTINCT FROM $3
+		AND team_id IS NOT DISTINCT FROM $4;`,
+		typeID, scope.OrganizationID, scope.DepartmentID, scope.TeamID,
+	)
+	if err != nil {
+		return itemConfigError(err, "ITEM_TYPE", "error deleting item type")
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return errors.New("ITEM_TYPE_NOT_FOUND")
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/microcosm-cc/bluemonday"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 
 	"go.uber.org/zap"
@@ -13,8 +14,9 @@ import (
 
 // Service represents a PostgreSQL implementation of thunderdome.ProjectDataSvc.
 type Service struct {
-	DB     *sql.DB
-	Logger *otelzap.Logger
+	DB                  *sql.DB
+	Logger              *otelzap.Logger
+	HTMLSanitizerPolicy *bluemonday.Policy
 }
 
 // GetProjectsByOrganization retrieves all projects for a specific organization
@@ -0,0 +1,81 @@
+// Package ratelimit provides fixed window request counters stored in Postgres,
+// so limits are shared by every application replica
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// cleanupInterval is how often expired counters are removed
+const cleanupInterval = 10 * time.Minute
+
+// Service represents the rate limit database service
+type Service struct {
+	DB     *sql.DB
+	Logger *otelzap.Logger
+}
+
+// Start begins periodically removing expired counters until the context is canceled
+func (d *Service) Start(ctx context.Context) {
+	go d.cleanup(ctx)
+}
+
+// Hit counts a request against the key's current window, starting a new window when the previous one has ended,
+// returning the number of requests in the window and when the window ends
+func (d *Service) Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	var hits int
+	var windowEnd time.Time
+
+	err := d.DB.QueryRowContext(ctx, `
+		INSERT INTO thunderdome.rate_limit AS rl (key, hits, window_end)
+		VALUES ($1, 1, NOW() + make_interval(secs => $2))
+		ON CONFLICT (key) DO UPDATE SET
+			hits = CASE WHEN rl.window_end <= NOW() THEN 1 ELSE rl.hits + 1 END,
+			window_end = CASE WHEN rl.window_end <= NOW() THEN EXCLUDED.window_end ELSE rl.window_end END
+		RETURNING hits, window_end;
+		`,
+		key,
+		window.Seconds(),
+	).Scan(&hits, &windowEnd)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("rate limit hit query error: %v", err)
+	}
+
+	return hits, windowEnd, nil
+}
+
+// Reset clears the counter of a key, such as an account budget after a successful login
+func (d *Service) Reset(ctx context.Context, key string) error {
+	if _, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.rate_limit WHERE key = $1;`, key,
+	); err != nil {
+		return fmt.Errorf("rate limit reset query error: %v", err)
+	}
+
+	return nil
+}
+
+// cleanup periodically removes counters whose window has ended
+func (d *Service) cleanup(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.DB.ExecContext(ctx,
+				`DELETE FROM thunderdome.rate_limit WHERE window_end < NOW();`,
+			); err != nil {
+				d.Logger.Ctx(ctx).Error("rate limit cleanup error", zap.Error(err))
+			}
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package retro
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
@@ -11,10 +13,11 @@ import (
 	"go.uber.org/zap"
 )
 
-// CreateRetroAction adds a new action to the retro
-func (d *Service) CreateRetroAction(retroID string, userID string, content string) ([]*thunderdome.RetroAction, error) {
+// CreateRetroAction adds a new action to the retro, an empty due date leaves the action without one
+func (d *Service) CreateRetroAction(retroID string, userID string, content string, dueDate string) ([]*thunderdome.RetroAction, error) {
 	if _, err := d.DB.Exec(
-		`INSERT INTO thunderdome.retro_action (retro_id, content) VALUES ($1, $2);`, retroID, content,
+		`INSERT INTO thunderdome.retro_action (retro_id, content, due_date) VALUES ($1, $2, NULLIF($3, '')::date);`,
+		retroID, content, dueDate,
 	); err != nil {
 		d.Logger.Error("insert retro_action error", zap.Error(err))
 	}
@@ -24,10 +27,20 @@ func (d *Service) CreateRetroAction(retroID string, userID string, content strin
 	return actions, nil
 }
 
-// UpdateRetroAction updates an actions status
-func (d *Service) UpdateRetroAction(retroID string, actionID string, content string, completed bool) (Actions []*thunderdome.RetroAction, DeleteError error) {
+// UpdateRetroAction updates an actions content, status and due date, the action is completed once done or dropped,
+// an empty status (from clients without action statuses) keeps the existing status unless completed changes it
+func (d *Service) UpdateRetroAction(retroID string, actionID string, content string, status string, completed bool, dueDate string) (Actions []*thunderdome.RetroAction, DeleteError error) {
 	if _, err := d.DB.Exec(
-		`UPDATE thunderdome.retro_action SET completed = $2, content = $3, updated_date = NOW() WHERE id = $1;`, actionID, completed, content); err != nil {
+		`UPDATE thunderdome.retro_action
+		SET status = CASE
+				WHEN $2 <> '' THEN $2
+				WHEN (status IN ('done', 'dropped')) = $5 THEN status
+				WHEN $5 THEN 'done'
+				ELSE 'open'
+			END,
+			completed = CASE WHEN $2 <> '' THEN $2 IN ('done', 'dropped') ELSE $5 END,
+			content = $3, due_date = NULLIF($4, '')::date, updated_date = NOW()
+		WHERE id = $1;`, actionID, status, content, dueDate, completed); err != nil {
 		d.Logger.Error("update retro_action error", zap.Error(err))
 	}
 
@@ -53,7 +66,7 @@ func (d *Service) GetRetroActions(retroID string) []*thunderdome.RetroAction {
 	var actions = make([]*thunderdome.RetroAction, 0)
 
 	actionRows, actionsErr := d.DB.Query(
-		`SELECT a.id, a.content, a.completed,
+		`SELECT a.id, a.content, a.completed, a.status, COALESCE(to_char(a.due_date, 'YYYY-MM-DD'), ''),
  		COALESCE(json_agg(json_build_object('id', u.id, 'name', u.name, 'email', COALESCE(u.email, ''), 'avatar', u.avatar))
  		 FILTER (WHERE u.id IS NOT NULL), '[]') AS assignees
 		FROM thunderdome.retro_action a
@@ -74,7 +87,7 @@ func (d *Service) GetRetroActions(retroID string) []*thunderdome.RetroAction {
 				Assignees: make([]*thunderdome.User, 0),
 			}
 			var assignees string
-			if err := actionRows.Scan(&ri.ID, &ri.Content, &ri.Completed, &assignees); err != nil {
+			if err := actionRows.Scan(&ri.ID, &ri.Content, &ri.Completed, &ri.Status, &ri.DueDate, &assignees); err != nil {
 				d.Logger.Error("get retro actions error", zap.Error(err))
 			} else {
 				jsonErr := json.Unmarshal([]byte(assignees), &ri.Assignees)
@@ -115,7 +128,7 @@ func (d *Service) GetTeamRetroActions(teamID string, limit int, offset int, comp
 	}
 
 	actionRows, err := d.DB.Query(
-		`SELECT ra.id, ra.content, ra.completed, ra.retro_id,
+		`SELECT ra.id, ra.content, ra.completed, ra.status, COALESCE(to_char(ra.due_date, 'YYYY-MM-DD'), ''), ra.retro_id,
 				(SELECT COALESCE(
 					json_agg(rac ORDER BY rac.created_date) FILTER (WHERE rac.id IS NOT NULL), '[]'
 				) AS comments
@@ -144,7 +157,9 @@ func (d *Service) GetTeamRetroActions(teamID string, limit int, offset int, comp
 			}
 			var comments string
 			var assignees string
-			if err := actionRows.Scan(&ri.ID, &ri.Content, &ri.Completed, &ri.RetroID, &comments, &assignees); err != nil {
+			if err := actionRows.Scan(
+				&ri.ID, &ri.Content, &ri.Completed, &ri.Status, &ri.DueDate, &ri.RetroID, &comments, &assignees,
+			); err != nil {
 				d.Logger.Error("get retro actions error", zap.Error(err))
 			} else {
 				jsonErr := json.Unmarshal([]byte(comments), &ri.Comments)
@@ -217,7 +232,8 @@ func (d *Service) GetUserRetroActions(userID string, teamID string, limit int, o
 				FROM user_teams
 				WHERE $2 = '' OR id = $2::uuid
 			)
-			SELECT ra.id, ra.content, ra.completed, ra.retro_id, r.team_id, ft.name,
+			SELECT ra.id, ra.content, ra.completed, ra.status, COALESCE(to_char(ra.due_date, 'YYYY-MM-DD'), ''),
+				ra.retro_id, r.team_id, ft.name,
 				(SELECT COALESCE(
 					json_agg(rac ORDER BY rac.created_date) FILTER (WHERE rac.id IS NOT NULL), '[]'
 				) AS comments
@@ -250,7 +266,10 @@ func (d *Service) GetUserRetroActions(userID string, teamID string, limit int, o
 			}
 			var comments string
 			var assignees string
-			if err := actionRows.Scan(&ri.ID, &ri.Content, &ri.Completed, &ri.RetroID, &ri.TeamID, &ri.TeamName, &comments, &assignees); err != nil {
+			if err := actionRows.Scan(
+				&ri.ID, &ri.Content, &ri.Completed, &ri.Status, &ri.DueDate, &ri.RetroID, &ri.TeamID, &ri.TeamName,
+				&comments, &assignees,
+			); err != nil {
 				d.Logger.Error("get user retro actions error", zap.Error(err))
 			} else {
 				jsonErr := json.Unmarshal([]byte(comments), &ri.Comments)
@@ -352,3 +371,112 @@ func (d *Service) RetroActionAssigneeDelete(retroID string, actionID string, use
 
 	return actions, nil
 }
+
+// GetRetroReviewActions retrieves the open actions from the team's previous retros to review in the retro,
+// along with the actions already reviewed in the retro and their decision
+func (d *Service) GetRetroReviewActions(retroID string) []*thunderdome.RetroAction {
+	var actions = make([]*thunderdome.RetroAction, 0)
+
+	actionRows, err := d.DB.Query(
+		`SELECT ra.id, ra.retro_id, ra.content, ra.completed, ra.status, COALESCE(to_char(ra.due_date, 'YYYY-MM-DD'), ''),
+			COALESCE(rar.decision, ''),
+			COALESCE(json_agg(json_build_object('id', u.id, 'name', u.name, 'email', COALESCE(u.email, ''), 'avatar', u.avatar))
+				FILTER (WHERE u.id IS NOT NULL), '[]') AS assignees
+		FROM thunderdome.retro r
+		JOIN thunderdome.retro pr ON pr.team_id = r.team_id AND pr.id <> r.id AND pr.created_date < r.created_date
+		JOIN thunderdome.retro_action ra ON ra.retro_id = pr.id
+		LEFT JOIN thunderdome.retro_action_review rar ON rar.action_id = ra.id AND rar.retro_id = r.id
+		LEFT JOIN thunderdome.retro_action_assignee t ON t.action_id = ra.id
+		LEFT JOIN thunderdome.users u ON t.user_id = u.id
+		WHERE r.id = $1 AND (ra.status IN ('open', 'in_progress') OR rar.action_id IS NOT NULL)
+		GROUP BY ra.id, rar.decision
+		ORDER BY ra.due_date ASC NULLS LAST, ra.created_date ASC;`,
+		retroID,
+	)
+	if err != nil {
+		d.Logger.Error("get retro review actions error", zap.Error(err))
+		return actions
+	}
+	defer actionRows.Close()
+
+	for actionRows.Next() {
+		var ri = &thunderdome.RetroAction{
+			Comments:  make([]*thunderdome.RetroActionComment, 0),
+			Assignees: make([]*thunderdome.User, 0),
+		}
+		var assignees string
+		if err := actionRows.Scan(
+			&ri.ID, &ri.RetroID, &ri.Content, &ri.Completed, &ri.Status, &ri.DueDate, &ri.ReviewDecision, &assignees,
+		); err != nil {
+			d.Logger.Error("get retro review actions error", zap.Error(err))
+			continue
+		}
+		if err := json.Unmarshal([]byte(assignees), &ri.Assignees); err != nil {
+			d.Logger.Error("retro review action assignees json error", zap.Error(err))
+		}
+		for i, assignee := range ri.Assignees {
+			if assignee.Email != "" {
+				ri.Assignees[i].GravatarHash = db.CreateGravatarHash(assignee.Email)
+			} else {
+				ri.Assignees[i].GravatarHash = db.CreateGravatarHash(assignee.ID)
+			}
+		}
+		actions = append(actions, ri)
+	}
+
+	return actions
+}
+
+// ReviewRetroAction records the review decision for a previous retro's action, closing the action as done or
+// dropped or keeping it open to carry over into the team's next retro, actions are only reviewed in the review phase
+func (d *Service) ReviewRetroAction(ctx context.Context, retroID string, actionID string, decision string) ([]*thunderdome.RetroAction, error) {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("review retro action begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+
+	phase, _, err := d.getRetroPhaseForUpdate(ctx, tx, retroID)
+	if err != nil {
+		return nil, err
+	}
+	if phase != thunderdome.RetroPhaseReview {
+		return nil, errors.New("REVIEW_PHASE_NOT_ACTIVE")
+	}
+
+	// only the team's previous retro actions can be reviewed
+	res, err := tx.ExecContext(ctx,
+		`UPDATE thunderdome.retro_action ra
+		SET status = CASE
+				WHEN $3 = 'done' THEN 'done'
+				WHEN $3 = 'dropped' THEN 'dropped'
+				WHEN ra.status IN ('open', 'in_progress') THEN ra.status
+				ELSE 'open'
+			END,
+			completed = $3 <> 'kept', updated_date = NOW()
+		FROM thunderdome.retro r
+		JOIN thunderdome.retro pr ON pr.team_id = r.team_id AND pr.id <> r.id AND pr.created_date < r.created_date
+		WHERE r.id = $1 AND ra.id = $2 AND ra.retro_id = pr.id;`,
+		retroID, actionID, decision,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("review retro action query error: %v", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return nil, errors.New("RETRO_ACTION_NOT_FOUND")
+	}
+
+	if _, err = tx.ExecContext(ctx,
+		`INSERT INTO thunderdome.retro_action_review (retro_id, action_id, decision) VALUES ($1, $2, $3)
+		ON CONFLICT (retro_id, action_id) DO UPDATE SET decision = EXCLUDED.decision, created_date = NOW();`,
+		retroID, actionID, decision,
+	); err != nil {
+		return nil, fmt.Errorf("review retro action insert error: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("review retro action commit error: %v", err)
+	}
+
+	return d.GetRetroReviewActions(retroID), nil
+}
@@ -0,0 +1,49 @@
+package retro
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+const testActionID = "2c9d8e7f-6a5b-4c3d-9e8f-7a6b5c4d3e2f"
+
+func TestReviewRetroActionOutsideReviewPhase(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT phase, phases FROM thunderdome.retro WHERE id = \$1 FOR UPDATE`).
+		WithArgs(testRetroID).
+		WillReturnRows(sqlmock.NewRows([]string{"phase", "phases"}).AddRow("brainstorm", testPhases))
+	// the carried over action is left as it is
+	mock.ExpectRollback()
+
+	_, err := s.ReviewRetroAction(context.Background(), testRetroID, testActionID, thunderdome.RetroActionReviewDropped)
+	if err == nil || err.Error() != "REVIEW_PHASE_NOT_ACTIVE" {
+		t.Fatalf("ReviewRetroAction() error = %v, want REVIEW_PHASE_NOT_ACTIVE", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpdateRetroActionKeepsStatusWhenNoneSent(t *testing.T) {
+	s, mock := newTestService(t)
+
+	// clients without action statuses send an empty status with the completed flag
+	mock.ExpectExec(`UPDATE thunderdome.retro_action(.|\n)+WHEN \(status IN \('done', 'dropped'\)\) = \$5 THEN status`).
+		WithArgs(testActionID, "", "updated content", "", false).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`FROM thunderdome.retro_action a`).
+		WithArgs(testRetroID).
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	if _, err := s.UpdateRetroAction(testRetroID, testActionID, "updated content", "", false, ""); err != nil {
+		t.Fatalf("UpdateRetroAction() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,195 @@
+package retro
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/retrophase"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// healthCheckVoterHash identifies a user's scores within a single retro without storing who scored them,
+// the hash is keyed so it can't be matched to a user by hashing the retro's user IDs
+func (d *Service) healthCheckVoterHash(retroID string, userID string) string {
+	return db.HMACString(retroID+":"+userID, d.AESHashKey)
+}
+
+// RetroHealthCheckScore sets a user's anonymous 1-5 score for a health check dimension while the retro
+// is in its health check phase, returning the updated response counts
+func (d *Service) RetroHealthCheckScore(
+	ctx context.Context, retroID string, userID string, dimension string, score int,
+) ([]*thunderdome.RetroHealthCheckResult, error) {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	authorized, err := isUserAuthorizedForRetro(tx, retroID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking user authorization: %w", err)
+	}
+	if !authorized {
+		return nil, &UnauthorizedUserError{}
+	}
+
+	phase, phases, err := d.getRetroPhaseForUpdate(ctx, tx, retroID)
+	if err != nil {
+		return nil, err
+	}
+	if phase != thunderdome.RetroPhaseHealthCheck {
+		return nil, errors.New("HEALTH_CHECK_PHASE_NOT_ACTIVE")
+	}
+	dimensions := retrophase.HealthCheckDimensions(phases)
+	if !slices.Contains(dimensions, dimension) {
+		return nil, errors.New("INVALID_HEALTH_CHECK_DIMENSION")
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO thunderdome.retro_health_check (retro_id, voter_hash, dimension, score)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (retro_id, voter_hash, dimension)
+			DO UPDATE SET score = EXCLUDED.score, updated_date = NOW();`,
+		retroID, d.healthCheckVoterHash(retroID, userID), dimension, score,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("retro health check score query error: %v", err)
+	}
+
+	results, err := getHealthCheckResults(ctx, tx, retroID, dimensions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return healthCheckResponses(results), nil
+}
+
+// GetRetroHealthCheck gets the aggregated health check results of the retro in the order of its dimensions,
+// while the retro is in its health check phase only the response counts are included
+func (d *Service) GetRetroHealthCheck(retroID string, phase string, dimensions []string) []*thunderdome.RetroHealthCheckResult {
+	results, err := getHealthCheckResults(context.Background(), d.DB, retroID, dimensions)
+	if err != nil {
+		d.Logger.Error("get retro health check error", zap.Error(err))
+		return emptyHealthCheckResults(dimensions)
+	}
+	if phase == thunderdome.RetroPhaseHealthCheck {
+		return healthCheckResponses(results)
+	}
+
+	return results
+}
+
+// healthCheckResponses strips the averages and distributions from the results leaving the response counts,
+// the scores stay hidden until the health check phase ends so early scores don't sway the rest of the team
+func healthCheckResponses(results []*thunderdome.RetroHealthCheckResult) []*thunderdome.RetroHealthCheckResult {
+	responses := make([]*thunderdome.RetroHealthCheckResult, 0, len(results))
+	for _, r := range results {
+		responses = append(responses, &thunderdome.RetroHealthCheckResult{Dimension: r.Dimension, Responses: r.Responses})
+	}
+
+	return responses
+}
+
+// GetRetroHealthCheckScores gets the user's own health check scores keyed by dimension
+func (d *Service) GetRetroHealthCheckScores(retroID string, userID string) map[string]int {
+	scores := make(map[string]int)
+
+	rows, err := d.DB.Query(
+		`SELECT dimension, score FROM thunderdome.retro_health_check WHERE retro_id = $1 AND voter_hash = $2;`,
+		retroID, d.healthCheckVoterHash(retroID, userID),
+	)
+	if err != nil {
+		d.Logger.Error("get retro health check scores query error", zap.Error(err))
+		return scores
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dimension string
+		var score int
+		if err := rows.Scan(&dimension, &score); err != nil {
+			d.Logger.Error("get retro health check scores scan error", zap.Error(err))
+			continue
+		}
+		scores[dimension] = score
+	}
+
+	return scores
+}
+
+// saveTeamHealthCheck snapshots the retro's health check results into its team's health trend,
+// the snapshot outlives the retro so trends survive retro cleanup
+func saveTeamHealthCheck(ctx context.Context, tx *sql.Tx, retroID string) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO thunderdome.team_health_check
+			(team_id, retro_id, retro_name, dimension, average_score, response_count)
+		SELECT r.team_id, r.id, r.name, hc.dimension, ROUND(AVG(hc.score), 2), COUNT(*)
+		FROM thunderdome.retro_health_check hc
+		JOIN thunderdome.retro r ON r.id = hc.retro_id
+		WHERE hc.retro_id = $1 AND r.team_id IS NOT NULL
+		GROUP BY r.team_id, r.id, r.name, hc.dimension
+		ON CONFLICT (retro_id, dimension) DO UPDATE
+		SET average_score = EXCLUDED.average_score, response_count = EXCLUDED.response_count, updated_date = NOW();`,
+		retroID,
+	)
+	if err != nil {
+		return fmt.Errorf("save team health check query error: %v", err)
+	}
+
+	return nil
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func getHealthCheckResults(ctx context.Context, q queryer, retroID string, dimensions []string) ([]*thunderdome.RetroHealthCheckResult, error) {
+	results := emptyHealthCheckResults(dimensions)
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT dimension, AVG(score)::FLOAT, COUNT(*),
+			COUNT(*) FILTER (WHERE score = 1), COUNT(*) FILTER (WHERE score = 2), COUNT(*) FILTER (WHERE score = 3),
+			COUNT(*) FILTER (WHERE score = 4), COUNT(*) FILTER (WHERE score = 5)
+		FROM thunderdome.retro_health_check
+		WHERE retro_id = $1
+		GROUP BY dimension;`,
+		retroID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get retro health check query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r thunderdome.RetroHealthCheckResult
+		if err := rows.Scan(
+			&r.Dimension, &r.Average, &r.Responses,
+			&r.Distribution[0], &r.Distribution[1], &r.Distribution[2], &r.Distribution[3], &r.Distribution[4],
+		); err != nil {
+			return nil, fmt.Errorf("get retro health check scan error: %v", err)
+		}
+		if i := slices.Index(dimensions, r.Dimension); i != -1 {
+			results[i] = &r
+		}
+	}
+
+	return results, rows.Err()
+}
+
+func emptyHealthCheckResults(dimensions []string) []*thunderdome.RetroHealthCheckResult {
+	results := make([]*thunderdome.RetroHealthCheckResult, 0, len(dimensions))
+	for _, dimension := range dimensions {
+		results = append(results, &thunderdome.RetroHealthCheckResult{Dimension: dimension})
+	}
+
+	return results
+}
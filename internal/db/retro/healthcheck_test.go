@@ -0,0 +1,91 @@
+package retro
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+const (
+	testHealthCheckUserID = "c3d4e5f6-a7b8-4c9d-8e1f-2a3b4c5d6e7f"
+	testHealthCheckPhases = `[{"name":"healthcheck","dimensions":["Speed","Fun"]},{"name":"completed"}]`
+)
+
+func healthCheckResultRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"dimension", "avg", "count", "s1", "s2", "s3", "s4", "s5"}).
+		AddRow("Speed", 4.5, 2, 0, 0, 0, 1, 1)
+}
+
+func TestHealthCheckVoterHash(t *testing.T) {
+	s, _ := newTestService(t)
+	other := &Service{AESHashKey: "another-hash-key"}
+
+	hash := s.healthCheckVoterHash(testRetroID, testHealthCheckUserID)
+	if hash == db.HashString(testRetroID+testHealthCheckUserID) {
+		t.Error("healthCheckVoterHash() is an unkeyed hash of the retro and user IDs")
+	}
+	if hash == other.healthCheckVoterHash(testRetroID, testHealthCheckUserID) {
+		t.Error("healthCheckVoterHash() doesn't depend on the hash key")
+	}
+	if len(hash) > 64 {
+		t.Errorf("healthCheckVoterHash() length = %d, want at most 64", len(hash))
+	}
+}
+
+func TestRetroHealthCheckScoreHidesResults(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM thunderdome.retro_user`).
+		WithArgs(testRetroID, testHealthCheckUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"active"}).AddRow(true))
+	mock.ExpectQuery(`SELECT phase, phases FROM thunderdome.retro WHERE id = \$1 FOR UPDATE`).
+		WithArgs(testRetroID).
+		WillReturnRows(sqlmock.NewRows([]string{"phase", "phases"}).AddRow("healthcheck", testHealthCheckPhases))
+	mock.ExpectExec(`INSERT INTO thunderdome.retro_health_check`).
+		WithArgs(testRetroID, s.healthCheckVoterHash(testRetroID, testHealthCheckUserID), "Speed", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`FROM thunderdome.retro_health_check`).
+		WithArgs(testRetroID).
+		WillReturnRows(healthCheckResultRows())
+	mock.ExpectCommit()
+
+	results, err := s.RetroHealthCheckScore(context.Background(), testRetroID, testHealthCheckUserID, "Speed", 5)
+	if err != nil {
+		t.Fatalf("RetroHealthCheckScore() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Responses != 2 || results[0].Average != 0 || results[0].Distribution != [5]int{} {
+		t.Errorf("RetroHealthCheckScore()[0] = %+v, want only the 2 responses", results[0])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetRetroHealthCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		phase       string
+		wantAverage float64
+	}{
+		{name: "during the health check phase", phase: thunderdome.RetroPhaseHealthCheck, wantAverage: 0},
+		{name: "after the health check phase", phase: "completed", wantAverage: 4.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newTestService(t)
+			mock.ExpectQuery(`FROM thunderdome.retro_health_check`).
+				WithArgs(testRetroID).
+				WillReturnRows(healthCheckResultRows())
+
+			results := s.GetRetroHealthCheck(testRetroID, tt.phase, []string{"Speed", "Fun"})
+			if results[0].Responses != 2 || results[0].Average != tt.wantAverage {
+				t.Errorf("GetRetroHealthCheck()[0] = %+v, want 2 responses averaging %v", results[0], tt.wantAverage)
+			}
+		})
+	}
+}
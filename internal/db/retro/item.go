@@ -4,12 +4,21 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 	"go.uber.org/zap"
 )
 
-// CreateRetroItem adds a feedback item to the retro
-func (d *Service) CreateRetroItem(retroID string, userID string, itemType string, content string) ([]*thunderdome.RetroItem, error) {
+// CreateRetroItem adds a feedback item to the retro, in anonymous retros the author tag and a hash of the author
+// secret are stored so only the author can recognize and delete the item, untracked retros don't store the user
+func (d *Service) CreateRetroItem(
+	retroID string, userID string, itemType string, content string, authorTag string, authorSecret string,
+) ([]*thunderdome.RetroItem, error) {
+	var authorSecretHash string
+	if authorSecret != "" {
+		authorSecretHash = db.HashString(authorSecret)
+	}
+
 	var groupID string
 	err := d.DB.QueryRow(
 		`INSERT INTO thunderdome.retro_group
@@ -23,9 +32,13 @@ func (d *Service) CreateRetroItem(retroID string, userID string, itemType string
 
 	if _, err := d.DB.Exec(
 		`INSERT INTO thunderdome.retro_item
-		(retro_id, group_id, type, content, user_id)
-		VALUES ($1, $2, $3, $4, $5);`,
-		retroID, groupID, itemType, content, userID,
+		(retro_id, group_id, type, content, user_id, author_tag, author_secret_hash)
+		SELECT r.id, $2, $3, $4,
+			CASE WHEN r.anonymity = 'untracked' THEN NULL ELSE $5::uuid END,
+			CASE WHEN r.anonymity = 'none' THEN NULL ELSE NULLIF($6, '') END,
+			CASE WHEN r.anonymity = 'none' THEN NULL ELSE NULLIF($7, '') END
+		FROM thunderdome.retro r WHERE r.id = $1;`,
+		retroID, groupID, itemType, content, userID, authorTag, authorSecretHash,
 	); err != nil {
 		d.Logger.Error("insert retro item error", zap.Error(err))
 	}
@@ -40,11 +53,13 @@ func (d *Service) GroupRetroItem(retroID string, itemID string, groupID string)
 	ri := thunderdome.RetroItem{}
 
 	err := d.DB.QueryRow(
-		`UPDATE thunderdome.retro_item SET group_id = $3
- 				WHERE retro_id = $1 AND id = $2
- 				RETURNING id, user_id, group_id, content, type;`,
+		`UPDATE thunderdome.retro_item ri SET group_id = $3
+				FROM thunderdome.retro r
+ 				WHERE ri.retro_id = $1 AND ri.id = $2 AND r.id = ri.retro_id
+ 				RETURNING ri.id, CASE WHEN r.anonymity = 'none' THEN COALESCE(ri.user_id::text, '') ELSE '' END,
+ 					COALESCE(ri.author_tag, ''), ri.group_id, ri.content, ri.type;`,
 		retroID, itemID, groupID,
-	).Scan(&ri.ID, &ri.UserID, &ri.GroupID, &ri.Content, &ri.Type)
+	).Scan(&ri.ID, &ri.UserID, &ri.AuthorTag, &ri.GroupID, &ri.Content, &ri.Type)
 
 	if err != nil {
 		d.Logger.Error("move (group) retro item error", zap.Error(err))
@@ -54,10 +69,16 @@ func (d *Service) GroupRetroItem(retroID string, itemID string, groupID string)
 	return ri, nil
 }
 
-// DeleteRetroItem removes item from the current board by ID
-func (d *Service) DeleteRetroItem(retroID string, userID string, itemType string, itemID string) ([]*thunderdome.RetroItem, error) {
+// DeleteRetroItem removes item from the current board by ID, in anonymous retros the author secret must match
+func (d *Service) DeleteRetroItem(
+	retroID string, userID string, itemType string, itemID string, authorSecret string,
+) ([]*thunderdome.RetroItem, error) {
 	if _, err := d.DB.Exec(
-		`DELETE FROM thunderdome.retro_item WHERE id = $1 AND type = $2;`, itemID, itemType); err != nil {
+		`DELETE FROM thunderdome.retro_item ri
+		USING thunderdome.retro r
+		WHERE ri.id = $1 AND ri.type = $2 AND ri.retro_id = $3 AND r.id = ri.retro_id
+			AND (r.anonymity = 'none' OR ri.author_secret_hash = $4);`,
+		itemID, itemType, retroID, db.HashString(authorSecret)); err != nil {
 		d.Logger.Error("delete retro item error", zap.Error(err))
 	}
 
@@ -72,7 +93,8 @@ func (d *Service) GetRetroItems(retroID string) []*thunderdome.RetroItem {
 
 	itemRows, itemsErr := d.DB.Query(
 		`SELECT
-				ri.id, ri.user_id, ri.group_id, ri.content, ri.type,
+				ri.id, CASE WHEN r.anonymity = 'none' THEN COALESCE(ri.user_id::text, '') ELSE '' END,
+				COALESCE(ri.author_tag, ''), ri.group_id, ri.content, ri.type,
 				COALESCE(
 					(
 						SELECT json_agg(rc ORDER BY rc.created_date)
@@ -90,6 +112,7 @@ func (d *Service) GetRetroItems(retroID string) []*thunderdome.RetroItem {
 					'[]'
 				) AS reactions
 			FROM thunderdome.retro_item ri
+			JOIN thunderdome.retro r ON r.id = ri.retro_id
 			WHERE ri.retro_id = $1
 			ORDER BY ri.created_date ASC;`,
 		retroID,
@@ -103,7 +126,9 @@ func (d *Service) GetRetroItems(retroID string) []*thunderdome.RetroItem {
 				Comments:  make([]*thunderdome.RetroItemComment, 0),
 				Reactions: make([]*thunderdome.RetroItemReaction, 0),
 			}
-			if err := itemRows.Scan(&ri.ID, &ri.UserID, &ri.GroupID, &ri.Content, &ri.Type, &comments, &reactions); err != nil {
+			if err := itemRows.Scan(
+				&ri.ID, &ri.UserID, &ri.AuthorTag, &ri.GroupID, &ri.Content, &ri.Type, &comments, &reactions,
+			); err != nil {
 				d.Logger.Error("get retro items query scan error", zap.Error(err))
 			} else {
 				jsonErr := json.Unmarshal([]byte(comments), &ri.Comments)
@@ -0,0 +1,99 @@
+package retro
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/retrophase"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// GetRetroPhaseDeadlines gets the deadlines of retros in a timed phase,
+// deadlines that ran out longer than lookback ago are skipped as abandoned retros
+func (d *Service) GetRetroPhaseDeadlines(ctx context.Context, lookback time.Duration) ([]*thunderdome.RetroPhaseDeadline, error) {
+	deadlines := make([]*thunderdome.RetroPhaseDeadline, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT id, phase, phase_time_start + make_interval(mins => time_limit_min) AS deadline
+		FROM (
+			SELECT r.id, r.phase, r.phase_time_start,
+				COALESCE((
+					SELECT (p->>'timeLimitMin')::int FROM jsonb_array_elements(r.phases) AS p
+					WHERE p->>'name' = r.phase LIMIT 1
+				), 0) AS time_limit_min
+			FROM thunderdome.retro r
+			WHERE r.phase <> 'completed'
+		) rp
+		WHERE time_limit_min > 0
+		AND phase_time_start + make_interval(mins => time_limit_min) > NOW() - make_interval(secs => $1);`,
+		lookback.Seconds(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get retro phase deadlines query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pd thunderdome.RetroPhaseDeadline
+		if err := rows.Scan(&pd.RetroID, &pd.Phase, &pd.Deadline); err != nil {
+			return nil, fmt.Errorf("get retro phase deadlines scan error: %v", err)
+		}
+		deadlines = append(deadlines, &pd)
+	}
+
+	return deadlines, nil
+}
+
+// RetroPhaseTimeout advances a retro whose timed phase has run out to the next phase in its sequence,
+// the retro is locked while checking the deadline so only one caller advances the retro
+func (d *Service) RetroPhaseTimeout(ctx context.Context, retroID string) (*thunderdome.Retro, error) {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	phase, phases, err := d.getRetroPhaseForUpdate(ctx, tx, retroID)
+	if err != nil {
+		return nil, err
+	}
+
+	timeLimitMin := retrophase.TimeLimit(phases, phase)
+	next, ok := retrophase.Next(phases, phase)
+	if timeLimitMin == 0 || !ok {
+		return nil, errors.New("RETRO_PHASE_NOT_EXPIRED")
+	}
+
+	var expired bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT phase_time_start + make_interval(mins => $2) <= NOW() FROM thunderdome.retro WHERE id = $1;`,
+		retroID, timeLimitMin,
+	).Scan(&expired)
+	if err != nil {
+		return nil, fmt.Errorf("retro phase timeout query error: %v", err)
+	}
+	if !expired {
+		return nil, errors.New("RETRO_PHASE_NOT_EXPIRED")
+	}
+
+	b, err := d.updateRetroPhase(ctx, tx, retroID, phases, phase, next)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	b.Items = d.GetRetroItems(retroID)
+	b.Groups = d.GetRetroGroups(retroID)
+	b.ActionItems = d.GetRetroActions(retroID)
+	b.Votes = d.GetRetroVotes(retroID)
+	if dimensions := retrophase.HealthCheckDimensions(phases); dimensions != nil {
+		b.HealthCheck = d.GetRetroHealthCheck(retroID, next, dimensions)
+	}
+
+	return b, nil
+}
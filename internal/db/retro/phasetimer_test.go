@@ -0,0 +1,109 @@
+package retro
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const (
+	testRetroID = "8f14e45f-ceea-4e67-a0b5-1a2b3c4d5e6f"
+	testPhases  = `[{"name":"brainstorm","timeLimitMin":5},{"name":"group"},{"name":"vote"},{"name":"completed"}]`
+)
+
+func newTestService(t *testing.T) (*Service, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return &Service{
+		DB:         db,
+		Logger:     otelzap.New(zap.NewNop()),
+		AESHashKey: "test-hash-key",
+	}, mock
+}
+
+func TestRetroPhaseTimeout(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(mock sqlmock.Sqlmock)
+	}{
+		{
+			// the facilitator restarted the phase or another instance already advanced it
+			name: "deadline moved",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT phase, phases FROM thunderdome.retro WHERE id = \$1 FOR UPDATE`).
+					WithArgs(testRetroID).
+					WillReturnRows(sqlmock.NewRows([]string{"phase", "phases"}).AddRow("brainstorm", testPhases))
+				mock.ExpectQuery(`SELECT phase_time_start \+ make_interval\(mins => \$2\) <= NOW\(\)`).
+					WithArgs(testRetroID, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"expired"}).AddRow(false))
+			},
+		},
+		{
+			name: "phase not timed",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT phase, phases FROM thunderdome.retro WHERE id = \$1 FOR UPDATE`).
+					WithArgs(testRetroID).
+					WillReturnRows(sqlmock.NewRows([]string{"phase", "phases"}).AddRow("group", testPhases))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newTestService(t)
+			mock.ExpectBegin()
+			tt.setup(mock)
+			// the retro's phase is left untouched
+			mock.ExpectRollback()
+
+			_, err := s.RetroPhaseTimeout(context.Background(), testRetroID)
+			if err == nil || err.Error() != "RETRO_PHASE_NOT_EXPIRED" {
+				t.Fatalf("RetroPhaseTimeout() error = %v, want RETRO_PHASE_NOT_EXPIRED", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRetroPhaseTimeoutAdvances(t *testing.T) {
+	s, mock := newTestService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT phase, phases FROM thunderdome.retro WHERE id = \$1 FOR UPDATE`).
+		WithArgs(testRetroID).
+		WillReturnRows(sqlmock.NewRows([]string{"phase", "phases"}).AddRow("brainstorm", testPhases))
+	mock.ExpectQuery(`SELECT phase_time_start \+ make_interval\(mins => \$2\) <= NOW\(\)`).
+		WithArgs(testRetroID, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"expired"}).AddRow(true))
+	// the retro is advanced in the same transaction that checked the deadline
+	mock.ExpectQuery(`UPDATE thunderdome.retro`).
+		WithArgs(testRetroID, "group").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"name", "phase", "phase_time_limit_min", "phase_time_start", "phase_auto_advance",
+			"hide_votes_during_voting", "template_id",
+		}).AddRow("Retro", "group", 0, time.Now(), false, false, "c0ffee00-0000-4000-8000-000000000000"))
+	mock.ExpectCommit()
+	mock.MatchExpectationsInOrder(true)
+
+	retro, err := s.RetroPhaseTimeout(context.Background(), testRetroID)
+	if err != nil {
+		t.Fatalf("RetroPhaseTimeout() error = %v", err)
+	}
+	if retro.Phase != "group" {
+		t.Errorf("RetroPhaseTimeout() phase = %s, want group", retro.Phase)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
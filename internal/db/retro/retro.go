@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/retrophase"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
@@ -23,25 +25,24 @@ type Service struct {
 
 func (d *Service) CreateRetro(
 	ctx context.Context, ownerID, teamID string, retroName, joinCode,
-	facilitatorCode string, maxVotes int, brainstormVisibility string, phaseTimeLimitMin int,
+	facilitatorCode string, maxVotes int, brainstormVisibility string, anonymity string, phaseTimeLimitMin int,
 	phaseAutoAdvance bool, allowCumulativeVoting bool, hideVotesDuringVoting bool, skipPrimeDirective bool, templateID string) (*thunderdome.Retro, error) {
 	var encryptedFacilitatorCode string
 	var encryptedJoinCode string
-	phase := "intro"
-	if skipPrimeDirective {
-		phase = "brainstorm"
+	if anonymity == "" {
+		anonymity = thunderdome.RetroAnonymityNone
 	}
 	var retro = &thunderdome.Retro{
 		OwnerID:               ownerID,
 		TeamID:                teamID,
 		Name:                  retroName,
-		Phase:                 phase,
-		PhaseTimeLimitMin:     phaseTimeLimitMin,
 		PhaseAutoAdvance:      phaseAutoAdvance,
 		Users:                 make([]*thunderdome.RetroUser, 0),
 		Items:                 make([]*thunderdome.RetroItem, 0),
 		ActionItems:           make([]*thunderdome.RetroAction, 0),
+		ReviewActions:         make([]*thunderdome.RetroAction, 0),
 		BrainstormVisibility:  brainstormVisibility,
+		Anonymity:             anonymity,
 		MaxVotes:              maxVotes,
 		TemplateID:            templateID,
 		AllowCumulativeVoting: allowCumulativeVoting,
@@ -71,16 +72,54 @@ func (d *Service) CreateRetro(
 	}
 	defer tx.Rollback()
 
+	var templateFormat string
+	err = tx.QueryRowContext(ctx,
+		`SELECT format FROM thunderdome.retro_template WHERE id = $1;`,
+		templateID,
+	).Scan(&templateFormat)
+	if err != nil {
+		d.Logger.Error("create retro error", zap.Error(err))
+		return nil, fmt.Errorf("failed to get retro template: %v", err)
+	}
+	var format thunderdome.RetroTemplateFormat
+	if err = json.Unmarshal([]byte(templateFormat), &format); err != nil {
+		return nil, fmt.Errorf("create retro template format json error: %v", err)
+	}
+
+	// team retros review the open actions from the team's previous retros
+	var hasOpenActions bool
+	if teamID != "" {
+		err = tx.QueryRowContext(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM thunderdome.retro_action ra
+				JOIN thunderdome.retro r ON r.id = ra.retro_id
+				WHERE r.team_id = $1 AND ra.status IN ('open', 'in_progress')
+			);
+		`, teamID).Scan(&hasOpenActions)
+		if err != nil {
+			d.Logger.Error("create retro error", zap.Error(err))
+			return nil, fmt.Errorf("failed to check team open retro actions: %v", err)
+		}
+	}
+
+	retro.Phases = retrophase.Sequence(format.Phases, phaseTimeLimitMin, skipPrimeDirective, hasOpenActions)
+	retro.Phase = retro.Phases[0].Name
+	retro.PhaseTimeLimitMin = retrophase.TimeLimit(retro.Phases, thunderdome.RetroPhaseBrainstorm)
+	phases, err := json.Marshal(retro.Phases)
+	if err != nil {
+		return nil, fmt.Errorf("create retro phases json error: %v", err)
+	}
+
 	err = tx.QueryRowContext(ctx, `
 		INSERT INTO thunderdome.retro (
 			owner_id, team_id, name, phase, join_code, facilitator_code,
 			max_votes, brainstorm_visibility, phase_time_limit_min, phase_auto_advance,
-			allow_cumulative_voting, hide_votes_during_voting, template_id
+			allow_cumulative_voting, hide_votes_during_voting, template_id, anonymity, phases
 		)
-		VALUES ($1, NULLIF($2::text, '')::uuid, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		VALUES ($1, NULLIF($2::text, '')::uuid, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		RETURNING id, created_date, updated_date;
-	`, ownerID, teamID, retroName, phase, encryptedJoinCode, encryptedFacilitatorCode, maxVotes, brainstormVisibility,
-		phaseTimeLimitMin, phaseAutoAdvance, allowCumulativeVoting, hideVotesDuringVoting, templateID).Scan(
+	`, ownerID, teamID, retroName, retro.Phase, encryptedJoinCode, encryptedFacilitatorCode, maxVotes, brainstormVisibility,
+		retro.PhaseTimeLimitMin, phaseAutoAdvance, allowCumulativeVoting, hideVotesDuringVoting, templateID, anonymity, string(phases)).Scan(
 		&retro.ID, &retro.CreatedDate, &retro.UpdatedDate,
 	)
 
@@ -141,9 +180,16 @@ func (d *Service) EditRetro(
 		encryptedFacilitatorCode = encryptedCode
 	}
 
+	// the phase time limit is the brainstorm phase's, so it's kept in sync with the brainstorm phase in the sequence
 	if _, err := d.DB.Exec(`UPDATE thunderdome.retro
     SET name = $2, join_code = $3, facilitator_code = $4, max_votes = $5,
-        brainstorm_visibility = $6, phase_auto_advance = $7, hide_votes_during_voting = $8, phase_time_limit_min = $9, updated_date = NOW()
+        brainstorm_visibility = $6, phase_auto_advance = $7, hide_votes_during_voting = $8, phase_time_limit_min = $9,
+        phases = COALESCE((
+            SELECT jsonb_agg(CASE WHEN p->>'name' = 'brainstorm'
+                THEN jsonb_set(p, '{timeLimitMin}', to_jsonb($9::int)) ELSE p END ORDER BY ord)
+            FROM jsonb_array_elements(phases) WITH ORDINALITY AS t(p, ord)
+        ), '[]'::jsonb),
+        updated_date = NOW()
     WHERE id = $1;`,
 		retroID, retroName, encryptedJoinCode, encryptedFacilitatorCode,
 		maxVotes, brainstormVisibility, phaseAutoAdvance, hideVotesDuringVoting, phaseTimeLimitMin,
@@ -157,14 +203,15 @@ func (d *Service) EditRetro(
 // RetroGetByID gets a retro by ID
 func (d *Service) RetroGetByID(retroID string, userID string) (*thunderdome.Retro, error) {
 	var b = &thunderdome.Retro{
-		ID:           retroID,
-		Users:        make([]*thunderdome.RetroUser, 0),
-		Items:        make([]*thunderdome.RetroItem, 0),
-		Groups:       make([]*thunderdome.RetroGroup, 0),
-		ActionItems:  make([]*thunderdome.RetroAction, 0),
-		Votes:        make([]*thunderdome.RetroVote, 0),
-		Facilitators: make([]string, 0),
-		ReadyUsers:   make([]string, 0),
+		ID:            retroID,
+		Users:         make([]*thunderdome.RetroUser, 0),
+		Items:         make([]*thunderdome.RetroItem, 0),
+		Groups:        make([]*thunderdome.RetroGroup, 0),
+		ActionItems:   make([]*thunderdome.RetroAction, 0),
+		ReviewActions: make([]*thunderdome.RetroAction, 0),
+		Votes:         make([]*thunderdome.RetroVote, 0),
+		Facilitators:  make([]string, 0),
+		ReadyUsers:    make([]string, 0),
 	}
 
 	// get retro
@@ -172,6 +219,7 @@ func (d *Service) RetroGetByID(retroID string, userID string) (*thunderdome.Retr
 	var facilitatorCode string
 	var facilitators string
 	var readyUsers string
+	var phases string
 	var template string
 	err := d.DB.QueryRow(
 		`SELECT
@@ -179,7 +227,7 @@ func (d *Service) RetroGetByID(retroID string, userID string) (*thunderdome.Retr
 			 COALESCE(r.join_code, ''), COALESCE(r.facilitator_code, ''), r.allow_cumulative_voting,
 			r.max_votes, r.brainstorm_visibility, r.ready_users, r.created_date, r.updated_date, r.template_id,
 			CASE WHEN COUNT(rf) = 0 THEN '[]'::json ELSE array_to_json(array_agg(rf.user_id)) END AS facilitators,
-			hide_votes_during_voting,
+			hide_votes_during_voting, r.anonymity, r.phases,
 			(SELECT row_to_json(t.*) as template FROM thunderdome.retro_template t WHERE t.id = r.template_id) AS template
 		FROM thunderdome.retro r
 		LEFT JOIN thunderdome.retro_facilitator rf ON r.id = rf.retro_id
@@ -206,6 +254,8 @@ func (d *Service) RetroGetByID(retroID string, userID string) (*thunderdome.Retr
 		&b.TemplateID,
 		&facilitators,
 		&b.HideVotesDuringVoting,
+		&b.Anonymity,
+		&phases,
 		&template,
 	)
 	if err != nil {
@@ -240,6 +290,11 @@ func (d *Service) RetroGetByID(retroID string, userID string) (*thunderdome.Retr
 		d.Logger.Error("ready users json error", zap.Error(readyUsersError))
 	}
 
+	phasesError := json.Unmarshal([]byte(phases), &b.Phases)
+	if phasesError != nil {
+		d.Logger.Error("retro phases json error", zap.Error(phasesError))
+	}
+
 	templateError := json.Unmarshal([]byte(template), &b.Template)
 	if templateError != nil {
 		d.Logger.Error("retro template json error", zap.Error(templateError))
@@ -250,7 +305,14 @@ func (d *Service) RetroGetByID(retroID string, userID string) (*thunderdome.Retr
 	b.Groups = d.GetRetroGroups(retroID)
 	b.Users = d.RetroGetUsers(retroID)
 	b.ActionItems = d.GetRetroActions(retroID)
+	if b.TeamID != "" {
+		b.ReviewActions = d.GetRetroReviewActions(retroID)
+	}
 	b.Votes = d.GetRetroVotes(retroID)
+	if dimensions := retrophase.HealthCheckDimensions(b.Phases); dimensions != nil {
+		b.HealthCheck = d.GetRetroHealthCheck(retroID, b.Phase, dimensions)
+		b.HealthCheckScores = d.GetRetroHealthCheckScores(retroID, userID)
+	}
 
 	return b, nil
 }
@@ -352,41 +414,100 @@ func (d *Service) RetroGetByUser(userID string, limit int, offset int) ([]*thund
 	return retros, count, nil
 }
 
-// RetroAdvancePhase sets the phase for the retro
-func (d *Service) RetroAdvancePhase(retroID string, phase string) (*thunderdome.Retro, error) {
-	var b thunderdome.Retro
-
-	// Start a transaction
-	tx, err := d.DB.Begin()
+// RetroAdvancePhase sets the phase for the retro, an empty phase advances the retro to the next phase
+// in its phase sequence, other phases must be part of the sequence
+func (d *Service) RetroAdvancePhase(ctx context.Context, retroID string, phase string) (*thunderdome.Retro, error) {
+	tx, err := d.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback() // Rollback if not committed
 
-	// Get previous retro phase
-	var previousPhase string
-	err = tx.QueryRow(
-		`SELECT phase FROM thunderdome.retro WHERE id = $1;`,
+	previousPhase, phases, err := d.getRetroPhaseForUpdate(ctx, tx, retroID)
+	if err != nil {
+		return nil, err
+	}
+
+	if phase == "" {
+		next, ok := retrophase.Next(phases, previousPhase)
+		if !ok {
+			return nil, errors.New("RETRO_NO_NEXT_PHASE")
+		}
+		phase = next
+	} else if !retrophase.Contains(phases, phase) {
+		return nil, errors.New("INVALID_RETRO_PHASE")
+	}
+
+	b, err := d.updateRetroPhase(ctx, tx, retroID, phases, previousPhase, phase)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	b.Items = d.GetRetroItems(retroID)
+	b.Groups = d.GetRetroGroups(retroID)
+	b.ActionItems = d.GetRetroActions(retroID)
+	b.Votes = d.GetRetroVotes(retroID)
+	if dimensions := retrophase.HealthCheckDimensions(phases); dimensions != nil {
+		b.HealthCheck = d.GetRetroHealthCheck(retroID, phase, dimensions)
+	}
+
+	return b, nil
+}
+
+// getRetroPhaseForUpdate gets the retro's current phase and phase sequence, locking the retro until the transaction ends
+func (d *Service) getRetroPhaseForUpdate(ctx context.Context, tx *sql.Tx, retroID string) (string, []thunderdome.RetroPhase, error) {
+	var phase string
+	var phasesJSON string
+	err := tx.QueryRowContext(ctx,
+		`SELECT phase, phases FROM thunderdome.retro WHERE id = $1 FOR UPDATE;`,
 		retroID,
-	).Scan(&previousPhase)
+	).Scan(&phase, &phasesJSON)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get previous retro phase: %w", err)
+		return "", nil, fmt.Errorf("failed to get previous retro phase: %w", err)
+	}
+
+	var phases []thunderdome.RetroPhase
+	if err = json.Unmarshal([]byte(phasesJSON), &phases); err != nil {
+		return "", nil, fmt.Errorf("retro phases json error: %w", err)
 	}
 
-	// Update the retro phase
-	err = tx.QueryRow(
+	return phase, phases, nil
+}
+
+// updateRetroPhase moves the retro to the phase and restarts the phase time, votes are cleared when
+// the vote phase is started from an earlier phase so the vote reflects the final groups and
+// leaving the health check phase saves its results to the team's health trend
+func (d *Service) updateRetroPhase(
+	ctx context.Context, tx *sql.Tx, retroID string, phases []thunderdome.RetroPhase, previousPhase string, phase string,
+) (*thunderdome.Retro, error) {
+	b := &thunderdome.Retro{ID: retroID, Phases: phases}
+
+	err := tx.QueryRowContext(ctx,
 		`UPDATE thunderdome.retro
 			SET updated_date = NOW(), phase = $2, phase_time_start = NOW(), ready_users = '[]'::jsonb
-			WHERE id = $1 RETURNING name, phase_time_start, hide_votes_during_voting, template_id;`,
+			WHERE id = $1
+			RETURNING name, phase, phase_time_limit_min, phase_time_start, phase_auto_advance, hide_votes_during_voting, template_id;`,
 		retroID, phase,
-	).Scan(&b.Name, &b.PhaseTimeStart, &b.HideVotesDuringVoting, &b.TemplateID)
+	).Scan(
+		&b.Name, &b.Phase, &b.PhaseTimeLimitMin, &b.PhaseTimeStart, &b.PhaseAutoAdvance,
+		&b.HideVotesDuringVoting, &b.TemplateID,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("retro advance phase query error: %v", err)
 	}
 
-	// Delete any existing user votes for the retro if advancing to voting phase from grouping phase
-	if previousPhase == "group" && phase == "vote" {
-		_, err := tx.Exec(`
+	if previousPhase == thunderdome.RetroPhaseHealthCheck && phase != previousPhase {
+		if err := saveTeamHealthCheck(ctx, tx, retroID); err != nil {
+			return nil, err
+		}
+	}
+
+	if phase == thunderdome.RetroPhaseVote && retrophase.Before(phases, previousPhase, phase) {
+		_, err := tx.ExecContext(ctx, `
             DELETE FROM thunderdome.retro_group_vote
             WHERE retro_id = $1;
         `, retroID)
@@ -396,19 +517,7 @@ func (d *Service) RetroAdvancePhase(retroID string, phase string) (*thunderdome.
 		}
 	}
 
-	// Commit the transaction
-	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	b.ID = retroID
-	b.Items = d.GetRetroItems(retroID)
-	b.Groups = d.GetRetroGroups(retroID)
-	b.ActionItems = d.GetRetroActions(retroID)
-	b.Votes = d.GetRetroVotes(retroID)
-	b.Phase = phase
-
-	return &b, nil
+	return b, nil
 }
 
 // RetroDelete removes all retro associations and the retro itself from DB by Id
@@ -16,7 +16,7 @@ func (d *Service) RetroGetUsers(retroID string) []*thunderdome.RetroUser {
 	var users = make([]*thunderdome.RetroUser, 0)
 	rows, err := d.DB.Query(
 		`SELECT
-			u.id, u.name, su.active, u.avatar, COALESCE(u.email, ''), COALESCE(u.picture, '')
+			u.id, u.name, u.type, su.active, u.avatar, COALESCE(u.email, ''), COALESCE(u.picture, '')
 		FROM thunderdome.retro_user su
 		LEFT JOIN thunderdome.users u ON su.user_id = u.id
 		WHERE su.retro_id = $1
@@ -27,7 +27,7 @@ func (d *Service) RetroGetUsers(retroID string) []*thunderdome.RetroUser {
 		defer rows.Close()
 		for rows.Next() {
 			var ru thunderdome.RetroUser
-			if err := rows.Scan(&ru.ID, &ru.Name, &ru.Active, &ru.Avatar, &ru.Email, &ru.PictureURL); err != nil {
+			if err := rows.Scan(&ru.ID, &ru.Name, &ru.Type, &ru.Active, &ru.Avatar, &ru.Email, &ru.PictureURL); err != nil {
 				d.Logger.Error("get retro users error", zap.Error(err))
 			} else {
 				if ru.Email != "" {
@@ -0,0 +1,314 @@
+package scim
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+const groupSelect = `SELECT id, display_name, external_id, team_id, department_id, created_date, updated_date
+	FROM thunderdome.scim_group`
+
+func scanGroup(scanner interface{ Scan(dest ...any) error }) (*thunderdome.SCIMGroup, error) {
+	g := thunderdome.SCIMGroup{Members: make([]*thunderdome.SCIMGroupMember, 0)}
+	err := scanner.Scan(
+		&g.ID,
+		&g.DisplayName,
+		&g.ExternalID,
+		&g.TeamID,
+		&g.DepartmentID,
+		&g.CreatedDate,
+		&g.UpdatedDate,
+	)
+
+	return &g, err
+}
+
+// groupMembers gets the members of a group's team
+func (d *Service) groupMembers(ctx context.Context, g *thunderdome.SCIMGroup) error {
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT u.id, COALESCE(u.name, '')
+		FROM thunderdome.team_user tu
+		JOIN thunderdome.users u ON u.id = tu.user_id
+		WHERE tu.team_id = $1
+		ORDER BY u.name;`,
+		g.TeamID,
+	)
+	if err != nil {
+		return fmt.Errorf("scim group members query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m thunderdome.SCIMGroupMember
+		if err := rows.Scan(&m.UserID, &m.Name); err != nil {
+			return fmt.Errorf("scim group members scan error: %v", err)
+		}
+		g.Members = append(g.Members, &m)
+	}
+
+	return nil
+}
+
+// GroupList gets the organization's provisioned groups with their members along with the total count,
+// optionally filtered by display name or external ID
+func (d *Service) GroupList(ctx context.Context, orgID string, displayName string, externalID string, limit int, offset int) ([]*thunderdome.SCIMGroup, int, error) {
+	groups := make([]*thunderdome.SCIMGroup, 0)
+	where := ` WHERE organization_id = $1
+		AND ($2 = '' OR display_name = $2)
+		AND ($3 = '' OR external_id = $3)`
+
+	var count int
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM thunderdome.scim_group`+where+`;`,
+		orgID,
+		displayName,
+		externalID,
+	).Scan(&count)
+	if err != nil {
+		return nil, 0, fmt.Errorf("scim group count query error: %v", err)
+	}
+
+	rows, err := d.DB.QueryContext(ctx,
+		groupSelect+where+` ORDER BY display_name LIMIT $4 OFFSET $5;`,
+		orgID,
+		displayName,
+		externalID,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("scim group list query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		g, err := scanGroup(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scim group list scan error: %v", err)
+		}
+		groups = append(groups, g)
+	}
+	rows.Close()
+
+	for _, g := range groups {
+		if err := d.groupMembers(ctx, g); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return groups, count, nil
+}
+
+// GroupGet gets a provisioned group of the organization with its members
+func (d *Service) GroupGet(ctx context.Context, orgID string, groupID string) (*thunderdome.SCIMGroup, error) {
+	g, err := scanGroup(d.DB.QueryRowContext(ctx,
+		groupSelect+` WHERE organization_id = $1 AND id = $2;`,
+		orgID,
+		groupID,
+	))
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("SCIM_GROUP_NOT_FOUND")
+	} else if err != nil {
+		return nil, fmt.Errorf("scim group get query error: %v", err)
+	}
+
+	if err := d.groupMembers(ctx, g); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// GroupCreate maps a new group to the organization team with the team name, within the department
+// with the department name when one is given, creating the department and team when they don't exist
+func (d *Service) GroupCreate(ctx context.Context, orgID string, displayName string, externalID string, departmentName string, teamName string) (*thunderdome.SCIMGroup, error) {
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("scim group create begin error: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM thunderdome.scim_group WHERE organization_id = $1 AND display_name = $2);`,
+		orgID, displayName,
+	).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("scim group create exists query error: %v", err)
+	}
+	if exists {
+		return nil, errors.New("SCIM_GROUP_EXISTS")
+	}
+
+	var departmentID *string
+	var teamID string
+	if departmentName != "" {
+		var id string
+		if err := tx.QueryRowContext(ctx,
+			`INSERT INTO thunderdome.organization_department (organization_id, name) VALUES ($1, $2)
+			ON CONFLICT (organization_id, name) DO UPDATE SET updated_date = NOW()
+			RETURNING id;`,
+			orgID, departmentName,
+		).Scan(&id); err != nil {
+			return nil, fmt.Errorf("scim group create department query error: %v", err)
+		}
+		departmentID = &id
+
+		err = tx.QueryRowContext(ctx,
+			`SELECT id FROM thunderdome.team WHERE department_id = $1 AND name = $2 ORDER BY created_date LIMIT 1;`,
+			id, teamName,
+		).Scan(&teamID)
+		if err != nil && errors.Is(err, sql.ErrNoRows) {
+			err = tx.QueryRowContext(ctx,
+				`INSERT INTO thunderdome.team (name, department_id) VALUES ($1, $2) RETURNING id;`,
+				teamName, id,
+			).Scan(&teamID)
+		}
+	} else {
+		err = tx.QueryRowContext(ctx,
+			`SELECT id FROM thunderdome.team WHERE organization_id = $1 AND name = $2 ORDER BY created_date LIMIT 1;`,
+			orgID, teamName,
+		).Scan(&teamID)
+		if err != nil && errors.Is(err, sql.ErrNoRows) {
+			err = tx.QueryRowContext(ctx,
+				`INSERT INTO thunderdome.team (name, organization_id) VALUES ($1, $2) RETURNING id;`,
+				teamName, orgID,
+			).Scan(&teamID)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scim group create team query error: %v", err)
+	}
+
+	var groupID string
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.scim_group (organization_id, display_name, external_id, team_id, department_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (team_id) DO NOTHING
+		RETURNING id;`,
+		orgID, displayName, externalID, teamID, departmentID,
+	).Scan(&groupID)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("SCIM_GROUP_EXISTS")
+	} else if err != nil {
+		return nil, fmt.Errorf("scim group create query error: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("scim group create commit error: %v", err)
+	}
+
+	return d.GroupGet(ctx, orgID, groupID)
+}
+
+// GroupUpdate updates a provisioned group's display name and external ID, renaming its team
+func (d *Service) GroupUpdate(ctx context.Context, orgID string, groupID string, displayName string, externalID string, teamName string) error {
+	result, err := d.DB.ExecContext(ctx,
+		`WITH g AS (
+			UPDATE thunderdome.scim_group SET display_name = $3, external_id = $4, updated_date = NOW()
+			WHERE organization_id = $1 AND id = $2
+			RETURNING team_id
+		)
+		UPDATE thunderdome.team SET name = $5, updated_date = NOW()
+		WHERE id = (SELECT team_id FROM g);`,
+		orgID,
+		groupID,
+		displayName,
+		externalID,
+		teamName,
+	)
+	if err != nil {
+		return fmt.Errorf("scim group update query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("SCIM_GROUP_NOT_FOUND")
+	}
+
+	return nil
+}
+
+// GroupDelete removes a group's mapping, the team and its members are kept
+func (d *Service) GroupDelete(ctx context.Context, orgID string, groupID string) error {
+	result, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.scim_group WHERE organization_id = $1 AND id = $2;`,
+		orgID,
+		groupID,
+	)
+	if err != nil {
+		return fmt.Errorf("scim group delete query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("SCIM_GROUP_NOT_FOUND")
+	}
+
+	return nil
+}
+
+// GroupMembersAdd adds the organization's provisioned users to the group's team, and its department if any,
+// users that are not provisioned into the organization are ignored
+func (d *Service) GroupMembersAdd(ctx context.Context, orgID string, groupID string, userIDs []string) error {
+	if _, err := d.DB.ExecContext(ctx,
+		`WITH g AS (
+			SELECT team_id, department_id FROM thunderdome.scim_group WHERE organization_id = $1 AND id = $2
+		), u AS (
+			SELECT su.user_id FROM thunderdome.scim_user su
+			WHERE su.organization_id = $1 AND su.user_id = ANY($3::uuid[])
+		), du AS (
+			INSERT INTO thunderdome.department_user (department_id, user_id, role)
+			SELECT g.department_id, u.user_id, $4 FROM g, u WHERE g.department_id IS NOT NULL
+			ON CONFLICT DO NOTHING
+		)
+		INSERT INTO thunderdome.team_user (team_id, user_id, role)
+		SELECT g.team_id, u.user_id, $4 FROM g, u
+		ON CONFLICT DO NOTHING;`,
+		orgID,
+		groupID,
+		userIDs,
+		thunderdome.EntityMemberUserType,
+	); err != nil {
+		return fmt.Errorf("scim group members add query error: %v", err)
+	}
+
+	return nil
+}
+
+// GroupMembersRemove removes users from the group's team
+func (d *Service) GroupMembersRemove(ctx context.Context, orgID string, groupID string, userIDs []string) error {
+	if _, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.team_user
+		WHERE team_id = (SELECT team_id FROM thunderdome.scim_group WHERE organization_id = $1 AND id = $2)
+		AND user_id = ANY($3::uuid[]);`,
+		orgID,
+		groupID,
+		userIDs,
+	); err != nil {
+		return fmt.Errorf("scim group members remove query error: %v", err)
+	}
+
+	return nil
+}
+
+// GroupMembersReplace sets the provisioned users of the group's team, members of the team
+// that weren't provisioned (e.g. added by a team admin) are kept
+func (d *Service) GroupMembersReplace(ctx context.Context, orgID string, groupID string, userIDs []string) error {
+	if userIDs == nil {
+		userIDs = make([]string, 0)
+	}
+
+	if _, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.team_user
+		WHERE team_id = (SELECT team_id FROM thunderdome.scim_group WHERE organization_id = $1 AND id = $2)
+		AND user_id IN (SELECT user_id FROM thunderdome.scim_user WHERE organization_id = $1)
+		AND NOT (user_id = ANY($3::uuid[]));`,
+		orgID,
+		groupID,
+		userIDs,
+	); err != nil {
+		return fmt.Errorf("scim group members replace query error: %v", err)
+	}
+
+	return d.GroupMembersAdd(ctx, orgID, groupID, userIDs)
+}
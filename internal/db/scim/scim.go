@@ -0,0 +1,126 @@
+// Package scim provides the organization SCIM provisioning database service
+package scim
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/db"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+)
+
+// Service represents the SCIM provisioning database service
+type Service struct {
+	DB     *sql.DB
+	Logger *otelzap.Logger
+}
+
+// TokenList gets the organization's SCIM tokens, the token values are never returned
+func (d *Service) TokenList(ctx context.Context, orgID string) ([]*thunderdome.SCIMToken, error) {
+	tokens := make([]*thunderdome.SCIMToken, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT id, organization_id, name, prefix, created_by, last_used, created_date
+		FROM thunderdome.organization_scim_token
+		WHERE organization_id = $1
+		ORDER BY created_date;`,
+		orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scim token list query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t thunderdome.SCIMToken
+		if err := rows.Scan(
+			&t.ID,
+			&t.OrganizationID,
+			&t.Name,
+			&t.Prefix,
+			&t.CreatedBy,
+			&t.LastUsed,
+			&t.CreatedDate,
+		); err != nil {
+			return nil, fmt.Errorf("scim token list scan error: %v", err)
+		}
+		tokens = append(tokens, &t)
+	}
+
+	return tokens, nil
+}
+
+// TokenCreate creates a SCIM token for the organization, the token value is only returned on creation
+func (d *Service) TokenCreate(ctx context.Context, orgID string, createdBy string, name string) (*thunderdome.SCIMToken, error) {
+	prefix, prefixErr := db.RandomString(8)
+	if prefixErr != nil {
+		return nil, fmt.Errorf("error generating scim token prefix: %v", prefixErr)
+	}
+	secret, secretErr := db.RandomString(32)
+	if secretErr != nil {
+		return nil, fmt.Errorf("error generating scim token secret: %v", secretErr)
+	}
+
+	t := thunderdome.SCIMToken{
+		OrganizationID: orgID,
+		Name:           name,
+		Prefix:         prefix,
+		Token:          prefix + "." + secret,
+		CreatedBy:      &createdBy,
+	}
+
+	err := d.DB.QueryRowContext(ctx,
+		`INSERT INTO thunderdome.organization_scim_token (organization_id, name, prefix, token_hash, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_date;`,
+		orgID,
+		name,
+		prefix,
+		db.HashString(t.Token),
+		createdBy,
+	).Scan(&t.ID, &t.CreatedDate)
+	if err != nil {
+		return nil, fmt.Errorf("scim token create query error: %v", err)
+	}
+
+	return &t, nil
+}
+
+// TokenDelete deletes an organization's SCIM token
+func (d *Service) TokenDelete(ctx context.Context, orgID string, tokenID string) error {
+	result, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.organization_scim_token WHERE organization_id = $1 AND id = $2;`,
+		orgID,
+		tokenID,
+	)
+	if err != nil {
+		return fmt.Errorf("scim token delete query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("SCIM_TOKEN_NOT_FOUND")
+	}
+
+	return nil
+}
+
+// TokenOrganization gets the ID of the organization a SCIM token belongs to and marks the token used
+func (d *Service) TokenOrganization(ctx context.Context, token string) (string, error) {
+	var orgID string
+
+	err := d.DB.QueryRowContext(ctx,
+		`UPDATE thunderdome.organization_scim_token SET last_used = NOW()
+		WHERE token_hash = $1
+		RETURNING organization_id;`,
+		db.HashString(token),
+	).Scan(&orgID)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return "", errors.New("INVALID_SCIM_TOKEN")
+	} else if err != nil {
+		return "", fmt.Errorf("scim token organization query error: %v", err)
+	}
+
+	return orgID, nil
+}
@@ -0,0 +1,198 @@
+package scim
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+const userSelect = `SELECT u.id, su.external_id, COALESCE(u.name, ''), COALESCE(u.email, ''),
+	su.active AND NOT COALESCE(u.disabled, false),
+	su.created_user AND NOT EXISTS (
+		SELECT 1 FROM thunderdome.scim_user o WHERE o.user_id = su.user_id AND o.organization_id <> su.organization_id
+	),
+	su.created_date, su.updated_date
+	FROM thunderdome.scim_user su
+	JOIN thunderdome.users u ON u.id = su.user_id`
+
+func scanUser(scanner interface{ Scan(dest ...any) error }) (*thunderdome.SCIMUser, error) {
+	var u thunderdome.SCIMUser
+	err := scanner.Scan(
+		&u.ID,
+		&u.ExternalID,
+		&u.Name,
+		&u.Email,
+		&u.Active,
+		&u.Owned,
+		&u.CreatedDate,
+		&u.UpdatedDate,
+	)
+
+	return &u, err
+}
+
+// UserList gets the organization's provisioned users along with the total count,
+// optionally filtered by email (userName) or external ID
+func (d *Service) UserList(ctx context.Context, orgID string, email string, externalID string, limit int, offset int) ([]*thunderdome.SCIMUser, int, error) {
+	users := make([]*thunderdome.SCIMUser, 0)
+	where := ` WHERE su.organization_id = $1
+		AND ($2 = '' OR lower(u.email) = lower($2))
+		AND ($3 = '' OR su.external_id = $3)`
+
+	var count int
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM thunderdome.scim_user su
+		JOIN thunderdome.users u ON u.id = su.user_id`+where+`;`,
+		orgID,
+		email,
+		externalID,
+	).Scan(&count)
+	if err != nil {
+		return nil, 0, fmt.Errorf("scim user count query error: %v", err)
+	}
+
+	rows, err := d.DB.QueryContext(ctx,
+		userSelect+where+` ORDER BY su.created_date, u.id LIMIT $4 OFFSET $5;`,
+		orgID,
+		email,
+		externalID,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("scim user list query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scim user list scan error: %v", err)
+		}
+		users = append(users, u)
+	}
+
+	return users, count, nil
+}
+
+// UserGet gets a user provisioned into the organization
+func (d *Service) UserGet(ctx context.Context, orgID string, userID string) (*thunderdome.SCIMUser, error) {
+	u, err := scanUser(d.DB.QueryRowContext(ctx,
+		userSelect+` WHERE su.organization_id = $1 AND su.user_id = $2;`,
+		orgID,
+		userID,
+	))
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("SCIM_USER_NOT_FOUND")
+	} else if err != nil {
+		return nil, fmt.Errorf("scim user get query error: %v", err)
+	}
+
+	return u, nil
+}
+
+// UserLink marks a user as provisioned into the organization, adding them as an organization member,
+// created records whether the provisioning created the user
+func (d *Service) UserLink(ctx context.Context, orgID string, userID string, externalID string, created bool) error {
+	if _, err := d.DB.ExecContext(ctx,
+		`WITH ou AS (
+			INSERT INTO thunderdome.organization_user (organization_id, user_id, role)
+			VALUES ($1, $2, $4) ON CONFLICT DO NOTHING
+		)
+		INSERT INTO thunderdome.scim_user (organization_id, user_id, external_id, created_user)
+		VALUES ($1, $2, $3, $5)
+		ON CONFLICT (organization_id, user_id) DO UPDATE
+		SET external_id = EXCLUDED.external_id, updated_date = NOW();`,
+		orgID,
+		userID,
+		externalID,
+		thunderdome.EntityMemberUserType,
+		created,
+	); err != nil {
+		return fmt.Errorf("scim user link query error: %v", err)
+	}
+
+	return nil
+}
+
+// UserUpdate updates the external ID of a user provisioned into the organization,
+// the user's name is only updated when the organization owns the user
+func (d *Service) UserUpdate(ctx context.Context, orgID string, userID string, externalID string, name string) error {
+	var updated int
+	err := d.DB.QueryRowContext(ctx,
+		`WITH su AS (
+			UPDATE thunderdome.scim_user SET external_id = $3, updated_date = NOW()
+			WHERE organization_id = $1 AND user_id = $2
+			RETURNING user_id, created_user
+		), u AS (
+			UPDATE thunderdome.users SET name = $4, updated_date = NOW()
+			WHERE id = (SELECT user_id FROM su WHERE created_user)
+			AND NOT EXISTS (
+				SELECT 1 FROM thunderdome.scim_user o WHERE o.user_id = $2 AND o.organization_id <> $1
+			)
+		)
+		SELECT COUNT(*) FROM su;`,
+		orgID,
+		userID,
+		externalID,
+		name,
+	).Scan(&updated)
+	if err != nil {
+		return fmt.Errorf("scim user update query error: %v", err)
+	}
+	if updated == 0 {
+		return errors.New("SCIM_USER_NOT_FOUND")
+	}
+
+	return nil
+}
+
+// UserSetActive sets whether a user provisioned into the organization is active,
+// an activated user is added back to the organization as a member
+func (d *Service) UserSetActive(ctx context.Context, orgID string, userID string, active bool) error {
+	var updated int
+	err := d.DB.QueryRowContext(ctx,
+		`WITH su AS (
+			UPDATE thunderdome.scim_user SET active = $3, updated_date = NOW()
+			WHERE organization_id = $1 AND user_id = $2
+			RETURNING user_id
+		), ou AS (
+			INSERT INTO thunderdome.organization_user (organization_id, user_id, role)
+			SELECT $1, user_id, $4 FROM su WHERE $3
+			ON CONFLICT DO NOTHING
+		)
+		SELECT COUNT(*) FROM su;`,
+		orgID,
+		userID,
+		active,
+		thunderdome.EntityMemberUserType,
+	).Scan(&updated)
+	if err != nil {
+		return fmt.Errorf("scim user set active query error: %v", err)
+	}
+	if updated == 0 {
+		return errors.New("SCIM_USER_NOT_FOUND")
+	}
+
+	return nil
+}
+
+// UserUnlink removes a user's provisioning record for the organization
+func (d *Service) UserUnlink(ctx context.Context, orgID string, userID string) error {
+	result, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.scim_user WHERE organization_id = $1 AND user_id = $2;`,
+		orgID,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("scim user unlink query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("SCIM_USER_NOT_FOUND")
+	}
+
+	return nil
+}
@@ -16,7 +16,7 @@ func (d *Service) GetStoryboardUsers(storyboardID string) []*thunderdome.Storybo
 	var users = make([]*thunderdome.StoryboardUser, 0)
 	rows, err := d.DB.Query(
 		`SELECT
-			w.id, w.name, su.active, w.avatar, COALESCE(w.email, ''), COALESCE(w.picture, '')
+			w.id, w.name, w.type, su.active, w.avatar, COALESCE(w.email, ''), COALESCE(w.picture, '')
 		FROM thunderdome.storyboard_user su
 		LEFT JOIN thunderdome.users w ON su.user_id = w.id
 		WHERE su.storyboard_id = $1
@@ -27,7 +27,7 @@ func (d *Service) GetStoryboardUsers(storyboardID string) []*thunderdome.Storybo
 		defer rows.Close()
 		for rows.Next() {
 			var su thunderdome.StoryboardUser
-			if err := rows.Scan(&su.ID, &su.Name, &su.Active, &su.Avatar, &su.GravatarHash, &su.PictureURL); err != nil {
+			if err := rows.Scan(&su.ID, &su.Name, &su.Type, &su.Active, &su.Avatar, &su.GravatarHash, &su.PictureURL); err != nil {
 				d.Logger.Error("get_storyboard_users query scan error", zap.Error(err))
 			} else {
 				if su.GravatarHash != "" {
@@ -0,0 +1,220 @@
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/pointvalue"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// estimationStory is a voted story along with the raw details needed to summarize team estimation metrics
+type estimationStory struct {
+	metrics   *thunderdome.StoryEstimationMetrics
+	votes     []thunderdome.Vote
+	scaleID   string
+	scaleName string
+}
+
+// GetTeamEstimationMetrics retrieves estimation accuracy and velocity metrics from the stories voted on
+// in the team's poker games between from (inclusive) and to (exclusive), grouped by week or month
+func (d *Service) GetTeamEstimationMetrics(ctx context.Context, teamID string, from time.Time, to time.Time, period string) (*thunderdome.TeamEstimationMetrics, error) {
+	rows, err := d.DB.QueryContext(ctx, `
+		SELECT
+			ps.id, ps.poker_id, COALESCE(ps.name, ''), COALESCE(ps.points, ''), COALESCE(ps.skipped, false),
+			COALESCE(ps.votes, '[]'::jsonb), ps.voteend_time,
+			COALESCE(es.id::text, ''), COALESCE(es.name, ''),
+			(SELECT COUNT(*) FROM thunderdome.poker_story_vote_round vr WHERE vr.story_id = ps.id) AS rounds
+		FROM thunderdome.poker_story ps
+		JOIN thunderdome.poker p ON p.id = ps.poker_id
+		LEFT JOIN thunderdome.estimation_scale es ON es.id = p.estimation_scale_id
+		WHERE p.team_id = $1
+			AND ps.voteend_time >= $2 AND ps.voteend_time < $3
+			AND ps.active = false
+			AND (ps.skipped = true OR ps.points <> '')
+		ORDER BY ps.voteend_time DESC;`,
+		teamID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get team estimation metrics query error: %v", err)
+	}
+	defer rows.Close()
+
+	stories := make([]estimationStory, 0)
+	for rows.Next() {
+		var votes string
+		s := estimationStory{metrics: &thunderdome.StoryEstimationMetrics{}}
+		if err := rows.Scan(
+			&s.metrics.StoryID,
+			&s.metrics.PokerID,
+			&s.metrics.Name,
+			&s.metrics.Points,
+			&s.metrics.Skipped,
+			&votes,
+			&s.metrics.VoteEnd,
+			&s.scaleID,
+			&s.scaleName,
+			&s.metrics.Rounds,
+		); err != nil {
+			return nil, fmt.Errorf("get team estimation metrics scan error: %v", err)
+		}
+		if err := json.Unmarshal([]byte(votes), &s.votes); err != nil {
+			d.Logger.Ctx(ctx).Error("team estimation metrics votes json error", zap.Error(err),
+				zap.String("story_id", s.metrics.StoryID))
+		}
+		stories = append(stories, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get team estimation metrics rows error: %v", err)
+	}
+
+	metrics := summarizeEstimationMetrics(stories, period)
+	metrics.TeamID = teamID
+	metrics.From = from
+	metrics.To = to
+
+	return metrics, nil
+}
+
+// summarizeEstimationMetrics aggregates the voted stories into team estimation metrics
+func summarizeEstimationMetrics(stories []estimationStory, period string) *thunderdome.TeamEstimationMetrics {
+	metrics := &thunderdome.TeamEstimationMetrics{
+		Period:            period,
+		Periods:           make([]*thunderdome.EstimationPeriodMetrics, 0),
+		PointDistribution: make([]*thunderdome.EstimationScaleDistribution, 0),
+		Stories:           make([]*thunderdome.StoryEstimationMetrics, 0, len(stories)),
+	}
+	periods := make(map[time.Time]*thunderdome.EstimationPeriodMetrics)
+	scales := make(map[string]*thunderdome.EstimationScaleDistribution)
+	scaleCounts := make(map[string]map[string]int)
+	var consensusCount, consensusRounds int
+
+	for _, s := range stories {
+		sm := s.metrics
+		spread, voteCount, consensus := voteSpread(s.votes)
+		sm.VoteCount = voteCount
+		sm.Spread = spread
+		sm.Consensus = consensus && !sm.Skipped
+		metrics.Stories = append(metrics.Stories, sm)
+		metrics.StoryCount++
+
+		start := estimationPeriodStart(sm.VoteEnd, period)
+		p, ok := periods[start]
+		if !ok {
+			p = &thunderdome.EstimationPeriodMetrics{PeriodStart: start}
+			periods[start] = p
+			metrics.Periods = append(metrics.Periods, p)
+		}
+
+		if sm.Skipped {
+			metrics.SkippedCount++
+			p.SkippedCount++
+			continue
+		}
+
+		metrics.EstimatedCount++
+		p.EstimatedCount++
+		if points, ok := pointvalue.Parse(sm.Points); ok {
+			p.Points += points
+		}
+		// rounds are only counted for stories the team reached consensus on
+		if sm.Consensus {
+			consensusCount++
+			consensusRounds += max(sm.Rounds, 1)
+		}
+
+		if _, ok := scales[s.scaleID]; !ok {
+			scales[s.scaleID] = &thunderdome.EstimationScaleDistribution{
+				EstimationScaleID:   s.scaleID,
+				EstimationScaleName: s.scaleName,
+			}
+			scaleCounts[s.scaleID] = make(map[string]int)
+			metrics.PointDistribution = append(metrics.PointDistribution, scales[s.scaleID])
+		}
+		scaleCounts[s.scaleID][sm.Points]++
+	}
+
+	if metrics.StoryCount > 0 {
+		metrics.SkipRate = float64(metrics.SkippedCount) / float64(metrics.StoryCount)
+	}
+	if metrics.EstimatedCount > 0 {
+		metrics.ConsensusRate = float64(consensusCount) / float64(metrics.EstimatedCount)
+	}
+	if consensusCount > 0 {
+		metrics.AverageRoundsToConsensus = float64(consensusRounds) / float64(consensusCount)
+	}
+
+	sort.Slice(metrics.Periods, func(i, j int) bool {
+		return metrics.Periods[i].PeriodStart.Before(metrics.Periods[j].PeriodStart)
+	})
+	for _, scale := range metrics.PointDistribution {
+		scale.Points = make([]*thunderdome.EstimationPointsCount, 0, len(scaleCounts[scale.EstimationScaleID]))
+		for points, count := range scaleCounts[scale.EstimationScaleID] {
+			scale.Points = append(scale.Points, &thunderdome.EstimationPointsCount{Points: points, StoryCount: count})
+		}
+		sort.Slice(scale.Points, func(i, j int) bool {
+			return pointvalue.Less(scale.Points[i].Points, scale.Points[j].Points)
+		})
+	}
+
+	return metrics
+}
+
+// voteSpread returns the difference between the highest and lowest numeric votes (nil when no votes are numeric),
+// the number of votes cast, and whether every vote cast was the same value
+func voteSpread(votes []thunderdome.Vote) (*float64, int, bool) {
+	var low, high float64
+	var numeric, count int
+	consensus := true
+	first := ""
+
+	for _, v := range votes {
+		if v.VoteValue == "" {
+			continue
+		}
+		count++
+		if first == "" {
+			first = v.VoteValue
+		} else if v.VoteValue != first {
+			consensus = false
+		}
+
+		value, ok := pointvalue.Parse(v.VoteValue)
+		if !ok {
+			continue
+		}
+		if numeric == 0 || value < low {
+			low = value
+		}
+		if numeric == 0 || value > high {
+			high = value
+		}
+		numeric++
+	}
+
+	if count == 0 {
+		return nil, 0, false
+	}
+	if numeric == 0 {
+		return nil, count, consensus
+	}
+
+	spread := high - low
+	return &spread, count, consensus
+}
+
+// estimationPeriodStart returns the start of the UTC week (Monday) or month containing t
+func estimationPeriodStart(t time.Time, period string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if period == thunderdome.EstimationPeriodMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,97 @@
+package team
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func TestEstimationPeriodStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		t      time.Time
+		period string
+		want   time.Time
+	}{
+		{
+			name:   "week from wednesday",
+			t:      time.Date(2026, 10, 14, 15, 30, 0, 0, time.UTC),
+			period: thunderdome.EstimationPeriodWeek,
+			want:   time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "week from sunday",
+			t:      time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC),
+			period: thunderdome.EstimationPeriodWeek,
+			want:   time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "month",
+			t:      time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC),
+			period: thunderdome.EstimationPeriodMonth,
+			want:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimationPeriodStart(tt.t, tt.period); !got.Equal(tt.want) {
+				t.Errorf("estimationPeriodStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeEstimationMetrics(t *testing.T) {
+	voteEnd := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	story := func(points string, skipped bool, rounds int, votes ...string) estimationStory {
+		s := estimationStory{
+			metrics: &thunderdome.StoryEstimationMetrics{
+				Points:  points,
+				Skipped: skipped,
+				Rounds:  rounds,
+				VoteEnd: voteEnd,
+			},
+			scaleID:   "scale",
+			scaleName: "Fibonacci",
+		}
+		for _, v := range votes {
+			s.votes = append(s.votes, thunderdome.Vote{VoteValue: v})
+		}
+		return s
+	}
+
+	metrics := summarizeEstimationMetrics([]estimationStory{
+		story("5", false, 1, "5", "5", "5"),
+		story("8", false, 3, "5", "8", "13"),
+		story("", true, 1, "?"),
+		story("3", false, 0, "3", "?"),
+		story("2", false, 2, "2", "2"),
+	}, thunderdome.EstimationPeriodWeek)
+
+	if metrics.StoryCount != 5 || metrics.EstimatedCount != 4 || metrics.SkippedCount != 1 {
+		t.Fatalf("unexpected counts: stories %d, estimated %d, skipped %d",
+			metrics.StoryCount, metrics.EstimatedCount, metrics.SkippedCount)
+	}
+	if metrics.SkipRate != 0.2 {
+		t.Errorf("SkipRate = %v, want 0.2", metrics.SkipRate)
+	}
+	if metrics.ConsensusRate != 0.5 {
+		t.Errorf("ConsensusRate = %v, want 0.5", metrics.ConsensusRate)
+	}
+	// the 3 rounds of the story without consensus aren't counted
+	if metrics.AverageRoundsToConsensus != 1.5 {
+		t.Errorf("AverageRoundsToConsensus = %v, want 1.5", metrics.AverageRoundsToConsensus)
+	}
+	if len(metrics.Periods) != 1 || metrics.Periods[0].Points != 18 {
+		t.Errorf("Periods = %+v, want a single period with 18 points", metrics.Periods)
+	}
+	if spread := metrics.Stories[1].Spread; spread == nil || *spread != 8 {
+		t.Errorf("Spread = %v, want 8", spread)
+	}
+	if len(metrics.PointDistribution) != 1 || len(metrics.PointDistribution[0].Points) != 4 ||
+		metrics.PointDistribution[0].Points[0].Points != "2" {
+		t.Errorf("PointDistribution = %+v, want four point values ordered numerically", metrics.PointDistribution)
+	}
+}
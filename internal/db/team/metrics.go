@@ -169,5 +169,38 @@ func (d *Service) GetTeamMetrics(ctx context.Context, teamID string) (*thunderdo
 		return nil, fmt.Errorf("unable to get team metrics: %v", err)
 	}
 
+	metrics.HealthCheck, err = d.getTeamHealthCheckTrend(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &metrics, nil
 }
+
+// getTeamHealthCheckTrend gets the team's retro health check results oldest first
+func (d *Service) getTeamHealthCheckTrend(ctx context.Context, teamID string) ([]*thunderdome.TeamHealthCheck, error) {
+	trend := make([]*thunderdome.TeamHealthCheck, 0)
+
+	rows, err := d.DB.QueryContext(ctx, `
+		SELECT COALESCE(retro_id::TEXT, ''), retro_name, dimension, average_score, response_count, created_date
+		FROM thunderdome.team_health_check
+		WHERE team_id = $1
+		ORDER BY created_date, dimension
+	`, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get team health check trend: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hc thunderdome.TeamHealthCheck
+		if err := rows.Scan(
+			&hc.RetroID, &hc.RetroName, &hc.Dimension, &hc.Average, &hc.Responses, &hc.CreatedDate,
+		); err != nil {
+			return nil, fmt.Errorf("unable to scan team health check trend: %v", err)
+		}
+		trend = append(trend, &hc)
+	}
+
+	return trend, rows.Err()
+}
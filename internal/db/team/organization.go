@@ -148,7 +148,7 @@ func (d *OrganizationService) OrganizationUpdate(ctx context.Context, orgID stri
 func (d *OrganizationService) OrganizationUserList(ctx context.Context, orgID string, limit int, offset int) []*thunderdome.OrganizationUser {
 	var users = make([]*thunderdome.OrganizationUser, 0)
 	rows, err := d.DB.QueryContext(ctx,
-		`SELECT u.id, u.name, COALESCE(u.email, ''), ou.role, u.avatar, COALESCE(u.picture, '')
+		`SELECT u.id, u.name, COALESCE(u.email, ''), u.type, ou.role, u.avatar, COALESCE(u.picture, '')
         FROM thunderdome.organization_user ou
         LEFT JOIN thunderdome.users u ON ou.user_id = u.id
         WHERE ou.organization_id = $1
@@ -169,6 +169,7 @@ func (d *OrganizationService) OrganizationUserList(ctx context.Context, orgID st
 				&usr.ID,
 				&usr.Name,
 				&usr.Email,
+				&usr.Type,
 				&usr.Role,
 				&usr.Avatar,
 				&usr.PictureURL,
@@ -234,9 +235,25 @@ func (d *OrganizationService) OrganizationUpdateUser(ctx context.Context, orgID
 	return orgID, nil
 }
 
-// OrganizationRemoveUser removes a user from an organization
+// OrganizationRemoveUser removes a user from an organization, the organization's service accounts
+// are only removed by deleting them so they never outlive their membership
 func (d *OrganizationService) OrganizationRemoveUser(ctx context.Context, orgID string, userID string) error {
-	_, err := d.DB.ExecContext(ctx,
+	var isServiceAccount bool
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT EXISTS(
+			SELECT 1 FROM thunderdome.organization_service_account WHERE organization_id = $1 AND user_id = $2
+		);`,
+		orgID,
+		userID,
+	).Scan(&isServiceAccount)
+	if err != nil {
+		return fmt.Errorf("organization remove user service account query error: %v", err)
+	}
+	if isServiceAccount {
+		return errors.New("SERVICE_ACCOUNT_MEMBER")
+	}
+
+	_, err = d.DB.ExecContext(ctx,
 		`CALL thunderdome.organization_user_remove($1, $2);`,
 		orgID,
 		userID,
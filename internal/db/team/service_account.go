@@ -0,0 +1,162 @@
+package team
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// OrganizationServiceAccountList gets a list of the organization's service accounts
+func (d *OrganizationService) OrganizationServiceAccountList(ctx context.Context, orgID string) ([]*thunderdome.OrganizationServiceAccount, error) {
+	accounts := make([]*thunderdome.OrganizationServiceAccount, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT sa.user_id, sa.organization_id, u.name, sa.description, sa.created_by,
+		(SELECT COUNT(*) FROM thunderdome.api_key ak WHERE ak.user_id = sa.user_id),
+		sa.created_date, sa.updated_date
+		FROM thunderdome.organization_service_account sa
+		JOIN thunderdome.users u ON u.id = sa.user_id
+		WHERE sa.organization_id = $1
+		ORDER BY u.name;`,
+		orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("organization service account list query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sa thunderdome.OrganizationServiceAccount
+		if err := rows.Scan(
+			&sa.ID,
+			&sa.OrganizationID,
+			&sa.Name,
+			&sa.Description,
+			&sa.CreatedBy,
+			&sa.APIKeyCount,
+			&sa.CreatedDate,
+			&sa.UpdatedDate,
+		); err != nil {
+			return nil, fmt.Errorf("organization service account list scan error: %v", err)
+		}
+		accounts = append(accounts, &sa)
+	}
+
+	return accounts, nil
+}
+
+// OrganizationServiceAccountGet gets an organization's service account by ID
+func (d *OrganizationService) OrganizationServiceAccountGet(ctx context.Context, orgID string, accountID string) (*thunderdome.OrganizationServiceAccount, error) {
+	var sa thunderdome.OrganizationServiceAccount
+
+	err := d.DB.QueryRowContext(ctx,
+		`SELECT sa.user_id, sa.organization_id, u.name, sa.description, sa.created_by,
+		(SELECT COUNT(*) FROM thunderdome.api_key ak WHERE ak.user_id = sa.user_id),
+		sa.created_date, sa.updated_date
+		FROM thunderdome.organization_service_account sa
+		JOIN thunderdome.users u ON u.id = sa.user_id
+		WHERE sa.organization_id = $1 AND sa.user_id = $2;`,
+		orgID,
+		accountID,
+	).Scan(
+		&sa.ID,
+		&sa.OrganizationID,
+		&sa.Name,
+		&sa.Description,
+		&sa.CreatedBy,
+		&sa.APIKeyCount,
+		&sa.CreatedDate,
+		&sa.UpdatedDate,
+	)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("SERVICE_ACCOUNT_NOT_FOUND")
+	} else if err != nil {
+		return nil, fmt.Errorf("organization service account get query error: %v", err)
+	}
+
+	return &sa, nil
+}
+
+// OrganizationServiceAccountCreate creates a service account user owned by the organization,
+// the service account is added to the organization as a member so it can be added to teams
+func (d *OrganizationService) OrganizationServiceAccountCreate(ctx context.Context, orgID string, createdBy string, name string, description string) (*thunderdome.OrganizationServiceAccount, error) {
+	sa := thunderdome.OrganizationServiceAccount{
+		OrganizationID: orgID,
+		Name:           name,
+		Description:    description,
+		CreatedBy:      &createdBy,
+	}
+
+	err := d.DB.QueryRowContext(ctx,
+		`WITH u AS (
+			INSERT INTO thunderdome.users (name, type, verified, notifications_enabled)
+			VALUES ($2, $5, true, false)
+			RETURNING id
+		), sa AS (
+			INSERT INTO thunderdome.organization_service_account (user_id, organization_id, description, created_by)
+			SELECT id, $1, $3, $4 FROM u
+			RETURNING user_id, created_date, updated_date
+		), ou AS (
+			INSERT INTO thunderdome.organization_user (organization_id, user_id, role)
+			SELECT $1, id, $6 FROM u
+		)
+		SELECT user_id, created_date, updated_date FROM sa;`,
+		orgID,
+		name,
+		description,
+		createdBy,
+		thunderdome.ServiceUserType,
+		thunderdome.EntityMemberUserType,
+	).Scan(&sa.ID, &sa.CreatedDate, &sa.UpdatedDate)
+	if err != nil {
+		return nil, fmt.Errorf("organization service account create query error: %v", err)
+	}
+
+	return &sa, nil
+}
+
+// OrganizationServiceAccountUpdate updates an organization's service account name and description
+func (d *OrganizationService) OrganizationServiceAccountUpdate(ctx context.Context, orgID string, accountID string, name string, description string) (*thunderdome.OrganizationServiceAccount, error) {
+	result, err := d.DB.ExecContext(ctx,
+		`WITH sa AS (
+			UPDATE thunderdome.organization_service_account
+			SET description = $3, updated_date = NOW()
+			WHERE organization_id = $1 AND user_id = $2
+			RETURNING user_id
+		)
+		UPDATE thunderdome.users SET name = $4, updated_date = NOW()
+		WHERE id = (SELECT user_id FROM sa);`,
+		orgID,
+		accountID,
+		description,
+		name,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("organization service account update query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, errors.New("SERVICE_ACCOUNT_NOT_FOUND")
+	}
+
+	return d.OrganizationServiceAccountGet(ctx, orgID, accountID)
+}
+
+// OrganizationServiceAccountDelete deletes an organization's service account along with its user and API keys
+func (d *OrganizationService) OrganizationServiceAccountDelete(ctx context.Context, orgID string, accountID string) error {
+	result, err := d.DB.ExecContext(ctx,
+		`DELETE FROM thunderdome.organization_service_account WHERE organization_id = $1 AND user_id = $2;`,
+		orgID,
+		accountID,
+	)
+	if err != nil {
+		return fmt.Errorf("organization service account delete query error: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("SERVICE_ACCOUNT_NOT_FOUND")
+	}
+
+	return nil
+}
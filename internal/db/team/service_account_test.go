@@ -0,0 +1,112 @@
+package team
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const (
+	testOrgID            = "6d2c1f8e-0a4b-4c1e-9d7f-2b3a4c5d6e7f"
+	testServiceAccountID = "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"
+)
+
+func newTestOrganizationService(t *testing.T) (*OrganizationService, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return &OrganizationService{
+		DB:     db,
+		Logger: otelzap.New(zap.NewNop()),
+	}, mock
+}
+
+func TestOrganizationRemoveUser(t *testing.T) {
+	tests := []struct {
+		name             string
+		isServiceAccount bool
+		wantErr          string
+	}{
+		{name: "member removed", isServiceAccount: false},
+		{name: "service account kept", isServiceAccount: true, wantErr: "SERVICE_ACCOUNT_MEMBER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newTestOrganizationService(t)
+			mock.ExpectQuery(`FROM thunderdome.organization_service_account WHERE organization_id = \$1 AND user_id = \$2`).
+				WithArgs(testOrgID, testServiceAccountID).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(tt.isServiceAccount))
+			if !tt.isServiceAccount {
+				mock.ExpectExec(`CALL thunderdome.organization_user_remove`).
+					WithArgs(testOrgID, testServiceAccountID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+
+			err := s.OrganizationRemoveUser(context.Background(), testOrgID, testServiceAccountID)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("OrganizationRemoveUser() error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("OrganizationRemoveUser() error = %v, want %s", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestOrganizationServiceAccountGetOtherOrganization(t *testing.T) {
+	s, mock := newTestOrganizationService(t)
+	mock.ExpectQuery(`WHERE sa.organization_id = \$1 AND sa.user_id = \$2`).
+		WithArgs(testOrgID, testServiceAccountID).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := s.OrganizationServiceAccountGet(context.Background(), testOrgID, testServiceAccountID)
+	if err == nil || err.Error() != "SERVICE_ACCOUNT_NOT_FOUND" {
+		t.Fatalf("OrganizationServiceAccountGet() error = %v, want SERVICE_ACCOUNT_NOT_FOUND", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOrganizationServiceAccountDelete(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		wantErr      string
+	}{
+		{name: "deleted", rowsAffected: 1},
+		{name: "not owned by organization", rowsAffected: 0, wantErr: "SERVICE_ACCOUNT_NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newTestOrganizationService(t)
+			mock.ExpectExec(`DELETE FROM thunderdome.organization_service_account WHERE organization_id = \$1 AND user_id = \$2`).
+				WithArgs(testOrgID, testServiceAccountID).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			err := s.OrganizationServiceAccountDelete(context.Background(), testOrgID, testServiceAccountID)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("OrganizationServiceAccountDelete() error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("OrganizationServiceAccountDelete() error = %v, want %s", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -119,7 +119,7 @@ func (d *Service) TeamUserList(ctx context.Context, teamID string, limit int, of
 	}
 
 	rows, err := d.DB.QueryContext(ctx,
-		`SELECT u.id, u.name, COALESCE(u.email, ''), tu.role, u.avatar, COALESCE(u.picture, '')
+		`SELECT u.id, u.name, COALESCE(u.email, ''), u.type, tu.role, u.avatar, COALESCE(u.picture, '')
         FROM thunderdome.team_user tu
         LEFT JOIN thunderdome.users u ON tu.user_id = u.id
         WHERE tu.team_id = $1
@@ -140,6 +140,7 @@ func (d *Service) TeamUserList(ctx context.Context, teamID string, limit int, of
 				&usr.ID,
 				&usr.Name,
 				&usr.Email,
+				&usr.Type,
 				&usr.Role,
 				&usr.Avatar,
 				&usr.PictureURL,
@@ -25,6 +25,19 @@ type Config struct {
 	DefaultEstimationScale []string
 }
 
+// ConnString returns the postgres connection string for the configuration
+func (c *Config) ConnString() string {
+	return fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		c.Host,
+		c.Port,
+		c.User,
+		c.Password,
+		c.Name,
+		c.SSLMode,
+	)
+}
+
 // Service contains all the methods to interact with DB
 type Service struct {
 	Config              *Config
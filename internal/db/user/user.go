@@ -209,6 +209,41 @@ func (d *Service) CreateUserGuest(ctx context.Context, userName string) (*thunde
 	return &thunderdome.User{ID: userID, Name: userName, Avatar: "robohash", NotificationsEnabled: true, Locale: "en", GravatarHash: db.CreateGravatarHash(userID), Type: thunderdome.GuestUserType}, nil
 }
 
+// ProvisionUser gets the user with the email or creates a registered user without a password
+// for an identity provider, returning whether the user was created.
+// The user signs in through the identity provider linked by email
+func (d *Service) ProvisionUser(ctx context.Context, userName string, userEmail string) (*thunderdome.User, bool, error) {
+	sanitizedEmail := db.SanitizeEmail(userEmail)
+	user := &thunderdome.User{
+		Email:        sanitizedEmail,
+		Type:         thunderdome.RegisteredUserType,
+		Avatar:       "robohash",
+		GravatarHash: db.CreateGravatarHash(sanitizedEmail),
+	}
+	var created bool
+
+	err := d.DB.QueryRowContext(ctx,
+		`WITH existing AS (
+			SELECT id, name, type, disabled FROM thunderdome.users WHERE lower(email) = lower($2)
+		), created AS (
+			INSERT INTO thunderdome.users (name, email, type, verified)
+			SELECT $1, $2, $3, true WHERE NOT EXISTS (SELECT 1 FROM existing)
+			RETURNING id, name, type, disabled
+		)
+		SELECT id, name, type, disabled, false FROM existing
+		UNION ALL
+		SELECT id, name, type, disabled, true FROM created;`,
+		userName,
+		sanitizedEmail,
+		thunderdome.RegisteredUserType,
+	).Scan(&user.ID, &user.Name, &user.Type, &user.Disabled, &created)
+	if err != nil {
+		return nil, false, fmt.Errorf("provision user query error: %v", err)
+	}
+
+	return user, created, nil
+}
+
 // CreateUserRegistered adds a new registered user
 func (d *Service) CreateUserRegistered(ctx context.Context, userName string, userEmail string, userPassword string, activeUserID string) (newUser *thunderdome.User, verifyID string, registerErr error) {
 	hashedPassword, hashErr := db.HashSaltPassword(userPassword)
@@ -510,6 +545,32 @@ func (d *Service) DemoteUser(ctx context.Context, userID string) error {
 	return nil
 }
 
+// GetActiveRegisteredUsers gets all registered and admin users that aren't disabled
+func (d *Service) GetActiveRegisteredUsers(ctx context.Context) ([]*thunderdome.User, error) {
+	var users = make([]*thunderdome.User, 0)
+
+	rows, err := d.DB.QueryContext(ctx,
+		`SELECT u.id, u.name, u.email, u.type
+		FROM thunderdome.users u
+		WHERE u.type IN ('REGISTERED', 'ADMIN') AND u.disabled = false AND COALESCE(u.email, '') <> ''
+		ORDER BY u.created_date;`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get active registered users query error: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u thunderdome.User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Type); err != nil {
+			return nil, fmt.Errorf("get active registered users scan error: %v", err)
+		}
+		users = append(users, &u)
+	}
+
+	return users, nil
+}
+
 // DisableUser disables a user from logging in
 func (d *Service) DisableUser(ctx context.Context, userID string) error {
 	if _, err := d.DB.ExecContext(ctx,
@@ -3,6 +3,7 @@ package db
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/md5"
 	"crypto/rand"
 	"crypto/sha256"
@@ -79,6 +80,14 @@ func HashString(s string) string {
 	return result
 }
 
+// HMACString hashes the string using HMAC-SHA256 keyed with the key (not reversible or guessable without the key)
+func HMACString(s string, key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(s))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 // HashSaltPassword takes a password byte then salt + hashes it returning a hash string
 func HashSaltPassword(password string) (string, error) {
 	pwd := []byte(password)
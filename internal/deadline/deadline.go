@@ -0,0 +1,110 @@
+// Package deadline fires actions once their deadlines run out, the deadlines are loaded
+// from a shared store (the database) so they survive restarts and are shared by all instances.
+package deadline
+
+import (
+	"context"
+	"time"
+)
+
+// Clock provides the tickers a Scheduler waits on
+type Clock interface {
+	// Tick returns a channel receiving the current time every interval and a func to stop it
+	Tick(interval time.Duration) (<-chan time.Time, func())
+}
+
+type realClock struct{}
+
+func (realClock) Tick(interval time.Duration) (<-chan time.Time, func()) {
+	t := time.NewTicker(interval)
+	return t.C, t.Stop
+}
+
+// Config configures a Scheduler
+type Config[K comparable] struct {
+	// how often deadlines are reloaded, picking up changes made on other instances
+	ReloadInterval time.Duration
+	// how often loaded deadlines are checked for having run out
+	TickInterval time.Duration
+	// Load returns the current deadlines keyed by what they belong to,
+	// on error the previously loaded deadlines are kept
+	Load func(ctx context.Context) (map[K]time.Time, error)
+	// Fire is called once for each deadline that ran out,
+	// returning true reloads the deadlines (e.g. when firing started a new timed period)
+	Fire func(ctx context.Context, key K) bool
+	// Clock defaults to the system clock
+	Clock Clock
+}
+
+// Scheduler tracks deadlines and fires them once they run out
+type Scheduler[K comparable] struct {
+	config    Config[K]
+	deadlines map[K]time.Time
+	reload    chan struct{}
+}
+
+// New returns a new Scheduler, call Run to start it
+func New[K comparable](config Config[K]) *Scheduler[K] {
+	if config.Clock == nil {
+		config.Clock = realClock{}
+	}
+
+	return &Scheduler[K]{
+		config:    config,
+		deadlines: make(map[K]time.Time),
+		reload:    make(chan struct{}, 1),
+	}
+}
+
+// Run tracks and fires deadlines until the context is done
+func (s *Scheduler[K]) Run(ctx context.Context) {
+	reloadC, stopReload := s.config.Clock.Tick(s.config.ReloadInterval)
+	defer stopReload()
+	tickC, stopTick := s.config.Clock.Tick(s.config.TickInterval)
+	defer stopTick()
+
+	s.load(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-reloadC:
+			s.load(ctx)
+		case <-s.reload:
+			s.load(ctx)
+		case now := <-tickC:
+			s.fire(ctx, now)
+		}
+	}
+}
+
+// RequestReload reloads the deadlines on the next loop, used after a deadline is set or changed
+func (s *Scheduler[K]) RequestReload() {
+	select {
+	case s.reload <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler[K]) load(ctx context.Context) {
+	deadlines, err := s.config.Load(ctx)
+	if err != nil {
+		return
+	}
+
+	s.deadlines = deadlines
+}
+
+func (s *Scheduler[K]) fire(ctx context.Context, now time.Time) {
+	for key, deadline := range s.deadlines {
+		if now.Before(deadline) {
+			continue
+		}
+		delete(s.deadlines, key)
+
+		if s.config.Fire(ctx, key) {
+			s.RequestReload()
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package deadline
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testReloadInterval = time.Minute
+	testTickInterval   = time.Second
+)
+
+// fakeClock lets tests send the ticks the scheduler waits on
+type fakeClock struct {
+	tickers map[time.Duration]chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{tickers: map[time.Duration]chan time.Time{
+		testReloadInterval: make(chan time.Time),
+		testTickInterval:   make(chan time.Time),
+	}}
+}
+
+func (c *fakeClock) Tick(interval time.Duration) (<-chan time.Time, func()) {
+	return c.tickers[interval], func() {}
+}
+
+// tick blocks until the scheduler has received the tick, so anything it does in response
+// has finished by the time the next tick (or the test's context cancel) is received
+func (c *fakeClock) tick(interval time.Duration, now time.Time) {
+	c.tickers[interval] <- now
+}
+
+// testStore is a fake deadline store recording what the scheduler loaded and fired
+type testStore struct {
+	mu        sync.Mutex
+	deadlines map[string]time.Time
+	loadErr   error
+	fired     []string
+	// firing moves the key's deadline on by advance and requests a reload, like a retro advancing to a timed phase
+	advance time.Duration
+	loaded  chan struct{}
+}
+
+func newTestStore(deadlines map[string]time.Time) *testStore {
+	return &testStore{deadlines: deadlines, loaded: make(chan struct{}, 10)}
+}
+
+func (s *testStore) load(_ context.Context) (map[string]time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer func() { s.loaded <- struct{}{} }()
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+
+	deadlines := make(map[string]time.Time, len(s.deadlines))
+	for k, d := range s.deadlines {
+		deadlines[k] = d
+	}
+	return deadlines, nil
+}
+
+func (s *testStore) fire(_ context.Context, key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fired = append(s.fired, key)
+	if s.advance == 0 {
+		return false
+	}
+	s.deadlines[key] = s.deadlines[key].Add(s.advance)
+	return true
+}
+
+func (s *testStore) set(f func(s *testStore)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s)
+}
+
+func (s *testStore) firedKeys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.fired)
+}
+
+func (s *testStore) waitLoad(t *testing.T) {
+	t.Helper()
+	select {
+	case <-s.loaded:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the scheduler to load deadlines")
+	}
+}
+
+func startScheduler(t *testing.T, store *testStore) (*Scheduler[string], *fakeClock, func()) {
+	t.Helper()
+	clock := newFakeClock()
+	s := New(Config[string]{
+		ReloadInterval: testReloadInterval,
+		TickInterval:   testTickInterval,
+		Load:           store.load,
+		Fire:           store.fire,
+		Clock:          clock,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	store.waitLoad(t)
+
+	return s, clock, func() {
+		cancel()
+		<-done
+	}
+}
+
+func TestSchedulerFiresExpiredDeadlines(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(map[string]time.Time{
+		"expired": now.Add(-time.Second),
+		"due":     now,
+		"later":   now.Add(time.Minute),
+	})
+	_, clock, stop := startScheduler(t, store)
+
+	clock.tick(testTickInterval, now)
+	// already fired deadlines are not fired again
+	clock.tick(testTickInterval, now)
+	stop()
+
+	fired := store.firedKeys()
+	slices.Sort(fired)
+	if !slices.Equal(fired, []string{"due", "expired"}) {
+		t.Errorf("fired = %v, want [due expired]", fired)
+	}
+}
+
+func TestSchedulerFiresAfterDeadlinePasses(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(map[string]time.Time{"retro": now.Add(time.Minute)})
+	_, clock, stop := startScheduler(t, store)
+
+	clock.tick(testTickInterval, now)
+	if fired := store.firedKeys(); len(fired) != 0 {
+		t.Fatalf("fired = %v before the deadline", fired)
+	}
+	clock.tick(testTickInterval, now.Add(time.Minute))
+	stop()
+
+	if fired := store.firedKeys(); !slices.Equal(fired, []string{"retro"}) {
+		t.Errorf("fired = %v, want [retro]", fired)
+	}
+}
+
+func TestSchedulerReloads(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(map[string]time.Time{})
+	_, clock, stop := startScheduler(t, store)
+
+	// a deadline set on another instance is picked up on the reload interval
+	store.set(func(s *testStore) { s.deadlines["retro"] = now })
+	clock.tick(testReloadInterval, now)
+	store.waitLoad(t)
+	clock.tick(testTickInterval, now)
+	stop()
+
+	if fired := store.firedKeys(); !slices.Equal(fired, []string{"retro"}) {
+		t.Errorf("fired = %v, want [retro]", fired)
+	}
+}
+
+func TestSchedulerRequestReload(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(map[string]time.Time{})
+	s, clock, stop := startScheduler(t, store)
+
+	store.set(func(s *testStore) { s.deadlines["retro"] = now })
+	s.RequestReload()
+	// pending reloads are coalesced instead of blocking the caller
+	s.RequestReload()
+	store.waitLoad(t)
+	clock.tick(testTickInterval, now)
+	stop()
+
+	if fired := store.firedKeys(); !slices.Equal(fired, []string{"retro"}) {
+		t.Errorf("fired = %v, want [retro]", fired)
+	}
+}
+
+func TestSchedulerReloadsWhenFireRequests(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(map[string]time.Time{"retro": now})
+	store.advance = time.Minute
+	_, clock, stop := startScheduler(t, store)
+
+	clock.tick(testTickInterval, now)
+	// the next phase's deadline is loaded after the retro advanced
+	store.waitLoad(t)
+	clock.tick(testTickInterval, now)
+	if fired := store.firedKeys(); len(fired) != 1 {
+		t.Fatalf("fired = %v before the next phase's deadline", fired)
+	}
+	clock.tick(testTickInterval, now.Add(time.Minute))
+	stop()
+
+	if fired := store.firedKeys(); !slices.Equal(fired, []string{"retro", "retro"}) {
+		t.Errorf("fired = %v, want retro fired for both phases", fired)
+	}
+}
+
+func TestSchedulerKeepsDeadlinesOnLoadError(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(map[string]time.Time{"retro": now})
+	_, clock, stop := startScheduler(t, store)
+
+	store.set(func(s *testStore) { s.loadErr = errors.New("connection refused") })
+	clock.tick(testReloadInterval, now)
+	store.waitLoad(t)
+	clock.tick(testTickInterval, now)
+	stop()
+
+	if fired := store.firedKeys(); !slices.Equal(fired, []string{"retro"}) {
+		t.Errorf("fired = %v, want [retro]", fired)
+	}
+}
@@ -0,0 +1,205 @@
+// Package directory provides LDAP directory authentication, group authorization and user sync
+package directory
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// Config holds the configuration for the LDAP directory
+type Config struct {
+	URL      string
+	UseTLS   bool
+	Bindname string
+	Bindpass string
+	BaseDN   string
+	// Filter finds a user by the username entered at login, %s is replaced with the username
+	Filter   string
+	MailAttr string
+	CnAttr   string
+	// GroupAttr is the user attribute listing the DNs of the user's groups, e.g. memberOf
+	GroupAttr string
+	// AllowedGroups restricts login to members of the groups, anyone in the directory may log in when empty
+	AllowedGroups []string
+	AdminGroups   []string
+	GroupMappings []thunderdome.GroupMapping
+	// SyncFilter finds all the directory's users during a sync
+	SyncFilter string
+}
+
+// Entry is a user found in the directory, groups include both the full DN and common name of each group
+type Entry struct {
+	DN     string
+	Email  string
+	Name   string
+	Groups []string
+}
+
+// Service is the LDAP directory service
+type Service struct {
+	config      Config
+	logger      *otelzap.Logger
+	userDataSvc UserDataSvc
+	authDataSvc AuthDataSvc
+}
+
+// New creates a new LDAP directory service, groups are matched case-insensitively
+func New(config Config, logger *otelzap.Logger, userDataSvc UserDataSvc, authDataSvc AuthDataSvc) *Service {
+	config.AllowedGroups = lowerAll(config.AllowedGroups)
+	config.AdminGroups = lowerAll(config.AdminGroups)
+	mappings := make([]thunderdome.GroupMapping, 0, len(config.GroupMappings))
+	for _, m := range config.GroupMappings {
+		m.Group = strings.ToLower(strings.TrimSpace(m.Group))
+		mappings = append(mappings, m)
+	}
+	config.GroupMappings = mappings
+
+	return &Service{
+		config:      config,
+		logger:      logger,
+		userDataSvc: userDataSvc,
+		authDataSvc: authDataSvc,
+	}
+}
+
+// connect dials the directory and binds with the configured service account
+func (s *Service) connect() (*ldap.Conn, error) {
+	l, err := ldap.DialURL(s.config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed connecting to ldap server at %s: %v", s.config.URL, err)
+	}
+
+	if s.config.UseTLS {
+		if err = l.StartTLS(&tls.Config{InsecureSkipVerify: true}); err != nil {
+			l.Close()
+			return nil, fmt.Errorf("failed securing ldap connection: %v", err)
+		}
+	}
+
+	if s.config.Bindname != "" {
+		if err = l.Bind(s.config.Bindname, s.config.Bindpass); err != nil {
+			l.Close()
+			return nil, fmt.Errorf("failed binding to ldap server: %v", err)
+		}
+	}
+
+	return l, nil
+}
+
+// attributes gets the user attributes to request in searches
+func (s *Service) attributes() []string {
+	attributes := []string{"dn", s.config.MailAttr, s.config.CnAttr}
+	if s.config.GroupAttr != "" {
+		attributes = append(attributes, s.config.GroupAttr)
+	}
+
+	return attributes
+}
+
+// entry converts an LDAP search entry to a directory user
+func (s *Service) entry(e *ldap.Entry) *Entry {
+	var groups []string
+	if s.config.GroupAttr != "" {
+		groups = entryGroups(e.GetAttributeValues(s.config.GroupAttr))
+	}
+
+	return &Entry{
+		DN:     e.DN,
+		Email:  e.GetAttributeValue(s.config.MailAttr),
+		Name:   e.GetAttributeValue(s.config.CnAttr),
+		Groups: groups,
+	}
+}
+
+// Authenticate finds the user by username and verifies their password, returning an error when the user
+// isn't a member of an allowed group
+func (s *Service) Authenticate(ctx context.Context, username string, password string) (*Entry, error) {
+	l, err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer l.Close()
+
+	sr, err := l.Search(ldap.NewSearchRequest(s.config.BaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf(s.config.Filter, ldap.EscapeFilter(username)),
+		s.attributes(),
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed performing ldap search query: %v", err)
+	}
+
+	if len(sr.Entries) != 1 {
+		return nil, errors.New("user not found")
+	}
+	entry := s.entry(sr.Entries[0])
+
+	if err = l.Bind(entry.DN, password); err != nil {
+		return nil, fmt.Errorf("failed authenticating user: %v", err)
+	}
+
+	if !groupAllowed(entry.Groups, s.config.AllowedGroups) {
+		s.logger.Ctx(ctx).Warn("ldap user not in an allowed group", zap.String("dn", entry.DN))
+		return nil, errors.New("LDAP_GROUP_NOT_ALLOWED")
+	}
+
+	return entry, nil
+}
+
+// entryGroups normalizes the group DNs of a user into the lower case DN and common name of each group,
+// so groups can be configured by either
+func entryGroups(dns []string) []string {
+	groups := make([]string, 0, len(dns)*2)
+
+	for _, dn := range dns {
+		dn = strings.ToLower(strings.TrimSpace(dn))
+		if dn == "" {
+			continue
+		}
+		groups = append(groups, dn)
+
+		parsed, err := ldap.ParseDN(dn)
+		if err != nil || len(parsed.RDNs) == 0 || len(parsed.RDNs[0].Attributes) == 0 {
+			continue
+		}
+		if name := parsed.RDNs[0].Attributes[0].Value; !slices.Contains(groups, name) {
+			groups = append(groups, name)
+		}
+	}
+
+	return groups
+}
+
+// groupAllowed checks if any of the user's groups is allowed, all users are allowed when no groups are configured
+func groupAllowed(groups []string, allowedGroups []string) bool {
+	if len(allowedGroups) == 0 {
+		return true
+	}
+
+	for _, g := range groups {
+		if slices.Contains(allowedGroups, g) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, 0, len(values))
+	for _, v := range values {
+		lowered = append(lowered, strings.ToLower(strings.TrimSpace(v)))
+	}
+
+	return lowered
+}
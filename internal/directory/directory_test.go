@@ -0,0 +1,65 @@
+package directory
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEntryGroups(t *testing.T) {
+	tests := []struct {
+		name string
+		dns  []string
+		want []string
+	}{
+		{
+			name: "dn and common name",
+			dns:  []string{"CN=Engineering,OU=Groups,DC=example,DC=com"},
+			want: []string{"cn=engineering,ou=groups,dc=example,dc=com", "engineering"},
+		},
+		{
+			name: "skips empty values",
+			dns:  []string{"", "  "},
+			want: []string{},
+		},
+		{
+			name: "invalid dn keeps raw value",
+			dns:  []string{"Engineering"},
+			want: []string{"engineering"},
+		},
+		{
+			name: "duplicate common names",
+			dns:  []string{"cn=devs,ou=a,dc=example,dc=com", "cn=devs,ou=b,dc=example,dc=com"},
+			want: []string{"cn=devs,ou=a,dc=example,dc=com", "devs", "cn=devs,ou=b,dc=example,dc=com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := entryGroups(tt.dns); !slices.Equal(got, tt.want) {
+				t.Errorf("entryGroups() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		groups  []string
+		allowed []string
+		want    bool
+	}{
+		{name: "no allowed groups", groups: nil, allowed: nil, want: true},
+		{name: "member of allowed group", groups: []string{"cn=devs,dc=example,dc=com", "devs"}, allowed: []string{"devs"}, want: true},
+		{name: "not a member", groups: []string{"ops"}, allowed: []string{"devs"}, want: false},
+		{name: "no groups", groups: nil, allowed: []string{"devs"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := groupAllowed(tt.groups, tt.allowed); got != tt.want {
+				t.Errorf("groupAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
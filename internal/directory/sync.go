@@ -0,0 +1,168 @@
+package directory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/groupmap"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/go-ldap/ldap/v3"
+	"go.uber.org/zap"
+)
+
+// UserDataSvc is an interface for the user data service
+type UserDataSvc interface {
+	GetActiveRegisteredUsers(ctx context.Context) ([]*thunderdome.User, error)
+	DisableUser(ctx context.Context, userID string) error
+}
+
+// AuthDataSvc is an interface for the auth data service
+type AuthDataSvc interface {
+	SyncGroupMemberships(ctx context.Context, userID string, memberships []thunderdome.GroupMembership, userType string) error
+}
+
+// SyncResult is the outcome of a directory sync
+type SyncResult struct {
+	// DirectoryUsers is the number of users in the directory allowed to log in
+	DirectoryUsers int
+	Disabled       int
+	GroupsSynced   int
+	// Failed is the number of users that couldn't be disabled or have their groups synced,
+	// they're retried on the next sync
+	Failed int
+}
+
+// SyncUserGroups applies the user's directory groups to their mapped memberships and user type,
+// returning the user type the groups grant or an empty type when the user type isn't managed
+func (s *Service) SyncUserGroups(ctx context.Context, userID string, entry *Entry) (string, error) {
+	if !s.groupsManaged() {
+		return "", nil
+	}
+
+	userType := groupmap.UserType(entry.Groups, s.config.AdminGroups)
+	if err := s.authDataSvc.SyncGroupMemberships(
+		ctx, userID, groupmap.Memberships(entry.Groups, s.config.GroupMappings), userType,
+	); err != nil {
+		return "", err
+	}
+
+	return userType, nil
+}
+
+// groupsManaged checks if directory groups manage memberships or the user type
+func (s *Service) groupsManaged() bool {
+	return len(s.config.GroupMappings) > 0 || len(s.config.AdminGroups) > 0
+}
+
+// Users gets all the directory's users that are allowed to log in, keyed by lower case email
+func (s *Service) Users(ctx context.Context) (map[string]*Entry, error) {
+	l, err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer l.Close()
+
+	sr, err := l.SearchWithPaging(ldap.NewSearchRequest(s.config.BaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		s.config.SyncFilter,
+		s.attributes(),
+		nil,
+	), 500)
+	if err != nil {
+		return nil, fmt.Errorf("failed performing ldap sync search query: %v", err)
+	}
+
+	users := make(map[string]*Entry, len(sr.Entries))
+	for _, e := range sr.Entries {
+		entry := s.entry(e)
+		if entry.Email == "" || !groupAllowed(entry.Groups, s.config.AllowedGroups) {
+			continue
+		}
+		users[strings.ToLower(entry.Email)] = entry
+	}
+
+	return users, nil
+}
+
+// Sync disables users that are no longer in the directory, or no longer in an allowed group,
+// and applies the directory groups of the remaining users
+func (s *Service) Sync(ctx context.Context) (SyncResult, error) {
+	entries, err := s.Users(ctx)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	// an empty result is far more likely a misconfigured filter or base DN than an empty directory
+	if len(entries) == 0 {
+		return SyncResult{}, errors.New("directory returned no users, skipping sync")
+	}
+
+	return s.syncEntries(ctx, entries)
+}
+
+// syncEntries applies the directory's users to the active registered users,
+// a user that fails to sync is logged and counted without stopping the sync of the others
+func (s *Service) syncEntries(ctx context.Context, entries map[string]*Entry) (SyncResult, error) {
+	result := SyncResult{DirectoryUsers: len(entries)}
+
+	users, err := s.userDataSvc.GetActiveRegisteredUsers(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	for _, u := range users {
+		entry, ok := entries[strings.ToLower(u.Email)]
+		if !ok {
+			if err := s.userDataSvc.DisableUser(ctx, u.ID); err != nil {
+				s.logger.Ctx(ctx).Error("ldap directory sync disable user error", zap.Error(err),
+					zap.String("user_id", u.ID))
+				result.Failed++
+				continue
+			}
+			s.logger.Ctx(ctx).Info("disabled user no longer in ldap directory", zap.String("user_id", u.ID))
+			result.Disabled++
+			continue
+		}
+
+		if !s.groupsManaged() {
+			continue
+		}
+		if _, err := s.SyncUserGroups(ctx, u.ID, entry); err != nil {
+			s.logger.Ctx(ctx).Error("ldap directory sync user groups error", zap.Error(err),
+				zap.String("user_id", u.ID))
+			result.Failed++
+			continue
+		}
+		result.GroupsSynced++
+	}
+
+	return result, nil
+}
+
+// Start runs the directory sync every interval until the context is done
+func (s *Service) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				result, err := s.Sync(ctx)
+				if err != nil {
+					s.logger.Ctx(ctx).Error("ldap directory sync error", zap.Error(err))
+					continue
+				}
+				s.logger.Ctx(ctx).Info("ldap directory sync complete",
+					zap.Int("directory_users", result.DirectoryUsers),
+					zap.Int("disabled", result.Disabled),
+					zap.Int("groups_synced", result.GroupsSynced),
+					zap.Int("failed", result.Failed))
+			}
+		}
+	}()
+}
@@ -0,0 +1,76 @@
+package directory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+type fakeUserDataSvc struct {
+	users      []*thunderdome.User
+	disableErr map[string]error
+	disabled   []string
+}
+
+func (f *fakeUserDataSvc) GetActiveRegisteredUsers(_ context.Context) ([]*thunderdome.User, error) {
+	return f.users, nil
+}
+
+func (f *fakeUserDataSvc) DisableUser(_ context.Context, userID string) error {
+	if err := f.disableErr[userID]; err != nil {
+		return err
+	}
+	f.disabled = append(f.disabled, userID)
+	return nil
+}
+
+type fakeAuthDataSvc struct {
+	syncErr map[string]error
+	synced  []string
+}
+
+func (f *fakeAuthDataSvc) SyncGroupMemberships(_ context.Context, userID string, _ []thunderdome.GroupMembership, _ string) error {
+	if err := f.syncErr[userID]; err != nil {
+		return err
+	}
+	f.synced = append(f.synced, userID)
+	return nil
+}
+
+func TestSyncContinuesPastFailedUsers(t *testing.T) {
+	userSvc := &fakeUserDataSvc{
+		users: []*thunderdome.User{
+			{ID: "gone-1", Email: "gone1@example.com"},
+			{ID: "gone-2", Email: "gone2@example.com"},
+			{ID: "member-1", Email: "Member1@example.com"},
+			{ID: "member-2", Email: "member2@example.com"},
+		},
+		disableErr: map[string]error{"gone-1": errors.New("connection reset")},
+	}
+	authSvc := &fakeAuthDataSvc{syncErr: map[string]error{"member-1": errors.New("connection reset")}}
+	s := New(Config{AdminGroups: []string{"admins"}}, otelzap.New(zap.NewNop()), userSvc, authSvc)
+
+	result, err := s.syncEntries(context.Background(), map[string]*Entry{
+		"member1@example.com": {Email: "member1@example.com"},
+		"member2@example.com": {Email: "member2@example.com", Groups: []string{"admins"}},
+	})
+	if err != nil {
+		t.Fatalf("syncEntries() error = %v", err)
+	}
+
+	want := SyncResult{DirectoryUsers: 2, Disabled: 1, GroupsSynced: 1, Failed: 2}
+	if result != want {
+		t.Errorf("syncEntries() = %+v, want %+v", result, want)
+	}
+	// the users after each failure are still synced
+	if len(userSvc.disabled) != 1 || userSvc.disabled[0] != "gone-2" {
+		t.Errorf("disabled users = %v, want [gone-2]", userSvc.disabled)
+	}
+	if len(authSvc.synced) != 1 || authSvc.synced[0] != "member-2" {
+		t.Errorf("synced users = %v, want [member-2]", authSvc.synced)
+	}
+}
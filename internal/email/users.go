@@ -2,6 +2,7 @@ package email
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/matcornic/hermes/v2"
 	"go.uber.org/zap"
@@ -231,6 +232,54 @@ func (s *Service) SendPasswordUpdate(userName string, userEmail string) error {
 	return nil
 }
 
+// SendAccountLocked Sends an account temporarily locked notice to user after repeated failed logins
+func (s *Service) SendAccountLocked(userName string, userEmail string, lockedUntil time.Time) error {
+	emailBody, err := s.generateBody(
+		hermes.Body{
+			Name: userName,
+			Intros: []string{
+				"Your Thunderdome account has been temporarily locked after too many failed login attempts.",
+				fmt.Sprintf("You will be able to log in again after %s.", lockedUntil.UTC().Format("Jan 2, 2006 15:04 MST")),
+			},
+			Actions: []hermes.Action{
+				{
+					Instructions: "If this wasn't you, we recommend resetting your password.",
+					Button: hermes.Button{
+						Text: "Reset Password",
+						Link: s.Config.AppURL + "login",
+					},
+				},
+				{
+					Instructions: "Need help, or have questions? Visit our Github page",
+					Button: hermes.Button{
+						Text: "Github Repo",
+						Link: s.Config.RepoURL,
+					},
+				},
+			},
+		},
+	)
+	if err != nil {
+		s.Logger.Error("Error Generating Account Locked Email HTML", zap.Error(err),
+			zap.String("user_email", userEmail))
+		return err
+	}
+
+	sendErr := s.send(
+		userName,
+		userEmail,
+		"Your Thunderdome account has been temporarily locked",
+		emailBody,
+	)
+	if sendErr != nil {
+		s.Logger.Error("Error sending Account Locked Email", zap.Error(sendErr),
+			zap.String("user_email", userEmail))
+		return sendErr
+	}
+
+	return nil
+}
+
 // SendDeleteConfirmation Sends an delete account confirmation email to user
 func (s *Service) SendDeleteConfirmation(userName string, userEmail string) error {
 	emailBody, err := s.generateBody(
@@ -0,0 +1,64 @@
+// Package groupmap resolves identity provider groups into organization, department and team memberships
+package groupmap
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// Memberships resolves the user's groups against the group mappings into the role the user should have
+// in each mapped organization, department and team. An admin role from any group wins over a member role,
+// and mapped entities the user has no group for get an empty role
+func Memberships(groups []string, mappings []thunderdome.GroupMapping) []thunderdome.GroupMembership {
+	memberships := make([]thunderdome.GroupMembership, 0, len(mappings))
+	index := make(map[thunderdome.GroupMembership]int)
+
+	for _, m := range mappings {
+		key := thunderdome.GroupMembership{TeamID: m.TeamID}
+		if m.TeamID == "" {
+			key.DepartmentID = m.DepartmentID
+			if m.DepartmentID == "" {
+				key.OrganizationID = m.OrganizationID
+			}
+		}
+
+		role := ""
+		if slices.Contains(groups, m.Group) {
+			role = thunderdome.EntityMemberUserType
+			if strings.EqualFold(m.Role, thunderdome.AdminUserType) {
+				role = thunderdome.AdminUserType
+			}
+		}
+
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(memberships)
+			key.Role = role
+			memberships = append(memberships, key)
+			continue
+		}
+		if role == thunderdome.AdminUserType || memberships[i].Role == "" {
+			memberships[i].Role = role
+		}
+	}
+
+	return memberships
+}
+
+// UserType gets the user type the user's groups grant, an empty type means the user type isn't managed
+// because no admin groups are configured
+func UserType(groups []string, adminGroups []string) string {
+	if len(adminGroups) == 0 {
+		return ""
+	}
+
+	for _, g := range groups {
+		if slices.Contains(adminGroups, g) {
+			return thunderdome.AdminUserType
+		}
+	}
+
+	return thunderdome.RegisteredUserType
+}
@@ -0,0 +1,80 @@
+package groupmap
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func TestMemberships(t *testing.T) {
+	mappings := []thunderdome.GroupMapping{
+		{Group: "engineering", OrganizationID: "org", Role: "member"},
+		{Group: "eng-leads", OrganizationID: "org", Role: "admin"},
+		{Group: "platform", OrganizationID: "org", DepartmentID: "dept", TeamID: "team", Role: "member"},
+		{Group: "design", DepartmentID: "design", Role: "admin"},
+	}
+
+	tests := []struct {
+		name   string
+		groups []string
+		want   []thunderdome.GroupMembership
+	}{
+		{
+			name:   "admin wins",
+			groups: []string{"eng-leads", "engineering"},
+			want: []thunderdome.GroupMembership{
+				{OrganizationID: "org", Role: thunderdome.AdminUserType},
+				{TeamID: "team"},
+				{DepartmentID: "design"},
+			},
+		},
+		{
+			name:   "member not downgraded by missing group",
+			groups: []string{"engineering", "platform", "design"},
+			want: []thunderdome.GroupMembership{
+				{OrganizationID: "org", Role: thunderdome.EntityMemberUserType},
+				{TeamID: "team", Role: thunderdome.EntityMemberUserType},
+				{DepartmentID: "design", Role: thunderdome.AdminUserType},
+			},
+		},
+		{
+			name:   "no groups removes",
+			groups: []string{},
+			want: []thunderdome.GroupMembership{
+				{OrganizationID: "org"},
+				{TeamID: "team"},
+				{DepartmentID: "design"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Memberships(tt.groups, mappings); !slices.Equal(got, tt.want) {
+				t.Errorf("Memberships() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserType(t *testing.T) {
+	tests := []struct {
+		name        string
+		groups      []string
+		adminGroups []string
+		want        string
+	}{
+		{name: "unmanaged", groups: []string{"admins"}, adminGroups: nil, want: ""},
+		{name: "admin", groups: []string{"engineering", "admins"}, adminGroups: []string{"admins"}, want: thunderdome.AdminUserType},
+		{name: "registered", groups: []string{"engineering"}, adminGroups: []string{"admins"}, want: thunderdome.RegisteredUserType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UserType(tt.groups, tt.adminGroups); got != tt.want {
+				t.Errorf("UserType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
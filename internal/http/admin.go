@@ -122,6 +122,8 @@ func (s *Service) handleUserCreate() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionAdminUserCreate, newUser.ID, nil)
+
 		err = s.Email.SendWelcome(user.Name, user.Email, verifyID)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleUserCreate error sending welcome email", zap.Error(err),
@@ -163,6 +165,8 @@ func (s *Service) handleUserPromote() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionAdminUserPromote, userID, nil)
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
@@ -197,6 +201,8 @@ func (s *Service) handleUserDemote() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionAdminUserDemote, userID, nil)
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
@@ -231,6 +237,8 @@ func (s *Service) handleUserDisable() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionAdminUserDisable, userID, nil)
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
@@ -265,6 +273,8 @@ func (s *Service) handleUserEnable() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionAdminUserEnable, userID, nil)
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
@@ -319,6 +329,8 @@ func (s *Service) handleAdminUpdateUserPassword() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionAdminUserPasswordUpdate, userID, nil)
+
 		emailErr := s.Email.SendPasswordUpdate(userName, userEmail)
 		if emailErr != nil {
 			s.Logger.Ctx(ctx).Error("handleAdminUpdateUserPassword error sending password update email", zap.Error(emailErr),
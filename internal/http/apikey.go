@@ -2,9 +2,16 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 	"go.uber.org/zap"
 )
 
@@ -45,12 +52,39 @@ func (s *Service) handleUserAPIKeys() http.HandlerFunc {
 
 type apikeyGenerateRequestBody struct {
 	Name string `json:"name" validate:"required"`
+	// Scopes limits what the key can access, no scopes grants the full access of the user
+	Scopes         []string   `json:"scopes" validate:"omitempty,unique"`
+	ExpiresAt      *time.Time `json:"expiresAt"`
+	TeamID         *string    `json:"teamId" validate:"omitempty,uuid"`
+	OrganizationID *string    `json:"organizationId" validate:"omitempty,uuid"`
+}
+
+// restrictions validates and returns the API key restrictions from the request body
+func (b *apikeyGenerateRequestBody) restrictions(now time.Time) (thunderdome.APIKeyRestrictions, error) {
+	for _, scope := range b.Scopes {
+		if !slices.Contains(thunderdome.APIKeyScopes, scope) {
+			return thunderdome.APIKeyRestrictions{}, fmt.Errorf("INVALID_APIKEY_SCOPE: %s", scope)
+		}
+	}
+	if b.ExpiresAt != nil && !b.ExpiresAt.After(now) {
+		return thunderdome.APIKeyRestrictions{}, errors.New("APIKEY_EXPIRY_IN_PAST")
+	}
+	if b.TeamID != nil && b.OrganizationID != nil {
+		return thunderdome.APIKeyRestrictions{}, errors.New("APIKEY_TEAM_OR_ORGANIZATION_ONLY")
+	}
+
+	return thunderdome.APIKeyRestrictions{
+		Scopes:         b.Scopes,
+		ExpiresAt:      b.ExpiresAt,
+		TeamID:         b.TeamID,
+		OrganizationID: b.OrganizationID,
+	}, nil
 }
 
 // handleAPIKeyGenerate handles generating an API key for a user
 //
 //	@Summary		Generate API Key
-//	@Description	Generates an API key for the user
+//	@Description	Generates an API key for the user, optionally limited to scopes, an expiry date, and a single team or organization
 //	@Tags			apikey
 //	@Produce		json
 //	@Param			userId	path	string						true	"the user ID to generate API key for"
@@ -91,6 +125,24 @@ func (s *Service) handleAPIKeyGenerate() http.HandlerFunc {
 			return
 		}
 
+		restrictions, restrictionsErr := k.restrictions(time.Now())
+		if restrictionsErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, restrictionsErr.Error()))
+			return
+		}
+		if restrictions.TeamID != nil {
+			if _, err := s.TeamDataSvc.TeamGetByID(ctx, *restrictions.TeamID); err != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "TEAM_NOT_FOUND"))
+				return
+			}
+		}
+		if restrictions.OrganizationID != nil {
+			if _, err := s.OrganizationDataSvc.OrganizationGetByID(ctx, *restrictions.OrganizationID); err != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ORGANIZATION_NOT_FOUND"))
+				return
+			}
+		}
+
 		apiKeys, keysErr := s.ApiKeyDataSvc.GetUserAPIKeys(ctx, userID)
 		if keysErr != nil {
 			s.Logger.Ctx(ctx).Error("handleAPIKeyGenerate error", zap.Error(keysErr),
@@ -104,7 +156,7 @@ func (s *Service) handleAPIKeyGenerate() http.HandlerFunc {
 			return
 		}
 
-		apiKey, keyErr := s.ApiKeyDataSvc.GenerateAPIKey(ctx, userID, k.Name)
+		apiKey, keyErr := s.ApiKeyDataSvc.GenerateAPIKey(ctx, userID, k.Name, restrictions)
 		if keyErr != nil {
 			s.Logger.Ctx(ctx).Error("handleAPIKeyGenerate error", zap.Error(keyErr),
 				zap.String("entity_user_id", userID), zap.String("session_user_id", sessionUserID))
@@ -112,6 +164,11 @@ func (s *Service) handleAPIKeyGenerate() http.HandlerFunc {
 			return
 		}
 
+		s.recordAPIKeyAuditEvent(r, thunderdome.AuditActionAPIKeyCreate, apiKey.ID, userID, map[string]string{
+			"name":   apiKey.Name,
+			"scopes": strings.Join(apiKey.Scopes, " "),
+		})
+
 		s.Success(w, r, http.StatusOK, apiKey, nil)
 	}
 }
@@ -179,6 +236,10 @@ func (s *Service) handleUserAPIKeyUpdate() http.HandlerFunc {
 			return
 		}
 
+		s.recordAPIKeyAuditEvent(r, thunderdome.AuditActionAPIKeyUpdate, keyID, userID, map[string]string{
+			"active": strconv.FormatBool(k.Active),
+		})
+
 		s.Success(w, r, http.StatusOK, keys, nil)
 	}
 }
@@ -222,6 +283,8 @@ func (s *Service) handleUserAPIKeyDelete() http.HandlerFunc {
 			return
 		}
 
+		s.recordAPIKeyAuditEvent(r, thunderdome.AuditActionAPIKeyDelete, keyID, userID, nil)
+
 		s.Success(w, r, http.StatusOK, keys, nil)
 	}
 }
@@ -0,0 +1,456 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// apiKeyRouteScopes is the allow-list of routes available to scoped API keys, keyed by the route
+// pattern without the path prefix, with the scope each route requires. Routes missing from the list
+// such as API key, session, MFA, SCIM token, service account, audit, and admin routes are never
+// available to scoped API keys
+var apiKeyRouteScopes = map[string]string{
+	"GET /api/users/{userId}":                                                                                               "user:read",
+	"PUT /api/users/{userId}":                                                                                               "user:write",
+	"DELETE /api/users/{userId}":                                                                                            "user:write",
+	"POST /api/users/{userId}/request-verify":                                                                               "user:write",
+	"POST /api/users/{userId}/support-ticket":                                                                               "user:write",
+	"GET /api/users/{userId}/invites":                                                                                       "user:read",
+	"POST /api/users/{userId}/invite/team/{inviteId}":                                                                       "user:write",
+	"DELETE /api/users/{userId}/invite/team/{inviteId}":                                                                     "user:write",
+	"POST /api/users/{userId}/invite/organization/{inviteId}":                                                               "user:write",
+	"DELETE /api/users/{userId}/invite/organization/{inviteId}":                                                             "user:write",
+	"POST /api/users/{userId}/invite/department/{inviteId}":                                                                 "user:write",
+	"DELETE /api/users/{userId}/invite/department/{inviteId}":                                                               "user:write",
+	"GET /api/users/{userId}/organizations":                                                                                 "organization:read",
+	"POST /api/users/{userId}/organizations":                                                                                "organization:admin",
+	"GET /api/users/{userId}/teams":                                                                                         "team:read",
+	"POST /api/users/{userId}/teams":                                                                                        "team:admin",
+	"GET /api/users/{userId}/teams-non-org":                                                                                 "user:read",
+	"GET /api/users/{userId}/jira-instances":                                                                                "user:read",
+	"POST /api/users/{userId}/jira-instances":                                                                               "user:write",
+	"PUT /api/users/{userId}/jira-instances/{instanceId}":                                                                   "user:write",
+	"DELETE /api/users/{userId}/jira-instances/{instanceId}":                                                                "user:write",
+	"POST /api/users/{userId}/jira-instances/{instanceId}/jql-story-search":                                                 "user:write",
+	"GET /api/users/{userId}/jira-instances/{instanceId}/fields":                                                            "user:read",
+	"GET /api/organizations/{orgId}":                                                                                        "organization:read",
+	"PUT /api/organizations/{orgId}":                                                                                        "organization:admin",
+	"DELETE /api/organizations/{orgId}":                                                                                     "organization:admin",
+	"GET /api/organizations/{orgId}/metrics":                                                                                "organization:read",
+	"GET /api/organizations/{orgId}/departments":                                                                            "organization:read",
+	"POST /api/organizations/{orgId}/departments":                                                                           "organization:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}":                                                             "organization:read",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}":                                                             "organization:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}":                                                          "organization:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/invites":                                                     "organization:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/invites":                                                    "organization:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/invites/{inviteId}":                                       "organization:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/users":                                                       "organization:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/users":                                                      "organization:admin",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/users/{userId}":                                              "organization:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/users/{userId}":                                           "organization:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams":                                                       "team:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams":                                                      "team:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}":                                              "team:read",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}":                                              "team:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}":                                           "team:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/invites":                                      "team:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/invites":                                     "team:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/invites/{inviteId}":                        "team:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/users":                                        "team:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/users":                                       "team:admin",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/users/{userId}":                               "team:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/users/{userId}":                            "team:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/checkins":                                     "team:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/checkins":                                    "team:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/checkins/kudos":                               "team:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/checkins/kudos":                              "team:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/checkins/kudos/{kudoId}":                      "team:read",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/checkins/kudos/{kudoId}":                      "team:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/checkins/kudos/{kudoId}":                   "team:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/checkins/users/{userId}/last":                 "team:read",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/checkins/{checkinId}":                         "team:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/checkins/{checkinId}":                      "team:admin",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/checkins/{checkinId}/comments":               "team:admin",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/checkins/{checkinId}/comments/{commentId}":    "team:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/checkins/{checkinId}/comments/{commentId}": "team:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/metrics":                                      "team:read",
+	"GET /api/organizations/{orgId}/teams":                                                                                  "team:read",
+	"POST /api/organizations/{orgId}/teams":                                                                                 "team:admin",
+	"GET /api/organizations/{orgId}/teams/{teamId}":                                                                         "team:read",
+	"PUT /api/organizations/{orgId}/teams/{teamId}":                                                                         "team:admin",
+	"DELETE /api/organizations/{orgId}/teams/{teamId}":                                                                      "team:admin",
+	"GET /api/organizations/{orgId}/teams/{teamId}/invites":                                                                 "team:read",
+	"POST /api/organizations/{orgId}/teams/{teamId}/invites":                                                                "team:admin",
+	"DELETE /api/organizations/{orgId}/teams/{teamId}/invites/{inviteId}":                                                   "team:admin",
+	"GET /api/organizations/{orgId}/teams/{teamId}/users":                                                                   "team:read",
+	"POST /api/organizations/{orgId}/teams/{teamId}/users":                                                                  "team:admin",
+	"PUT /api/organizations/{orgId}/teams/{teamId}/users/{userId}":                                                          "team:admin",
+	"DELETE /api/organizations/{orgId}/teams/{teamId}/users/{userId}":                                                       "team:admin",
+	"GET /api/organizations/{orgId}/teams/{teamId}/checkins":                                                                "team:read",
+	"POST /api/organizations/{orgId}/teams/{teamId}/checkins":                                                               "team:admin",
+	"GET /api/organizations/{orgId}/teams/{teamId}/checkins/kudos":                                                          "team:read",
+	"POST /api/organizations/{orgId}/teams/{teamId}/checkins/kudos":                                                         "team:admin",
+	"GET /api/organizations/{orgId}/teams/{teamId}/checkins/kudos/{kudoId}":                                                 "team:read",
+	"PUT /api/organizations/{orgId}/teams/{teamId}/checkins/kudos/{kudoId}":                                                 "team:admin",
+	"DELETE /api/organizations/{orgId}/teams/{teamId}/checkins/kudos/{kudoId}":                                              "team:admin",
+	"GET /api/organizations/{orgId}/teams/{teamId}/checkins/users/{userId}/last":                                            "team:read",
+	"PUT /api/organizations/{orgId}/teams/{teamId}/checkins/{checkinId}":                                                    "team:admin",
+	"DELETE /api/organizations/{orgId}/teams/{teamId}/checkins/{checkinId}":                                                 "team:admin",
+	"POST /api/organizations/{orgId}/teams/{teamId}/checkins/{checkinId}/comments":                                          "team:admin",
+	"PUT /api/organizations/{orgId}/teams/{teamId}/checkins/{checkinId}/comments/{commentId}":                               "team:admin",
+	"DELETE /api/organizations/{orgId}/teams/{teamId}/checkins/{checkinId}/comments/{commentId}":                            "team:admin",
+	"GET /api/organizations/{orgId}/teams/{teamId}/metrics":                                                                 "team:read",
+	"GET /api/organizations/{orgId}/users":                                                                                  "organization:read",
+	"PUT /api/organizations/{orgId}/users/{userId}":                                                                         "organization:admin",
+	"DELETE /api/organizations/{orgId}/users/{userId}":                                                                      "organization:admin",
+	"GET /api/organizations/{orgId}/invites":                                                                                "organization:read",
+	"POST /api/organizations/{orgId}/invites":                                                                               "organization:admin",
+	"DELETE /api/organizations/{orgId}/invites/{inviteId}":                                                                  "organization:admin",
+	"GET /api/teams/{teamId}":                                                                                               "team:read",
+	"PUT /api/teams/{teamId}":                                                                                               "team:admin",
+	"DELETE /api/teams/{teamId}":                                                                                            "team:admin",
+	"GET /api/teams/{teamId}/invites":                                                                                       "team:read",
+	"POST /api/teams/{teamId}/invites":                                                                                      "team:admin",
+	"DELETE /api/teams/{teamId}/invites/{inviteId}":                                                                         "team:admin",
+	"GET /api/teams/{teamId}/users":                                                                                         "team:read",
+	"PUT /api/teams/{teamId}/users/{userId}":                                                                                "team:admin",
+	"DELETE /api/teams/{teamId}/users/{userId}":                                                                             "team:admin",
+	"GET /api/teams/{teamId}/checkins":                                                                                      "team:read",
+	"POST /api/teams/{teamId}/checkins":                                                                                     "team:admin",
+	"GET /api/teams/{teamId}/checkins/kudos":                                                                                "team:read",
+	"POST /api/teams/{teamId}/checkins/kudos":                                                                               "team:admin",
+	"GET /api/teams/{teamId}/checkins/kudos/{kudoId}":                                                                       "team:read",
+	"PUT /api/teams/{teamId}/checkins/kudos/{kudoId}":                                                                       "team:admin",
+	"DELETE /api/teams/{teamId}/checkins/kudos/{kudoId}":                                                                    "team:admin",
+	"GET /api/teams/{teamId}/checkins/users/{userId}/last":                                                                  "team:read",
+	"PUT /api/teams/{teamId}/checkins/{checkinId}":                                                                          "team:admin",
+	"DELETE /api/teams/{teamId}/checkins/{checkinId}":                                                                       "team:admin",
+	"POST /api/teams/{teamId}/checkins/{checkinId}/comments":                                                                "team:admin",
+	"PUT /api/teams/{teamId}/checkins/{checkinId}/comments/{commentId}":                                                     "team:admin",
+	"DELETE /api/teams/{teamId}/checkins/{checkinId}/comments/{commentId}":                                                  "team:admin",
+	"GET /api/teams/{teamId}/metrics":                                                                                       "team:read",
+	"POST /api/users/{userId}/battles":                                                                                      "poker:write",
+	"GET /api/users/{userId}/battles":                                                                                       "poker:read",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/battles":                                      "poker:read",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/battles/{battleId}":                        "poker:write",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/users/{userId}/battles":                      "poker:write",
+	"GET /api/organizations/{orgId}/teams/{teamId}/battles":                                                                 "poker:read",
+	"DELETE /api/organizations/{orgId}/teams/{teamId}/battles/{battleId}":                                                   "poker:write",
+	"POST /api/organizations/{orgId}/teams/{teamId}/users/{userId}/battles":                                                 "poker:write",
+	"GET /api/teams/{teamId}/battles":                                                                                       "poker:read",
+	"DELETE /api/teams/{teamId}/battles/{battleId}":                                                                         "poker:write",
+	"POST /api/teams/{teamId}/users/{userId}/battles":                                                                       "poker:write",
+	"GET /api/battles":                                                                                        "poker:read",
+	"GET /api/battles/{battleId}":                                                                             "poker:read",
+	"GET /api/battles/{battleId}/export":                                                                      "poker:read",
+	"PATCH /api/battles/{battleId}/end":                                                                       "poker:write",
+	"DELETE /api/battles/{battleId}":                                                                          "poker:write",
+	"POST /api/battles/{battleId}/plans":                                                                      "poker:write",
+	"PUT /api/battles/{battleId}/plans/{planId}":                                                              "poker:write",
+	"DELETE /api/battles/{battleId}/plans/{planId}":                                                           "poker:write",
+	"GET /api/estimation-scales/public":                                                                       "poker:read",
+	"GET /api/estimation-scales/public/{scaleId}":                                                             "poker:read",
+	"GET /api/organizations/{orgId}/estimation-scales":                                                        "poker:read",
+	"POST /api/organizations/{orgId}/estimation-scales":                                                       "poker:write",
+	"PUT /api/organizations/{orgId}/estimation-scales/{scaleId}":                                              "poker:write",
+	"DELETE /api/organizations/{orgId}/estimation-scales/{scaleId}":                                           "poker:write",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/estimation-scales":              "poker:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/estimation-scales":             "poker:write",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/estimation-scales/{scaleId}":    "poker:write",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/estimation-scales/{scaleId}": "poker:write",
+	"GET /api/organizations/{orgId}/teams/{teamId}/estimation-scales":                                         "poker:read",
+	"POST /api/organizations/{orgId}/teams/{teamId}/estimation-scales":                                        "poker:write",
+	"PUT /api/organizations/{orgId}/teams/{teamId}/estimation-scales/{scaleId}":                               "poker:write",
+	"DELETE /api/organizations/{orgId}/teams/{teamId}/estimation-scales/{scaleId}":                            "poker:write",
+	"GET /api/teams/{teamId}/estimation-scales":                                                               "poker:read",
+	"POST /api/teams/{teamId}/estimation-scales":                                                              "poker:write",
+	"PUT /api/teams/{teamId}/estimation-scales/{scaleId}":                                                     "poker:write",
+	"DELETE /api/teams/{teamId}/estimation-scales/{scaleId}":                                                  "poker:write",
+	"GET /api/organizations/{orgId}/poker-settings":                                                           "poker:read",
+	"POST /api/organizations/{orgId}/poker-settings":                                                          "poker:write",
+	"PUT /api/organizations/{orgId}/poker-settings":                                                           "poker:write",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/poker-settings":                                "poker:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/poker-settings":                               "poker:write",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/poker-settings":                                "poker:write",
+	"GET /api/teams/{teamId}/poker-settings":                                                                  "poker:read",
+	"POST /api/teams/{teamId}/poker-settings":                                                                 "poker:write",
+	"PUT /api/teams/{teamId}/poker-settings":                                                                  "poker:write",
+	"POST /api/users/{userId}/retros":                                                                         "retro:write",
+	"GET /api/users/{userId}/retros":                                                                          "retro:read",
+	"GET /api/users/{userId}/retro-actions":                                                                   "retro:read",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/retros":                         "retro:read",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/retros/{retroId}":            "retro:write",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/retro-actions":                  "retro:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/users/{userId}/retros":         "retro:write",
+	"GET /api/organizations/{orgId}/teams/{teamId}/retros":                                                    "retro:read",
+	"GET /api/organizations/{orgId}/teams/{teamId}/retro-actions":                                             "retro:read",
+	"DELETE /api/organizations/{orgId}/teams/{teamId}/retros/{retroId}":                                       "retro:write",
+	"POST /api/organizations/{orgId}/teams/{teamId}/users/{userId}/retros":                                    "retro:write",
+	"GET /api/teams/{teamId}/retros":                                                                          "retro:read",
+	"DELETE /api/teams/{teamId}/retros/{retroId}":                                                             "retro:write",
+	"GET /api/teams/{teamId}/retro-actions":                                                                   "retro:read",
+	"POST /api/teams/{teamId}/users/{userId}/retros":                                                          "retro:write",
+	"GET /api/retros":                                                                                          "retro:read",
+	"GET /api/retros/{retroId}":                                                                                "retro:read",
+	"GET /api/retros/{retroId}/export":                                                                         "retro:read",
+	"DELETE /api/retros/{retroId}":                                                                             "retro:write",
+	"PUT /api/retros/{retroId}/actions/{actionId}":                                                             "retro:write",
+	"DELETE /api/retros/{retroId}/actions/{actionId}":                                                          "retro:write",
+	"POST /api/retros/{retroId}/actions/{actionId}/assignees":                                                  "retro:write",
+	"DELETE /api/retros/{retroId}/actions/{actionId}/assignees":                                                "retro:write",
+	"POST /api/retros/{retroId}/actions/{actionId}/comments":                                                   "retro:write",
+	"PUT /api/retros/{retroId}/actions/{actionId}/comments/{commentId}":                                        "retro:write",
+	"DELETE /api/retros/{retroId}/actions/{actionId}/comments/{commentId}":                                     "retro:write",
+	"GET /api/retro-templates/public":                                                                          "retro:read",
+	"GET /api/organizations/{orgId}/retro-templates":                                                           "retro:read",
+	"POST /api/organizations/{orgId}/retro-templates":                                                          "retro:write",
+	"PUT /api/organizations/{orgId}/retro-templates/{templateId}":                                              "retro:write",
+	"DELETE /api/organizations/{orgId}/retro-templates/{templateId}":                                           "retro:write",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/retro-templates":                 "retro:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/retro-templates":                "retro:write",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/retro-templates/{templateId}":    "retro:write",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/retro-templates/{templateId}": "retro:write",
+	"GET /api/organizations/{orgId}/teams/{teamId}/retro-templates":                                            "retro:read",
+	"POST /api/organizations/{orgId}/teams/{teamId}/retro-templates":                                           "retro:write",
+	"PUT /api/organizations/{orgId}/teams/{teamId}/retro-templates/{templateId}":                               "retro:write",
+	"DELETE /api/organizations/{orgId}/teams/{teamId}/retro-templates/{templateId}":                            "retro:write",
+	"GET /api/teams/{teamId}/retro-templates":                                                                  "retro:read",
+	"POST /api/teams/{teamId}/retro-templates":                                                                 "retro:write",
+	"PUT /api/teams/{teamId}/retro-templates/{templateId}":                                                     "retro:write",
+	"DELETE /api/teams/{teamId}/retro-templates/{templateId}":                                                  "retro:write",
+	"GET /api/organizations/{orgId}/retro-settings":                                                            "retro:read",
+	"POST /api/organizations/{orgId}/retro-settings":                                                           "retro:write",
+	"PUT /api/organizations/{orgId}/retro-settings":                                                            "retro:write",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/retro-settings":                                 "retro:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/retro-settings":                                "retro:write",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/retro-settings":                                 "retro:write",
+	"GET /api/teams/{teamId}/retro-settings":                                                                   "retro:read",
+	"POST /api/teams/{teamId}/retro-settings":                                                                  "retro:write",
+	"PUT /api/teams/{teamId}/retro-settings":                                                                   "retro:write",
+	"POST /api/users/{userId}/storyboards":                                                                     "storyboard:write",
+	"GET /api/users/{userId}/storyboards":                                                                      "storyboard:read",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/storyboards":                     "storyboard:read",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/storyboards/{storyboardId}":   "storyboard:write",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/users/{userId}/storyboards":     "storyboard:write",
+	"GET /api/organizations/{orgId}/teams/{teamId}/storyboards":                                                "storyboard:read",
+	"DELETE /api/organizations/{orgId}/teams/{teamId}/storyboards/{storyboardId}":                              "storyboard:write",
+	"POST /api/organizations/{orgId}/teams/{teamId}/users/{userId}/storyboards":                                "storyboard:write",
+	"GET /api/teams/{teamId}/storyboards":                                                                      "storyboard:read",
+	"DELETE /api/teams/{teamId}/storyboards/{storyboardId}":                                                    "storyboard:write",
+	"POST /api/teams/{teamId}/users/{userId}/storyboards":                                                      "storyboard:write",
+	"GET /api/storyboards":                                                                                            "storyboard:read",
+	"GET /api/storyboards/{storyboardId}":                                                                             "storyboard:read",
+	"GET /api/organizations/{orgId}/color-legend-templates":                                                           "team:read",
+	"POST /api/organizations/{orgId}/color-legend-templates":                                                          "team:admin",
+	"PUT /api/organizations/{orgId}/color-legend-templates/{templateId}":                                              "team:admin",
+	"DELETE /api/organizations/{orgId}/color-legend-templates/{templateId}":                                           "team:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/color-legend-templates":                 "team:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/color-legend-templates":                "team:admin",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/color-legend-templates/{templateId}":    "team:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/color-legend-templates/{templateId}": "team:admin",
+	"GET /api/organizations/{orgId}/teams/{teamId}/color-legend-templates":                                            "team:read",
+	"POST /api/organizations/{orgId}/teams/{teamId}/color-legend-templates":                                           "team:admin",
+	"PUT /api/organizations/{orgId}/teams/{teamId}/color-legend-templates/{templateId}":                               "team:admin",
+	"DELETE /api/organizations/{orgId}/teams/{teamId}/color-legend-templates/{templateId}":                            "team:admin",
+	"GET /api/teams/{teamId}/color-legend-templates":                                                                  "team:read",
+	"POST /api/teams/{teamId}/color-legend-templates":                                                                 "team:admin",
+	"PUT /api/teams/{teamId}/color-legend-templates/{templateId}":                                                     "team:admin",
+	"DELETE /api/teams/{teamId}/color-legend-templates/{templateId}":                                                  "team:admin",
+	"DELETE /api/storyboards/{storyboardId}":                                                                          "storyboard:write",
+	"POST /api/storyboards/{storyboardId}/goals":                                                                      "storyboard:write",
+	"PUT /api/storyboards/{storyboardId}/goals/{goalId}":                                                              "storyboard:write",
+	"DELETE /api/storyboards/{storyboardId}/goals/{goalId}":                                                           "storyboard:write",
+	"POST /api/storyboards/{storyboardId}/columns":                                                                    "storyboard:write",
+	"PUT /api/storyboards/{storyboardId}/columns/{columnId}":                                                          "storyboard:write",
+	"DELETE /api/storyboards/{storyboardId}/columns/{columnId}":                                                       "storyboard:write",
+	"POST /api/storyboards/{storyboardId}/stories":                                                                    "storyboard:write",
+	"PUT /api/storyboards/{storyboardId}/stories/{storyId}/name":                                                      "storyboard:write",
+	"PUT /api/storyboards/{storyboardId}/stories/{storyId}/content":                                                   "storyboard:write",
+	"PUT /api/storyboards/{storyboardId}/stories/{storyId}/color":                                                     "storyboard:write",
+	"PUT /api/storyboards/{storyboardId}/stories/{storyId}/points":                                                    "storyboard:write",
+	"PUT /api/storyboards/{storyboardId}/stories/{storyId}/closed":                                                    "storyboard:write",
+	"PUT /api/storyboards/{storyboardId}/stories/{storyId}/link":                                                      "storyboard:write",
+	"PUT /api/storyboards/{storyboardId}/stories/{storyId}/move":                                                      "storyboard:write",
+	"DELETE /api/storyboards/{storyboardId}/stories/{storyId}":                                                        "storyboard:write",
+	"GET /api/organizations/{orgId}/projects":                                                                         "project:read",
+	"POST /api/organizations/{orgId}/projects":                                                                        "project:write",
+	"PUT /api/organizations/{orgId}/projects/{projectId}":                                                             "project:write",
+	"DELETE /api/organizations/{orgId}/projects/{projectId}":                                                          "project:write",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/projects":                                              "project:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/projects":                                             "project:write",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/projects/{projectId}":                                  "project:write",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/projects/{projectId}":                               "project:write",
+	"GET /api/teams/{teamId}/projects":                                                                                "project:read",
+	"POST /api/teams/{teamId}/projects":                                                                               "project:write",
+	"PUT /api/teams/{teamId}/projects/{projectId}":                                                                    "project:write",
+	"DELETE /api/teams/{teamId}/projects/{projectId}":                                                                 "project:write",
+	"GET /api/projects/{projectId}":                                                                                   "project:read",
+	"GET /api/projects/{projectId}/item-types":                                                                        "project:read",
+	"GET /api/projects/{projectId}/item-statuses":                                                                     "project:read",
+	"GET /api/projects/{projectId}/item-priorities":                                                                   "project:read",
+	"GET /api/projects/{projectId}/items":                                                                             "project:read",
+	"POST /api/projects/{projectId}/items":                                                                            "project:write",
+	"GET /api/projects/{projectId}/items/{itemId}":                                                                    "project:read",
+	"PUT /api/projects/{projectId}/items/{itemId}":                                                                    "project:write",
+	"PATCH /api/projects/{projectId}/items/{itemId}/status":                                                           "project:write",
+	"PUT /api/projects/{projectId}/items/{itemId}/move":                                                               "project:write",
+	"DELETE /api/projects/{projectId}/items/{itemId}":                                                                 "project:write",
+	"GET /api/projects/{projectId}/storyboards":                                                                       "storyboard:read",
+	"POST /api/projects/{projectId}/storyboards":                                                                      "storyboard:write",
+	"DELETE /api/projects/{projectId}/storyboards/{storyboardId}":                                                     "storyboard:write",
+	"GET /api/projects/{projectId}/retros":                                                                            "retro:read",
+	"POST /api/projects/{projectId}/retros":                                                                           "retro:write",
+	"DELETE /api/projects/{projectId}/retros/{retroId}":                                                               "retro:write",
+	"GET /api/projects/{projectId}/poker":                                                                             "poker:read",
+	"POST /api/projects/{projectId}/poker":                                                                            "poker:write",
+	"DELETE /api/projects/{projectId}/poker/{gameId}":                                                                 "poker:write",
+	"GET /api/organizations/{orgId}/item-types":                                                                       "organization:read",
+	"POST /api/organizations/{orgId}/item-types":                                                                      "organization:admin",
+	"PUT /api/organizations/{orgId}/item-types/{typeId}":                                                              "organization:admin",
+	"DELETE /api/organizations/{orgId}/item-types/{typeId}":                                                           "organization:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/item-types":                                            "organization:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/item-types":                                           "organization:admin",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/item-types/{typeId}":                                   "organization:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/item-types/{typeId}":                                "organization:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-types":                             "team:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-types":                            "team:admin",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-types/{typeId}":                    "team:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-types/{typeId}":                 "team:admin",
+	"GET /api/organizations/{orgId}/item-statuses":                                                                    "organization:read",
+	"POST /api/organizations/{orgId}/item-statuses":                                                                   "organization:admin",
+	"PUT /api/organizations/{orgId}/item-statuses/{statusId}":                                                         "organization:admin",
+	"DELETE /api/organizations/{orgId}/item-statuses/{statusId}":                                                      "organization:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/item-statuses":                                         "organization:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/item-statuses":                                        "organization:admin",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/item-statuses/{statusId}":                              "organization:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/item-statuses/{statusId}":                           "organization:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-statuses":                          "team:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-statuses":                         "team:admin",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-statuses/{statusId}":               "team:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-statuses/{statusId}":            "team:admin",
+	"GET /api/organizations/{orgId}/item-priorities":                                                                  "organization:read",
+	"POST /api/organizations/{orgId}/item-priorities":                                                                 "organization:admin",
+	"PUT /api/organizations/{orgId}/item-priorities/{priorityId}":                                                     "organization:admin",
+	"DELETE /api/organizations/{orgId}/item-priorities/{priorityId}":                                                  "organization:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/item-priorities":                                       "organization:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/item-priorities":                                      "organization:admin",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/item-priorities/{priorityId}":                          "organization:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/item-priorities/{priorityId}":                       "organization:admin",
+	"GET /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-priorities":                        "team:read",
+	"POST /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-priorities":                       "team:admin",
+	"PUT /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-priorities/{priorityId}":           "team:admin",
+	"DELETE /api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-priorities/{priorityId}":        "team:admin",
+}
+
+// apiKeyRequiredScope returns the scope an API key needs for the matched route pattern,
+// an empty scope means the route is not available to scoped API keys
+func apiKeyRequiredScope(pattern string, pathPrefix string) string {
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		return ""
+	}
+
+	return apiKeyRouteScopes[method+" "+strings.TrimPrefix(path, pathPrefix)]
+}
+
+// apiKeyHasScope checks whether the granted scopes include the required scope,
+// write and admin scopes also grant read access to the same resource
+func apiKeyHasScope(scopes []string, required string) bool {
+	if required == "" {
+		return false
+	}
+	if slices.Contains(scopes, required) {
+		return true
+	}
+
+	resource, action, _ := strings.Cut(required, ":")
+	if action != "read" {
+		return false
+	}
+
+	return slices.Contains(scopes, resource+":write") || slices.Contains(scopes, resource+":admin")
+}
+
+// apiKeyRequestTeamID returns the team the request targets, either from the path or the team
+// of the game, retro, storyboard, or project being accessed
+func (s *Service) apiKeyRequestTeamID(r *http.Request, userID string) string {
+	if teamID := r.PathValue("teamId"); teamID != "" {
+		return teamID
+	}
+
+	for _, param := range []string{"battleId", "gameId"} {
+		if pokerID := r.PathValue(param); pokerID != "" {
+			if game, err := s.PokerDataSvc.GetGameByID(pokerID, userID); err == nil {
+				return game.TeamID
+			}
+			return ""
+		}
+	}
+	if retroID := r.PathValue("retroId"); retroID != "" {
+		if retro, err := s.RetroDataSvc.RetroGetByID(retroID, userID); err == nil {
+			return retro.TeamID
+		}
+		return ""
+	}
+	if storyboardID := r.PathValue("storyboardId"); storyboardID != "" {
+		if storyboard, err := s.StoryboardDataSvc.GetStoryboardByID(storyboardID, userID); err == nil {
+			return storyboard.TeamID
+		}
+		return ""
+	}
+	if projectID := r.PathValue("projectId"); projectID != "" {
+		if project, err := s.ProjectDataSvc.GetProjectByID(r.Context(), projectID); err == nil && project.TeamID != nil {
+			return *project.TeamID
+		}
+	}
+
+	return ""
+}
+
+// apiKeyRequestOrganizationID returns the organization the request targets, either from the path
+// or the organization of the team or project being accessed
+func (s *Service) apiKeyRequestOrganizationID(ctx context.Context, r *http.Request, teamID string) string {
+	if orgID := r.PathValue("orgId"); orgID != "" {
+		return orgID
+	}
+
+	if teamID != "" {
+		if team, err := s.TeamDataSvc.TeamGetByID(ctx, teamID); err == nil {
+			return team.OrganizationID
+		}
+		return ""
+	}
+	if projectID := r.PathValue("projectId"); projectID != "" {
+		if project, err := s.ProjectDataSvc.GetProjectByID(ctx, projectID); err == nil && project.OrganizationID != nil {
+			return *project.OrganizationID
+		}
+	}
+
+	return ""
+}
+
+// apiKeyAllowed enforces the scopes and team or organization restriction of an API key for the request,
+// returning the error code to respond with when the request is not allowed
+func (s *Service) apiKeyAllowed(r *http.Request, key *thunderdome.APIKey, userID string) string {
+	if len(key.Scopes) > 0 && !apiKeyHasScope(key.Scopes, apiKeyRequiredScope(r.Pattern, s.Config.PathPrefix)) {
+		return "APIKEY_SCOPE_REQUIRED"
+	}
+	if key.TeamID == nil && key.OrganizationID == nil {
+		return ""
+	}
+
+	teamID := s.apiKeyRequestTeamID(r, userID)
+	if key.TeamID != nil && teamID != *key.TeamID {
+		return "APIKEY_RESOURCE_RESTRICTED"
+	}
+	if key.OrganizationID != nil && s.apiKeyRequestOrganizationID(r.Context(), r, teamID) != *key.OrganizationID {
+		return "APIKEY_RESOURCE_RESTRICTED"
+	}
+
+	return ""
+}
@@ -0,0 +1,213 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func TestAPIKeyRequiredScope(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{pattern: "GET /api/battles/{battleId}", want: "poker:read"},
+		{pattern: "POST /thunderdome/api/battles/{battleId}/plans", want: "poker:write"},
+		{pattern: "GET /api/teams/{teamId}/retros", want: "retro:read"},
+		{pattern: "PUT /api/teams/{teamId}", want: "team:admin"},
+		{pattern: "PUT /api/teams/{teamId}/users/{userId}", want: "team:admin"},
+		{pattern: "DELETE /api/organizations/{orgId}/users/{userId}", want: "organization:admin"},
+		{pattern: "GET /api/users/{userId}", want: "user:read"},
+		{pattern: "POST /api/users/{userId}/apikeys", want: ""},
+		{pattern: "GET /api/users/{userId}/sessions", want: ""},
+		{pattern: "GET /api/organizations/{orgId}/audit-events", want: ""},
+		{pattern: "GET /api/organizations/{orgId}/service-accounts", want: ""},
+		{pattern: "GET /api/organizations/{orgId}/scim-tokens", want: ""},
+		{pattern: "GET /api/unknown", want: ""},
+		{pattern: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := apiKeyRequiredScope(tt.pattern, "/thunderdome"); got != tt.want {
+				t.Errorf("apiKeyRequiredScope() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKeyHasScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		scopes   []string
+		required string
+		want     bool
+	}{
+		{name: "exact scope", scopes: []string{"poker:read"}, required: "poker:read", want: true},
+		{name: "write grants read", scopes: []string{"poker:write"}, required: "poker:read", want: true},
+		{name: "admin grants read", scopes: []string{"team:admin"}, required: "team:read", want: true},
+		{name: "read does not grant write", scopes: []string{"retro:read"}, required: "retro:write", want: false},
+		{name: "other resource", scopes: []string{"retro:write"}, required: "poker:read", want: false},
+		{name: "unavailable route", scopes: []string{"user:write"}, required: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := apiKeyHasScope(tt.scopes, tt.required); got != tt.want {
+				t.Errorf("apiKeyHasScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+const testAPIKeyID = "key-1"
+
+// fakeAPIKeyDataSvc returns a key with the test scopes for any API key
+type fakeAPIKeyDataSvc struct {
+	APIKeyDataSvc
+	scopes []string
+}
+
+func (f *fakeAPIKeyDataSvc) GetAPIKeyUser(ctx context.Context, apiKey string) (*thunderdome.User, *thunderdome.APIKey, error) {
+	return &thunderdome.User{ID: "user-1"}, &thunderdome.APIKey{ID: testAPIKeyID, APIKeyRestrictions: thunderdome.APIKeyRestrictions{Scopes: f.scopes}}, nil
+}
+
+// fakeCookieManager never finds a cookie, requests are authenticated by API key
+type fakeCookieManager struct {
+	CookieManager
+}
+
+func (fakeCookieManager) ValidateUserCookie(w http.ResponseWriter, r *http.Request) (string, error) {
+	return "", errors.New("COOKIE_NOT_FOUND")
+}
+
+func (fakeCookieManager) ValidateSessionCookie(w http.ResponseWriter, r *http.Request) (string, error) {
+	return "", errors.New("COOKIE_NOT_FOUND")
+}
+
+// noDeadlinesPokerDataSvc and noDeadlinesRetroDataSvc keep the vote and phase timers started by New idle
+type noDeadlinesPokerDataSvc struct {
+	PokerDataSvc
+}
+
+func (noDeadlinesPokerDataSvc) GetStoryVotingDeadlines(ctx context.Context, lookback time.Duration) ([]*thunderdome.StoryVotingDeadline, error) {
+	return nil, nil
+}
+
+type noDeadlinesRetroDataSvc struct {
+	RetroDataSvc
+}
+
+func (noDeadlinesRetroDataSvc) GetRetroPhaseDeadlines(ctx context.Context, lookback time.Duration) ([]*thunderdome.RetroPhaseDeadline, error) {
+	return nil, nil
+}
+
+// newAPIKeyRouter builds the real router with every feature enabled for requests made with an API key
+// holding the scopes, the key's rate limit is always spent so requests passing the scope check stop
+// with a 429 before reaching a handler
+func newAPIKeyRouter(scopes []string) http.Handler {
+	rateLimitDataSvc := &fakeRateLimitDataSvc{hits: map[string]int{
+		rateLimitKey("apikey", "api", testAPIKeyID): 1,
+	}}
+
+	a := New(Service{
+		Config: &Config{
+			PathPrefix:           "/thunderdome",
+			ExternalAPIEnabled:   true,
+			FeaturePoker:         true,
+			FeatureRetro:         true,
+			FeatureStoryboard:    true,
+			FeatureProject:       true,
+			OrganizationsEnabled: true,
+			RateLimit: RateLimitConfig{
+				Enabled:        true,
+				APIKeyRequests: 1,
+				APIKeyWindow:   time.Hour,
+			},
+		},
+		Cookie:           fakeCookieManager{},
+		Logger:           otelzap.New(zap.NewNop()),
+		ApiKeyDataSvc:    &fakeAPIKeyDataSvc{scopes: scopes},
+		PokerDataSvc:     noDeadlinesPokerDataSvc{},
+		RetroDataSvc:     noDeadlinesRetroDataSvc{},
+		RateLimitDataSvc: rateLimitDataSvc,
+		// the websocket services started by New bind methods of these
+		StoryboardDataSvc: struct{ StoryboardDataSvc }{},
+		CheckinDataSvc:    struct{ CheckinDataSvc }{},
+	}, fstest.MapFS{}, http.FS(fstest.MapFS{}))
+
+	return a.Handler
+}
+
+var routePathValue = regexp.MustCompile(`\{[^}]+\}`)
+
+func apiKeyRouteRequest(handler http.Handler, pattern string) (int, string) {
+	method, path, _ := strings.Cut(pattern, " ")
+	req := httptest.NewRequest(method, "/thunderdome"+routePathValue.ReplaceAllString(path, "8a9b"), nil)
+	req.Header.Set(apiKeyHeaderName, "test-key")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	var response standardJsonResponse
+	_ = json.NewDecoder(w.Body).Decode(&response)
+
+	return w.Code, response.Error
+}
+
+func TestAPIKeyRouteScopesMatchRouter(t *testing.T) {
+	unscoped := newAPIKeyRouter([]string{"none:read"})
+	scoped := make(map[string]http.Handler)
+	for _, scope := range apiKeyRouteScopes {
+		if scoped[scope] == nil {
+			scoped[scope] = newAPIKeyRouter([]string{scope})
+		}
+	}
+
+	for pattern, scope := range apiKeyRouteScopes {
+		t.Run(pattern, func(t *testing.T) {
+			if code, errCode := apiKeyRouteRequest(unscoped, pattern); code != http.StatusForbidden || errCode != "APIKEY_SCOPE_REQUIRED" {
+				t.Fatalf("request without scope = %d %s, want 403 APIKEY_SCOPE_REQUIRED", code, errCode)
+			}
+			if code, errCode := apiKeyRouteRequest(scoped[scope], pattern); code != http.StatusTooManyRequests {
+				t.Errorf("request with %s = %d %s, want the scope check to pass", scope, code, errCode)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRouterDeniesUnlistedRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []string
+		pattern string
+	}{
+		{name: "team membership needs team admin", scopes: []string{"user:write"}, pattern: "PUT /api/teams/{teamId}/users/{userId}"},
+		{name: "team membership removal needs team admin", scopes: []string{"user:write"}, pattern: "DELETE /api/teams/{teamId}/users/{userId}"},
+		{name: "organization membership needs organization admin", scopes: []string{"user:write"}, pattern: "PUT /api/organizations/{orgId}/users/{userId}"},
+		{name: "audit events", scopes: thunderdome.APIKeyScopes, pattern: "GET /api/organizations/{orgId}/audit-events"},
+		{name: "service accounts", scopes: thunderdome.APIKeyScopes, pattern: "POST /api/organizations/{orgId}/service-accounts"},
+		{name: "scim tokens", scopes: thunderdome.APIKeyScopes, pattern: "POST /api/organizations/{orgId}/scim-tokens"},
+		{name: "api keys", scopes: thunderdome.APIKeyScopes, pattern: "POST /api/users/{userId}/apikeys"},
+		{name: "sessions", scopes: thunderdome.APIKeyScopes, pattern: "DELETE /api/users/{userId}/sessions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, errCode := apiKeyRouteRequest(newAPIKeyRouter(tt.scopes), tt.pattern); code != http.StatusForbidden || errCode != "APIKEY_SCOPE_REQUIRED" {
+				t.Errorf("apiKeyRouteRequest() = %d %s, want 403 APIKEY_SCOPE_REQUIRED", code, errCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package http
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// auditEventExportLimit is the most audit events exported to CSV in one request
+const auditEventExportLimit = 10000
+
+// recordAuditEvent appends an audit event for the request, the actor defaults to the session user
+// and the organization to the {orgId} path value. Failures are logged and never fail the request
+func (s *Service) recordAuditEvent(r *http.Request, event thunderdome.AuditEvent) {
+	if s.AuditDataSvc == nil {
+		return
+	}
+	ctx := r.Context()
+
+	if event.ActorID == nil {
+		if userID, ok := ctx.Value(contextKeyUserID).(string); ok && userID != "" {
+			event.ActorID = &userID
+		}
+	}
+	if event.ActorType == "" {
+		if userType, ok := ctx.Value(contextKeyUserType).(string); ok {
+			event.ActorType = userType
+		}
+	}
+	if event.OrganizationID == nil {
+		if orgID := r.PathValue("orgId"); orgID != "" {
+			event.OrganizationID = &orgID
+		}
+	}
+	event.IPAddress = s.clientIP(r)
+	event.UserAgent = r.UserAgent()
+
+	if err := s.AuditDataSvc.CreateEvent(ctx, event); err != nil {
+		s.Logger.Ctx(ctx).Error("recordAuditEvent error", zap.Error(err),
+			zap.String("audit_action", event.Action), zap.String("audit_target_id", event.TargetID))
+	}
+}
+
+// recordUserAuditEvent records an action by the session user that targets the user
+func (s *Service) recordUserAuditEvent(r *http.Request, action string, userID string, metadata map[string]string) {
+	s.recordAuditEvent(r, thunderdome.AuditEvent{
+		Action:     action,
+		TargetType: thunderdome.AuditTargetTypeUser,
+		TargetID:   userID,
+		Metadata:   metadata,
+	})
+}
+
+// recordAPIKeyAuditEvent records an action by the session user on the API key owned by the user,
+// the key is identified by its prefix so the hashed secret is never written to the audit log
+func (s *Service) recordAPIKeyAuditEvent(r *http.Request, action string, keyID string, userID string, metadata map[string]string) {
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	metadata["userId"] = userID
+	prefix, _, _ := strings.Cut(keyID, ".")
+
+	s.recordAuditEvent(r, thunderdome.AuditEvent{
+		Action:     action,
+		TargetType: thunderdome.AuditTargetTypeAPIKey,
+		TargetID:   prefix,
+		Metadata:   metadata,
+	})
+}
+
+// recordLoginAuditEvent records a completed login of the user with the authentication method
+func (s *Service) recordLoginAuditEvent(r *http.Request, user *thunderdome.User, method string) {
+	s.recordAuditEvent(r, thunderdome.AuditEvent{
+		Action:     thunderdome.AuditActionLogin,
+		ActorID:    &user.ID,
+		ActorType:  user.Type,
+		TargetType: thunderdome.AuditTargetTypeUser,
+		TargetID:   user.ID,
+		Metadata:   map[string]string{"method": method},
+	})
+}
+
+// recordLoginFailedAuditEvent records a failed login attempt for the email with the authentication method
+func (s *Service) recordLoginFailedAuditEvent(r *http.Request, email string, method string, reason string) {
+	s.recordAuditEvent(r, thunderdome.AuditEvent{
+		Action:     thunderdome.AuditActionLoginFailed,
+		TargetType: thunderdome.AuditTargetTypeUser,
+		Metadata: map[string]string{
+			"method": method,
+			"email":  sanitizeUserInputForLogs(email),
+			"reason": reason,
+		},
+	})
+}
+
+// auditEventFilterFromRequest builds the audit event filter from the request query parameters,
+// from and to are dates formatted as YYYY-MM-DD with to being inclusive
+func auditEventFilterFromRequest(r *http.Request) (thunderdome.AuditEventFilter, error) {
+	query := r.URL.Query()
+	filter := thunderdome.AuditEventFilter{
+		Action:     query.Get("action"),
+		ActorID:    query.Get("actorId"),
+		TargetType: query.Get("targetType"),
+		TargetID:   query.Get("targetId"),
+	}
+	if filter.ActorID != "" {
+		if err := validate.Var(filter.ActorID, "uuid"); err != nil {
+			return filter, errors.New("actorId must be a uuid")
+		}
+	}
+
+	if from := query.Get("from"); from != "" {
+		parsed, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return filter, errors.New("from must be a date formatted as YYYY-MM-DD")
+		}
+		filter.From = &parsed
+	}
+	if to := query.Get("to"); to != "" {
+		parsed, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return filter, errors.New("to must be a date formatted as YYYY-MM-DD")
+		}
+		parsed = parsed.AddDate(0, 0, 1)
+		filter.To = &parsed
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, errors.New("from must not be after to")
+	}
+
+	return filter, nil
+}
+
+// csvSafe neutralizes a user controlled CSV cell that a spreadsheet would evaluate as a formula
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+// writeAuditEventsCSV writes the audit events as CSV with the metadata as key=value pairs
+func writeAuditEventsCSV(cw *csv.Writer, events []*thunderdome.AuditEvent) error {
+	if err := cw.Write([]string{
+		"Date", "Action", "Actor ID", "Actor Name", "Actor Type", "Target Type", "Target ID",
+		"Organization ID", "IP Address", "User Agent", "Metadata",
+	}); err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		var actorID, orgID string
+		if e.ActorID != nil {
+			actorID = *e.ActorID
+		}
+		if e.OrganizationID != nil {
+			orgID = *e.OrganizationID
+		}
+		keys := make([]string, 0, len(e.Metadata))
+		for k := range e.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		metadata := make([]string, 0, len(keys))
+		for _, k := range keys {
+			metadata = append(metadata, k+"="+e.Metadata[k])
+		}
+
+		if err := cw.Write([]string{
+			e.CreatedDate.UTC().Format(time.RFC3339), e.Action, actorID, csvSafe(e.ActorName), e.ActorType,
+			e.TargetType, csvSafe(e.TargetID), orgID, csvSafe(e.IPAddress), csvSafe(e.UserAgent),
+			csvSafe(strings.Join(metadata, "; ")),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// respondAuditEvents queries the audit events for the filter responding with JSON or as a CSV download
+func (s *Service) respondAuditEvents(w http.ResponseWriter, r *http.Request, filter thunderdome.AuditEventFilter, filename string) {
+	ctx := r.Context()
+	sessionUserID := ctx.Value(contextKeyUserID).(string)
+	limit, offset := getLimitOffsetFromRequest(r)
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "format must be one of json, csv"))
+		return
+	}
+	if format == "csv" && (r.URL.Query().Get("limit") == "" || limit > auditEventExportLimit) {
+		limit = auditEventExportLimit
+	}
+
+	events, count, err := s.AuditDataSvc.ListEvents(ctx, filter, limit, offset)
+	if err != nil {
+		s.Logger.Ctx(ctx).Error("respondAuditEvents error", zap.Error(err),
+			zap.String("organization_id", filter.OrganizationID), zap.String("session_user_id", sessionUserID))
+		s.Failure(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if format == "json" {
+		s.Success(w, r, http.StatusOK, events, &pagination{
+			Count:  count,
+			Offset: offset,
+			Limit:  limit,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	if err := writeAuditEventsCSV(csv.NewWriter(w), events); err != nil {
+		s.Logger.Ctx(ctx).Error("respondAuditEvents error", zap.Error(err),
+			zap.String("organization_id", filter.OrganizationID), zap.String("session_user_id", sessionUserID))
+	}
+}
+
+// handleGetAuditEvents gets the audit events of the application
+//
+//	@Summary		Get Audit Events
+//	@Description	get a list of authentication and administrative audit events newest first
+//	@Tags			admin
+//	@Produce		json,text/csv
+//	@Param			limit		query	int		false	"Max number of results to return"
+//	@Param			offset		query	int		false	"Starting point to return rows from, should be multiplied by limit or 0"
+//	@Param			action		query	string	false	"filter by action e.g. admin.user_promote"
+//	@Param			actorId		query	string	false	"filter by the user that performed the action"
+//	@Param			targetType	query	string	false	"filter by target type e.g. user"
+//	@Param			targetId	query	string	false	"filter by target id"
+//	@Param			from		query	string	false	"start date YYYY-MM-DD"
+//	@Param			to			query	string	false	"end date YYYY-MM-DD (inclusive)"
+//	@Param			format		query	string	false	"json (default) or csv"
+//	@Success		200			object	standardJsonResponse{data=[]thunderdome.AuditEvent,meta=pagination}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/audit-events [get]
+func (s *Service) handleGetAuditEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, filterErr := auditEventFilterFromRequest(r)
+		if filterErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, filterErr.Error()))
+			return
+		}
+		if orgID := r.URL.Query().Get("organizationId"); orgID != "" {
+			if err := validate.Var(orgID, "uuid"); err != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "organizationId must be a uuid"))
+				return
+			}
+			filter.OrganizationID = orgID
+		}
+
+		s.respondAuditEvents(w, r, filter, "audit-events.csv")
+	}
+}
+
+// handleGetOrganizationAuditEvents gets the audit events of an organization
+//
+//	@Summary		Get Organization Audit Events
+//	@Description	get a list of the organization's membership and administrative audit events newest first
+//	@Tags			organization
+//	@Produce		json,text/csv
+//	@Param			orgId		path	string	true	"organization id"
+//	@Param			limit		query	int		false	"Max number of results to return"
+//	@Param			offset		query	int		false	"Starting point to return rows from, should be multiplied by limit or 0"
+//	@Param			action		query	string	false	"filter by action e.g. team.user_add"
+//	@Param			actorId		query	string	false	"filter by the user that performed the action"
+//	@Param			targetType	query	string	false	"filter by target type e.g. user"
+//	@Param			targetId	query	string	false	"filter by target id"
+//	@Param			from		query	string	false	"start date YYYY-MM-DD"
+//	@Param			to			query	string	false	"end date YYYY-MM-DD (inclusive)"
+//	@Param			format		query	string	false	"json (default) or csv"
+//	@Success		200			object	standardJsonResponse{data=[]thunderdome.AuditEvent,meta=pagination}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/audit-events [get]
+func (s *Service) handleGetOrganizationAuditEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.Config.OrganizationsEnabled {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ORGANIZATIONS_DISABLED"))
+			return
+		}
+		orgID := r.PathValue("orgId")
+		idErr := validate.Var(orgID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		filter, filterErr := auditEventFilterFromRequest(r)
+		if filterErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, filterErr.Error()))
+			return
+		}
+		filter.OrganizationID = orgID
+
+		s.respondAuditEvents(w, r, filter, fmt.Sprintf("organization-%s-audit-events.csv", orgID))
+	}
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func TestAuditEventFilterFromRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantErr  bool
+		wantFrom string
+		wantTo   string
+	}{
+		{name: "no filters", query: ""},
+		{name: "action and target", query: "?action=admin.user_promote&targetType=user&targetId=abc"},
+		{name: "date range is inclusive", query: "?from=2026-10-01&to=2026-10-17", wantFrom: "2026-10-01", wantTo: "2026-10-18"},
+		{name: "invalid actor", query: "?actorId=nope", wantErr: true},
+		{name: "invalid from", query: "?from=10/01/2026", wantErr: true},
+		{name: "from after to", query: "?from=2026-10-17&to=2026-10-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/admin/audit-events"+tt.query, nil)
+
+			filter, err := auditEventFilterFromRequest(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("auditEventFilterFromRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantFrom != "" && (filter.From == nil || filter.From.Format(time.DateOnly) != tt.wantFrom) {
+				t.Errorf("auditEventFilterFromRequest() From = %v, want %s", filter.From, tt.wantFrom)
+			}
+			if tt.wantTo != "" && (filter.To == nil || filter.To.Format(time.DateOnly) != tt.wantTo) {
+				t.Errorf("auditEventFilterFromRequest() To = %v, want %s", filter.To, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestWriteAuditEventsCSVNeutralizesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeAuditEventsCSV(csv.NewWriter(&buf), []*thunderdome.AuditEvent{{
+		Action:     thunderdome.AuditActionLogin,
+		ActorName:  "=HYPERLINK(\"https://example.com\")",
+		TargetType: thunderdome.AuditTargetTypeUser,
+		TargetID:   "@SUM(A1)",
+		UserAgent:  "+cmd|' /C calc'!A0",
+		Metadata:   map[string]string{"email": "-1+1@example.com"},
+	}})
+	if err != nil {
+		t.Fatalf("writeAuditEventsCSV() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV error = %v", err)
+	}
+	row := records[1]
+	want := map[int]string{
+		3:  "'=HYPERLINK(\"https://example.com\")",
+		6:  "'@SUM(A1)",
+		9:  "'+cmd|' /C calc'!A0",
+		10: "email=-1+1@example.com",
+	}
+	for i, value := range want {
+		if row[i] != value {
+			t.Errorf("column %s = %q, want %q", records[0][i], row[i], value)
+		}
+	}
+}
@@ -20,6 +20,7 @@ type loginResponse struct {
 	User        *thunderdome.User `json:"user"`
 	SessionId   string            `json:"sessionId"`
 	MFARequired bool              `json:"mfaRequired"`
+	MFAMethods  []string          `json:"mfaMethods,omitempty"`
 	Subscribed  bool              `json:"subscribed"`
 }
 
@@ -34,6 +35,7 @@ type loginResponse struct {
 //	@Success		200			object	standardJsonResponse{data=loginResponse}
 //	@Failure		401			object	standardJsonResponse{}
 //	@Failure		500			object	standardJsonResponse{}
+//	@Failure		429			object	standardJsonResponse{}
 //	@Router			/auth [post]
 func (s *Service) handleLogin() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -57,10 +59,18 @@ func (s *Service) handleLogin() http.HandlerFunc {
 			return
 		}
 
+		if s.accountRateLimitExceeded(w, r, "login", u.Email) {
+			return
+		}
+
 		authedUser, credential, sessionID, err := s.AuthDataSvc.AuthUser(ctx, u.Email, u.Password)
 		if err != nil {
 			userErr := err.Error()
-			if userErr == "USER_NOT_FOUND" || userErr == "INVALID_PASSWORD" || userErr == "USER_DISABLED" {
+			if userErr == "USER_NOT_FOUND" || userErr == "INVALID_PASSWORD" || userErr == "USER_DISABLED" || userErr == "USER_LOCKED" {
+				s.recordLoginFailedAuditEvent(r, u.Email, "password", userErr)
+				if userErr == "INVALID_PASSWORD" {
+					s.recordFailedPasswordLogin(r, u.Email)
+				}
 				s.Failure(w, r, http.StatusUnauthorized, Errorf(EINVALID, "INVALID_LOGIN"))
 			} else {
 				s.Logger.Ctx(ctx).Error("handleLogin error", zap.Error(err),
@@ -70,6 +80,7 @@ func (s *Service) handleLogin() http.HandlerFunc {
 			return
 		}
 
+		s.resetAccountRateLimit(r, "login", u.Email)
 		subscribed := s.SubscriptionDataSvc.CheckActiveSubscriber(ctx, authedUser.ID)
 
 		res := loginResponse{
@@ -80,11 +91,12 @@ func (s *Service) handleLogin() http.HandlerFunc {
 		}
 
 		if res.MFARequired {
+			res.MFAMethods = s.userMFAMethods(ctx, authedUser.ID)
 			s.Success(w, r, http.StatusOK, res, nil)
 			return
 		}
 
-		cookieErr := s.Cookie.CreateSessionCookie(w, sessionID)
+		cookieErr := s.createSessionCookie(w, r, sessionID)
 		if cookieErr != nil {
 			s.Logger.Ctx(ctx).Error("handleLogin error", zap.Error(cookieErr),
 				zap.String("session_id", sessionID), zap.String("session_user_id", authedUser.ID))
@@ -92,6 +104,7 @@ func (s *Service) handleLogin() http.HandlerFunc {
 			return
 		}
 
+		s.recordLoginAuditEvent(r, authedUser, "password")
 		s.Success(w, r, http.StatusOK, res, nil)
 	}
 }
@@ -113,6 +126,7 @@ type userLoginLdapRequestBody struct {
 //	@Success		200			object	standardJsonResponse{data=loginResponse}
 //	@Failure		401			object	standardJsonResponse{}
 //	@Failure		500			object	standardJsonResponse{}
+//	@Failure		429			object	standardJsonResponse{}
 //	@Router			/auth/ldap [post]
 func (s *Service) handleLdapLogin() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -136,14 +150,21 @@ func (s *Service) handleLdapLogin() http.HandlerFunc {
 			return
 		}
 
+		if s.accountRateLimitExceeded(w, r, "ldap", u.Email) {
+			return
+		}
+
 		authedUser, sessionID, err := s.authAndCreateUserLdap(ctx, u.Email, u.Password)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleLdapLogin error", zap.Error(err),
 				zap.String("user_email", sanitizeUserInputForLogs(u.Email)))
+			s.recordLoginFailedAuditEvent(r, u.Email, "ldap", "INVALID_LOGIN")
 			s.Failure(w, r, http.StatusUnauthorized, Errorf(EINVALID, "INVALID_LOGIN"))
 			return
 		}
 
+		s.resetAccountRateLimit(r, "ldap", u.Email)
+
 		res := loginResponse{
 			User:        authedUser,
 			SessionId:   sessionID,
@@ -155,7 +176,7 @@ func (s *Service) handleLdapLogin() http.HandlerFunc {
 			return
 		}
 
-		cookieErr := s.Cookie.CreateSessionCookie(w, sessionID)
+		cookieErr := s.createSessionCookie(w, r, sessionID)
 		if cookieErr != nil {
 			s.Logger.Ctx(ctx).Error("handleLdapLogin error", zap.Error(cookieErr),
 				zap.String("session_user_id", authedUser.ID), zap.String("session_id", sessionID))
@@ -163,6 +184,7 @@ func (s *Service) handleLdapLogin() http.HandlerFunc {
 			return
 		}
 
+		s.recordLoginAuditEvent(r, authedUser, "ldap")
 		s.Success(w, r, http.StatusOK, res, nil)
 	}
 }
@@ -195,6 +217,7 @@ func (s *Service) handleHeaderLogin() http.HandlerFunc {
 			s.Logger.Ctx(ctx).Error("handleHeaderLogin error", zap.Error(err),
 				zap.String("user_name", sanitizeUserInputForLogs(username)),
 				zap.String("user_email", sanitizeUserInputForLogs(username)))
+			s.recordLoginFailedAuditEvent(r, useremail, "header", "INVALID_LOGIN")
 			s.Failure(w, r, http.StatusUnauthorized, Errorf(EINVALID, "INVALID_LOGIN"))
 			return
 		}
@@ -210,7 +233,7 @@ func (s *Service) handleHeaderLogin() http.HandlerFunc {
 			return
 		}
 
-		cookieErr := s.Cookie.CreateSessionCookie(w, sessionID)
+		cookieErr := s.createSessionCookie(w, r, sessionID)
 		if cookieErr != nil {
 			s.Logger.Ctx(ctx).Error("handleHeaderLogin error", zap.Error(cookieErr),
 				zap.String("session_user_id", authedUser.ID), zap.String("session_id", sessionID))
@@ -218,25 +241,28 @@ func (s *Service) handleHeaderLogin() http.HandlerFunc {
 			return
 		}
 
+		s.recordLoginAuditEvent(r, authedUser, "header")
 		s.Success(w, r, http.StatusOK, res, nil)
 	}
 }
 
 type mfaLoginRequestBody struct {
-	Passcode  string `json:"passcode" validate:"required"`
-	SessionID string `json:"sessionId" validate:"required"`
+	Passcode     string `json:"passcode" validate:"required_without=RecoveryCode"`
+	RecoveryCode string `json:"recoveryCode" validate:"required_without=Passcode"`
+	SessionID    string `json:"sessionId" validate:"required"`
 }
 
 // handleMFALogin attempts to log in the user with MFA token
 //
 //	@Summary		MFA Login
-//	@Description	attempts to log the user in with provided MFA token
+//	@Description	attempts to log the user in with provided MFA token, or one of their unused recovery codes
 //	@Tags			auth
 //	@Produce		json
 //	@Param			credentials	body	mfaLoginRequestBody	false	"mfa login object"
 //	@Success		200			object	standardJsonResponse{}
 //	@Failure		401			object	standardJsonResponse{}
 //	@Failure		500			object	standardJsonResponse{}
+//	@Failure		429			object	standardJsonResponse{}
 //	@Router			/auth/mfa [post]
 func (s *Service) handleMFALogin() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -260,15 +286,44 @@ func (s *Service) handleMFALogin() http.HandlerFunc {
 			return
 		}
 
-		err := s.AuthDataSvc.MFATokenValidate(ctx, u.SessionID, u.Passcode)
+		// the budget is per user, a new login would otherwise get a fresh budget with its new session
+		userID, err := s.AuthDataSvc.MFASessionUserID(ctx, u.SessionID)
+		if err != nil && err.Error() == "SESSION_NOT_FOUND" {
+			s.Failure(w, r, http.StatusUnauthorized, Errorf(EUNAUTHORIZED, "INVALID_SESSION"))
+			return
+		} else if err != nil {
+			s.Logger.Ctx(ctx).Error("handleMFALogin error", zap.Error(err))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if s.accountRateLimitExceeded(w, r, "mfa", userID) {
+			return
+		}
+
+		method, failMethod, failReason := "password+mfa", "mfa", "INVALID_AUTHENTICATOR_TOKEN"
+		if u.Passcode != "" {
+			err = s.AuthDataSvc.MFATokenValidate(ctx, u.SessionID, u.Passcode)
+		} else {
+			method, failMethod, failReason = "password+recovery_code", thunderdome.MFAMethodRecoveryCode, "INVALID_RECOVERY_CODE"
+			err = s.AuthDataSvc.MFARecoveryCodeValidate(ctx, u.SessionID, u.RecoveryCode)
+		}
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleMFALogin error", zap.Error(err),
 				zap.String("session_id", u.SessionID))
-			s.Failure(w, r, http.StatusUnauthorized, Errorf(EINVALID, "INVALID_AUTHENTICATOR_TOKEN"))
+			s.recordAuditEvent(r, thunderdome.AuditEvent{
+				ActorID:    &userID,
+				Action:     thunderdome.AuditActionLoginFailed,
+				TargetType: thunderdome.AuditTargetTypeUser,
+				TargetID:   userID,
+				Metadata:   map[string]string{"method": failMethod, "reason": failReason},
+			})
+			s.recordFailedSecondFactor(r, userID)
+			s.Failure(w, r, http.StatusUnauthorized, Errorf(EINVALID, failReason))
 			return
 		}
 
-		cookieErr := s.Cookie.CreateSessionCookie(w, u.SessionID)
+		cookieErr := s.createSessionCookie(w, r, u.SessionID)
 		if cookieErr != nil {
 			s.Logger.Ctx(ctx).Error("handleMFALogin error", zap.Error(cookieErr),
 				zap.String("session_id", u.SessionID))
@@ -276,6 +331,10 @@ func (s *Service) handleMFALogin() http.HandlerFunc {
 			return
 		}
 
+		if sessionUser, sessionErr := s.AuthDataSvc.GetSessionUserByID(ctx, u.SessionID); sessionErr == nil {
+			s.recordLoginAuditEvent(r, sessionUser, method)
+		}
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
@@ -296,6 +355,8 @@ func (s *Service) handleLogout() http.HandlerFunc {
 			return
 		}
 
+		sessionUser, _ := s.AuthDataSvc.GetSessionUserByID(ctx, sessionID)
+
 		err := s.AuthDataSvc.DeleteSession(ctx, sessionID)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleLogout error", zap.Error(err),
@@ -304,6 +365,16 @@ func (s *Service) handleLogout() http.HandlerFunc {
 			return
 		}
 
+		if sessionUser != nil {
+			s.recordAuditEvent(r, thunderdome.AuditEvent{
+				Action:     thunderdome.AuditActionLogout,
+				ActorID:    &sessionUser.ID,
+				ActorType:  sessionUser.Type,
+				TargetType: thunderdome.AuditTargetTypeUser,
+				TargetID:   sessionUser.ID,
+			})
+		}
+
 		s.Cookie.ClearUserCookies(w)
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
@@ -323,6 +394,7 @@ type guestUserCreateRequestBody struct {
 //	@Success		200		object	standardJsonResponse{data=thunderdome.User}
 //	@Failure		400		object	standardJsonResponse{}
 //	@Failure		500		object	standardJsonResponse{}
+//	@Failure		429		object	standardJsonResponse{}
 //	@Router			/auth/guest [post]
 func (s *Service) handleCreateGuestUser() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -394,6 +466,7 @@ type userRegisterRequestBody struct {
 //	@Success		200		object	standardJsonResponse{data=thunderdome.User}
 //	@Failure		400		object	standardJsonResponse{}
 //	@Failure		500		object	standardJsonResponse{}
+//	@Failure		429		object	standardJsonResponse{}
 //	@Router			/auth/register [post]
 func (s *Service) handleUserRegistration() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -470,7 +543,7 @@ func (s *Service) handleUserRegistration() http.HandlerFunc {
 			return
 		}
 
-		cookieErr := s.Cookie.CreateSessionCookie(w, sessionID)
+		cookieErr := s.createSessionCookie(w, r, sessionID)
 		if cookieErr != nil {
 			s.Logger.Ctx(ctx).Error("handleUserRegistration error", zap.Error(cookieErr),
 				zap.String("session_user_id", newUser.ID),
@@ -495,6 +568,7 @@ type forgotPasswordRequestBody struct {
 //	@Produce		json
 //	@Param			user	body	forgotPasswordRequestBody	false	"forgot password object"
 //	@Success		200		object	standardJsonResponse{}
+//	@Failure		429		object	standardJsonResponse{}
 //	@Router			/auth/forgot-password [post]
 func (s *Service) handleForgotPassword() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -520,6 +594,10 @@ func (s *Service) handleForgotPassword() http.HandlerFunc {
 
 		userEmail := strings.ToLower(u.Email)
 
+		if s.accountRateLimitExceeded(w, r, "forgot_password", userEmail) {
+			return
+		}
+
 		resetID, userName, resetErr := s.AuthDataSvc.UserResetRequest(ctx, userEmail)
 		if resetErr == nil {
 			emailErr := s.Email.SendForgotPassword(userName, userEmail, resetID)
@@ -552,6 +630,7 @@ type resetPasswordRequestBody struct {
 //	@Success		200		object	standardJsonResponse{}
 //	@Success		400		object	standardJsonResponse{}
 //	@Success		500		object	standardJsonResponse{}
+//	@Failure		429		object	standardJsonResponse{}
 //	@Router			/auth/reset-password [patch]
 func (s *Service) handleResetPassword() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -583,6 +662,12 @@ func (s *Service) handleResetPassword() http.HandlerFunc {
 			return
 		}
 
+		s.recordAuditEvent(r, thunderdome.AuditEvent{
+			Action:     thunderdome.AuditActionPasswordReset,
+			TargetType: thunderdome.AuditTargetTypeUser,
+			Metadata:   map[string]string{"email": userEmail},
+		})
+
 		emailErr := s.Email.SendPasswordReset(userName, userEmail)
 
 		if emailErr != nil {
@@ -643,6 +728,8 @@ func (s *Service) handleUpdatePassword() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionPasswordUpdate, sessionUserID, nil)
+
 		emailErr := s.Email.SendPasswordUpdate(userName, userEmail)
 		if emailErr != nil {
 			s.Logger.Ctx(ctx).Error("handleUpdatePassword error sending password update email", zap.Error(emailErr),
@@ -776,15 +863,29 @@ func (s *Service) handleMFASetupValidate() http.HandlerFunc {
 		}
 
 		type result struct {
-			Result string `json:"result"`
+			Result        string   `json:"result"`
+			RecoveryCodes []string `json:"recoveryCodes,omitempty"`
 		}
 		res := result{Result: "SUCCESS"}
 
-		err := s.AuthDataSvc.MFASetupValidate(ctx, sessionUserID, v.Secret, v.Passcode)
+		status, err := s.AuthDataSvc.MFAStatus(ctx, sessionUserID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleMFASetupValidate error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		err = s.AuthDataSvc.MFASetupValidate(ctx, sessionUserID, v.Secret, v.Passcode)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleMFASetupValidate error", zap.Error(err),
 				zap.String("session_user_id", sessionUserID))
 			res.Result = err.Error()
+		} else {
+			s.recordUserAuditEvent(r, thunderdome.AuditActionMFAEnable, sessionUserID, map[string]string{
+				"method": thunderdome.MFAMethodTOTP,
+			})
+			res.RecoveryCodes = s.issueFirstFactorRecoveryCodes(ctx, sessionUserID, status)
 		}
 
 		s.Success(w, r, http.StatusOK, res, nil)
@@ -811,6 +912,8 @@ func (s *Service) handleMFARemove() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionMFARemove, sessionUserID, nil)
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
@@ -24,6 +24,9 @@ type Config struct {
 
 	// Websocket Subdomain (for Websocket origin check)
 	WebsocketSubdomain string
+
+	// Broadcast backend for fanning out events to other instances
+	BroadcastBackend wshub.Backend
 }
 
 type CheckinDataSvc interface {
@@ -95,6 +98,8 @@ func New(
 		WriteWaitSec:       config.WriteWaitSec,
 		PongWaitSec:        config.PongWaitSec,
 		PingPeriodSec:      config.PingPeriodSec,
+		Backend:            config.BroadcastBackend,
+		Channel:            "checkin",
 	}, map[string]func(context.Context, string, string, string) (any, []byte, error, bool){
 		"checkin_create": s.CheckinCreate,
 		"checkin_update": s.CheckinUpdate,
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// clientIP returns the client IP address of the request, the X-Forwarded-For and X-Real-Ip headers
+// are only honored when the connection comes from a trusted proxy since any client can set them
+func (s *Service) clientIP(r *http.Request) string {
+	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remoteIP = r.RemoteAddr
+	}
+	if !s.trustedProxy(remoteIP) {
+		return remoteIP
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		// each proxy appends the address it received the request from, so walking back from the nearest
+		// proxy the first address not belonging to a trusted proxy is the client
+		ips := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(ips) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(ips[i])
+			if ip != "" && (i == 0 || !s.trustedProxy(ip)) {
+				return ip
+			}
+		}
+	}
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-Ip")); realIP != "" {
+		return realIP
+	}
+
+	return remoteIP
+}
+
+// trustedProxy checks whether the IP address belongs to one of the configured trusted proxies
+func (s *Service) trustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range s.Config.TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
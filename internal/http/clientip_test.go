@@ -0,0 +1,71 @@
+package http
+
+import (
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name           string
+		trustedProxies []netip.Prefix
+		remoteAddr     string
+		headers        map[string]string
+		want           string
+	}{
+		{name: "remote address", remoteAddr: "10.0.0.5:52341", want: "10.0.0.5"},
+		{name: "remote address without port", remoteAddr: "10.0.0.5", want: "10.0.0.5"},
+		{
+			name:       "forwarded for ignored without trusted proxies",
+			remoteAddr: "203.0.113.9:52341",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.2"},
+			want:       "203.0.113.9",
+		},
+		{
+			name:           "forwarded for ignored from untrusted peer",
+			trustedProxies: trusted,
+			remoteAddr:     "203.0.113.9:52341",
+			headers:        map[string]string{"X-Forwarded-For": "198.51.100.2", "X-Real-Ip": "198.51.100.2"},
+			want:           "203.0.113.9",
+		},
+		{
+			name:           "forwarded for from trusted proxy",
+			trustedProxies: trusted,
+			remoteAddr:     "10.0.0.5:52341",
+			headers:        map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			want:           "203.0.113.7",
+		},
+		{
+			name:           "spoofed forwarded for entries before the client are skipped",
+			trustedProxies: trusted,
+			remoteAddr:     "10.0.0.5:52341",
+			headers:        map[string]string{"X-Forwarded-For": "198.51.100.66, 203.0.113.7, 10.0.0.1"},
+			want:           "203.0.113.7",
+		},
+		{
+			name:           "real ip header from trusted proxy",
+			trustedProxies: trusted,
+			remoteAddr:     "10.0.0.5:52341",
+			headers:        map[string]string{"X-Real-Ip": "198.51.100.2"},
+			want:           "198.51.100.2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{Config: &Config{TrustedProxies: tt.trustedProxies}}
+			r := httptest.NewRequest("GET", "/api/admin/audit-events", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+
+			if got := s.clientIP(r); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
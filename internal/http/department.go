@@ -422,6 +422,8 @@ func (s *Service) handleDepartmentAddUser() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionDepartmentUserAdd, u.UserID, map[string]string{"departmentId": departmentID, "role": u.Role})
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
@@ -490,6 +492,8 @@ func (s *Service) handleDepartmentUpdateUser() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionDepartmentUserUpdate, userID, map[string]string{"departmentId": departmentID, "role": u.Role})
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
@@ -544,6 +548,8 @@ func (s *Service) handleDepartmentRemoveUser() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionDepartmentUserRemove, userID, map[string]string{"departmentId": departmentID})
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
@@ -623,6 +629,8 @@ func (s *Service) handleDepartmentTeamAddUser() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionTeamUserAdd, u.UserID, map[string]string{"departmentId": departmentID, "teamId": teamID, "role": u.Role})
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
@@ -924,6 +932,13 @@ func (s *Service) handleDepartmentInviteUser() http.HandlerFunc {
 			return
 		}
 
+		s.recordAuditEvent(r, thunderdome.AuditEvent{
+			Action:     thunderdome.AuditActionDepartmentUserInvite,
+			TargetType: thunderdome.AuditTargetTypeInvite,
+			TargetID:   inviteID,
+			Metadata:   map[string]string{"departmentId": departmentID, "email": userEmail, "role": u.Role},
+		})
+
 		org, orgErr := s.OrganizationDataSvc.OrganizationGetByID(ctx, orgID)
 		if orgErr != nil {
 			s.Logger.Ctx(ctx).Error("handleDepartmentInviteUser error", zap.Error(orgErr),
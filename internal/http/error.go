@@ -7,12 +7,13 @@ import (
 
 // Application error codes.
 const (
-	ECONFLICT       = "conflict"
-	EINTERNAL       = "internal"
-	EINVALID        = "invalid"
-	ENOTFOUND       = "not_found"
-	ENOTIMPLEMENTED = "not_implemented"
-	EUNAUTHORIZED   = "unauthorized"
+	ECONFLICT        = "conflict"
+	EINTERNAL        = "internal"
+	EINVALID         = "invalid"
+	ENOTFOUND        = "not_found"
+	ENOTIMPLEMENTED  = "not_implemented"
+	EUNAUTHORIZED    = "unauthorized"
+	ETOOMANYREQUESTS = "too_many_requests"
 )
 
 // Error represents an application-specific error. Application errors can be
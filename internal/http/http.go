@@ -25,6 +25,7 @@ import (
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 	"github.com/go-playground/validator/v10"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
+	"go.uber.org/zap"
 )
 
 // New initializes the http handlers
@@ -41,6 +42,9 @@ import (
 //	@securityDefinitions.apikey	ApiKeyAuth
 //	@in							header
 //	@name						X-API-Key
+//	@securityDefinitions.apikey	SCIMBearerAuth
+//	@in							header
+//	@name						Authorization
 func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	staticHandler := http.FileServer(HFS)
 
@@ -83,13 +87,15 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 		PingPeriodSec:      a.Config.WebsocketConfig.PingPeriodSec,
 		AppDomain:          a.Config.AppDomain,
 		WebsocketSubdomain: a.Config.WebsocketConfig.WebsocketSubdomain,
-	}, a.Logger, a.Cookie.ValidateSessionCookie, a.Cookie.ValidateUserCookie, a.UserDataSvc, a.AuthDataSvc, a.PokerDataSvc)
+		BroadcastBackend:   a.Config.WebsocketConfig.BroadcastBackend,
+	}, a.Logger, a.Cookie.ValidateSessionCookie, a.Cookie.ValidateUserCookie, a.UserDataSvc, a.AuthDataSvc, a.PokerDataSvc, a.JiraDataSvc)
 	retroSvc := retro.New(retro.Config{
 		WriteWaitSec:       a.Config.WebsocketConfig.WriteWaitSec,
 		PongWaitSec:        a.Config.WebsocketConfig.PongWaitSec,
 		PingPeriodSec:      a.Config.WebsocketConfig.PingPeriodSec,
 		AppDomain:          a.Config.AppDomain,
 		WebsocketSubdomain: a.Config.WebsocketConfig.WebsocketSubdomain,
+		BroadcastBackend:   a.Config.WebsocketConfig.BroadcastBackend,
 	}, a.Logger, a.Cookie.ValidateSessionCookie, a.Cookie.ValidateUserCookie, a.UserDataSvc, a.AuthDataSvc,
 		a.RetroDataSvc, a.RetroTemplateDataSvc, a.Email)
 	storyboardSvc := storyboard.New(storyboard.Config{
@@ -98,6 +104,7 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 		PingPeriodSec:      a.Config.WebsocketConfig.PingPeriodSec,
 		AppDomain:          a.Config.AppDomain,
 		WebsocketSubdomain: a.Config.WebsocketConfig.WebsocketSubdomain,
+		BroadcastBackend:   a.Config.WebsocketConfig.BroadcastBackend,
 	}, a.Logger, a.Cookie.ValidateSessionCookie, a.Cookie.ValidateUserCookie, a.UserDataSvc, a.AuthDataSvc, a.StoryboardDataSvc)
 	checkinSvc := checkin.New(checkin.Config{
 		WriteWaitSec:       a.Config.WebsocketConfig.WriteWaitSec,
@@ -105,10 +112,17 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 		PingPeriodSec:      a.Config.WebsocketConfig.PingPeriodSec,
 		AppDomain:          a.Config.AppDomain,
 		WebsocketSubdomain: a.Config.WebsocketConfig.WebsocketSubdomain,
+		BroadcastBackend:   a.Config.WebsocketConfig.BroadcastBackend,
 	}, a.Logger, a.Cookie.ValidateSessionCookie, a.Cookie.ValidateUserCookie, a.UserDataSvc, a.AuthDataSvc, a.CheckinDataSvc, a.TeamDataSvc)
 
 	validate = validator.New()
 
+	webAuthn, webAuthnErr := newWebAuthn(a.Config, a.appOrigin())
+	if webAuthnErr != nil {
+		a.Logger.Error("error configuring webauthn, security key MFA is unavailable", zap.Error(webAuthnErr))
+	}
+	a.webAuthn = webAuthn
+
 	prefix := apiService.Config.PathPrefix
 
 	router := http.NewServeMux()
@@ -122,18 +136,11 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 
 	// user authentication, profile
 	if a.Config.LdapEnabled {
-		router.Handle("POST "+prefix+"/api/auth/ldap", a.handleLdapLogin())
+		router.Handle("POST "+prefix+"/api/auth/ldap", a.authRateLimit("ldap", a.handleLdapLogin()))
 	} else if a.Config.HeaderAuthEnabled {
 		router.Handle("GET "+prefix+"/api/auth", a.handleHeaderLogin())
 	} else if a.Config.OIDCAuth.Enabled {
-		authProviderConfigs = append(authProviderConfigs, thunderdome.AuthProviderConfig{
-			ProviderName:           a.Config.OIDCAuth.ProviderName,
-			ProviderURL:            a.Config.OIDCAuth.ProviderURL,
-			ClientID:               a.Config.OIDCAuth.ClientID,
-			ClientSecret:           a.Config.OIDCAuth.ClientSecret,
-			RequestedScopes:        a.Config.OIDCAuth.RequestedScopes,
-			RequestedIDTokenClaims: a.Config.OIDCAuth.RequestedIDTokenClaims,
-		})
+		authProviderConfigs = append(authProviderConfigs, a.Config.OIDCAuth.Providers...)
 	} else {
 		if a.Config.GoogleAuth.Enabled {
 			authProviderConfigs = append(authProviderConfigs, thunderdome.AuthProviderConfig{
@@ -145,20 +152,28 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 				RequestedIDTokenClaims: a.Config.GoogleAuth.RequestedIDTokenClaims,
 			})
 		}
-		router.Handle("POST "+prefix+"/api/auth", a.handleLogin())
-		router.Handle("POST "+prefix+"/api/auth/forgot-password", a.handleForgotPassword())
-		router.Handle("PATCH "+prefix+"/api/auth/reset-password", a.handleResetPassword())
+		router.Handle("POST "+prefix+"/api/auth", a.authRateLimit("login", a.handleLogin()))
+		router.Handle("POST "+prefix+"/api/auth/forgot-password", a.authRateLimit("forgot_password", a.handleForgotPassword()))
+		router.Handle("PATCH "+prefix+"/api/auth/reset-password", a.authRateLimit("reset_password", a.handleResetPassword()))
 		router.Handle("PATCH "+prefix+"/api/auth/update-password", a.userOnly(a.handleUpdatePassword()))
 		router.Handle("PATCH "+prefix+"/api/auth/verify", a.handleAccountVerification())
-		router.Handle("POST "+prefix+"/api/auth/register", a.handleUserRegistration())
+		router.Handle("POST "+prefix+"/api/auth/register", a.authRateLimit("register", a.handleUserRegistration()))
 		router.Handle("GET "+prefix+"/api/auth/invite/team/{inviteId}", a.handleGetTeamInviteByID())
 		router.Handle("GET "+prefix+"/api/auth/invite/organization/{inviteId}", a.handleGetOrganizationInviteByID())
 	}
-	router.Handle("POST "+prefix+"/api/auth/mfa", a.handleMFALogin())
+	router.Handle("POST "+prefix+"/api/auth/mfa", a.authRateLimit("mfa", a.handleMFALogin()))
 	router.Handle("DELETE "+prefix+"/api/auth/mfa", a.userOnly(a.registeredUserOnly(a.handleMFARemove())))
 	router.Handle("POST "+prefix+"/api/auth/mfa/setup/generate", a.userOnly(a.registeredUserOnly(a.handleMFASetupGenerate())))
 	router.Handle("POST "+prefix+"/api/auth/mfa/setup/validate", a.userOnly(a.registeredUserOnly(a.handleMFASetupValidate())))
-	router.Handle("POST "+prefix+"/api/auth/guest", a.handleCreateGuestUser())
+	router.Handle("GET "+prefix+"/api/auth/mfa", a.userOnly(a.registeredUserOnly(a.handleMFAStatus())))
+	router.Handle("POST "+prefix+"/api/auth/mfa/recovery-codes", a.userOnly(a.registeredUserOnly(a.handleMFARecoveryCodesGenerate())))
+	router.Handle("POST "+prefix+"/api/auth/mfa/webauthn/register/begin", a.userOnly(a.registeredUserOnly(a.handleWebAuthnRegisterBegin())))
+	router.Handle("POST "+prefix+"/api/auth/mfa/webauthn/register/finish", a.userOnly(a.registeredUserOnly(a.handleWebAuthnRegisterFinish())))
+	router.Handle("GET "+prefix+"/api/auth/mfa/webauthn/credentials", a.userOnly(a.registeredUserOnly(a.handleWebAuthnCredentialList())))
+	router.Handle("DELETE "+prefix+"/api/auth/mfa/webauthn/credentials/{credentialId}", a.userOnly(a.registeredUserOnly(a.handleWebAuthnCredentialDelete())))
+	router.Handle("POST "+prefix+"/api/auth/mfa/webauthn/login/begin", a.authRateLimit("mfa", a.handleWebAuthnLoginBegin()))
+	router.Handle("POST "+prefix+"/api/auth/mfa/webauthn/login/finish", a.authRateLimit("mfa", a.handleWebAuthnLoginFinish()))
+	router.Handle("POST "+prefix+"/api/auth/guest", a.authRateLimit("guest", a.handleCreateGuestUser()))
 	router.Handle("GET "+prefix+"/api/auth/user", a.userOnly(a.handleSessionUserProfile()))
 	router.Handle("DELETE "+prefix+"/api/auth/logout", a.handleLogout())
 	// user(s)
@@ -166,6 +181,9 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	router.Handle("PUT "+prefix+"/api/users/{userId}", a.userOnly(a.entityUserOnly(a.handleUserProfileUpdate())))
 	router.Handle("DELETE "+prefix+"/api/users/{userId}", a.userOnly(a.entityUserOnly(a.handleUserDelete())))
 	router.Handle("GET "+prefix+"/api/users/{userId}/credential", a.userOnly(a.entityUserOnly(a.handleUserCredential())))
+	router.Handle("GET "+prefix+"/api/users/{userId}/sessions", a.userOnly(a.entityUserOnly(a.handleUserSessions())))
+	router.Handle("DELETE "+prefix+"/api/users/{userId}/sessions", a.userOnly(a.entityUserOnly(a.handleUserSessionsDelete())))
+	router.Handle("DELETE "+prefix+"/api/users/{userId}/sessions/{sessionId}", a.userOnly(a.entityUserOnly(a.handleUserSessionDelete())))
 	router.Handle("POST "+prefix+"/api/users/{userId}/request-verify", a.userOnly(a.entityUserOnly(a.handleVerifyRequest())))
 	router.Handle("POST "+prefix+"/api/users/{userId}/email-change", a.userOnly(a.entityUserOnly(a.handleChangeEmailRequest())))
 	router.Handle("POST "+prefix+"/api/users/{userId}/email-change/{changeId}", a.userOnly(a.entityUserOnly(a.handleChangeEmailAction())))
@@ -191,6 +209,7 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	router.Handle("PUT "+prefix+"/api/users/{userId}/jira-instances/{instanceId}", a.userOnly(a.entityUserOnly(a.subscribedEntityUserOnly(a.handleJiraInstanceUpdate()))))
 	router.Handle("DELETE "+prefix+"/api/users/{userId}/jira-instances/{instanceId}", a.userOnly(a.entityUserOnly(a.subscribedEntityUserOnly(a.handleJiraInstanceDelete()))))
 	router.Handle("POST "+prefix+"/api/users/{userId}/jira-instances/{instanceId}/jql-story-search", a.userOnly(a.entityUserOnly(a.subscribedEntityUserOnly(a.handleJiraStoryJQLSearch()))))
+	router.Handle("GET "+prefix+"/api/users/{userId}/jira-instances/{instanceId}/fields", a.userOnly(a.entityUserOnly(a.subscribedEntityUserOnly(a.handleJiraInstanceFields()))))
 
 	if a.Config.ExternalAPIEnabled {
 		router.Handle("GET "+prefix+"/api/users/{userId}/apikeys", a.userOnly(a.entityUserOnly(a.handleUserAPIKeys())))
@@ -280,6 +299,34 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	router.Handle("GET "+prefix+"/api/organizations/{orgId}/invites", a.userOnly(a.orgUserOnly(a.handleGetOrganizationUserInvites())))
 	router.Handle("POST "+prefix+"/api/organizations/{orgId}/invites", a.userOnly(a.orgAdminOnly(a.handleOrganizationInviteUser())))
 	router.Handle("DELETE "+prefix+"/api/organizations/{orgId}/invites/{inviteId}", a.userOnly(a.orgAdminOnly(a.handleDeleteOrganizationUserInvite())))
+	router.Handle("GET "+prefix+"/api/organizations/{orgId}/audit-events", a.userOnly(a.orgAdminOnly(a.handleGetOrganizationAuditEvents())))
+	// org service accounts
+	router.Handle("GET "+prefix+"/api/organizations/{orgId}/service-accounts", a.userOnly(a.orgAdminOnly(a.handleOrganizationServiceAccounts())))
+	router.Handle("POST "+prefix+"/api/organizations/{orgId}/service-accounts", a.userOnly(a.orgAdminOnly(a.handleOrganizationServiceAccountCreate())))
+	router.Handle("PUT "+prefix+"/api/organizations/{orgId}/service-accounts/{serviceAccountId}", a.userOnly(a.orgAdminOnly(a.handleOrganizationServiceAccountUpdate())))
+	router.Handle("DELETE "+prefix+"/api/organizations/{orgId}/service-accounts/{serviceAccountId}", a.userOnly(a.orgAdminOnly(a.handleOrganizationServiceAccountDelete())))
+	if a.Config.ExternalAPIEnabled {
+		router.Handle("GET "+prefix+"/api/organizations/{orgId}/service-accounts/{serviceAccountId}/apikeys", a.userOnly(a.orgAdminOnly(a.handleOrganizationServiceAccountAPIKeys())))
+		router.Handle("POST "+prefix+"/api/organizations/{orgId}/service-accounts/{serviceAccountId}/apikeys", a.userOnly(a.orgAdminOnly(a.handleOrganizationServiceAccountAPIKeyGenerate())))
+		router.Handle("PUT "+prefix+"/api/organizations/{orgId}/service-accounts/{serviceAccountId}/apikeys/{keyID}", a.userOnly(a.orgAdminOnly(a.handleOrganizationServiceAccountAPIKeyUpdate())))
+		router.Handle("DELETE "+prefix+"/api/organizations/{orgId}/service-accounts/{serviceAccountId}/apikeys/{keyID}", a.userOnly(a.orgAdminOnly(a.handleOrganizationServiceAccountAPIKeyDelete())))
+	}
+	// org scim provisioning
+	router.Handle("GET "+prefix+"/api/organizations/{orgId}/scim-tokens", a.userOnly(a.orgAdminOnly(a.handleOrganizationSCIMTokens())))
+	router.Handle("POST "+prefix+"/api/organizations/{orgId}/scim-tokens", a.userOnly(a.orgAdminOnly(a.handleOrganizationSCIMTokenCreate())))
+	router.Handle("DELETE "+prefix+"/api/organizations/{orgId}/scim-tokens/{tokenId}", a.userOnly(a.orgAdminOnly(a.handleOrganizationSCIMTokenDelete())))
+	router.Handle("GET "+prefix+"/api/scim/v2/Users", a.scimOnly(a.handleSCIMUsers()))
+	router.Handle("POST "+prefix+"/api/scim/v2/Users", a.scimOnly(a.handleSCIMUserCreate()))
+	router.Handle("GET "+prefix+"/api/scim/v2/Users/{userId}", a.scimOnly(a.handleSCIMUser()))
+	router.Handle("PUT "+prefix+"/api/scim/v2/Users/{userId}", a.scimOnly(a.handleSCIMUserReplace()))
+	router.Handle("PATCH "+prefix+"/api/scim/v2/Users/{userId}", a.scimOnly(a.handleSCIMUserPatch()))
+	router.Handle("DELETE "+prefix+"/api/scim/v2/Users/{userId}", a.scimOnly(a.handleSCIMUserDelete()))
+	router.Handle("GET "+prefix+"/api/scim/v2/Groups", a.scimOnly(a.handleSCIMGroups()))
+	router.Handle("POST "+prefix+"/api/scim/v2/Groups", a.scimOnly(a.handleSCIMGroupCreate()))
+	router.Handle("GET "+prefix+"/api/scim/v2/Groups/{groupId}", a.scimOnly(a.handleSCIMGroup()))
+	router.Handle("PUT "+prefix+"/api/scim/v2/Groups/{groupId}", a.scimOnly(a.handleSCIMGroupReplace()))
+	router.Handle("PATCH "+prefix+"/api/scim/v2/Groups/{groupId}", a.scimOnly(a.handleSCIMGroupPatch()))
+	router.Handle("DELETE "+prefix+"/api/scim/v2/Groups/{groupId}", a.scimOnly(a.handleSCIMGroupDelete()))
 	// teams(s)
 	router.Handle("GET "+prefix+"/api/teams/{teamId}", a.userOnly(a.teamUserOnly(a.handleGetTeamByUser())))
 	router.Handle("PUT "+prefix+"/api/teams/{teamId}", a.userOnly(a.teamUserOnly(a.teamAdminOnly(a.handleTeamUpdate()))))
@@ -315,9 +362,11 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	router.Handle("PATCH "+prefix+"/api/admin/users/{userId}/disable", a.userOnly(a.adminOnly(a.handleUserDisable())))
 	router.Handle("PATCH "+prefix+"/api/admin/users/{userId}/enable", a.userOnly(a.adminOnly(a.handleUserEnable())))
 	router.Handle("PATCH "+prefix+"/api/admin/users/{userId}/password", a.userOnly(a.adminOnly(a.handleAdminUpdateUserPassword())))
+	router.Handle("DELETE "+prefix+"/api/admin/users/{userId}/sessions", a.userOnly(a.adminOnly(a.handleAdminUserSessionsDelete())))
 	router.Handle("GET "+prefix+"/api/admin/organizations", a.userOnly(a.adminOnly(a.handleGetOrganizations())))
 	router.Handle("GET "+prefix+"/api/admin/teams", a.userOnly(a.adminOnly(a.handleGetTeams())))
 	router.Handle("GET "+prefix+"/api/admin/apikeys", a.userOnly(a.adminOnly(a.handleGetAPIKeys())))
+	router.Handle("GET "+prefix+"/api/admin/audit-events", a.userOnly(a.adminOnly(a.handleGetAuditEvents())))
 	router.Handle("GET "+prefix+"/api/admin/search/users/email", a.userOnly(a.adminOnly(a.handleSearchRegisteredUsersByEmail())))
 
 	// Admin support ticket routes
@@ -348,6 +397,7 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 		router.Handle("DELETE "+prefix+"/api/maintenance/clean-battles", a.userOnly(a.adminOnly(a.handleCleanPokerGames())))
 		router.Handle("GET "+prefix+"/api/battles", a.userOnly(a.adminOnly(a.handleGetPokerGames())))
 		router.Handle("GET "+prefix+"/api/battles/{battleId}", a.userOnly(a.handleGetPokerGame()))
+		router.Handle("GET "+prefix+"/api/battles/{battleId}/export", a.userOnly(a.handlePokerExport()))
 		router.Handle("PATCH "+prefix+"/api/battles/{battleId}/end", a.userOnly(a.handlePokerEndGame(pokerSvc)))
 		router.Handle("DELETE "+prefix+"/api/battles/{battleId}", a.userOnly(a.handlePokerDelete(pokerSvc)))
 		router.Handle("POST "+prefix+"/api/battles/{battleId}/plans", a.userOnly(a.handlePokerStoryAdd(pokerSvc)))
@@ -425,6 +475,7 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 		router.Handle("DELETE "+prefix+"/api/maintenance/clean-retros", a.userOnly(a.adminOnly(a.handleCleanRetros())))
 		router.Handle("GET "+prefix+"/api/retros", a.userOnly(a.adminOnly(a.handleGetRetros())))
 		router.Handle("GET "+prefix+"/api/retros/{retroId}", a.userOnly(a.handleRetroGet()))
+		router.Handle("GET "+prefix+"/api/retros/{retroId}/export", a.userOnly(a.handleRetroExport()))
 		router.Handle("DELETE "+prefix+"/api/retros/{retroId}", a.userOnly(a.handleRetroDelete(retroSvc)))
 		router.Handle("PUT "+prefix+"/api/retros/{retroId}/actions/{actionId}", a.userOnly(a.handleRetroActionUpdate(retroSvc)))
 		router.Handle("DELETE "+prefix+"/api/retros/{retroId}/actions/{actionId}", a.userOnly(a.handleRetroActionDelete(retroSvc)))
@@ -567,6 +618,18 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 		// Project operations
 		router.Handle("GET "+prefix+"/api/projects/{projectId}", a.userOnly(a.subscribedProjectOnly(a.projectUserOnly(a.handleGetProjectByID()))))
 
+		// Project items
+		router.Handle("GET "+prefix+"/api/projects/{projectId}/item-types", a.userOnly(a.subscribedProjectOnly(a.projectUserOnly(a.handleGetProjectItemTypes()))))
+		router.Handle("GET "+prefix+"/api/projects/{projectId}/item-statuses", a.userOnly(a.subscribedProjectOnly(a.projectUserOnly(a.handleGetProjectItemStatuses()))))
+		router.Handle("GET "+prefix+"/api/projects/{projectId}/item-priorities", a.userOnly(a.subscribedProjectOnly(a.projectUserOnly(a.handleGetProjectItemPriorities()))))
+		router.Handle("GET "+prefix+"/api/projects/{projectId}/items", a.userOnly(a.subscribedProjectOnly(a.projectUserOnly(a.handleGetProjectItems()))))
+		router.Handle("POST "+prefix+"/api/projects/{projectId}/items", a.userOnly(a.subscribedProjectOnly(a.projectUserOnly(a.handleProjectItemCreate()))))
+		router.Handle("GET "+prefix+"/api/projects/{projectId}/items/{itemId}", a.userOnly(a.subscribedProjectOnly(a.projectUserOnly(a.handleGetProjectItem()))))
+		router.Handle("PUT "+prefix+"/api/projects/{projectId}/items/{itemId}", a.userOnly(a.subscribedProjectOnly(a.projectUserOnly(a.handleProjectItemUpdate()))))
+		router.Handle("PATCH "+prefix+"/api/projects/{projectId}/items/{itemId}/status", a.userOnly(a.subscribedProjectOnly(a.projectUserOnly(a.handleProjectItemStatusUpdate()))))
+		router.Handle("PUT "+prefix+"/api/projects/{projectId}/items/{itemId}/move", a.userOnly(a.subscribedProjectOnly(a.projectUserOnly(a.handleProjectItemMove()))))
+		router.Handle("DELETE "+prefix+"/api/projects/{projectId}/items/{itemId}", a.userOnly(a.subscribedProjectOnly(a.projectUserOnly(a.handleProjectItemDelete()))))
+
 		// Project storyboards
 		if a.Config.FeatureStoryboard {
 			router.Handle("GET "+prefix+"/api/projects/{projectId}/storyboards", a.userOnly(a.subscribedProjectOnly(a.projectUserOnly(a.handleGetProjectStoryboards()))))
@@ -594,6 +657,56 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 		router.Handle("POST "+prefix+"/api/admin/projects", a.userOnly(a.adminOnly(a.handleAdminProjectCreate())))
 		router.Handle("PUT "+prefix+"/api/admin/projects/{projectId}", a.userOnly(a.adminOnly(a.handleAdminProjectUpdate())))
 		router.Handle("DELETE "+prefix+"/api/admin/projects/{projectId}", a.userOnly(a.adminOnly(a.handleAdminProjectDelete())))
+
+		// Project item types, statuses, and priorities by scope
+		router.Handle("GET "+prefix+"/api/admin/item-types", a.userOnly(a.adminOnly(a.handleGetItemTypes())))
+		router.Handle("POST "+prefix+"/api/admin/item-types", a.userOnly(a.adminOnly(a.handleItemTypeCreate())))
+		router.Handle("PUT "+prefix+"/api/admin/item-types/{typeId}", a.userOnly(a.adminOnly(a.handleItemTypeUpdate())))
+		router.Handle("DELETE "+prefix+"/api/admin/item-types/{typeId}", a.userOnly(a.adminOnly(a.handleItemTypeDelete())))
+		router.Handle("GET "+prefix+"/api/organizations/{orgId}/item-types", a.userOnly(a.subscribedOrgOnly(a.orgUserOnly(a.handleGetItemTypes()))))
+		router.Handle("POST "+prefix+"/api/organizations/{orgId}/item-types", a.userOnly(a.subscribedOrgOnly(a.orgAdminOnly(a.handleItemTypeCreate()))))
+		router.Handle("PUT "+prefix+"/api/organizations/{orgId}/item-types/{typeId}", a.userOnly(a.subscribedOrgOnly(a.orgAdminOnly(a.handleItemTypeUpdate()))))
+		router.Handle("DELETE "+prefix+"/api/organizations/{orgId}/item-types/{typeId}", a.userOnly(a.subscribedOrgOnly(a.orgAdminOnly(a.handleItemTypeDelete()))))
+		router.Handle("GET "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/item-types", a.userOnly(a.subscribedOrgOnly(a.departmentUserOnly(a.handleGetItemTypes()))))
+		router.Handle("POST "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/item-types", a.userOnly(a.subscribedOrgOnly(a.departmentAdminOnly(a.handleItemTypeCreate()))))
+		router.Handle("PUT "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/item-types/{typeId}", a.userOnly(a.subscribedOrgOnly(a.departmentAdminOnly(a.handleItemTypeUpdate()))))
+		router.Handle("DELETE "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/item-types/{typeId}", a.userOnly(a.subscribedOrgOnly(a.departmentAdminOnly(a.handleItemTypeDelete()))))
+		router.Handle("GET "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-types", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.handleGetItemTypes()))))
+		router.Handle("POST "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-types", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleItemTypeCreate())))))
+		router.Handle("PUT "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-types/{typeId}", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleItemTypeUpdate())))))
+		router.Handle("DELETE "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-types/{typeId}", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleItemTypeDelete())))))
+		router.Handle("GET "+prefix+"/api/admin/item-statuses", a.userOnly(a.adminOnly(a.handleGetItemStatuses())))
+		router.Handle("POST "+prefix+"/api/admin/item-statuses", a.userOnly(a.adminOnly(a.handleItemStatusCreate())))
+		router.Handle("PUT "+prefix+"/api/admin/item-statuses/{statusId}", a.userOnly(a.adminOnly(a.handleItemStatusUpdate())))
+		router.Handle("DELETE "+prefix+"/api/admin/item-statuses/{statusId}", a.userOnly(a.adminOnly(a.handleItemStatusDelete())))
+		router.Handle("GET "+prefix+"/api/organizations/{orgId}/item-statuses", a.userOnly(a.subscribedOrgOnly(a.orgUserOnly(a.handleGetItemStatuses()))))
+		router.Handle("POST "+prefix+"/api/organizations/{orgId}/item-statuses", a.userOnly(a.subscribedOrgOnly(a.orgAdminOnly(a.handleItemStatusCreate()))))
+		router.Handle("PUT "+prefix+"/api/organizations/{orgId}/item-statuses/{statusId}", a.userOnly(a.subscribedOrgOnly(a.orgAdminOnly(a.handleItemStatusUpdate()))))
+		router.Handle("DELETE "+prefix+"/api/organizations/{orgId}/item-statuses/{statusId}", a.userOnly(a.subscribedOrgOnly(a.orgAdminOnly(a.handleItemStatusDelete()))))
+		router.Handle("GET "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/item-statuses", a.userOnly(a.subscribedOrgOnly(a.departmentUserOnly(a.handleGetItemStatuses()))))
+		router.Handle("POST "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/item-statuses", a.userOnly(a.subscribedOrgOnly(a.departmentAdminOnly(a.handleItemStatusCreate()))))
+		router.Handle("PUT "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/item-statuses/{statusId}", a.userOnly(a.subscribedOrgOnly(a.departmentAdminOnly(a.handleItemStatusUpdate()))))
+		router.Handle("DELETE "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/item-statuses/{statusId}", a.userOnly(a.subscribedOrgOnly(a.departmentAdminOnly(a.handleItemStatusDelete()))))
+		router.Handle("GET "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-statuses", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.handleGetItemStatuses()))))
+		router.Handle("POST "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-statuses", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleItemStatusCreate())))))
+		router.Handle("PUT "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-statuses/{statusId}", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleItemStatusUpdate())))))
+		router.Handle("DELETE "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-statuses/{statusId}", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleItemStatusDelete())))))
+		router.Handle("GET "+prefix+"/api/admin/item-priorities", a.userOnly(a.adminOnly(a.handleGetItemPriorities())))
+		router.Handle("POST "+prefix+"/api/admin/item-priorities", a.userOnly(a.adminOnly(a.handleItemPriorityCreate())))
+		router.Handle("PUT "+prefix+"/api/admin/item-priorities/{priorityId}", a.userOnly(a.adminOnly(a.handleItemPriorityUpdate())))
+		router.Handle("DELETE "+prefix+"/api/admin/item-priorities/{priorityId}", a.userOnly(a.adminOnly(a.handleItemPriorityDelete())))
+		router.Handle("GET "+prefix+"/api/organizations/{orgId}/item-priorities", a.userOnly(a.subscribedOrgOnly(a.orgUserOnly(a.handleGetItemPriorities()))))
+		router.Handle("POST "+prefix+"/api/organizations/{orgId}/item-priorities", a.userOnly(a.subscribedOrgOnly(a.orgAdminOnly(a.handleItemPriorityCreate()))))
+		router.Handle("PUT "+prefix+"/api/organizations/{orgId}/item-priorities/{priorityId}", a.userOnly(a.subscribedOrgOnly(a.orgAdminOnly(a.handleItemPriorityUpdate()))))
+		router.Handle("DELETE "+prefix+"/api/organizations/{orgId}/item-priorities/{priorityId}", a.userOnly(a.subscribedOrgOnly(a.orgAdminOnly(a.handleItemPriorityDelete()))))
+		router.Handle("GET "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/item-priorities", a.userOnly(a.subscribedOrgOnly(a.departmentUserOnly(a.handleGetItemPriorities()))))
+		router.Handle("POST "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/item-priorities", a.userOnly(a.subscribedOrgOnly(a.departmentAdminOnly(a.handleItemPriorityCreate()))))
+		router.Handle("PUT "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/item-priorities/{priorityId}", a.userOnly(a.subscribedOrgOnly(a.departmentAdminOnly(a.handleItemPriorityUpdate()))))
+		router.Handle("DELETE "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/item-priorities/{priorityId}", a.userOnly(a.subscribedOrgOnly(a.departmentAdminOnly(a.handleItemPriorityDelete()))))
+		router.Handle("GET "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-priorities", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.handleGetItemPriorities()))))
+		router.Handle("POST "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-priorities", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleItemPriorityCreate())))))
+		router.Handle("PUT "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-priorities/{priorityId}", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleItemPriorityUpdate())))))
+		router.Handle("DELETE "+prefix+"/api/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-priorities/{priorityId}", a.userOnly(a.subscribedOrgOnly(a.teamUserOnly(a.teamAdminOnly(a.handleItemPriorityDelete())))))
 	}
 
 	// user avatar generation
@@ -648,21 +761,22 @@ func New(apiService Service, FSS fs.FS, HFS http.FileSystem) *Service {
 	return a
 }
 
-func (s *Service) registerOauthProviderEndpoints(router *http.ServeMux, prefix string, providers []thunderdome.AuthProviderConfig) {
-	ctx := context.Background()
-	var redirectBaseURL string
+// appOrigin returns the scheme and host the application is accessed from, including the port for localhost
+func (s *Service) appOrigin() string {
 	var port string
-
-	// redirect with port for localhost
 	if s.Config.AppDomain == "localhost" {
 		port = fmt.Sprintf(":%s", s.Config.Port)
 	}
 
 	if s.Config.SecureProtocol {
-		redirectBaseURL = fmt.Sprintf("https://%s%s%s", s.Config.AppDomain, port, s.Config.PathPrefix)
-	} else {
-		redirectBaseURL = fmt.Sprintf("http://%s%s%s", s.Config.AppDomain, port, s.Config.PathPrefix)
+		return fmt.Sprintf("https://%s%s", s.Config.AppDomain, port)
 	}
+	return fmt.Sprintf("http://%s%s", s.Config.AppDomain, port)
+}
+
+func (s *Service) registerOauthProviderEndpoints(router *http.ServeMux, prefix string, providers []thunderdome.AuthProviderConfig) {
+	ctx := context.Background()
+	redirectBaseURL := s.appOrigin() + s.Config.PathPrefix
 
 	for _, c := range providers {
 		providerNameUrlPath := strings.ToLower(c.ProviderName)
@@ -675,6 +789,10 @@ func (s *Service) registerOauthProviderEndpoints(router *http.ServeMux, prefix s
 			CallbackRedirectURL: callbackRedirectURL,
 			UIRedirectURL:       fmt.Sprintf("%s/", s.Config.PathPrefix),
 			InternalOnlyOidc:    s.Config.OIDCAuth.Enabled,
+			OnLogin: func(r *http.Request, user *thunderdome.User, sessionID string) {
+				s.recordSessionClient(r, sessionID)
+				s.recordLoginAuditEvent(r, user, c.ProviderName)
+			},
 		}, s.Cookie, s.Logger, s.AuthDataSvc, s.SubscriptionDataSvc, ctx)
 		if err != nil {
 			panic(err)
@@ -55,6 +55,20 @@ type jiraInstanceRequestBody struct {
 	ClientMail     string `json:"client_mail" validate:"required,email"`
 	AccessToken    string `json:"access_token" validate:"required"`
 	JiraDataCenter bool   `json:"jira_data_center"` // Checkbox for enabling Jira Data Center
+	// StoryPointsField is the Jira field ID finalized poker estimates are written back to, e.g. customfield_10016
+	StoryPointsField string `json:"story_points_field" validate:"omitempty,max=128"`
+	// AcceptanceCriteriaField is the Jira field ID acceptance criteria are imported from, e.g. customfield_10050
+	AcceptanceCriteriaField string `json:"acceptance_criteria_field" validate:"omitempty,max=128"`
+	// LinkTemplate builds story links from the {host} and issue {key} placeholders, defaults to {host}/browse/{key}
+	LinkTemplate string `json:"link_template" validate:"omitempty,max=512,contains={key}"`
+}
+
+func (b jiraInstanceRequestBody) fieldMapping() thunderdome.JiraFieldMapping {
+	return thunderdome.JiraFieldMapping{
+		StoryPointsField:        b.StoryPointsField,
+		AcceptanceCriteriaField: b.AcceptanceCriteriaField,
+		LinkTemplate:            b.LinkTemplate,
+	}
 }
 
 // handleJiraInstanceCreate creates a new Jira Instance
@@ -100,7 +114,7 @@ func (s *Service) handleJiraInstanceCreate() http.HandlerFunc {
 			return
 		}
 
-		instance, err := s.JiraDataSvc.CreateInstance(ctx, userID, req.Host, req.ClientMail, req.AccessToken, req.JiraDataCenter)
+		instance, err := s.JiraDataSvc.CreateInstance(ctx, userID, req.Host, req.ClientMail, req.AccessToken, req.JiraDataCenter, req.fieldMapping())
 		if err != nil {
 			s.Logger.Ctx(ctx).Error(
 				"handleJiraInstanceCreate error", zap.Error(err), zap.String("entity_user_id", userID),
@@ -165,7 +179,7 @@ func (s *Service) handleJiraInstanceUpdate() http.HandlerFunc {
 			return
 		}
 
-		instance, err := s.JiraDataSvc.UpdateInstance(ctx, instanceID, req.Host, req.ClientMail, req.AccessToken)
+		instance, err := s.JiraDataSvc.UpdateInstance(ctx, instanceID, req.Host, req.ClientMail, req.AccessToken, req.fieldMapping())
 		if err != nil {
 			s.Logger.Ctx(ctx).Error(
 				"handleJiraInstanceUpdate error", zap.Error(err), zap.String("entity_user_id", userID),
@@ -233,13 +247,13 @@ type jiraStoryJQLSearchRequestBody struct {
 // handleJiraStoryJQLSearch queries Jira API for Stories by JQL
 //
 //	@Summary		Query Jira for Stories by JQL
-//	@Description	Queries Jira Instance API for Stories by JQL
+//	@Description	Queries Jira Instance API for Stories by JQL, including the issues mapped to stories using the instance field mapping
 //	@Tags			jira
 //	@Produce		json
 //	@Param			userId		path	string							true	"the user ID associated to jira instance"
 //	@Param			instanceId	path	string							true	"the jira_instance ID to query"
 //	@Param			jira		body	jiraStoryJQLSearchRequestBody	true	"jql search request"
-//	@Success		200			object	standardJsonResponse{}
+//	@Success		200			object	standardJsonResponse{data=jiraStoryJQLSearchResponse}
 //	@Failure		500			object	standardJsonResponse{}
 //	@Security		ApiKeyAuth
 //	@Router			/users/{userId}/jira-instances/{instanceId}/jql-story-search [post]
@@ -281,38 +295,130 @@ func (s *Service) handleJiraStoryJQLSearch() http.HandlerFunc {
 			return
 		}
 
-		fields := []string{"key", "summary", "priority", "issuetype", "description"}
-
-		instance, err := s.JiraDataSvc.GetInstanceByID(ctx, instanceID)
 		errorTitle := "handleJiraStoryJQLSearch error"
-
-		s.logJiraSearchError(err, errorTitle, w, r, ctx, userID, instanceID, fields, req)
+		instance, err := s.JiraDataSvc.GetInstanceByID(ctx, instanceID)
+		if err != nil {
+			s.logJiraSearchError(err, errorTitle, w, r, ctx, userID, instanceID, jiraSearchFields, req)
+			return
+		}
+		fields := jiraStoryFields(instance.JiraFieldMapping)
 
 		// check here for DataCenter
 		if instance.JiraDataCenter {
-
 			jiraDataCenterClient, err := CreateNewJiraDataCenterInstance(instance)
-			s.logJiraSearchError(err, errorTitle, w, r, ctx, userID, instanceID, fields, req)
+			if err != nil {
+				s.logJiraSearchError(err, errorTitle, w, r, ctx, userID, instanceID, fields, req)
+				return
+			}
 
 			stories, err := jiraDataCenterClient.StoriesJQLSearch(ctx, req.JQL, fields, req.StartAt, req.MaxResults)
-
-			s.logErrorWithJSONResponse(err, errorTitle, w, ctx, userID, instanceID, fields, req)
-			s.Success(w, r, http.StatusOK, stories, nil)
-
+			if err != nil {
+				s.logErrorWithJSONResponse(err, errorTitle, w, ctx, userID, instanceID, fields, req)
+				return
+			}
+
+			s.Success(w, r, http.StatusOK, jiraStoryJQLSearchResponse{
+				Total:   stories.Total,
+				Issues:  stories.Issues,
+				Stories: jiraDataCenterStories(instance, stories),
+			}, nil)
 		} else {
-
 			jiraClient, err := CreateNewJiraInstance(instance)
-			s.logJiraSearchError(err, errorTitle, w, r, ctx, userID, instanceID, fields, req)
+			if err != nil {
+				s.logJiraSearchError(err, errorTitle, w, r, ctx, userID, instanceID, fields, req)
+				return
+			}
 
 			stories, err := jiraClient.StoriesJQLSearch(ctx, req.JQL, fields, req.StartAt, req.MaxResults)
+			if err != nil {
+				s.logErrorWithJSONResponse(err, errorTitle, w, ctx, userID, instanceID, fields, req)
+				return
+			}
+
+			s.Success(w, r, http.StatusOK, jiraStoryJQLSearchResponse{
+				Total:   stories.Total,
+				Issues:  stories.Issues,
+				Stories: jiraCloudStories(instance, stories),
+			}, nil)
+		}
+	}
+}
 
-			s.logErrorWithJSONResponse(err, errorTitle, w, ctx, userID, instanceID, fields, req)
-			s.Success(w, r, http.StatusOK, stories, nil)
+// handleJiraInstanceFields gets the issue fields available on a Jira instance
+//
+//	@Summary		Get Jira Instance Fields
+//	@Description	Gets the issue fields available on a Jira Instance for building its field mapping
+//	@Tags			jira
+//	@Produce		json
+//	@Param			userId		path	string	true	"the user ID associated to jira instance"
+//	@Param			instanceId	path	string	true	"the jira_instance ID to get fields for"
+//	@Success		200			object	standardJsonResponse{data=[]thunderdome.JiraField}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/jira-instances/{instanceId}/fields [get]
+func (s *Service) handleJiraInstanceFields() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		userID := r.PathValue("userId")
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
 		}
 
-	}
+		instanceID := r.PathValue("instanceId")
+		iidErr := validate.Var(instanceID, "required,uuid")
+		if iidErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, iidErr.Error()))
+			return
+		}
+
+		instance, err := s.JiraDataSvc.GetInstanceByID(ctx, instanceID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error(
+				"handleJiraInstanceFields error", zap.Error(err), zap.String("entity_user_id", userID),
+				zap.String("session_user_id", sessionUserID), zap.String("jira_instance_id", instanceID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		// the instance's stored credentials are only used on behalf of its owner
+		if instance.UserID != userID {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "JIRA_INSTANCE_NOT_FOUND"))
+			return
+		}
 
+		var fields []thunderdome.JiraField
+		if instance.JiraDataCenter {
+			client, clientErr := CreateNewJiraDataCenterInstance(instance)
+			if clientErr == nil {
+				fields, err = client.GetFields(ctx)
+			} else {
+				err = clientErr
+			}
+		} else {
+			client, clientErr := CreateNewJiraInstance(instance)
+			if clientErr == nil {
+				fields, err = client.GetFields(ctx)
+			} else {
+				err = clientErr
+			}
+		}
+		if err != nil {
+			s.Logger.Ctx(ctx).Error(
+				"handleJiraInstanceFields error", zap.Error(err), zap.String("entity_user_id", userID),
+				zap.String("session_user_id", sessionUserID), zap.String("jira_instance_id", instanceID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, fields, nil)
+	}
 }
+
 func CreateNewJiraDataCenterInstance(instance thunderdome.JiraInstance) (*jira_data_center.Client, error) {
 
 	jiraClient, err := jira_data_center.New(jira_data_center.Config{
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"html"
+	"strconv"
+	"strings"
+
+	jira "github.com/StevenWeathers/thunderdome-planning-poker/internal/atlassian/jira"
+	jira_data_center "github.com/StevenWeathers/thunderdome-planning-poker/internal/atlassian/jiraDataCenter"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// jiraSearchFields are the issue fields always requested when searching for stories
+var jiraSearchFields = []string{"key", "summary", "priority", "issuetype", "description"}
+
+type jiraStoryJQLSearchResponse struct {
+	Total  int `json:"total"`
+	Issues any `json:"issues"`
+	// Stories are the issues mapped to poker stories using the instance field mapping
+	Stories []*thunderdome.Story `json:"stories"`
+}
+
+// jiraStoryFields returns the fields to request in a story search including the mapped fields
+func jiraStoryFields(mapping thunderdome.JiraFieldMapping) []string {
+	fields := append([]string{}, jiraSearchFields...)
+	if mapping.AcceptanceCriteriaField != "" {
+		fields = append(fields, mapping.AcceptanceCriteriaField)
+	}
+	if mapping.StoryPointsField != "" {
+		fields = append(fields, mapping.StoryPointsField)
+	}
+
+	return fields
+}
+
+// jiraMappedStory maps a Jira issue to a poker story using the instance field mapping
+func jiraMappedStory(instance thunderdome.JiraInstance, key string, summary string, issueType string, values map[string]any) *thunderdome.Story {
+	story := &thunderdome.Story{
+		Name:        summary,
+		Type:        issueType,
+		ReferenceID: key,
+		Link:        jira.IssueLink(instance.Host, instance.LinkTemplate, key),
+		Votes:       make([]*thunderdome.Vote, 0),
+	}
+	if instance.AcceptanceCriteriaField != "" {
+		story.AcceptanceCriteria = jiraFieldHTML(values[instance.AcceptanceCriteriaField])
+	}
+	if instance.StoryPointsField != "" {
+		story.Points = jiraFieldText(values[instance.StoryPointsField])
+	}
+
+	return story
+}
+
+// jiraCloudStories maps the Jira Cloud search result issues to poker stories
+func jiraCloudStories(instance thunderdome.JiraInstance, result *jira.IssuesSearchResult) []*thunderdome.Story {
+	stories := make([]*thunderdome.Story, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		var summary, issueType string
+		if issue.Fields != nil {
+			summary = issue.Fields.Summary
+			if issue.Fields.IssueType != nil {
+				issueType = issue.Fields.IssueType.Name
+			}
+		}
+		stories = append(stories, jiraMappedStory(instance, issue.Key, summary, issueType, result.FieldValues[issue.Key]))
+	}
+
+	return stories
+}
+
+// jiraDataCenterStories maps the Jira Data Center search result issues to poker stories
+func jiraDataCenterStories(instance thunderdome.JiraInstance, result *jira_data_center.IssuesSearchResult) []*thunderdome.Story {
+	stories := make([]*thunderdome.Story, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		var summary, issueType string
+		if issue.Fields != nil {
+			summary = issue.Fields.Summary
+			issueType = issue.Fields.Type.Name
+		}
+		stories = append(stories, jiraMappedStory(instance, issue.Key, summary, issueType, result.FieldValues[issue.Key]))
+	}
+
+	return stories
+}
+
+// jiraFieldText converts a Jira field value to plain text, handling numbers, option and user
+// objects (value or name), lists, and Atlassian Document Format rich text
+func jiraFieldText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := jiraFieldText(item); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case map[string]any:
+		if v["type"] == "doc" {
+			return strings.Join(adfParagraphs(v), "\n")
+		}
+		if text, ok := v["value"].(string); ok {
+			return text
+		}
+		if text, ok := v["name"].(string); ok {
+			return text
+		}
+	}
+
+	return ""
+}
+
+// jiraFieldHTML converts a Jira field value to escaped HTML paragraphs
+func jiraFieldHTML(value any) string {
+	var paragraphs []string
+	if doc, ok := value.(map[string]any); ok && doc["type"] == "doc" {
+		paragraphs = adfParagraphs(doc)
+	} else {
+		paragraphs = strings.Split(jiraFieldText(value), "\n")
+	}
+
+	var sb strings.Builder
+	for _, paragraph := range paragraphs {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		sb.WriteString("<p>")
+		sb.WriteString(html.EscapeString(paragraph))
+		sb.WriteString("</p>")
+	}
+
+	return sb.String()
+}
+
+// adfParagraphs flattens an Atlassian Document Format node into the text of its block level nodes
+func adfParagraphs(node map[string]any) []string {
+	content, _ := node["content"].([]any)
+	var paragraphs []string
+	var inline strings.Builder
+
+	for _, c := range content {
+		child, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		switch child["type"] {
+		case "text":
+			text, _ := child["text"].(string)
+			inline.WriteString(text)
+		case "hardBreak":
+			inline.WriteString(" ")
+		default:
+			paragraphs = append(paragraphs, adfParagraphs(child)...)
+		}
+	}
+	if inline.Len() > 0 {
+		paragraphs = append([]string{inline.String()}, paragraphs...)
+	}
+
+	return paragraphs
+}
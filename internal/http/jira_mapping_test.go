@@ -0,0 +1,71 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func TestJiraFieldText(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "nil", value: nil, want: ""},
+		{name: "string", value: "Given a user", want: "Given a user"},
+		{name: "whole number", value: float64(8), want: "8"},
+		{name: "fraction", value: 0.5, want: "0.5"},
+		{name: "select option", value: map[string]any{"value": "High"}, want: "High"},
+		{name: "list", value: []any{"a", map[string]any{"name": "b"}}, want: "a, b"},
+		{
+			name: "atlassian document",
+			value: map[string]any{
+				"type": "doc",
+				"content": []any{
+					map[string]any{"type": "paragraph", "content": []any{
+						map[string]any{"type": "text", "text": "Given a user"},
+					}},
+					map[string]any{"type": "paragraph", "content": []any{
+						map[string]any{"type": "text", "text": "Then "},
+						map[string]any{"type": "text", "text": "it works"},
+					}},
+				},
+			},
+			want: "Given a user\nThen it works",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jiraFieldText(tt.value); got != tt.want {
+				t.Errorf("jiraFieldText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJiraMappedStory(t *testing.T) {
+	instance := thunderdome.JiraInstance{
+		Host: "https://example.atlassian.net/",
+		JiraFieldMapping: thunderdome.JiraFieldMapping{
+			StoryPointsField:        "customfield_10016",
+			AcceptanceCriteriaField: "customfield_10050",
+		},
+	}
+
+	story := jiraMappedStory(instance, "PROJ-1", "Login", "Story", map[string]any{
+		"customfield_10016": float64(5),
+		"customfield_10050": "Given <user>\nThen login",
+	})
+
+	if story.Link != "https://example.atlassian.net/browse/PROJ-1" {
+		t.Errorf("Link = %q", story.Link)
+	}
+	if story.Points != "5" {
+		t.Errorf("Points = %q, want 5", story.Points)
+	}
+	if want := "<p>Given &lt;user&gt;</p><p>Then login</p>"; story.AcceptanceCriteria != want {
+		t.Errorf("AcceptanceCriteria = %q, want %q", story.AcceptanceCriteria, want)
+	}
+}
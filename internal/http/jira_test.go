@@ -0,0 +1,47 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// fakeJiraDataSvc returns a stored instance owned by ownerID
+type fakeJiraDataSvc struct {
+	JiraDataSvc
+	ownerID string
+}
+
+func (f fakeJiraDataSvc) GetInstanceByID(ctx context.Context, instanceID string) (thunderdome.JiraInstance, error) {
+	return thunderdome.JiraInstance{ID: instanceID, UserID: f.ownerID, Host: "https://jira.invalid"}, nil
+}
+
+func TestHandleJiraInstanceFieldsForeignInstance(t *testing.T) {
+	const (
+		userID     = "6d2c1f8e-0a4b-4c1e-9d7f-2b3a4c5d6e7f"
+		ownerID    = "1f2e3d4c-5b6a-4978-8a9b-0c1d2e3f4a5b"
+		instanceID = "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"
+	)
+	s := &Service{
+		Config:      &Config{},
+		Logger:      otelzap.New(zap.NewNop()),
+		JiraDataSvc: fakeJiraDataSvc{ownerID: ownerID},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/"+userID+"/jira-instances/"+instanceID+"/fields", nil)
+	req.SetPathValue("userId", userID)
+	req.SetPathValue("instanceId", instanceID)
+	req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, userID))
+	w := httptest.NewRecorder()
+
+	s.handleJiraInstanceFields()(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusNotFound, w.Body.String())
+	}
+}
@@ -0,0 +1,683 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	webAuthnCeremonyRegistration = "registration"
+	webAuthnCeremonyLogin        = "login"
+)
+
+// newWebAuthn configures the WebAuthn relying party for the application domain and origin
+func newWebAuthn(config *Config, origin string) (*webauthn.WebAuthn, error) {
+	return webauthn.New(&webauthn.Config{
+		RPID:          config.AppDomain,
+		RPDisplayName: "Thunderdome",
+		RPOrigins:     []string{origin},
+	})
+}
+
+// webAuthnUser adapts a thunderdome user and their registered security keys to a webauthn.User
+type webAuthnUser struct {
+	id          uuid.UUID
+	name        string
+	displayName string
+	credentials []webauthn.Credential
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return u.id[:]
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.name
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.displayName
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+// newWebAuthnUser builds the webauthn.User of a thunderdome user from their stored security keys
+func newWebAuthnUser(user *thunderdome.User, credentials []*thunderdome.WebAuthnCredential) (*webAuthnUser, error) {
+	id, err := uuid.Parse(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	wu := &webAuthnUser{
+		id:          id,
+		name:        user.Email,
+		displayName: user.Name,
+		credentials: make([]webauthn.Credential, 0, len(credentials)),
+	}
+	if wu.name == "" {
+		wu.name = user.Name
+	}
+
+	for _, c := range credentials {
+		var credential webauthn.Credential
+		if err := json.Unmarshal(c.Credential, &credential); err != nil {
+			return nil, err
+		}
+		wu.credentials = append(wu.credentials, credential)
+	}
+
+	return wu, nil
+}
+
+// loadWebAuthnUser gets a user along with their security keys as a webauthn.User
+func (s *Service) loadWebAuthnUser(ctx context.Context, userID string) (*webAuthnUser, error) {
+	user, err := s.UserDataSvc.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := s.AuthDataSvc.WebAuthnCredentialList(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newWebAuthnUser(user, credentials)
+}
+
+// consumeWebAuthnSession gets and removes the pending challenge of a user's ceremony
+func (s *Service) consumeWebAuthnSession(ctx context.Context, userID string, ceremony string) (*webauthn.SessionData, error) {
+	sessionJSON, err := s.AuthDataSvc.WebAuthnChallengeConsume(ctx, userID, ceremony)
+	if err != nil {
+		return nil, err
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal(sessionJSON, &session); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// userMFAMethods lists the second factors the user can complete an MFA login with
+func (s *Service) userMFAMethods(ctx context.Context, userID string) []string {
+	methods := make([]string, 0)
+
+	status, err := s.AuthDataSvc.MFAStatus(ctx, userID)
+	if err != nil {
+		s.Logger.Ctx(ctx).Error("userMFAMethods error", zap.Error(err), zap.String("user_id", userID))
+		return append(methods, thunderdome.MFAMethodTOTP)
+	}
+
+	if status.TOTPEnabled {
+		methods = append(methods, thunderdome.MFAMethodTOTP)
+	}
+	if status.WebAuthnCredentials > 0 && s.webAuthn != nil {
+		methods = append(methods, thunderdome.MFAMethodWebAuthn)
+	}
+	if status.RecoveryCodesRemaining > 0 {
+		methods = append(methods, thunderdome.MFAMethodRecoveryCode)
+	}
+
+	return methods
+}
+
+// issueFirstFactorRecoveryCodes generates recovery codes for the user when the second factor they
+// just enabled is their first, based on the status from before it was enabled
+func (s *Service) issueFirstFactorRecoveryCodes(ctx context.Context, userID string, before *thunderdome.MFAStatus) []string {
+	if before.TOTPEnabled || before.WebAuthnCredentials > 0 {
+		return nil
+	}
+
+	codes, err := s.AuthDataSvc.MFARecoveryCodesGenerate(ctx, userID)
+	if err != nil {
+		s.Logger.Ctx(ctx).Error("issueFirstFactorRecoveryCodes error", zap.Error(err),
+			zap.String("session_user_id", userID))
+		return nil
+	}
+
+	return codes
+}
+
+// handleMFAStatus gets the second factors the user has configured
+//
+//	@Summary		Get MFA Status
+//	@Description	Gets the second factors configured for the session user and how many recovery codes are unused
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=thunderdome.MFAStatus}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Router			/auth/mfa [get]
+func (s *Service) handleMFAStatus() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		status, err := s.AuthDataSvc.MFAStatus(ctx, sessionUserID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleMFAStatus error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, status, nil)
+	}
+}
+
+// handleMFARecoveryCodesGenerate replaces the user's MFA recovery codes
+//
+//	@Summary		Regenerate MFA Recovery Codes
+//	@Description	Replaces the session user's MFA recovery codes with a new set, the codes are only shown once
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=[]string}
+//	@Failure		400	object	standardJsonResponse{}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Router			/auth/mfa/recovery-codes [post]
+func (s *Service) handleMFARecoveryCodesGenerate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		status, err := s.AuthDataSvc.MFAStatus(ctx, sessionUserID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleMFARecoveryCodesGenerate error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if !status.TOTPEnabled && status.WebAuthnCredentials == 0 {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "MFA_NOT_ENABLED"))
+			return
+		}
+
+		codes, err := s.AuthDataSvc.MFARecoveryCodesGenerate(ctx, sessionUserID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleMFARecoveryCodesGenerate error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.recordUserAuditEvent(r, thunderdome.AuditActionMFARecoveryCodesReset, sessionUserID, nil)
+
+		s.Success(w, r, http.StatusOK, codes, nil)
+	}
+}
+
+// handleWebAuthnRegisterBegin starts registering a security key for the user
+//
+//	@Summary		Begin Security Key Registration
+//	@Description	Starts registering a WebAuthn security key or passkey as a second factor for the session user,
+//	@Description	returns the options to pass to navigator.credentials.create
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Router			/auth/mfa/webauthn/register/begin [post]
+func (s *Service) handleWebAuthnRegisterBegin() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		if s.webAuthn == nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "WEBAUTHN_UNAVAILABLE"))
+			return
+		}
+
+		wu, err := s.loadWebAuthnUser(ctx, sessionUserID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnRegisterBegin error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		creation, session, err := s.webAuthn.BeginRegistration(wu,
+			webauthn.WithExclusions(webauthn.Credentials(wu.credentials).CredentialDescriptors()),
+		)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnRegisterBegin error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		sessionJSON, _ := json.Marshal(session)
+		err = s.AuthDataSvc.WebAuthnChallengeSave(ctx, sessionUserID, webAuthnCeremonyRegistration, sessionJSON)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnRegisterBegin error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, creation, nil)
+	}
+}
+
+type webAuthnRegisterFinishRequestBody struct {
+	Name       string          `json:"name" validate:"required,max=64"`
+	Credential json.RawMessage `json:"credential" validate:"required" swaggertype:"object"`
+}
+
+// handleWebAuthnRegisterFinish completes registering a security key for the user
+//
+//	@Summary		Finish Security Key Registration
+//	@Description	Verifies the response of navigator.credentials.create and stores the security key,
+//	@Description	recovery codes are returned when it is the user's first second factor
+//	@Tags			auth
+//	@Produce		json
+//	@Param			credential	body	webAuthnRegisterFinishRequestBody	true	"the named attestation response"
+//	@Success		200			object	standardJsonResponse{}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Router			/auth/mfa/webauthn/register/finish [post]
+func (s *Service) handleWebAuthnRegisterFinish() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		if s.webAuthn == nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "WEBAUTHN_UNAVAILABLE"))
+			return
+		}
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var v = webAuthnRegisterFinishRequestBody{}
+		jsonErr := json.Unmarshal(body, &v)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(v)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		session, err := s.consumeWebAuthnSession(ctx, sessionUserID, webAuthnCeremonyRegistration)
+		if err != nil && err.Error() == "WEBAUTHN_CHALLENGE_NOT_FOUND" {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "WEBAUTHN_CHALLENGE_NOT_FOUND"))
+			return
+		} else if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnRegisterFinish error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		wu, err := s.loadWebAuthnUser(ctx, sessionUserID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnRegisterFinish error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		credential, err := s.finishWebAuthnRegistration(wu, session, v.Credential)
+		if err != nil {
+			s.Logger.Ctx(ctx).Warn("handleWebAuthnRegisterFinish invalid credential", zap.Error(err),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "INVALID_WEBAUTHN_CREDENTIAL"))
+			return
+		}
+
+		status, err := s.AuthDataSvc.MFAStatus(ctx, sessionUserID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnRegisterFinish error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		credentialJSON, _ := json.Marshal(credential)
+		stored, err := s.AuthDataSvc.WebAuthnCredentialCreate(ctx, sessionUserID, v.Name, credential.ID, credentialJSON)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnRegisterFinish error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.recordUserAuditEvent(r, thunderdome.AuditActionMFAEnable, sessionUserID, map[string]string{
+			"method":       thunderdome.MFAMethodWebAuthn,
+			"credentialId": stored.ID,
+		})
+
+		type result struct {
+			Credential    *thunderdome.WebAuthnCredential `json:"credential"`
+			RecoveryCodes []string                        `json:"recoveryCodes,omitempty"`
+		}
+
+		s.Success(w, r, http.StatusOK, result{
+			Credential:    stored,
+			RecoveryCodes: s.issueFirstFactorRecoveryCodes(ctx, sessionUserID, status),
+		}, nil)
+	}
+}
+
+// finishWebAuthnRegistration verifies an attestation response against the pending registration challenge
+func (s *Service) finishWebAuthnRegistration(wu *webAuthnUser, session *webauthn.SessionData, response []byte) (*webauthn.Credential, error) {
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.webAuthn.CreateCredential(wu, *session, parsed)
+}
+
+// handleWebAuthnCredentialList gets the security keys registered by the user
+//
+//	@Summary		Get Security Keys
+//	@Description	Gets the WebAuthn security keys registered by the session user
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	object	standardJsonResponse{data=[]thunderdome.WebAuthnCredential}
+//	@Failure		500	object	standardJsonResponse{}
+//	@Router			/auth/mfa/webauthn/credentials [get]
+func (s *Service) handleWebAuthnCredentialList() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		credentials, err := s.AuthDataSvc.WebAuthnCredentialList(ctx, sessionUserID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnCredentialList error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, credentials, nil)
+	}
+}
+
+// handleWebAuthnCredentialDelete removes a security key from the user
+//
+//	@Summary		Delete Security Key
+//	@Description	Removes a WebAuthn security key of the session user, MFA is disabled when no second factor remains
+//	@Tags			auth
+//	@Produce		json
+//	@Param			credentialId	path	string	true	"the security key ID"
+//	@Success		200				object	standardJsonResponse{}
+//	@Failure		404				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Router			/auth/mfa/webauthn/credentials/{credentialId} [delete]
+func (s *Service) handleWebAuthnCredentialDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		credentialID := r.PathValue("credentialId")
+		idErr := validate.Var(credentialID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		err := s.AuthDataSvc.WebAuthnCredentialDelete(ctx, sessionUserID, credentialID)
+		if err != nil && err.Error() == "WEBAUTHN_CREDENTIAL_NOT_FOUND" {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "WEBAUTHN_CREDENTIAL_NOT_FOUND"))
+			return
+		} else if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnCredentialDelete error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID), zap.String("credential_id", credentialID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.recordUserAuditEvent(r, thunderdome.AuditActionMFARemove, sessionUserID, map[string]string{
+			"method":       thunderdome.MFAMethodWebAuthn,
+			"credentialId": credentialID,
+		})
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+type webAuthnLoginBeginRequestBody struct {
+	SessionID string `json:"sessionId" validate:"required"`
+}
+
+// handleWebAuthnLoginBegin starts the security key step of an MFA login
+//
+//	@Summary		Begin Security Key Login
+//	@Description	Starts completing an MFA login with a security key, returns the options to pass to navigator.credentials.get
+//	@Tags			auth
+//	@Produce		json
+//	@Param			session	body	webAuthnLoginBeginRequestBody	true	"the pending login session"
+//	@Success		200		object	standardJsonResponse{}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		401		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Failure		429		object	standardJsonResponse{}
+//	@Router			/auth/mfa/webauthn/login/begin [post]
+func (s *Service) handleWebAuthnLoginBegin() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if s.webAuthn == nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "WEBAUTHN_UNAVAILABLE"))
+			return
+		}
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var v = webAuthnLoginBeginRequestBody{}
+		jsonErr := json.Unmarshal(body, &v)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(v)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		userID, err := s.AuthDataSvc.MFASessionUserID(ctx, v.SessionID)
+		if err != nil && err.Error() == "SESSION_NOT_FOUND" {
+			s.Failure(w, r, http.StatusUnauthorized, Errorf(EUNAUTHORIZED, "INVALID_SESSION"))
+			return
+		} else if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnLoginBegin error", zap.Error(err))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		wu, err := s.loadWebAuthnUser(ctx, userID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnLoginBegin error", zap.Error(err), zap.String("user_id", userID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if len(wu.credentials) == 0 {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "WEBAUTHN_NOT_ENABLED"))
+			return
+		}
+
+		assertion, session, err := s.webAuthn.BeginLogin(wu)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnLoginBegin error", zap.Error(err), zap.String("user_id", userID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		sessionJSON, _ := json.Marshal(session)
+		err = s.AuthDataSvc.WebAuthnChallengeSave(ctx, userID, webAuthnCeremonyLogin, sessionJSON)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnLoginBegin error", zap.Error(err), zap.String("user_id", userID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, assertion, nil)
+	}
+}
+
+type webAuthnLoginFinishRequestBody struct {
+	SessionID  string          `json:"sessionId" validate:"required"`
+	Credential json.RawMessage `json:"credential" validate:"required" swaggertype:"object"`
+}
+
+// handleWebAuthnLoginFinish completes an MFA login with a security key
+//
+//	@Summary		Finish Security Key Login
+//	@Description	Verifies the response of navigator.credentials.get and logs the user in
+//	@Tags			auth
+//	@Produce		json
+//	@Param			credential	body	webAuthnLoginFinishRequestBody	true	"the pending login session and assertion response"
+//	@Success		200			object	standardJsonResponse{}
+//	@Failure		401			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Failure		429			object	standardJsonResponse{}
+//	@Router			/auth/mfa/webauthn/login/finish [post]
+func (s *Service) handleWebAuthnLoginFinish() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if s.webAuthn == nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "WEBAUTHN_UNAVAILABLE"))
+			return
+		}
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var v = webAuthnLoginFinishRequestBody{}
+		jsonErr := json.Unmarshal(body, &v)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(v)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		userID, err := s.AuthDataSvc.MFASessionUserID(ctx, v.SessionID)
+		if err != nil && err.Error() == "SESSION_NOT_FOUND" {
+			s.Failure(w, r, http.StatusUnauthorized, Errorf(EUNAUTHORIZED, "INVALID_SESSION"))
+			return
+		} else if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnLoginFinish error", zap.Error(err))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if s.accountRateLimitExceeded(w, r, "mfa", userID) {
+			return
+		}
+
+		loginFailed := func(reason string) {
+			s.recordAuditEvent(r, thunderdome.AuditEvent{
+				ActorID:    &userID,
+				Action:     thunderdome.AuditActionLoginFailed,
+				TargetType: thunderdome.AuditTargetTypeUser,
+				TargetID:   userID,
+				Metadata:   map[string]string{"method": thunderdome.MFAMethodWebAuthn, "reason": reason},
+			})
+			s.recordFailedSecondFactor(r, userID)
+			s.Failure(w, r, http.StatusUnauthorized, Errorf(EINVALID, reason))
+		}
+
+		session, err := s.consumeWebAuthnSession(ctx, userID, webAuthnCeremonyLogin)
+		if err != nil && err.Error() == "WEBAUTHN_CHALLENGE_NOT_FOUND" {
+			loginFailed("WEBAUTHN_CHALLENGE_NOT_FOUND")
+			return
+		} else if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnLoginFinish error", zap.Error(err), zap.String("user_id", userID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		wu, err := s.loadWebAuthnUser(ctx, userID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnLoginFinish error", zap.Error(err), zap.String("user_id", userID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		credential, err := s.finishWebAuthnLogin(wu, session, v.Credential)
+		if err != nil {
+			s.Logger.Ctx(ctx).Warn("handleWebAuthnLoginFinish invalid assertion", zap.Error(err),
+				zap.String("user_id", userID))
+			loginFailed("INVALID_WEBAUTHN_CREDENTIAL")
+			return
+		}
+
+		credentialJSON, _ := json.Marshal(credential)
+		if err := s.AuthDataSvc.WebAuthnCredentialUse(ctx, userID, credential.ID, credentialJSON); err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnLoginFinish error", zap.Error(err), zap.String("user_id", userID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		if err := s.AuthDataSvc.EnableSession(ctx, v.SessionID); err != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnLoginFinish error", zap.Error(err), zap.String("user_id", userID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		cookieErr := s.createSessionCookie(w, r, v.SessionID)
+		if cookieErr != nil {
+			s.Logger.Ctx(ctx).Error("handleWebAuthnLoginFinish error", zap.Error(cookieErr),
+				zap.String("user_id", userID))
+			s.Failure(w, r, http.StatusInternalServerError, Errorf(EINVALID, "INVALID_COOKIE"))
+			return
+		}
+
+		if sessionUser, sessionErr := s.AuthDataSvc.GetSessionUserByID(ctx, v.SessionID); sessionErr == nil {
+			s.recordLoginAuditEvent(r, sessionUser, "password+webauthn")
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+// finishWebAuthnLogin verifies an assertion response against the pending login challenge,
+// rejecting authenticators whose signature counter indicates they may have been cloned
+func (s *Service) finishWebAuthnLogin(wu *webAuthnUser, session *webauthn.SessionData, response []byte) (*webauthn.Credential, error) {
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := s.webAuthn.ValidateLogin(wu, *session, parsed)
+	if err != nil {
+		return nil, err
+	}
+	if credential.Authenticator.CloneWarning {
+		return nil, errors.New("authenticator sign count indicates a cloned authenticator")
+	}
+
+	return credential, nil
+}
@@ -0,0 +1,182 @@
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+// softwareAuthenticator is a minimal ES256 WebAuthn authenticator using "none" attestation
+type softwareAuthenticator struct {
+	t            *testing.T
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	signCount    uint32
+}
+
+func newSoftwareAuthenticator(t *testing.T) *softwareAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating authenticator key: %v", err)
+	}
+	credentialID := make([]byte, 16)
+	_, _ = rand.Read(credentialID)
+
+	return &softwareAuthenticator{t: t, key: key, credentialID: credentialID}
+}
+
+func (a *softwareAuthenticator) clientData(ceremony string, challenge string, origin string) []byte {
+	clientData, _ := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": challenge,
+		"origin":    origin,
+	})
+	return clientData
+}
+
+func (a *softwareAuthenticator) authData(rpID string, flags byte, attested []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	return append(data, attested...)
+}
+
+// create answers navigator.credentials.create for the challenge
+func (a *softwareAuthenticator) create(rpID string, challenge string, origin string) []byte {
+	ecdh, err := a.key.PublicKey.ECDH()
+	if err != nil {
+		a.t.Fatalf("error getting authenticator public key: %v", err)
+	}
+	point := ecdh.Bytes()
+	publicKey, err := webauthncbor.Marshal(map[int]any{1: 2, 3: -7, -1: 1, -2: point[1:33], -3: point[33:]})
+	if err != nil {
+		a.t.Fatalf("error encoding authenticator public key: %v", err)
+	}
+
+	attested := make([]byte, 16) // zero AAGUID
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.credentialID)))
+	attested = append(attested, a.credentialID...)
+	attested = append(attested, publicKey...)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(rpID, 0x45, attested),
+	})
+	if err != nil {
+		a.t.Fatalf("error encoding attestation object: %v", err)
+	}
+
+	response, _ := json.Marshal(map[string]any{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(a.clientData("webauthn.create", challenge, origin)),
+			"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+		},
+	})
+	return response
+}
+
+// get answers navigator.credentials.get for the challenge
+func (a *softwareAuthenticator) get(rpID string, challenge string, origin string) []byte {
+	a.signCount++
+	authData := a.authData(rpID, 0x05, nil)
+	clientData := a.clientData("webauthn.get", challenge, origin)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		a.t.Fatalf("error signing assertion: %v", err)
+	}
+
+	response, _ := json.Marshal(map[string]any{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+			"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+			"signature":         base64.RawURLEncoding.EncodeToString(signature),
+		},
+	})
+	return response
+}
+
+func TestWebAuthnCeremonies(t *testing.T) {
+	s := &Service{Config: &Config{AppDomain: "localhost", Port: "8080"}}
+	origin := s.appOrigin()
+	if origin != "http://localhost:8080" {
+		t.Fatalf("appOrigin() = %q, want http://localhost:8080", origin)
+	}
+	wa, err := newWebAuthn(s.Config, origin)
+	if err != nil {
+		t.Fatalf("newWebAuthn() error = %v", err)
+	}
+	s.webAuthn = wa
+
+	user := &thunderdome.User{ID: "2ad6e4c4-cb4c-4d36-9d2a-9c2ce4f7c0f5", Name: "Test User", Email: "test@thunderdome.dev"}
+	wu, err := newWebAuthnUser(user, nil)
+	if err != nil {
+		t.Fatalf("newWebAuthnUser() error = %v", err)
+	}
+	authenticator := newSoftwareAuthenticator(t)
+
+	_, registration, err := s.webAuthn.BeginRegistration(wu)
+	if err != nil {
+		t.Fatalf("BeginRegistration() error = %v", err)
+	}
+	credential, err := s.finishWebAuthnRegistration(wu, registration,
+		authenticator.create("localhost", registration.Challenge, origin))
+	if err != nil {
+		t.Fatalf("finishWebAuthnRegistration() error = %v", err)
+	}
+
+	// round trip the credential the way it is stored
+	credentialJSON, _ := json.Marshal(credential)
+	wu, err = newWebAuthnUser(user, []*thunderdome.WebAuthnCredential{{Credential: credentialJSON}})
+	if err != nil {
+		t.Fatalf("newWebAuthnUser() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		origin  string
+		wantErr bool
+	}{
+		{name: "valid assertion", origin: origin},
+		{name: "second assertion", origin: origin},
+		{name: "wrong origin", origin: "https://evil.example", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, login, err := s.webAuthn.BeginLogin(wu)
+			if err != nil {
+				t.Fatalf("BeginLogin() error = %v", err)
+			}
+
+			got, err := s.finishWebAuthnLogin(wu, login, authenticator.get("localhost", login.Challenge, tt.origin))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("finishWebAuthnLogin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Authenticator.SignCount != authenticator.signCount {
+				t.Errorf("finishWebAuthnLogin() sign count = %d, want %d", got.Authenticator.SignCount, authenticator.signCount)
+			}
+			wu.credentials = []webauthn.Credential{*got}
+		})
+	}
+}
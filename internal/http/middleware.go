@@ -76,12 +76,28 @@ func (s *Service) userOnly(h http.HandlerFunc) http.HandlerFunc {
 		var user *thunderdome.User
 
 		if apiKey != "" && s.Config.ExternalAPIEnabled {
+			var key *thunderdome.APIKey
 			var apiKeyErr error
-			user, apiKeyErr = s.ApiKeyDataSvc.GetAPIKeyUser(ctx, apiKey)
-			if apiKeyErr != nil {
+			user, key, apiKeyErr = s.ApiKeyDataSvc.GetAPIKeyUser(ctx, apiKey)
+			if apiKeyErr != nil && apiKeyErr.Error() == "APIKEY_EXPIRED" {
+				s.Failure(w, r, http.StatusUnauthorized, Errorf(EINVALID, "APIKEY_EXPIRED"))
+				return
+			} else if apiKeyErr != nil {
 				s.Failure(w, r, http.StatusUnauthorized, Errorf(EINVALID, "INVALID_APIKEY"))
 				return
 			}
+
+			if code := s.apiKeyAllowed(r, key, user.ID); code != "" {
+				s.Logger.Ctx(ctx).Warn("middleware userOnly "+code,
+					zap.String("apikey_prefix", key.Prefix),
+					zap.String("session_user_id", user.ID))
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, code))
+				return
+			}
+
+			if s.apiKeyRateLimitExceeded(w, r, key) {
+				return
+			}
 		} else {
 			sessionID, cookieErr := s.Cookie.ValidateSessionCookie(w, r)
 			if cookieErr != nil && cookieErr.Error() != "COOKIE_NOT_FOUND" {
@@ -96,6 +112,11 @@ func (s *Service) userOnly(h http.HandlerFunc) http.HandlerFunc {
 					s.Failure(w, r, http.StatusUnauthorized, Errorf(EINVALID, "INVALID_USER"))
 					return
 				}
+
+				if touchErr := s.AuthDataSvc.TouchSession(ctx, sessionID, s.clientIP(r), r.UserAgent()); touchErr != nil {
+					s.Logger.Ctx(ctx).Warn("middleware userOnly error updating session last seen",
+						zap.Error(touchErr), zap.String("session_user_id", user.ID))
+				}
 			} else {
 				userID, err := s.Cookie.ValidateUserCookie(w, r)
 				if err != nil {
@@ -11,6 +11,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/require"
@@ -40,8 +41,9 @@ func (m *MockTeamDataSvc) TeamUserRoleByUserID(ctx context.Context, UserID strin
 }
 
 func (m *MockTeamDataSvc) TeamGetByID(ctx context.Context, TeamID string) (*thunderdome.Team, error) {
-	//TODO implement me
-	panic("implement me")
+	args := m.Called(ctx, TeamID)
+	team, _ := args.Get(0).(*thunderdome.Team)
+	return team, args.Error(1)
 }
 
 func (m *MockTeamDataSvc) TeamListByUser(ctx context.Context, UserID string, Limit int, Offset int) []*thunderdome.UserTeam {
@@ -170,8 +172,19 @@ func (m *MockTeamDataSvc) TeamIsSubscribed(ctx context.Context, teamID string) (
 }
 
 func (m *MockTeamDataSvc) GetTeamMetrics(ctx context.Context, teamID string) (*thunderdome.TeamMetrics, error) {
-	//TODO implement me
-	panic("implement me")
+	args := m.Called(ctx, teamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.TeamMetrics), args.Error(1)
+}
+
+func (m *MockTeamDataSvc) GetTeamEstimationMetrics(ctx context.Context, teamID string, from time.Time, to time.Time, period string) (*thunderdome.TeamEstimationMetrics, error) {
+	args := m.Called(ctx, teamID, from, to, period)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*thunderdome.TeamEstimationMetrics), args.Error(1)
 }
 
 func (m *MockTeamDataSvc) TeamUserRolesByUserID(ctx context.Context, userID, teamID string) (*thunderdome.UserTeamRoleInfo, error) {
@@ -755,8 +768,8 @@ func (m *MockOrganizationDataService) OrganizationUpdateUser(ctx context.Context
 }
 
 func (m *MockOrganizationDataService) OrganizationRemoveUser(ctx context.Context, OrganizationID string, UserID string) error {
-	//TODO implement me
-	panic("implement me")
+	args := m.Called(ctx, OrganizationID, UserID)
+	return args.Error(0)
 }
 
 func (m *MockOrganizationDataService) OrganizationInviteUser(ctx context.Context, OrgID string, Email string, Role string) (string, error) {
@@ -914,6 +927,34 @@ func (m *MockOrganizationDataService) OrganizationIsSubscribed(ctx context.Conte
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockOrganizationDataService) OrganizationServiceAccountList(ctx context.Context, orgID string) ([]*thunderdome.OrganizationServiceAccount, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockOrganizationDataService) OrganizationServiceAccountGet(ctx context.Context, orgID string, accountID string) (*thunderdome.OrganizationServiceAccount, error) {
+	args := m.Called(ctx, orgID, accountID)
+	account, _ := args.Get(0).(*thunderdome.OrganizationServiceAccount)
+	return account, args.Error(1)
+}
+
+func (m *MockOrganizationDataService) OrganizationServiceAccountCreate(ctx context.Context, orgID string, createdBy string, name string, description string) (*thunderdome.OrganizationServiceAccount, error) {
+	args := m.Called(ctx, orgID, createdBy, name, description)
+	account, _ := args.Get(0).(*thunderdome.OrganizationServiceAccount)
+	return account, args.Error(1)
+}
+
+func (m *MockOrganizationDataService) OrganizationServiceAccountUpdate(ctx context.Context, orgID string, accountID string, name string, description string) (*thunderdome.OrganizationServiceAccount, error) {
+	args := m.Called(ctx, orgID, accountID, name, description)
+	account, _ := args.Get(0).(*thunderdome.OrganizationServiceAccount)
+	return account, args.Error(1)
+}
+
+func (m *MockOrganizationDataService) OrganizationServiceAccountDelete(ctx context.Context, orgID string, accountID string) error {
+	args := m.Called(ctx, orgID, accountID)
+	return args.Error(0)
+}
+
 func TestSubscribedOrgOnly(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -1936,6 +1977,73 @@ func (m *MockProjectDataSvc) RemoveRetro(ctx context.Context, projectID string,
 func (m *MockProjectDataSvc) RemoveStoryboard(ctx context.Context, projectID string, storyboardID string) error {
 	panic("implement me")
 }
+func (m *MockProjectDataSvc) GetItemTypes(ctx context.Context, scope thunderdome.ItemScope) ([]*thunderdome.ItemType, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) GetProjectItemTypes(ctx context.Context, projectID string) ([]*thunderdome.ItemType, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) CreateItemType(ctx context.Context, itemType *thunderdome.ItemType) (*thunderdome.ItemType, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) UpdateItemType(ctx context.Context, itemType *thunderdome.ItemType) (*thunderdome.ItemType, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) DeleteItemType(ctx context.Context, scope thunderdome.ItemScope, typeID string) error {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) GetItemStatuses(ctx context.Context, scope thunderdome.ItemScope) ([]*thunderdome.ItemStatus, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) GetProjectItemStatuses(ctx context.Context, projectID string) ([]*thunderdome.ItemStatus, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) CreateItemStatus(ctx context.Context, status *thunderdome.ItemStatus) (*thunderdome.ItemStatus, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) UpdateItemStatus(ctx context.Context, status *thunderdome.ItemStatus) (*thunderdome.ItemStatus, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) DeleteItemStatus(ctx context.Context, scope thunderdome.ItemScope, statusID string) error {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) GetItemPriorities(ctx context.Context, scope thunderdome.ItemScope) ([]*thunderdome.ItemPriority, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) GetProjectItemPriorities(ctx context.Context, projectID string) ([]*thunderdome.ItemPriority, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) CreateItemPriority(ctx context.Context, priority *thunderdome.ItemPriority) (*thunderdome.ItemPriority, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) UpdateItemPriority(ctx context.Context, priority *thunderdome.ItemPriority) (*thunderdome.ItemPriority, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) DeleteItemPriority(ctx context.Context, scope thunderdome.ItemScope, priorityID string) error {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) GetProjectItems(ctx context.Context, projectID string, filter thunderdome.ProjectItemFilter, limit int, offset int) ([]*thunderdome.ProjectItem, int, error) {
+	args := m.Called(ctx, projectID, filter, limit, offset)
+	return args.Get(0).([]*thunderdome.ProjectItem), args.Int(1), args.Error(2)
+}
+func (m *MockProjectDataSvc) GetProjectItemByID(ctx context.Context, projectID string, itemID string) (*thunderdome.ProjectItem, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) CreateProjectItem(ctx context.Context, item *thunderdome.ProjectItem) (*thunderdome.ProjectItem, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) UpdateProjectItem(ctx context.Context, item *thunderdome.ProjectItem) (*thunderdome.ProjectItem, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) TransitionProjectItem(ctx context.Context, projectID string, itemID string, statusID string) (*thunderdome.ProjectItem, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) MoveProjectItem(ctx context.Context, projectID string, itemID string, placeBefore string) (*thunderdome.ProjectItem, error) {
+	panic("implement me")
+}
+func (m *MockProjectDataSvc) DeleteProjectItem(ctx context.Context, projectID string, itemID string) error {
+	panic("implement me")
+}
 
 func TestProjectUserOnly(t *testing.T) {
 	tests := []struct {
@@ -2221,6 +2329,12 @@ func (m *MockUserDataService) CreateUserGuest(ctx context.Context, UserName stri
 	panic("implement me")
 }
 
+func (m *MockUserDataService) ProvisionUser(ctx context.Context, UserName string, UserEmail string) (*thunderdome.User, bool, error) {
+	args := m.Called(ctx, UserName, UserEmail)
+	user, _ := args.Get(0).(*thunderdome.User)
+	return user, args.Bool(1), args.Error(2)
+}
+
 func (m *MockUserDataService) CreateUserRegistered(ctx context.Context, UserName string, UserEmail string, UserPassword string, ActiveUserID string) (NewUser *thunderdome.User, VerifyID string, RegisterErr error) {
 	//TODO implement me
 	panic("implement me")
@@ -2252,13 +2366,13 @@ func (m *MockUserDataService) DemoteUser(ctx context.Context, UserID string) err
 }
 
 func (m *MockUserDataService) DisableUser(ctx context.Context, UserID string) error {
-	//TODO implement me
-	panic("implement me")
+	args := m.Called(ctx, UserID)
+	return args.Error(0)
 }
 
 func (m *MockUserDataService) EnableUser(ctx context.Context, UserID string) error {
-	//TODO implement me
-	panic("implement me")
+	args := m.Called(ctx, UserID)
+	return args.Error(0)
 }
 
 func (m *MockUserDataService) DeleteUser(ctx context.Context, UserID string) error {
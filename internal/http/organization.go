@@ -402,6 +402,7 @@ func (s *Service) handleOrganizationInviteUser() http.HandlerFunc {
 					s.Failure(w, r, http.StatusInternalServerError, err)
 					return
 				}
+				s.recordUserAuditEvent(r, thunderdome.AuditActionOrganizationUserAdd, user.ID, map[string]string{"role": u.Role})
 				s.Success(w, r, http.StatusOK, nil, userAddMeta{Invited: false, Added: true})
 				return
 			} else if userErr != nil && !errors.Is(userErr, sql.ErrNoRows) {
@@ -420,6 +421,13 @@ func (s *Service) handleOrganizationInviteUser() http.HandlerFunc {
 			s.Failure(w, r, http.StatusInternalServerError, inviteErr)
 			return
 		}
+
+		s.recordAuditEvent(r, thunderdome.AuditEvent{
+			Action:     thunderdome.AuditActionOrganizationUserInvite,
+			TargetType: thunderdome.AuditTargetTypeInvite,
+			TargetID:   inviteID,
+			Metadata:   map[string]string{"email": userEmail, "role": u.Role},
+		})
 		org, orgErr := s.OrganizationDataSvc.OrganizationGetByID(ctx, orgID)
 		if orgErr != nil {
 			s.Logger.Ctx(ctx).Error("handleOrganizationInviteUser error", zap.Error(orgErr),
@@ -499,6 +507,8 @@ func (s *Service) handleOrganizationUpdateUser() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionOrganizationUserUpdate, userID, map[string]string{"role": u.Role})
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
@@ -506,12 +516,13 @@ func (s *Service) handleOrganizationUpdateUser() http.HandlerFunc {
 // handleOrganizationRemoveUser handles removing user from an organization (including departments, teams)
 //
 //	@Summary		Remove Org User
-//	@Description	Remove user from organization including departments and teams
+//	@Description	Remove user from organization including departments and teams, service accounts are removed by deleting them
 //	@Tags			organization
 //	@Produce		json
 //	@Param			orgId	path	string	true	"organization id"
 //	@Param			userId	path	string	true	"user id"
 //	@Success		200		object	standardJsonResponse{}
+//	@Failure		400		object	standardJsonResponse{}
 //	@Failure		403		object	standardJsonResponse{}
 //	@Failure		500		object	standardJsonResponse{}
 //	@Security		ApiKeyAuth
@@ -539,7 +550,10 @@ func (s *Service) handleOrganizationRemoveUser() http.HandlerFunc {
 		}
 
 		err := s.OrganizationDataSvc.OrganizationRemoveUser(ctx, orgID, userID)
-		if err != nil {
+		if err != nil && err.Error() == "SERVICE_ACCOUNT_MEMBER" {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "SERVICE_ACCOUNT_MEMBER"))
+			return
+		} else if err != nil {
 			s.Logger.Ctx(ctx).Error(
 				"handleOrganizationRemoveUser error", zap.Error(err), zap.String("user_id", userID),
 				zap.String("session_user_id", sessionUserID), zap.String("organization_id", orgID))
@@ -547,6 +561,8 @@ func (s *Service) handleOrganizationRemoveUser() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionOrganizationUserRemove, userID, nil)
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
@@ -698,6 +714,8 @@ func (s *Service) handleOrganizationTeamAddUser() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionTeamUserAdd, u.UserID, map[string]string{"teamId": teamID, "role": u.Role})
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
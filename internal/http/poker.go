@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -63,12 +64,53 @@ type battleRequestBody struct {
 	Stories              []*thunderdome.Story `json:"plans"`
 	PointAverageRounding string               `json:"pointAverageRounding" validate:"required,oneof=ceil round floor"`
 	HideVoterIdentity    bool                 `json:"hideVoterIdentity"`
+	VotingTimeLimitSec   *int                 `json:"votingTimeLimitSec" validate:"omitempty,gte=0,lte=3600" example:"60"`
 	Facilitators         []string             `json:"battleLeaders"`
 	JoinCode             string               `json:"joinCode"`
 	FacilitatorCode      string               `json:"leaderCode"`
 	ProjectIds           []string             `json:"projectIds"`
 }
 
+// clearStoryProjectItems drops any project item references sent with the stories,
+// only games created from a project's items may link stories back to those items
+func clearStoryProjectItems(stories []*thunderdome.Story) {
+	for _, story := range stories {
+		if story != nil {
+			story.ProjectItemID = nil
+		}
+	}
+}
+
+// pokerVotingTimeLimitSec returns the requested voting time limit, when none was requested
+// the default from the team, department, or organization poker settings is used
+func (s *Service) pokerVotingTimeLimitSec(ctx context.Context, r *http.Request, requested *int, teamID string) int {
+	if requested != nil {
+		return *requested
+	}
+
+	var settings *thunderdome.PokerSettings
+	var err error
+	if teamID != "" {
+		settings, err = s.PokerDataSvc.GetSettingsByTeam(ctx, teamID)
+	}
+	if deptID := r.PathValue("departmentId"); err == nil && settings == nil && deptID != "" {
+		settings, err = s.PokerDataSvc.GetSettingsByDepartment(ctx, deptID)
+	}
+	if orgID := r.PathValue("orgId"); err == nil && settings == nil && orgID != "" {
+		settings, err = s.PokerDataSvc.GetSettingsByOrganization(ctx, orgID)
+	}
+	if err != nil {
+		s.Logger.Ctx(ctx).Error("pokerVotingTimeLimitSec get poker settings error", zap.Error(err),
+			zap.String("team_id", teamID))
+		return 0
+	}
+	if settings == nil || settings.VotingTimeLimitSec == nil {
+		return 0
+	}
+
+	return *settings.VotingTimeLimitSec
+}
+
 // handlePokerCreate handles creating a poker game
 //
 //	@Summary		Create Poker Game
@@ -124,6 +166,7 @@ func (s *Service) handlePokerCreate() http.HandlerFunc {
 			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
 			return
 		}
+		clearStoryProjectItems(b.Stories)
 
 		// set a default for backwards compatibility
 		scale := &thunderdome.EstimationScale{}
@@ -153,12 +196,14 @@ func (s *Service) handlePokerCreate() http.HandlerFunc {
 			}
 		}
 
+		votingTimeLimitSec := s.pokerVotingTimeLimitSec(ctx, r, b.VotingTimeLimitSec, teamID)
+
 		var newGame *thunderdome.Poker
 		var err error
 		// if battle created with team association
 		if teamID != "" {
 			if isTeamUserOrAnAdmin(r) {
-				newGame, err = s.PokerDataSvc.TeamCreateGame(ctx, teamID, userID, b.Name, b.EstimationScaleID, b.PointValuesAllowed, b.Stories, b.AutoFinishVoting, b.PointAverageRounding, b.JoinCode, b.FacilitatorCode, b.HideVoterIdentity)
+				newGame, err = s.PokerDataSvc.TeamCreateGame(ctx, teamID, userID, b.Name, b.EstimationScaleID, b.PointValuesAllowed, b.Stories, b.AutoFinishVoting, b.PointAverageRounding, b.JoinCode, b.FacilitatorCode, b.HideVoterIdentity, votingTimeLimitSec)
 				if err != nil {
 					s.Logger.Ctx(ctx).Error("handlePokerCreate error", zap.Error(err),
 						zap.String("entity_user_id", userID), zap.String("team_id", teamID),
@@ -171,7 +216,7 @@ func (s *Service) handlePokerCreate() http.HandlerFunc {
 				return
 			}
 		} else {
-			newGame, err = s.PokerDataSvc.CreateGame(ctx, userID, b.Name, b.EstimationScaleID, b.PointValuesAllowed, b.Stories, b.AutoFinishVoting, b.PointAverageRounding, b.JoinCode, b.FacilitatorCode, b.HideVoterIdentity)
+			newGame, err = s.PokerDataSvc.CreateGame(ctx, userID, b.Name, b.EstimationScaleID, b.PointValuesAllowed, b.Stories, b.AutoFinishVoting, b.PointAverageRounding, b.JoinCode, b.FacilitatorCode, b.HideVoterIdentity, votingTimeLimitSec)
 			if err != nil {
 				s.Logger.Ctx(ctx).Error("handlePokerCreate error", zap.Error(err),
 					zap.String("entity_user_id", userID), zap.String("poker_name", b.Name),
@@ -259,10 +304,22 @@ func (s *Service) handleGetPokerGames() http.HandlerFunc {
 	}
 }
 
+// attachStoryVoteRounds adds each voting round to its story's round history
+func attachStoryVoteRounds(stories []*thunderdome.Story, rounds []*thunderdome.StoryVoteRound) {
+	storyRounds := make(map[string][]*thunderdome.StoryVoteRound)
+	for _, round := range rounds {
+		storyRounds[round.StoryID] = append(storyRounds[round.StoryID], round)
+	}
+
+	for _, story := range stories {
+		story.VoteRounds = storyRounds[story.ID]
+	}
+}
+
 // handleGetPokerGame gets the poker game by ID
 //
 //	@Summary		Get Poker Game
-//	@Description	get poker game by ID
+//	@Description	get poker game by ID, including each story's voting round history
 //	@Tags			poker
 //	@Produce		json
 //	@Param			battleId	path	string	true	"the poker game ID to get"
@@ -298,6 +355,15 @@ func (s *Service) handleGetPokerGame() http.HandlerFunc {
 			}
 		}
 
+		rounds, err := s.PokerDataSvc.GetStoryVoteRounds(r.Context(), gameID)
+		if err != nil {
+			s.Logger.Ctx(r.Context()).Error("handleGetPokerGame error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		attachStoryVoteRounds(game.Stories, rounds)
+
 		s.Success(w, r, http.StatusOK, game, nil)
 	}
 }
@@ -36,7 +36,7 @@ func (s *Service) UserVote(ctx context.Context, pokerID string, userID string, e
 	msg = wshub.CreateSocketEvent("vote_activity", string(updatedStorys), userID)
 
 	if allVoted && wv.AutoFinishVoting {
-		plans, err := s.PokerService.EndStoryVoting(pokerID, wv.StoryID)
+		plans, err := s.PokerService.EndStoryVoting(pokerID, wv.StoryID, thunderdome.StoryVoteEndAllVoted, "")
 		if err != nil {
 			return nil, nil, err, false
 		}
@@ -132,7 +132,7 @@ func (s *Service) UserSpectatorToggle(ctx context.Context, pokerID string, userI
 
 // StoryVoteEnd handles ending story voting
 func (s *Service) StoryVoteEnd(ctx context.Context, pokerID string, userID string, eventValue string) (any, []byte, error, bool) {
-	plans, err := s.PokerService.EndStoryVoting(pokerID, eventValue)
+	plans, err := s.PokerService.EndStoryVoting(pokerID, eventValue, thunderdome.StoryVoteEndManual, userID)
 	if err != nil {
 		return nil, nil, err, false
 	}
@@ -150,6 +150,7 @@ func (s *Service) Revise(ctx context.Context, pokerID string, userID string, eve
 		AutoFinishVoting     bool     `json:"autoFinishVoting"`
 		PointAverageRounding string   `json:"pointAverageRounding"`
 		HideVoterIdentity    bool     `json:"hideVoterIdentity"`
+		VotingTimeLimitSec   *int     `json:"votingTimeLimitSec"`
 		JoinCode             string   `json:"joinCode"`
 		LeaderCode           string   `json:"leaderCode"`
 		TeamID               string   `json:"teamId"`
@@ -169,6 +170,7 @@ func (s *Service) Revise(ctx context.Context, pokerID string, userID string, eve
 		rb.JoinCode,
 		rb.LeaderCode,
 		rb.TeamID,
+		rb.VotingTimeLimitSec,
 	)
 	if err != nil {
 		return nil, nil, err, false
@@ -285,6 +287,20 @@ func (s *Service) StoryActivate(ctx context.Context, pokerID string, userID stri
 	if err != nil {
 		return nil, nil, err, false
 	}
+	s.voteTimer.RequestReload()
+	updatedStorys, _ := json.Marshal(plans)
+	msg := wshub.CreateSocketEvent("plan_activated", string(updatedStorys), "")
+
+	return nil, msg, nil, false
+}
+
+// StoryRevote handles starting a fresh voting round on a story, keeping the previous rounds in its history
+func (s *Service) StoryRevote(ctx context.Context, pokerID string, userID string, eventValue string) (any, []byte, error, bool) {
+	plans, err := s.PokerService.RevoteStory(pokerID, eventValue, userID)
+	if err != nil {
+		return nil, nil, err, false
+	}
+	s.voteTimer.RequestReload()
 	updatedStorys, _ := json.Marshal(plans)
 	msg := wshub.CreateSocketEvent("plan_activated", string(updatedStorys), "")
 
@@ -318,6 +334,13 @@ func (s *Service) StoryFinalize(ctx context.Context, pokerID string, userID stri
 	if err != nil {
 		return nil, nil, err, false
 	}
+	for _, story := range plans {
+		if story.ID == p.ID {
+			// don't hold up the game waiting on Jira
+			go s.pushJiraEstimate(context.WithoutCancel(ctx), pokerID, userID, story)
+			break
+		}
+	}
 	updatedStorys, _ := json.Marshal(plans)
 	msg := wshub.CreateSocketEvent("plan_finalized", string(updatedStorys), "")
 
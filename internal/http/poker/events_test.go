@@ -0,0 +1,37 @@
+package poker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func TestStoryRevoteFacilitatorOnly(t *testing.T) {
+	tests := []struct {
+		name        string
+		confirmErr  error
+		wantErr     bool
+		wantRevotes int
+	}{
+		{name: "facilitator", wantRevotes: 1},
+		{name: "not a facilitator", confirmErr: errors.New("REQUIRES_FACILITATOR"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataSvc := &fakePokerDataSvc{confirmErr: tt.confirmErr}
+			s := New(Config{}, otelzap.New(zap.NewNop()), nil, nil, nil, nil, dataSvc, nil)
+
+			_, err := s.APIEvent(context.Background(), "poker-1", "user-1", "revote", "story-1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("APIEvent(revote) error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(dataSvc.revotes) != tt.wantRevotes {
+				t.Errorf("RevoteStory called %d times, want %d", len(dataSvc.revotes), tt.wantRevotes)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/atlassian/jira"
+	jiradatacenter "github.com/StevenWeathers/thunderdome-planning-poker/internal/atlassian/jiraDataCenter"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/pointvalue"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// jiraEstimatePushTimeout bounds how long writing an estimate back to Jira may take
+const jiraEstimatePushTimeout = 30 * time.Second
+
+type JiraDataSvc interface {
+	FindInstancesByUserID(ctx context.Context, userID string) ([]thunderdome.JiraInstance, error)
+}
+
+// jiraInstanceForStory finds the Jira instance a story was imported from by matching the story link
+// to the issue link the instance builds, only instances with a story points field configured are considered
+func jiraInstanceForStory(instances []thunderdome.JiraInstance, story *thunderdome.Story) (thunderdome.JiraInstance, bool) {
+	if story.ReferenceID == "" || story.Link == "" {
+		return thunderdome.JiraInstance{}, false
+	}
+
+	for _, instance := range instances {
+		if instance.StoryPointsField == "" {
+			continue
+		}
+		if story.Link == jira.IssueLink(instance.Host, instance.LinkTemplate, story.ReferenceID) {
+			return instance, true
+		}
+	}
+
+	return thunderdome.JiraInstance{}, false
+}
+
+// pushJiraEstimate writes the finalized story points back to the Jira issue the story was imported from
+// using the facilitator's Jira instances, failures are only sent to the facilitator that finalized the story
+func (s *Service) pushJiraEstimate(ctx context.Context, pokerID string, userID string, story *thunderdome.Story) {
+	if s.JiraService == nil {
+		return
+	}
+	points, ok := pointvalue.Parse(story.Points)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, jiraEstimatePushTimeout)
+	defer cancel()
+
+	instances, err := s.JiraService.FindInstancesByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Ctx(ctx).Error("poker jira estimate push error", zap.Error(err),
+			zap.String("poker_id", pokerID), zap.String("story_id", story.ID), zap.String("session_user_id", userID))
+		return
+	}
+	instance, ok := jiraInstanceForStory(instances, story)
+	if !ok {
+		return
+	}
+
+	if instance.JiraDataCenter {
+		var client *jiradatacenter.Client
+		client, err = jiradatacenter.New(jiradatacenter.Config{
+			InstanceHost:   instance.Host,
+			ClientMail:     instance.ClientMail,
+			JiraDataCenter: instance.JiraDataCenter,
+			AccessToken:    instance.AccessToken,
+		})
+		if err == nil {
+			err = client.UpdateIssueStoryPoints(ctx, story.ReferenceID, instance.StoryPointsField, points)
+		}
+	} else {
+		var client *jira.Client
+		client, err = jira.New(jira.Config{
+			InstanceHost:   instance.Host,
+			ClientMail:     instance.ClientMail,
+			JiraDataCenter: instance.JiraDataCenter,
+			AccessToken:    instance.AccessToken,
+		})
+		if err == nil {
+			err = client.UpdateIssueStoryPoints(ctx, story.ReferenceID, instance.StoryPointsField, points)
+		}
+	}
+	if err == nil {
+		return
+	}
+
+	s.logger.Ctx(ctx).Error("poker jira estimate push error", zap.Error(err),
+		zap.String("poker_id", pokerID), zap.String("story_id", story.ID),
+		zap.String("jira_instance_id", instance.ID), zap.String("session_user_id", userID))
+
+	// the error is logged rather than sent as it can include details of the Jira instance
+	failure, _ := json.Marshal(map[string]string{
+		"storyId":     story.ID,
+		"referenceId": story.ReferenceID,
+	})
+	s.hub.Broadcast(wshub.Message{
+		Data:   wshub.CreateSocketEvent("jira_estimate_push_failed", string(failure), userID),
+		Room:   pokerID,
+		UserID: userID,
+	})
+}
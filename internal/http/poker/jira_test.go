@@ -0,0 +1,52 @@
+package poker
+
+import (
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func TestJiraInstanceForStory(t *testing.T) {
+	instances := []thunderdome.JiraInstance{
+		{ID: "no-field", Host: "https://example.atlassian.net"},
+		{ID: "cloud", Host: "https://example.atlassian.net/", JiraFieldMapping: thunderdome.JiraFieldMapping{StoryPointsField: "customfield_10016"}},
+		{ID: "dc", Host: "https://jira.example.com", JiraDataCenter: true, JiraFieldMapping: thunderdome.JiraFieldMapping{
+			StoryPointsField: "customfield_10002",
+			LinkTemplate:     "{host}/issues/{key}",
+		}},
+	}
+
+	tests := []struct {
+		name   string
+		story  *thunderdome.Story
+		wantID string
+	}{
+		{
+			name:   "cloud issue",
+			story:  &thunderdome.Story{ReferenceID: "PROJ-1", Link: "https://example.atlassian.net/browse/PROJ-1"},
+			wantID: "cloud",
+		},
+		{
+			name:   "data center issue",
+			story:  &thunderdome.Story{ReferenceID: "OPS-7", Link: "https://jira.example.com/issues/OPS-7"},
+			wantID: "dc",
+		},
+		{
+			name:  "not imported from jira",
+			story: &thunderdome.Story{ReferenceID: "42", Link: "https://github.com/org/repo/issues/42"},
+		},
+		{
+			name:  "no reference id",
+			story: &thunderdome.Story{Link: "https://example.atlassian.net/browse/PROJ-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance, ok := jiraInstanceForStory(instances, tt.story)
+			if ok != (tt.wantID != "") || instance.ID != tt.wantID {
+				t.Errorf("jiraInstanceForStory() = (%q, %v), want %q", instance.ID, ok, tt.wantID)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/deadline"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
@@ -23,11 +24,13 @@ type Config struct {
 	AppDomain string
 	// Websocket Subdomain (for Websocket origin check)
 	WebsocketSubdomain string
+	// Broadcast backend for fanning out events to other instances
+	BroadcastBackend wshub.Backend
 }
 
 type PokerDataSvc interface {
 	// UpdateGame updates an existing poker game
-	UpdateGame(pokerID string, name string, pointValuesAllowed []string, autoFinishVoting bool, pointAverageRounding string, hideVoterIdentity bool, joinCode string, facilitatorCode string, teamID string) error
+	UpdateGame(pokerID string, name string, pointValuesAllowed []string, autoFinishVoting bool, pointAverageRounding string, hideVoterIdentity bool, joinCode string, facilitatorCode string, teamID string, votingTimeLimitSec *int) error
 	// GetFacilitatorCode retrieves the facilitator code for a poker game
 	GetFacilitatorCode(pokerID string) (string, error)
 	// GetGameByID retrieves a poker game by its ID
@@ -58,8 +61,14 @@ type PokerDataSvc interface {
 	SetVote(pokerID string, userID string, storyID string, voteValue string) (stories []*thunderdome.Story, allUsersVoted bool)
 	// RetractVote retracts a user's vote for a story in a poker game
 	RetractVote(pokerID string, userID string, storyID string) ([]*thunderdome.Story, error)
+	// GetStoryVotingDeadlines gets the voting deadlines of stories being voted on with a time limit
+	GetStoryVotingDeadlines(ctx context.Context, lookback time.Duration) ([]*thunderdome.StoryVotingDeadline, error)
+	// TimeoutStoryVoting ends voting for a story whose voting deadline has run out
+	TimeoutStoryVoting(ctx context.Context, pokerID string, storyID string) ([]*thunderdome.Story, error)
 	// EndStoryVoting ends voting for a story in a poker game
-	EndStoryVoting(pokerID string, storyID string) ([]*thunderdome.Story, error)
+	EndStoryVoting(pokerID string, storyID string, endReason string, endedBy string) ([]*thunderdome.Story, error)
+	// RevoteStory starts a fresh voting round on a story in a poker game
+	RevoteStory(pokerID string, storyID string, userID string) ([]*thunderdome.Story, error)
 	// SkipStory skips a story in a poker game
 	SkipStory(pokerID string, storyID string) ([]*thunderdome.Story, error)
 	// UpdateStory updates an existing story in a poker game
@@ -91,7 +100,9 @@ type Service struct {
 	UserService           UserDataSvc
 	AuthService           AuthDataSvc
 	PokerService          PokerDataSvc
+	JiraService           JiraDataSvc
 	hub                   *wshub.Hub
+	voteTimer             *deadline.Scheduler[storyKey]
 }
 
 // New returns a new battle with websocket hub/client and event handlers
@@ -100,7 +111,7 @@ func New(
 	validateSessionCookie func(w http.ResponseWriter, r *http.Request) (string, error),
 	validateUserCookie func(w http.ResponseWriter, r *http.Request) (string, error),
 	userService UserDataSvc, authService AuthDataSvc,
-	pokerDataService PokerDataSvc,
+	pokerDataService PokerDataSvc, jiraDataService JiraDataSvc,
 ) *Service {
 	s := &Service{
 		config:                config,
@@ -110,6 +121,7 @@ func New(
 		UserService:           userService,
 		AuthService:           authService,
 		PokerService:          pokerDataService,
+		JiraService:           jiraDataService,
 	}
 
 	s.hub = wshub.NewHub(logger, wshub.Config{
@@ -118,6 +130,8 @@ func New(
 		WriteWaitSec:       config.WriteWaitSec,
 		PongWaitSec:        config.PongWaitSec,
 		PingPeriodSec:      config.PingPeriodSec,
+		Backend:            config.BroadcastBackend,
+		Channel:            "poker",
 	}, map[string]func(context.Context, string, string, string) (any, []byte, error, bool){
 		"jab_warrior":      s.UserNudge,
 		"vote":             s.UserVote,
@@ -128,6 +142,7 @@ func New(
 		"burn_plan":        s.StoryDelete,
 		"story_arrange":    s.StoryArrange,
 		"activate_plan":    s.StoryActivate,
+		"revote":           s.StoryRevote,
 		"skip_plan":        s.StorySkip,
 		"finalize_plan":    s.StoryFinalize,
 		"promote_leader":   s.UserPromote,
@@ -145,6 +160,7 @@ func New(
 			"burn_plan":      {},
 			"story_arrange":  {},
 			"activate_plan":  {},
+			"revote":         {},
 			"skip_plan":      {},
 			"end_voting":     {},
 			"finalize_plan":  {},
@@ -161,5 +177,8 @@ func New(
 
 	go s.hub.Run()
 
+	s.voteTimer = newVoteTimer(s)
+	go s.voteTimer.Run(context.Background())
+
 	return s
 }
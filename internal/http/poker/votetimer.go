@@ -0,0 +1,72 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/deadline"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"go.uber.org/zap"
+)
+
+const (
+	// how often voting deadlines are reloaded, picking up stories activated on other instances
+	voteTimerReloadInterval = 15 * time.Second
+	// how often loaded deadlines are checked for having run out
+	voteTimerTickInterval = time.Second
+	// deadlines that ran out longer ago than this (e.g. while no instance was running) are not fired
+	voteTimerLookback = 24 * time.Hour
+)
+
+// storyKey identifies the story a voting deadline belongs to
+type storyKey struct {
+	pokerID string
+	storyID string
+}
+
+// newVoteTimer returns a scheduler that ends story voting once a game's voting time limit runs out,
+// clients count down from the story's voteDeadline sent with plan_activated and receive voting_ended when it fires
+func newVoteTimer(svc *Service) *deadline.Scheduler[storyKey] {
+	return deadline.New(deadline.Config[storyKey]{
+		ReloadInterval: voteTimerReloadInterval,
+		TickInterval:   voteTimerTickInterval,
+		Load:           svc.loadVotingDeadlines,
+		Fire:           svc.votingTimeout,
+	})
+}
+
+// loadVotingDeadlines gets the voting deadlines of the stories being voted on
+func (s *Service) loadVotingDeadlines(ctx context.Context) (map[storyKey]time.Time, error) {
+	deadlines, err := s.PokerService.GetStoryVotingDeadlines(ctx, voteTimerLookback)
+	if err != nil {
+		s.logger.Ctx(ctx).Error("poker vote timer load error", zap.Error(err))
+		return nil, err
+	}
+
+	storyDeadlines := make(map[storyKey]time.Time, len(deadlines))
+	for _, vd := range deadlines {
+		storyDeadlines[storyKey{pokerID: vd.PokerID, storyID: vd.StoryID}] = vd.Deadline
+	}
+
+	return storyDeadlines, nil
+}
+
+// votingTimeout ends voting for a story whose voting deadline ran out
+func (s *Service) votingTimeout(ctx context.Context, story storyKey) bool {
+	stories, err := s.PokerService.TimeoutStoryVoting(ctx, story.pokerID, story.storyID)
+	if err != nil {
+		// voting was already ended (e.g. by the facilitator or another instance)
+		if err.Error() != "STORY_VOTING_NOT_EXPIRED" {
+			s.logger.Ctx(ctx).Error("poker vote timer end voting error", zap.Error(err),
+				zap.String("poker_id", story.pokerID), zap.String("story_id", story.storyID))
+		}
+		return false
+	}
+
+	updatedStories, _ := json.Marshal(stories)
+	msg := wshub.CreateSocketEvent("voting_ended", string(updatedStories), "")
+	s.hub.Broadcast(wshub.Message{Data: msg, Room: story.pokerID})
+
+	return false
+}
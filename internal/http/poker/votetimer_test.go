@@ -0,0 +1,116 @@
+package poker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// fakePokerDataSvc implements the vote timer's data service methods,
+// the embedded interface is nil so any other call panics
+type fakePokerDataSvc struct {
+	PokerDataSvc
+	deadlines  []*thunderdome.StoryVotingDeadline
+	timeoutErr error
+	timedOut   []string
+	confirmErr error
+	revotes    []string
+}
+
+func (f *fakePokerDataSvc) ConfirmFacilitator(_ string, _ string) error {
+	return f.confirmErr
+}
+
+func (f *fakePokerDataSvc) RevoteStory(_ string, storyID string, userID string) ([]*thunderdome.Story, error) {
+	f.revotes = append(f.revotes, userID+":"+storyID)
+	return []*thunderdome.Story{{ID: storyID, Active: true}}, nil
+}
+
+func (f *fakePokerDataSvc) GetStoryVotingDeadlines(_ context.Context, _ time.Duration) ([]*thunderdome.StoryVotingDeadline, error) {
+	return f.deadlines, nil
+}
+
+func (f *fakePokerDataSvc) TimeoutStoryVoting(_ context.Context, _ string, storyID string) ([]*thunderdome.Story, error) {
+	f.timedOut = append(f.timedOut, storyID)
+	if f.timeoutErr != nil {
+		return nil, f.timeoutErr
+	}
+	return []*thunderdome.Story{{ID: storyID}}, nil
+}
+
+// publishedBackend records what the hub publishes to other instances
+type publishedBackend struct {
+	published chan wshub.Message
+}
+
+func (b *publishedBackend) Publish(_ context.Context, _ string, msg wshub.Message) error {
+	b.published <- msg
+	return nil
+}
+
+func (b *publishedBackend) Subscribe(_ string, _ func(wshub.Message)) {}
+
+func newVoteTimerTestService(dataSvc PokerDataSvc) (*Service, *publishedBackend) {
+	logger := otelzap.New(zap.NewNop())
+	backend := &publishedBackend{published: make(chan wshub.Message, 1)}
+	hub := wshub.NewHub(logger, wshub.Config{Backend: backend, Channel: "poker"}, nil, nil, nil, nil)
+	go hub.Run()
+
+	return &Service{logger: logger, PokerService: dataSvc, hub: hub}, backend
+}
+
+func TestLoadVotingDeadlines(t *testing.T) {
+	deadline := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	s, _ := newVoteTimerTestService(&fakePokerDataSvc{deadlines: []*thunderdome.StoryVotingDeadline{
+		{PokerID: "poker-1", StoryID: "story-1", Deadline: deadline},
+	}})
+
+	deadlines, err := s.loadVotingDeadlines(context.Background())
+	if err != nil {
+		t.Fatalf("loadVotingDeadlines() error = %v", err)
+	}
+	got, ok := deadlines[storyKey{pokerID: "poker-1", storyID: "story-1"}]
+	if len(deadlines) != 1 || !ok || !got.Equal(deadline) {
+		t.Errorf("loadVotingDeadlines() = %v, want story-1 in poker-1 at %v", deadlines, deadline)
+	}
+}
+
+func TestVotingTimeoutEndsVoting(t *testing.T) {
+	dataSvc := &fakePokerDataSvc{}
+	s, backend := newVoteTimerTestService(dataSvc)
+
+	s.votingTimeout(context.Background(), storyKey{pokerID: "poker-1", storyID: "story-1"})
+
+	select {
+	case msg := <-backend.published:
+		if msg.Room != "poker-1" || !strings.Contains(string(msg.Data), "voting_ended") {
+			t.Errorf("broadcast %s to %s, want voting_ended to poker-1", msg.Data, msg.Room)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("voting_ended was not broadcast")
+	}
+}
+
+func TestVotingTimeoutNotExpired(t *testing.T) {
+	// the facilitator or another instance ended voting first
+	dataSvc := &fakePokerDataSvc{timeoutErr: errors.New("STORY_VOTING_NOT_EXPIRED")}
+	s, backend := newVoteTimerTestService(dataSvc)
+
+	s.votingTimeout(context.Background(), storyKey{pokerID: "poker-1", storyID: "story-1"})
+	if len(dataSvc.timedOut) != 1 {
+		t.Errorf("TimeoutStoryVoting called %d times, want 1", len(dataSvc.timedOut))
+	}
+
+	select {
+	case msg := <-backend.published:
+		t.Errorf("broadcast %s, want nothing broadcast", msg.Data)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
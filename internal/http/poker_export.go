@@ -0,0 +1,236 @@
+package http
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+type pokerExportVote struct {
+	UserID   string `json:"userId,omitempty"`
+	UserName string `json:"userName,omitempty"`
+	Vote     string `json:"vote"`
+}
+
+type pokerExportStory struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Type          string            `json:"type"`
+	ReferenceID   string            `json:"referenceId"`
+	Link          string            `json:"link"`
+	Points        string            `json:"points"`
+	Skipped       bool              `json:"skipped"`
+	VoteStartTime *time.Time        `json:"voteStartTime"`
+	VoteEndTime   *time.Time        `json:"voteEndTime"`
+	Votes         []pokerExportVote `json:"votes"`
+}
+
+type pokerExport struct {
+	ID                string             `json:"id"`
+	Name              string             `json:"name"`
+	HideVoterIdentity bool               `json:"hideVoterIdentity"`
+	ExportedAt        time.Time          `json:"exportedAt"`
+	Stories           []pokerExportStory `json:"stories"`
+	// voters are the users who voted on any story in game user order, used for the csv vote columns
+	voters []*thunderdome.PokerUser
+}
+
+// buildPokerExport flattens the game's stories and votes for export, leaving voters
+// unnamed when the game hides voter identity
+func buildPokerExport(game *thunderdome.Poker, exportedAt time.Time) *pokerExport {
+	export := &pokerExport{
+		ID:                game.ID,
+		Name:              game.Name,
+		HideVoterIdentity: game.HideVoterIdentity,
+		ExportedAt:        exportedAt,
+		Stories:           make([]pokerExportStory, 0, len(game.Stories)),
+	}
+
+	users := make(map[string]*thunderdome.PokerUser, len(game.Users))
+	for _, u := range game.Users {
+		users[u.ID] = u
+	}
+	voted := make(map[string]bool)
+
+	for _, story := range game.Stories {
+		es := pokerExportStory{
+			ID:          story.ID,
+			Name:        story.Name,
+			Type:        story.Type,
+			ReferenceID: story.ReferenceID,
+			Link:        story.Link,
+			Points:      story.Points,
+			Skipped:     story.Skipped,
+			Votes:       make([]pokerExportVote, 0, len(story.Votes)),
+		}
+		// vote times default to the story creation time, so only report them once the story has been voted on
+		if len(story.Votes) > 0 || story.Skipped || story.Points != "" {
+			start, end := story.VoteStartTime, story.VoteEndTime
+			es.VoteStartTime = &start
+			if !story.Active {
+				es.VoteEndTime = &end
+			}
+		}
+
+		for _, v := range story.Votes {
+			vote := pokerExportVote{Vote: v.VoteValue}
+			if !game.HideVoterIdentity {
+				vote.UserID = v.UserID
+				vote.UserName = v.UserID
+				if u, ok := users[v.UserID]; ok {
+					vote.UserName = u.Name
+				}
+				if !voted[v.UserID] {
+					voted[v.UserID] = true
+					if u, ok := users[v.UserID]; ok {
+						export.voters = append(export.voters, u)
+					} else {
+						export.voters = append(export.voters, &thunderdome.PokerUser{ID: v.UserID, Name: v.UserID})
+					}
+				}
+			}
+			es.Votes = append(es.Votes, vote)
+		}
+		// votes are stored in the order they were cast, sort them so the order doesn't hint at who voted what
+		if game.HideVoterIdentity {
+			sort.SliceStable(es.Votes, func(i, j int) bool {
+				return es.Votes[i].Vote < es.Votes[j].Vote
+			})
+		}
+
+		export.Stories = append(export.Stories, es)
+	}
+
+	return export
+}
+
+// writeCSV writes the export as one row per story with a vote column per voter,
+// or a single votes column when voter identity is hidden, user entered values are neutralized
+// so spreadsheets don't evaluate them as formulas
+func (e *pokerExport) writeCSV(w *csv.Writer) error {
+	header := []string{"Story ID", "Name", "Type", "Reference ID", "Link", "Points", "Skipped", "Vote Start", "Vote End"}
+	if e.HideVoterIdentity {
+		header = append(header, "Votes")
+	} else {
+		for _, u := range e.voters {
+			header = append(header, csvSafe(u.Name))
+		}
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	for _, story := range e.Stories {
+		row := []string{
+			story.ID, csvSafe(story.Name), csvSafe(story.Type), csvSafe(story.ReferenceID), csvSafe(story.Link),
+			csvSafe(story.Points),
+			strconv.FormatBool(story.Skipped), formatTime(story.VoteStartTime), formatTime(story.VoteEndTime),
+		}
+		if e.HideVoterIdentity {
+			votes := make([]string, 0, len(story.Votes))
+			for _, v := range story.Votes {
+				votes = append(votes, csvSafe(v.Vote))
+			}
+			row = append(row, strings.Join(votes, "; "))
+		} else {
+			userVotes := make(map[string]string, len(story.Votes))
+			for _, v := range story.Votes {
+				userVotes[v.UserID] = v.Vote
+			}
+			for _, u := range e.voters {
+				row = append(row, csvSafe(userVotes[u.ID]))
+			}
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// handlePokerExport exports the poker game stories and votes
+//
+//	@Summary		Export Poker Game
+//	@Description	Exports each poker story with its reference ID, final points, skip status, vote times, and each participant's vote,
+//	@Description	voter identities are left out when the game hides voter identity
+//	@Tags			poker
+//	@Produce		json
+//	@Produce		text/csv
+//	@Param			battleId	path	string	true	"the poker game ID to export"
+//	@Param			format		query	string	false	"the export format, defaults to json"	Enums(json, csv)
+//	@Success		200			object	standardJsonResponse{data=pokerExport}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/battles/{battleId}/export [get]
+func (s *Service) handlePokerExport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		gameID := r.PathValue("battleId")
+		idErr := validate.Var(gameID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = "json"
+		}
+		if format != "json" && format != "csv" {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "format must be one of json, csv"))
+			return
+		}
+
+		game, err := s.PokerDataSvc.GetGameByID(gameID, sessionUserID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "BATTLE_NOT_FOUND"))
+			return
+		}
+
+		// don't allow exporting battle details if battle has JoinCode and user hasn't joined yet
+		if game.JoinCode != "" {
+			userErr := s.PokerDataSvc.GetUserActiveStatus(gameID, sessionUserID)
+			if userErr != nil && userErr.Error() != "DUPLICATE_BATTLE_USER" && userType != thunderdome.AdminUserType {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "USER_MUST_JOIN_BATTLE"))
+				return
+			}
+		}
+
+		export := buildPokerExport(game, time.Now().UTC())
+
+		if format == "json" {
+			s.Success(w, r, http.StatusOK, export, nil)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"battle-%s.csv\"", gameID))
+		w.WriteHeader(http.StatusOK)
+		if err := export.writeCSV(csv.NewWriter(w)); err != nil {
+			s.Logger.Ctx(ctx).Error("handlePokerExport error", zap.Error(err),
+				zap.String("poker_id", gameID), zap.String("session_user_id", sessionUserID))
+		}
+	}
+}
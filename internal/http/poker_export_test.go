@@ -0,0 +1,140 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func TestBuildPokerExport(t *testing.T) {
+	game := func(hideVoterIdentity bool) *thunderdome.Poker {
+		return &thunderdome.Poker{
+			ID:                "game",
+			Name:              "Sprint 42",
+			HideVoterIdentity: hideVoterIdentity,
+			Users: []*thunderdome.PokerUser{
+				{ID: "u1", Name: "Ada"},
+				{ID: "u2", Name: "Grace"},
+			},
+			Stories: []*thunderdome.Story{
+				{
+					ID:          "s1",
+					Name:        "Login",
+					ReferenceID: "PROJ-1",
+					Points:      "5",
+					Votes: []*thunderdome.Vote{
+						{UserID: "u2", VoteValue: "8"},
+						{UserID: "u1", VoteValue: "5"},
+					},
+				},
+				{ID: "s2", Name: "Logout"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		hideVoterIdentity bool
+		wantHeader        []string
+		wantVotes         string
+	}{
+		{
+			name:       "voter identity shown",
+			wantHeader: []string{"Grace", "Ada"},
+			wantVotes:  "8,5",
+		},
+		{
+			name:              "voter identity hidden",
+			hideVoterIdentity: true,
+			wantHeader:        []string{"Votes"},
+			wantVotes:         "5; 8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			export := buildPokerExport(game(tt.hideVoterIdentity), time.Now())
+
+			if export.Stories[1].VoteStartTime != nil {
+				t.Errorf("VoteStartTime = %v, want nil for a story that was never voted on", export.Stories[1].VoteStartTime)
+			}
+			for _, v := range export.Stories[0].Votes {
+				if tt.hideVoterIdentity && (v.UserID != "" || v.UserName != "") {
+					t.Errorf("vote %+v includes voter identity", v)
+				}
+			}
+
+			var buf bytes.Buffer
+			if err := export.writeCSV(csv.NewWriter(&buf)); err != nil {
+				t.Fatalf("writeCSV() error = %v", err)
+			}
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("reading csv error = %v", err)
+			}
+			if len(records) != 3 {
+				t.Fatalf("got %d csv records, want 3", len(records))
+			}
+
+			header := records[0][9:]
+			if len(header) != len(tt.wantHeader) {
+				t.Fatalf("vote columns = %v, want %v", header, tt.wantHeader)
+			}
+			for i := range header {
+				if header[i] != tt.wantHeader[i] {
+					t.Errorf("vote columns = %v, want %v", header, tt.wantHeader)
+				}
+			}
+
+			votes := records[1][9:]
+			got := votes[0]
+			for _, v := range votes[1:] {
+				got += "," + v
+			}
+			if got != tt.wantVotes {
+				t.Errorf("votes = %q, want %q", got, tt.wantVotes)
+			}
+		})
+	}
+}
+
+func TestPokerExportCSVNeutralizesFormulas(t *testing.T) {
+	export := buildPokerExport(&thunderdome.Poker{
+		ID:    "game",
+		Users: []*thunderdome.PokerUser{{ID: "u1", Name: "@SUM(A1)"}},
+		Stories: []*thunderdome.Story{{
+			ID:          "s1",
+			Name:        "=HYPERLINK(\"https://example.com\")",
+			ReferenceID: "+cmd|' /C calc'!A0",
+			Link:        "-2+3",
+			Votes:       []*thunderdome.Vote{{UserID: "u1", VoteValue: "=1+1"}},
+		}},
+	}, time.Now())
+
+	var buf bytes.Buffer
+	if err := export.writeCSV(csv.NewWriter(&buf)); err != nil {
+		t.Fatalf("writeCSV() error = %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading csv error = %v", err)
+	}
+
+	if records[0][9] != "'@SUM(A1)" {
+		t.Errorf("voter column = %q, want %q", records[0][9], "'@SUM(A1)")
+	}
+	want := map[int]string{
+		1: "'=HYPERLINK(\"https://example.com\")",
+		3: "'+cmd|' /C calc'!A0",
+		4: "'-2+3",
+		9: "'=1+1",
+	}
+	for i, value := range want {
+		if records[1][i] != value {
+			t.Errorf("column %s = %q, want %q", records[0][i], records[1][i], value)
+		}
+	}
+}
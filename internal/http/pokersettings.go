@@ -14,6 +14,7 @@ type pokerSettingsRequestBody struct {
 	AutoFinishVoting     bool    `json:"autoFinishVoting"`
 	PointAverageRounding string  `json:"pointAverageRounding" validate:"oneof=ceil floor round"`
 	HideVoterIdentity    bool    `json:"hideVoterIdentity"`
+	VotingTimeLimitSec   *int    `json:"votingTimeLimitSec" validate:"omitempty,gte=0,lte=3600"`
 	EstimationScaleID    *string `json:"estimationScaleId" validate:"omitempty,uuid"`
 	JoinCode             string  `json:"joinCode"`
 	FacilitatorCode      string  `json:"facilitatorCode"`
@@ -68,6 +69,7 @@ func (s *Service) handleCreateOrganizationPokerSettings() http.HandlerFunc {
 			AutoFinishVoting:     settingsReq.AutoFinishVoting,
 			PointAverageRounding: settingsReq.PointAverageRounding,
 			HideVoterIdentity:    settingsReq.HideVoterIdentity,
+			VotingTimeLimitSec:   settingsReq.VotingTimeLimitSec,
 			EstimationScaleID:    settingsReq.EstimationScaleID,
 			JoinCode:             settingsReq.JoinCode,
 			FacilitatorCode:      settingsReq.FacilitatorCode,
@@ -134,6 +136,7 @@ func (s *Service) handleCreateTeamPokerSettings() http.HandlerFunc {
 			AutoFinishVoting:     settingsReq.AutoFinishVoting,
 			PointAverageRounding: settingsReq.PointAverageRounding,
 			HideVoterIdentity:    settingsReq.HideVoterIdentity,
+			VotingTimeLimitSec:   settingsReq.VotingTimeLimitSec,
 			EstimationScaleID:    settingsReq.EstimationScaleID,
 			JoinCode:             settingsReq.JoinCode,
 			FacilitatorCode:      settingsReq.FacilitatorCode,
@@ -200,6 +203,7 @@ func (s *Service) handleCreateDepartmentPokerSettings() http.HandlerFunc {
 			AutoFinishVoting:     settingsReq.AutoFinishVoting,
 			PointAverageRounding: settingsReq.PointAverageRounding,
 			HideVoterIdentity:    settingsReq.HideVoterIdentity,
+			VotingTimeLimitSec:   settingsReq.VotingTimeLimitSec,
 			EstimationScaleID:    settingsReq.EstimationScaleID,
 			JoinCode:             settingsReq.JoinCode,
 			FacilitatorCode:      settingsReq.FacilitatorCode,
@@ -302,6 +306,7 @@ func (s *Service) handleOrganizationPokerSettingsUpdate() http.HandlerFunc {
 			AutoFinishVoting:     settingsReq.AutoFinishVoting,
 			PointAverageRounding: settingsReq.PointAverageRounding,
 			HideVoterIdentity:    settingsReq.HideVoterIdentity,
+			VotingTimeLimitSec:   settingsReq.VotingTimeLimitSec,
 			EstimationScaleID:    settingsReq.EstimationScaleID,
 			JoinCode:             settingsReq.JoinCode,
 			FacilitatorCode:      settingsReq.FacilitatorCode,
@@ -404,6 +409,7 @@ func (s *Service) handleTeamPokerSettingsUpdate() http.HandlerFunc {
 			AutoFinishVoting:     settingsReq.AutoFinishVoting,
 			PointAverageRounding: settingsReq.PointAverageRounding,
 			HideVoterIdentity:    settingsReq.HideVoterIdentity,
+			VotingTimeLimitSec:   settingsReq.VotingTimeLimitSec,
 			EstimationScaleID:    settingsReq.EstimationScaleID,
 			JoinCode:             settingsReq.JoinCode,
 			FacilitatorCode:      settingsReq.FacilitatorCode,
@@ -508,6 +514,7 @@ func (s *Service) handleDepartmentPokerSettingsUpdate() http.HandlerFunc {
 			AutoFinishVoting:     settingsReq.AutoFinishVoting,
 			PointAverageRounding: settingsReq.PointAverageRounding,
 			HideVoterIdentity:    settingsReq.HideVoterIdentity,
+			VotingTimeLimitSec:   settingsReq.VotingTimeLimitSec,
 			EstimationScaleID:    settingsReq.EstimationScaleID,
 			JoinCode:             settingsReq.JoinCode,
 			FacilitatorCode:      settingsReq.FacilitatorCode,
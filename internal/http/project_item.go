@@ -0,0 +1,535 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+type projectItemRequestBody struct {
+	Title                 string     `json:"title" validate:"required,max=255"`
+	Description           string     `json:"description"`
+	TypeID                string     `json:"typeId" validate:"required,uuid"`
+	PriorityID            *string    `json:"priorityId" validate:"omitempty,uuid"`
+	ParentID              *string    `json:"parentId" validate:"omitempty,uuid"`
+	StoryPoints           *string    `json:"storyPoints" validate:"omitempty,max=8"`
+	StartDate             *time.Time `json:"startDate"`
+	EndDate               *time.Time `json:"endDate"`
+	ExternalReferenceID   *string    `json:"externalReferenceId" validate:"omitempty,max=100"`
+	ExternalReferenceLink *string    `json:"externalReferenceLink" validate:"omitempty,url"`
+}
+
+type projectItemStatusRequestBody struct {
+	StatusID string `json:"statusId" validate:"required,uuid"`
+}
+
+type projectItemMoveRequestBody struct {
+	PlaceBefore string `json:"placeBefore" validate:"omitempty,uuid"`
+}
+
+// itemScopeFromRequest builds the item type, status, and priority scope from the request path,
+// routes without organization, department, or team path values represent the global scope
+func itemScopeFromRequest(r *http.Request) thunderdome.ItemScope {
+	var scope thunderdome.ItemScope
+	if orgID := r.PathValue("orgId"); orgID != "" {
+		scope.OrganizationID = &orgID
+	}
+	if departmentID := r.PathValue("departmentId"); departmentID != "" {
+		scope.DepartmentID = &departmentID
+	}
+	if teamID := r.PathValue("teamId"); teamID != "" {
+		scope.TeamID = &teamID
+	}
+
+	return scope
+}
+
+// projectItemErrorStatus maps project item data errors to an http status and application error code
+func projectItemErrorStatus(err error) (int, string) {
+	code := err.Error()
+	switch {
+	case strings.HasSuffix(code, "_NOT_FOUND"):
+		return http.StatusNotFound, ENOTFOUND
+	case strings.HasSuffix(code, "_ALREADY_EXISTS"), strings.HasSuffix(code, "_IN_USE"),
+		code == "ITEM_HAS_OPEN_CHILDREN":
+		return http.StatusConflict, ECONFLICT
+	case strings.HasSuffix(code, "_INVALID"):
+		return http.StatusBadRequest, EINVALID
+	}
+
+	return http.StatusInternalServerError, EINTERNAL
+}
+
+// projectItemFailure responds with the appropriate failure for a project item data error
+func (s *Service) projectItemFailure(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := projectItemErrorStatus(err)
+	if code == EINTERNAL {
+		s.Failure(w, r, status, err)
+		return
+	}
+
+	s.Failure(w, r, status, Errorf(code, err.Error()))
+}
+
+// handleGetProjectItems gets a list of items for a project
+//
+//	@Summary		Get Project Items
+//	@Description	get a list of items for a project in rank order
+//	@Tags			project
+//	@Produce		json
+//	@Param			projectId	path	string	true	"the project ID"
+//	@Param			typeId		query	string	false	"filter by item type ID"
+//	@Param			statusId	query	string	false	"filter by item status ID"
+//	@Param			priorityId	query	string	false	"filter by item priority ID"
+//	@Param			parentId	query	string	false	"filter by parent item ID"
+//	@Param			open		query	boolean	false	"only return items not in a final status"
+//	@Param			limit		query	int		false	"Max number of results to return"
+//	@Param			offset		query	int		false	"Starting point to return rows from, should be multiplied by limit or 0"
+//	@Success		200			object	standardJsonResponse{data=[]thunderdome.ProjectItem,meta=pagination}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/projects/{projectId}/items [get]
+func (s *Service) handleGetProjectItems() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		projectID := r.PathValue("projectId")
+		idErr := validate.Var(projectID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		query := r.URL.Query()
+		filter := thunderdome.ProjectItemFilter{
+			TypeID:     query.Get("typeId"),
+			StatusID:   query.Get("statusId"),
+			PriorityID: query.Get("priorityId"),
+			ParentID:   query.Get("parentId"),
+			Open:       query.Get("open") == "true",
+		}
+		for _, filterID := range []string{filter.TypeID, filter.StatusID, filter.PriorityID, filter.ParentID} {
+			if err := validate.Var(filterID, "omitempty,uuid"); err != nil {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, err.Error()))
+				return
+			}
+		}
+		limit, offset := getLimitOffsetFromRequest(r)
+
+		items, count, err := s.ProjectDataSvc.GetProjectItems(ctx, projectID, filter, limit, offset)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetProjectItems error", zap.Error(err),
+				zap.String("project_id", projectID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		meta := &pagination{
+			Count:  count,
+			Offset: offset,
+			Limit:  limit,
+		}
+
+		s.Success(w, r, http.StatusOK, items, meta)
+	}
+}
+
+// handleGetProjectItem gets a project item by ID
+//
+//	@Summary		Get Project Item
+//	@Description	get a project item by its ID
+//	@Tags			project
+//	@Produce		json
+//	@Param			projectId	path	string	true	"the project ID"
+//	@Param			itemId		path	string	true	"the item ID"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.ProjectItem}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/projects/{projectId}/items/{itemId} [get]
+func (s *Service) handleGetProjectItem() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		projectID := r.PathValue("projectId")
+		idErr := validate.Var(projectID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		itemID := r.PathValue("itemId")
+		idErr = validate.Var(itemID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		item, err := s.ProjectDataSvc.GetProjectItemByID(ctx, projectID, itemID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetProjectItem error", zap.Error(err),
+				zap.String("project_id", projectID),
+				zap.String("item_id", itemID),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, item, nil)
+	}
+}
+
+// handleProjectItemCreate creates a new project item
+//
+//	@Summary		Create Project Item
+//	@Description	Creates a project item in the project's initial status, keyed by the project key and next item number
+//	@Tags			project
+//	@Produce		json
+//	@Param			projectId	path	string					true	"the project ID"
+//	@Param			item		body	projectItemRequestBody	true	"new project item object"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.ProjectItem}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/projects/{projectId}/items [post]
+func (s *Service) handleProjectItemCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		projectID := r.PathValue("projectId")
+		idErr := validate.Var(projectID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = projectItemRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		item := projectItemFromRequest(req)
+		item.ProjectID = projectID
+		item.CreatedBy = sessionUserID
+
+		newItem, err := s.ProjectDataSvc.CreateProjectItem(ctx, item)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleProjectItemCreate error", zap.Error(err),
+				zap.String("project_id", projectID),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, newItem, nil)
+	}
+}
+
+// handleProjectItemUpdate updates a project item
+//
+//	@Summary		Update Project Item
+//	@Description	Updates a project item, status changes are made through the status endpoint
+//	@Tags			project
+//	@Produce		json
+//	@Param			projectId	path	string					true	"the project ID"
+//	@Param			itemId		path	string					true	"the item ID"
+//	@Param			item		body	projectItemRequestBody	true	"updated project item object"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.ProjectItem}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/projects/{projectId}/items/{itemId} [put]
+func (s *Service) handleProjectItemUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		projectID := r.PathValue("projectId")
+		idErr := validate.Var(projectID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		itemID := r.PathValue("itemId")
+		idErr = validate.Var(itemID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = projectItemRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		item := projectItemFromRequest(req)
+		item.ID = itemID
+		item.ProjectID = projectID
+
+		updatedItem, err := s.ProjectDataSvc.UpdateProjectItem(ctx, item)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleProjectItemUpdate error", zap.Error(err),
+				zap.String("project_id", projectID),
+				zap.String("item_id", itemID),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, updatedItem, nil)
+	}
+}
+
+// handleProjectItemStatusUpdate transitions a project item to another status
+//
+//	@Summary		Update Project Item Status
+//	@Description	Transitions a project item to another status, setting its start and end dates as it leaves the initial status or enters/leaves a final status
+//	@Tags			project
+//	@Produce		json
+//	@Param			projectId	path	string							true	"the project ID"
+//	@Param			itemId		path	string							true	"the item ID"
+//	@Param			status		body	projectItemStatusRequestBody	true	"the status to transition to"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.ProjectItem}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		409			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/projects/{projectId}/items/{itemId}/status [patch]
+func (s *Service) handleProjectItemStatusUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		projectID := r.PathValue("projectId")
+		idErr := validate.Var(projectID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		itemID := r.PathValue("itemId")
+		idErr = validate.Var(itemID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = projectItemStatusRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		item, err := s.ProjectDataSvc.TransitionProjectItem(ctx, projectID, itemID, req.StatusID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleProjectItemStatusUpdate error", zap.Error(err),
+				zap.String("project_id", projectID),
+				zap.String("item_id", itemID),
+				zap.String("status_id", req.StatusID),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, item, nil)
+	}
+}
+
+// handleProjectItemMove moves a project item's rank
+//
+//	@Summary		Move Project Item
+//	@Description	Moves a project item before another item, or to the end when placeBefore is empty
+//	@Tags			project
+//	@Produce		json
+//	@Param			projectId	path	string						true	"the project ID"
+//	@Param			itemId		path	string						true	"the item ID"
+//	@Param			move		body	projectItemMoveRequestBody	true	"the item to place before"
+//	@Success		200			object	standardJsonResponse{data=thunderdome.ProjectItem}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/projects/{projectId}/items/{itemId}/move [put]
+func (s *Service) handleProjectItemMove() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		projectID := r.PathValue("projectId")
+		idErr := validate.Var(projectID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		itemID := r.PathValue("itemId")
+		idErr = validate.Var(itemID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = projectItemMoveRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+		if req.PlaceBefore == itemID {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ITEM_PLACE_BEFORE_INVALID"))
+			return
+		}
+
+		item, err := s.ProjectDataSvc.MoveProjectItem(ctx, projectID, itemID, req.PlaceBefore)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleProjectItemMove error", zap.Error(err),
+				zap.String("project_id", projectID),
+				zap.String("item_id", itemID),
+				zap.String("place_before", req.PlaceBefore),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, item, nil)
+	}
+}
+
+// handleProjectItemDelete deletes a project item
+//
+//	@Summary		Delete Project Item
+//	@Description	Deletes a project item, its child items are kept without a parent
+//	@Tags			project
+//	@Produce		json
+//	@Param			projectId	path	string	true	"the project ID"
+//	@Param			itemId		path	string	true	"the item ID"
+//	@Success		200			object	standardJsonResponse{}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/projects/{projectId}/items/{itemId} [delete]
+func (s *Service) handleProjectItemDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		projectID := r.PathValue("projectId")
+		idErr := validate.Var(projectID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		itemID := r.PathValue("itemId")
+		idErr = validate.Var(itemID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		err := s.ProjectDataSvc.DeleteProjectItem(ctx, projectID, itemID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleProjectItemDelete error", zap.Error(err),
+				zap.String("project_id", projectID),
+				zap.String("item_id", itemID),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+func projectItemFromRequest(req projectItemRequestBody) *thunderdome.ProjectItem {
+	// empty optional references are treated as unset
+	for _, ref := range []**string{&req.PriorityID, &req.ParentID} {
+		if *ref != nil && **ref == "" {
+			*ref = nil
+		}
+	}
+
+	return &thunderdome.ProjectItem{
+		Title:                 req.Title,
+		Description:           req.Description,
+		TypeID:                req.TypeID,
+		PriorityID:            req.PriorityID,
+		ParentID:              req.ParentID,
+		StoryPoints:           req.StoryPoints,
+		StartDate:             req.StartDate,
+		EndDate:               req.EndDate,
+		ExternalReferenceID:   req.ExternalReferenceID,
+		ExternalReferenceLink: req.ExternalReferenceLink,
+	}
+}
@@ -0,0 +1,285 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+type itemPriorityRequestBody struct {
+	Name          string  `json:"name" validate:"required,max=50"`
+	PriorityKey   string  `json:"priorityKey" validate:"required,max=20"`
+	Description   string  `json:"description"`
+	Color         *string `json:"color" validate:"omitempty,oneof=red orange amber yellow lime green emerald teal cyan sky blue indigo violet purple fuchsia pink rose"`
+	PriorityLevel int     `json:"priorityLevel"`
+	IsActive      bool    `json:"isActive"`
+}
+
+// handleGetItemPriorities gets the item priorities defined in a scope
+//
+//	@Summary		Get Item Priorities
+//	@Description	get the item priorities defined globally or within an organization, department, or team
+//	@Tags			project
+//	@Produce		json
+//	@Param			orgId			path	string	false	"the organization ID"
+//	@Param			departmentId	path	string	false	"the department ID"
+//	@Param			teamId			path	string	false	"the team ID"
+//	@Success		200				object	standardJsonResponse{data=[]thunderdome.ItemPriority}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/item-priorities [get]
+//	@Router			/organizations/{orgId}/item-priorities [get]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/item-priorities [get]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-priorities [get]
+func (s *Service) handleGetItemPriorities() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		scope := itemScopeFromRequest(r)
+
+		priorities, err := s.ProjectDataSvc.GetItemPriorities(ctx, scope)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetItemPriorities error", zap.Error(err),
+				zap.Any("scope", scope),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, priorities, nil)
+	}
+}
+
+// handleGetProjectItemPriorities gets the item priorities available to a project
+//
+//	@Summary		Get Project Item Priorities
+//	@Description	get the active item priorities available to a project, including global and inherited organization, department, and team priorities
+//	@Tags			project
+//	@Produce		json
+//	@Param			projectId	path	string	true	"the project ID"
+//	@Success		200			object	standardJsonResponse{data=[]thunderdome.ItemPriority}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/projects/{projectId}/item-priorities [get]
+func (s *Service) handleGetProjectItemPriorities() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		projectID := r.PathValue("projectId")
+		idErr := validate.Var(projectID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		priorities, err := s.ProjectDataSvc.GetProjectItemPriorities(ctx, projectID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetProjectItemPriorities error", zap.Error(err),
+				zap.String("project_id", projectID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, priorities, nil)
+	}
+}
+
+// handleItemPriorityCreate creates an item priority within a scope
+//
+//	@Summary		Create Item Priority
+//	@Description	Creates an item priority globally or within an organization, department, or team
+//	@Tags			project
+//	@Produce		json
+//	@Param			orgId			path	string					false	"the organization ID"
+//	@Param			departmentId	path	string					false	"the department ID"
+//	@Param			teamId			path	string					false	"the team ID"
+//	@Param			priority		body	itemPriorityRequestBody	true	"new item priority object"
+//	@Success		200				object	standardJsonResponse{data=thunderdome.ItemPriority}
+//	@Failure		400				object	standardJsonResponse{}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		409				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/item-priorities [post]
+//	@Router			/organizations/{orgId}/item-priorities [post]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/item-priorities [post]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-priorities [post]
+func (s *Service) handleItemPriorityCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		scope := itemScopeFromRequest(r)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = itemPriorityRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		priority, err := s.ProjectDataSvc.CreateItemPriority(ctx, &thunderdome.ItemPriority{
+			Name:          req.Name,
+			PriorityKey:   req.PriorityKey,
+			Description:   req.Description,
+			Color:         req.Color,
+			PriorityLevel: req.PriorityLevel,
+			IsActive:      req.IsActive,
+			ItemScope:     scope,
+		})
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleItemPriorityCreate error", zap.Error(err),
+				zap.Any("scope", scope),
+				zap.String("priority_key", req.PriorityKey),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, priority, nil)
+	}
+}
+
+// handleItemPriorityUpdate updates an item priority within a scope
+//
+//	@Summary		Update Item Priority
+//	@Description	Updates an item priority defined globally or within an organization, department, or team
+//	@Tags			project
+//	@Produce		json
+//	@Param			orgId			path	string					false	"the organization ID"
+//	@Param			departmentId	path	string					false	"the department ID"
+//	@Param			teamId			path	string					false	"the team ID"
+//	@Param			priorityId		path	string					true	"the item priority ID"
+//	@Param			priority		body	itemPriorityRequestBody	true	"updated item priority object"
+//	@Success		200				object	standardJsonResponse{data=thunderdome.ItemPriority}
+//	@Failure		400				object	standardJsonResponse{}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		404				object	standardJsonResponse{}
+//	@Failure		409				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/item-priorities/{priorityId} [put]
+//	@Router			/organizations/{orgId}/item-priorities/{priorityId} [put]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/item-priorities/{priorityId} [put]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-priorities/{priorityId} [put]
+func (s *Service) handleItemPriorityUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		scope := itemScopeFromRequest(r)
+
+		priorityID := r.PathValue("priorityId")
+		idErr := validate.Var(priorityID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = itemPriorityRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		priority, err := s.ProjectDataSvc.UpdateItemPriority(ctx, &thunderdome.ItemPriority{
+			ID:            priorityID,
+			Name:          req.Name,
+			PriorityKey:   req.PriorityKey,
+			Description:   req.Description,
+			Color:         req.Color,
+			PriorityLevel: req.PriorityLevel,
+			IsActive:      req.IsActive,
+			ItemScope:     scope,
+		})
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleItemPriorityUpdate error", zap.Error(err),
+				zap.Any("scope", scope),
+				zap.String("priority_id", priorityID),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, priority, nil)
+	}
+}
+
+// handleItemPriorityDelete deletes an item priority within a scope
+//
+//	@Summary		Delete Item Priority
+//	@Description	Deletes an item priority defined globally or within an organization, department, or team, priorities in use can not be deleted
+//	@Tags			project
+//	@Produce		json
+//	@Param			orgId			path	string	false	"the organization ID"
+//	@Param			departmentId	path	string	false	"the department ID"
+//	@Param			teamId			path	string	false	"the team ID"
+//	@Param			priorityId		path	string	true	"the item priority ID"
+//	@Success		200				object	standardJsonResponse{}
+//	@Failure		400				object	standardJsonResponse{}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		404				object	standardJsonResponse{}
+//	@Failure		409				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/item-priorities/{priorityId} [delete]
+//	@Router			/organizations/{orgId}/item-priorities/{priorityId} [delete]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/item-priorities/{priorityId} [delete]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-priorities/{priorityId} [delete]
+func (s *Service) handleItemPriorityDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		scope := itemScopeFromRequest(r)
+
+		priorityID := r.PathValue("priorityId")
+		idErr := validate.Var(priorityID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		err := s.ProjectDataSvc.DeleteItemPriority(ctx, scope, priorityID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleItemPriorityDelete error", zap.Error(err),
+				zap.Any("scope", scope),
+				zap.String("priority_id", priorityID),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
@@ -0,0 +1,291 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+type itemStatusRequestBody struct {
+	Name        string  `json:"name" validate:"required,max=50"`
+	StatusKey   string  `json:"statusKey" validate:"required,max=20"`
+	Description string  `json:"description"`
+	Color       *string `json:"color" validate:"omitempty,oneof=red orange amber yellow lime green emerald teal cyan sky blue indigo violet purple fuchsia pink rose"`
+	IsInitial   bool    `json:"isInitial"`
+	IsFinal     bool    `json:"isFinal"`
+	IsActive    bool    `json:"isActive"`
+	SortOrder   int     `json:"sortOrder"`
+}
+
+// handleGetItemStatuses gets the item statuses defined in a scope
+//
+//	@Summary		Get Item Statuses
+//	@Description	get the item statuses defined globally or within an organization, department, or team
+//	@Tags			project
+//	@Produce		json
+//	@Param			orgId			path	string	false	"the organization ID"
+//	@Param			departmentId	path	string	false	"the department ID"
+//	@Param			teamId			path	string	false	"the team ID"
+//	@Success		200				object	standardJsonResponse{data=[]thunderdome.ItemStatus}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/item-statuses [get]
+//	@Router			/organizations/{orgId}/item-statuses [get]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/item-statuses [get]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-statuses [get]
+func (s *Service) handleGetItemStatuses() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		scope := itemScopeFromRequest(r)
+
+		statuses, err := s.ProjectDataSvc.GetItemStatuses(ctx, scope)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetItemStatuses error", zap.Error(err),
+				zap.Any("scope", scope),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, statuses, nil)
+	}
+}
+
+// handleGetProjectItemStatuses gets the item statuses available to a project
+//
+//	@Summary		Get Project Item Statuses
+//	@Description	get the active item statuses available to a project, including global and inherited organization, department, and team statuses
+//	@Tags			project
+//	@Produce		json
+//	@Param			projectId	path	string	true	"the project ID"
+//	@Success		200			object	standardJsonResponse{data=[]thunderdome.ItemStatus}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/projects/{projectId}/item-statuses [get]
+func (s *Service) handleGetProjectItemStatuses() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		projectID := r.PathValue("projectId")
+		idErr := validate.Var(projectID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		statuses, err := s.ProjectDataSvc.GetProjectItemStatuses(ctx, projectID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetProjectItemStatuses error", zap.Error(err),
+				zap.String("project_id", projectID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, statuses, nil)
+	}
+}
+
+// handleItemStatusCreate creates an item status within a scope
+//
+//	@Summary		Create Item Status
+//	@Description	Creates an item status globally or within an organization, department, or team, marking it initial replaces the scope's current initial status
+//	@Tags			project
+//	@Produce		json
+//	@Param			orgId			path	string					false	"the organization ID"
+//	@Param			departmentId	path	string					false	"the department ID"
+//	@Param			teamId			path	string					false	"the team ID"
+//	@Param			status			body	itemStatusRequestBody	true	"new item status object"
+//	@Success		200				object	standardJsonResponse{data=thunderdome.ItemStatus}
+//	@Failure		400				object	standardJsonResponse{}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		409				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/item-statuses [post]
+//	@Router			/organizations/{orgId}/item-statuses [post]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/item-statuses [post]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-statuses [post]
+func (s *Service) handleItemStatusCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		scope := itemScopeFromRequest(r)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = itemStatusRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		status, err := s.ProjectDataSvc.CreateItemStatus(ctx, &thunderdome.ItemStatus{
+			Name:        req.Name,
+			StatusKey:   req.StatusKey,
+			Description: req.Description,
+			Color:       req.Color,
+			IsInitial:   req.IsInitial,
+			IsFinal:     req.IsFinal,
+			IsActive:    req.IsActive,
+			SortOrder:   req.SortOrder,
+			ItemScope:   scope,
+		})
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleItemStatusCreate error", zap.Error(err),
+				zap.Any("scope", scope),
+				zap.String("status_key", req.StatusKey),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, status, nil)
+	}
+}
+
+// handleItemStatusUpdate updates an item status within a scope
+//
+//	@Summary		Update Item Status
+//	@Description	Updates an item status defined globally or within an organization, department, or team
+//	@Tags			project
+//	@Produce		json
+//	@Param			orgId			path	string					false	"the organization ID"
+//	@Param			departmentId	path	string					false	"the department ID"
+//	@Param			teamId			path	string					false	"the team ID"
+//	@Param			statusId		path	string					true	"the item status ID"
+//	@Param			status			body	itemStatusRequestBody	true	"updated item status object"
+//	@Success		200				object	standardJsonResponse{data=thunderdome.ItemStatus}
+//	@Failure		400				object	standardJsonResponse{}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		404				object	standardJsonResponse{}
+//	@Failure		409				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/item-statuses/{statusId} [put]
+//	@Router			/organizations/{orgId}/item-statuses/{statusId} [put]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/item-statuses/{statusId} [put]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-statuses/{statusId} [put]
+func (s *Service) handleItemStatusUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		scope := itemScopeFromRequest(r)
+
+		statusID := r.PathValue("statusId")
+		idErr := validate.Var(statusID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = itemStatusRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		status, err := s.ProjectDataSvc.UpdateItemStatus(ctx, &thunderdome.ItemStatus{
+			ID:          statusID,
+			Name:        req.Name,
+			StatusKey:   req.StatusKey,
+			Description: req.Description,
+			Color:       req.Color,
+			IsInitial:   req.IsInitial,
+			IsFinal:     req.IsFinal,
+			IsActive:    req.IsActive,
+			SortOrder:   req.SortOrder,
+			ItemScope:   scope,
+		})
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleItemStatusUpdate error", zap.Error(err),
+				zap.Any("scope", scope),
+				zap.String("status_id", statusID),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, status, nil)
+	}
+}
+
+// handleItemStatusDelete deletes an item status within a scope
+//
+//	@Summary		Delete Item Status
+//	@Description	Deletes an item status defined globally or within an organization, department, or team, statuses in use can not be deleted
+//	@Tags			project
+//	@Produce		json
+//	@Param			orgId			path	string	false	"the organization ID"
+//	@Param			departmentId	path	string	false	"the department ID"
+//	@Param			teamId			path	string	false	"the team ID"
+//	@Param			statusId		path	string	true	"the item status ID"
+//	@Success		200				object	standardJsonResponse{}
+//	@Failure		400				object	standardJsonResponse{}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		404				object	standardJsonResponse{}
+//	@Failure		409				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/item-statuses/{statusId} [delete]
+//	@Router			/organizations/{orgId}/item-statuses/{statusId} [delete]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/item-statuses/{statusId} [delete]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-statuses/{statusId} [delete]
+func (s *Service) handleItemStatusDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		scope := itemScopeFromRequest(r)
+
+		statusID := r.PathValue("statusId")
+		idErr := validate.Var(statusID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		err := s.ProjectDataSvc.DeleteItemStatus(ctx, scope, statusID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleItemStatusDelete error", zap.Error(err),
+				zap.Any("scope", scope),
+				zap.String("status_id", statusID),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
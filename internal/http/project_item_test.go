@@ -0,0 +1,62 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestProjectItemErrorStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "Item not found",
+			err:        errors.New("PROJECT_ITEM_NOT_FOUND"),
+			wantStatus: http.StatusNotFound,
+			wantCode:   ENOTFOUND,
+		},
+		{
+			name:       "Duplicate type key",
+			err:        errors.New("ITEM_TYPE_ALREADY_EXISTS"),
+			wantStatus: http.StatusConflict,
+			wantCode:   ECONFLICT,
+		},
+		{
+			name:       "Status in use",
+			err:        errors.New("ITEM_STATUS_IN_USE"),
+			wantStatus: http.StatusConflict,
+			wantCode:   ECONFLICT,
+		},
+		{
+			name:       "Open children",
+			err:        errors.New("ITEM_HAS_OPEN_CHILDREN"),
+			wantStatus: http.StatusConflict,
+			wantCode:   ECONFLICT,
+		},
+		{
+			name:       "Invalid parent",
+			err:        errors.New("ITEM_PARENT_INVALID"),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   EINVALID,
+		},
+		{
+			name:       "Unexpected error",
+			err:        errors.New("error querying project item: connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   EINTERNAL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, code := projectItemErrorStatus(tt.err)
+			if status != tt.wantStatus || code != tt.wantCode {
+				t.Errorf("projectItemErrorStatus() = (%v, %v), want (%v, %v)", status, code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+type itemTypeRequestBody struct {
+	Name        string  `json:"name" validate:"required,max=50"`
+	TypeKey     string  `json:"typeKey" validate:"required,max=10"`
+	Description string  `json:"description"`
+	Color       *string `json:"color" validate:"omitempty,oneof=red orange amber yellow lime green emerald teal cyan sky blue indigo violet purple fuchsia pink rose"`
+	IsActive    bool    `json:"isActive"`
+}
+
+// handleGetItemTypes gets the item types defined in a scope
+//
+//	@Summary		Get Item Types
+//	@Description	get the item types defined globally or within an organization, department, or team
+//	@Tags			project
+//	@Produce		json
+//	@Param			orgId			path	string	false	"the organization ID"
+//	@Param			departmentId	path	string	false	"the department ID"
+//	@Param			teamId			path	string	false	"the team ID"
+//	@Success		200				object	standardJsonResponse{data=[]thunderdome.ItemType}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/item-types [get]
+//	@Router			/organizations/{orgId}/item-types [get]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/item-types [get]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-types [get]
+func (s *Service) handleGetItemTypes() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		scope := itemScopeFromRequest(r)
+
+		itemTypes, err := s.ProjectDataSvc.GetItemTypes(ctx, scope)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetItemTypes error", zap.Error(err),
+				zap.Any("scope", scope),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, itemTypes, nil)
+	}
+}
+
+// handleGetProjectItemTypes gets the item types available to a project
+//
+//	@Summary		Get Project Item Types
+//	@Description	get the active item types available to a project, including global and inherited organization, department, and team types
+//	@Tags			project
+//	@Produce		json
+//	@Param			projectId	path	string	true	"the project ID"
+//	@Success		200			object	standardJsonResponse{data=[]thunderdome.ItemType}
+//	@Failure		400			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/projects/{projectId}/item-types [get]
+func (s *Service) handleGetProjectItemTypes() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		projectID := r.PathValue("projectId")
+		idErr := validate.Var(projectID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		itemTypes, err := s.ProjectDataSvc.GetProjectItemTypes(ctx, projectID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleGetProjectItemTypes error", zap.Error(err),
+				zap.String("project_id", projectID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, itemTypes, nil)
+	}
+}
+
+// handleItemTypeCreate creates an item type within a scope
+//
+//	@Summary		Create Item Type
+//	@Description	Creates an item type globally or within an organization, department, or team
+//	@Tags			project
+//	@Produce		json
+//	@Param			orgId			path	string				false	"the organization ID"
+//	@Param			departmentId	path	string				false	"the department ID"
+//	@Param			teamId			path	string				false	"the team ID"
+//	@Param			itemType		body	itemTypeRequestBody	true	"new item type object"
+//	@Success		200				object	standardJsonResponse{data=thunderdome.ItemType}
+//	@Failure		400				object	standardJsonResponse{}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		409				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/item-types [post]
+//	@Router			/organizations/{orgId}/item-types [post]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/item-types [post]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-types [post]
+func (s *Service) handleItemTypeCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		scope := itemScopeFromRequest(r)
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = itemTypeRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		itemType, err := s.ProjectDataSvc.CreateItemType(ctx, &thunderdome.ItemType{
+			Name:        req.Name,
+			TypeKey:     req.TypeKey,
+			Description: req.Description,
+			Color:       req.Color,
+			IsActive:    req.IsActive,
+			ItemScope:   scope,
+		})
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleItemTypeCreate error", zap.Error(err),
+				zap.Any("scope", scope),
+				zap.String("type_key", req.TypeKey),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, itemType, nil)
+	}
+}
+
+// handleItemTypeUpdate updates an item type within a scope
+//
+//	@Summary		Update Item Type
+//	@Description	Updates an item type defined globally or within an organization, department, or team
+//	@Tags			project
+//	@Produce		json
+//	@Param			orgId			path	string				false	"the organization ID"
+//	@Param			departmentId	path	string				false	"the department ID"
+//	@Param			teamId			path	string				false	"the team ID"
+//	@Param			typeId			path	string				true	"the item type ID"
+//	@Param			itemType		body	itemTypeRequestBody	true	"updated item type object"
+//	@Success		200				object	standardJsonResponse{data=thunderdome.ItemType}
+//	@Failure		400				object	standardJsonResponse{}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		404				object	standardJsonResponse{}
+//	@Failure		409				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/item-types/{typeId} [put]
+//	@Router			/organizations/{orgId}/item-types/{typeId} [put]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/item-types/{typeId} [put]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-types/{typeId} [put]
+func (s *Service) handleItemTypeUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		scope := itemScopeFromRequest(r)
+
+		typeID := r.PathValue("typeId")
+		idErr := validate.Var(typeID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		var req = itemTypeRequestBody{}
+		jsonErr := json.Unmarshal(body, &req)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(req)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		itemType, err := s.ProjectDataSvc.UpdateItemType(ctx, &thunderdome.ItemType{
+			ID:          typeID,
+			Name:        req.Name,
+			TypeKey:     req.TypeKey,
+			Description: req.Description,
+			Color:       req.Color,
+			IsActive:    req.IsActive,
+			ItemScope:   scope,
+		})
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleItemTypeUpdate error", zap.Error(err),
+				zap.Any("scope", scope),
+				zap.String("type_id", typeID),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, itemType, nil)
+	}
+}
+
+// handleItemTypeDelete deletes an item type within a scope
+//
+//	@Summary		Delete Item Type
+//	@Description	Deletes an item type defined globally or within an organization, department, or team, types in use can not be deleted
+//	@Tags			project
+//	@Produce		json
+//	@Param			orgId			path	string	false	"the organization ID"
+//	@Param			departmentId	path	string	false	"the department ID"
+//	@Param			teamId			path	string	false	"the team ID"
+//	@Param			typeId			path	string	true	"the item type ID"
+//	@Success		200				object	standardJsonResponse{}
+//	@Failure		400				object	standardJsonResponse{}
+//	@Failure		403				object	standardJsonResponse{}
+//	@Failure		404				object	standardJsonResponse{}
+//	@Failure		409				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/item-types/{typeId} [delete]
+//	@Router			/organizations/{orgId}/item-types/{typeId} [delete]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/item-types/{typeId} [delete]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/item-types/{typeId} [delete]
+func (s *Service) handleItemTypeDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		scope := itemScopeFromRequest(r)
+
+		typeID := r.PathValue("typeId")
+		idErr := validate.Var(typeID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		err := s.ProjectDataSvc.DeleteItemType(ctx, scope, typeID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleItemTypeDelete error", zap.Error(err),
+				zap.Any("scope", scope),
+				zap.String("type_id", typeID),
+				zap.String("session_user_id", sessionUserID))
+			s.projectItemFailure(w, r, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
@@ -45,15 +45,50 @@ func (s *Service) handleGetProjectPokerGames() http.HandlerFunc {
 	}
 }
 
+// maxProjectPokerItems limits how many project items can be sent into a single poker game
+const maxProjectPokerItems = 250
+
+type projectPokerItemsRequestBody struct {
+	ItemIDs    []string `json:"itemIds" validate:"omitempty,max=250,dive,uuid"`
+	TypeID     string   `json:"typeId" validate:"omitempty,uuid"`
+	StatusID   string   `json:"statusId" validate:"omitempty,uuid"`
+	PriorityID string   `json:"priorityId" validate:"omitempty,uuid"`
+	ParentID   string   `json:"parentId" validate:"omitempty,uuid"`
+	Open       bool     `json:"open"`
+}
+
+type projectPokerRequestBody struct {
+	battleRequestBody
+	// ProjectItems selects the project items to add to the game as stories, by ID and/or filter
+	ProjectItems *projectPokerItemsRequestBody `json:"projectItems"`
+}
+
+// projectItemToStory builds a poker story from a project item, keeping a reference to the item
+// so the finalized points are written back to it
+func projectItemToStory(item *thunderdome.ProjectItem) *thunderdome.Story {
+	story := &thunderdome.Story{
+		Name:          item.Title,
+		Type:          item.TypeKey,
+		ReferenceID:   item.ItemKey,
+		Description:   item.Description,
+		ProjectItemID: &item.ID,
+	}
+	if item.ExternalReferenceLink != nil {
+		story.Link = *item.ExternalReferenceLink
+	}
+
+	return story
+}
+
 // handleCreateProjectPokerGame creates a new poker game associated with a specific project
 //
 //	@Summary		Create Project Poker
-//	@Description	Create a new poker game associated with a specific project
+//	@Description	Create a new poker game associated with a specific project, optionally adding a filtered set of the project's items as stories whose finalized points are written back to the items
 //	@Tags			projects,poker
 //	@Accept			json
 //	@Produce		json
-//	@Param			projectId	path	string				true	"the project ID to associate the poker game with"
-//	@Param			body		body	battleRequestBody	true	"The poker game request body"
+//	@Param			projectId	path	string					true	"the project ID to associate the poker game with"
+//	@Param			body		body	projectPokerRequestBody	true	"The poker game request body"
 //	@Success		200			object	standardJsonResponse{data=thunderdome.Poker}
 //	@Failure		400			object	standardJsonResponse{}
 //	@Failure		403			object	standardJsonResponse{}
@@ -77,7 +112,7 @@ func (s *Service) handleCreateProjectPokerGame() http.HandlerFunc {
 			return
 		}
 
-		var b = battleRequestBody{}
+		var b = projectPokerRequestBody{}
 		jsonErr := json.Unmarshal(body, &b)
 		if jsonErr != nil {
 			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
@@ -89,6 +124,37 @@ func (s *Service) handleCreateProjectPokerGame() http.HandlerFunc {
 			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
 			return
 		}
+		clearStoryProjectItems(b.Stories)
+
+		if b.ProjectItems != nil {
+			items, count, err := s.ProjectDataSvc.GetProjectItems(ctx, projectID, thunderdome.ProjectItemFilter{
+				IDs:        b.ProjectItems.ItemIDs,
+				TypeID:     b.ProjectItems.TypeID,
+				StatusID:   b.ProjectItems.StatusID,
+				PriorityID: b.ProjectItems.PriorityID,
+				ParentID:   b.ProjectItems.ParentID,
+				Open:       b.ProjectItems.Open,
+			}, maxProjectPokerItems, 0)
+			if err != nil {
+				s.Logger.Ctx(ctx).Error("handleCreateProjectPokerGame get project items error", zap.Error(err),
+					zap.String("project_id", projectID),
+					zap.String("session_user_id", sessionUserID))
+				s.Failure(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			if count > maxProjectPokerItems {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "PROJECT_ITEMS_LIMIT_EXCEEDED"))
+				return
+			}
+			if len(b.ProjectItems.ItemIDs) > 0 && count != len(slices.Compact(slices.Sorted(slices.Values(b.ProjectItems.ItemIDs)))) {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "PROJECT_ITEM_NOT_FOUND"))
+				return
+			}
+
+			for _, item := range items {
+				b.Stories = append(b.Stories, projectItemToStory(item))
+			}
+		}
 
 		// set a default for backwards compatibility
 		scale := &thunderdome.EstimationScale{}
@@ -120,7 +186,7 @@ func (s *Service) handleCreateProjectPokerGame() http.HandlerFunc {
 
 		var newGame *thunderdome.Poker
 		var err error
-		newGame, err = s.PokerDataSvc.CreateGame(ctx, sessionUserID, b.Name, b.EstimationScaleID, b.PointValuesAllowed, b.Stories, b.AutoFinishVoting, b.PointAverageRounding, b.JoinCode, b.FacilitatorCode, b.HideVoterIdentity)
+		newGame, err = s.PokerDataSvc.CreateGame(ctx, sessionUserID, b.Name, b.EstimationScaleID, b.PointValuesAllowed, b.Stories, b.AutoFinishVoting, b.PointAverageRounding, b.JoinCode, b.FacilitatorCode, b.HideVoterIdentity, s.pokerVotingTimeLimitSec(ctx, r, b.VotingTimeLimitSec, ""))
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handlePokerCreate error", zap.Error(err),
 				zap.String("entity_user_id", sessionUserID), zap.String("poker_name", b.Name),
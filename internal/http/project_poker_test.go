@@ -0,0 +1,125 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func TestProjectItemToStory(t *testing.T) {
+	link := "https://example.atlassian.net/browse/PROJ-7"
+	item := &thunderdome.ProjectItem{
+		ID:                    "1f2e3d4c-5b6a-4978-8a9b-0c1d2e3f4a5b",
+		ItemKey:               "PROJ-7",
+		Title:                 "Checkout with saved cards",
+		Description:           "<p>Returning customers can pick a saved card</p>",
+		TypeKey:               "story",
+		ExternalReferenceLink: &link,
+	}
+
+	story := projectItemToStory(item)
+
+	assert.Equal(t, item.Title, story.Name)
+	assert.Equal(t, "story", story.Type)
+	assert.Equal(t, "PROJ-7", story.ReferenceID)
+	assert.Equal(t, item.Description, story.Description)
+	assert.Equal(t, link, story.Link)
+	if assert.NotNil(t, story.ProjectItemID) {
+		assert.Equal(t, item.ID, *story.ProjectItemID)
+	}
+
+	item.ExternalReferenceLink = nil
+	assert.Empty(t, projectItemToStory(item).Link)
+}
+
+func TestHandleCreateProjectPokerGameItemSelection(t *testing.T) {
+	const projectID = "6d2c1f8e-0a4b-4c1e-9d7f-2b3a4c5d6e7f"
+	const itemID = "1f2e3d4c-5b6a-4978-8a9b-0c1d2e3f4a5b"
+
+	tooManyIDs := make([]string, maxProjectPokerItems+1)
+	for i := range tooManyIDs {
+		tooManyIDs[i] = fmt.Sprintf("00000000-0000-4000-8000-%012d", i)
+	}
+	tooManyBody, _ := json.Marshal(map[string]any{
+		"name":                 "Sprint 12",
+		"pointValuesAllowed":   []string{"1", "2", "3"},
+		"pointAverageRounding": "ceil",
+		"projectItems":         map[string]any{"itemIds": tooManyIDs},
+	})
+	const game = `"name":"Sprint 12","pointValuesAllowed":["1","2","3"],"pointAverageRounding":"ceil"`
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(m *MockProjectDataSvc)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "more item IDs than the cap",
+			body:           string(tooManyBody),
+			setupMocks:     func(m *MockProjectDataSvc) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "filter matches more items than the cap",
+			body: `{` + game + `,"projectItems":{"open":true}}`,
+			setupMocks: func(m *MockProjectDataSvc) {
+				m.On("GetProjectItems", mock.Anything, projectID,
+					thunderdome.ProjectItemFilter{Open: true}, maxProjectPokerItems, 0,
+				).Return(make([]*thunderdome.ProjectItem, maxProjectPokerItems), maxProjectPokerItems+1, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "PROJECT_ITEMS_LIMIT_EXCEEDED",
+		},
+		{
+			name: "selected item not in project",
+			body: `{` + game + `,"projectItems":{"itemIds":["` + itemID + `","` + itemID + `","00000000-0000-4000-8000-000000000001"]}}`,
+			setupMocks: func(m *MockProjectDataSvc) {
+				m.On("GetProjectItems", mock.Anything, projectID,
+					thunderdome.ProjectItemFilter{IDs: []string{itemID, itemID, "00000000-0000-4000-8000-000000000001"}},
+					maxProjectPokerItems, 0,
+				).Return([]*thunderdome.ProjectItem{{ID: itemID}}, 1, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "PROJECT_ITEM_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProjectDataSvc := new(MockProjectDataSvc)
+			tt.setupMocks(mockProjectDataSvc)
+
+			s := &Service{
+				ProjectDataSvc: mockProjectDataSvc,
+				Logger:         otelzap.New(zap.NewNop()),
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/projects/"+projectID+"/poker", strings.NewReader(tt.body))
+			req.SetPathValue("projectId", projectID)
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, "c3d4e5f6-a7b8-4c9d-8e1f-2a3b4c5d6e7f"))
+
+			rr := httptest.NewRecorder()
+			s.handleCreateProjectPokerGame()(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedError != "" {
+				var resp standardJsonResponse
+				_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+				assert.Equal(t, tt.expectedError, resp.Error)
+			}
+			mockProjectDataSvc.AssertExpectations(t)
+		})
+	}
+}
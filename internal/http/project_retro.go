@@ -103,7 +103,7 @@ func (s *Service) handleCreateProjectRetro() http.HandlerFunc {
 		var newRetro *thunderdome.Retro
 		var err error
 
-		newRetro, err = s.RetroDataSvc.CreateRetro(ctx, sessionUserID, "", nr.RetroName, nr.JoinCode, nr.FacilitatorCode, nr.MaxVotes, nr.BrainstormVisibility, nr.PhaseTimeLimitMin, nr.PhaseAutoAdvance, nr.AllowCumulativeVoting, nr.HideVotesDuringVoting, nr.SkipPrimeDirective, *nr.TemplateID)
+		newRetro, err = s.RetroDataSvc.CreateRetro(ctx, sessionUserID, "", nr.RetroName, nr.JoinCode, nr.FacilitatorCode, nr.MaxVotes, nr.BrainstormVisibility, nr.Anonymity, nr.PhaseTimeLimitMin, nr.PhaseAutoAdvance, nr.AllowCumulativeVoting, nr.HideVotesDuringVoting, nr.SkipPrimeDirective, *nr.TemplateID)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleRetroCreate error", zap.Error(err),
 				zap.String("entity_user_id", sessionUserID),
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// RateLimitConfig is the rate limiting and account lockout configuration,
+// a request or failure budget of zero disables that limit
+type RateLimitConfig struct {
+	Enabled bool
+	// AuthIPRequests is the number of requests an IP address can make to each auth route per AuthWindow
+	AuthIPRequests int
+	// AuthAccountRequests is the number of attempts made against a single account per AuthWindow
+	AuthAccountRequests int
+	AuthWindow          time.Duration
+	// LockoutThreshold is the number of consecutive failed password or second factor logins that locks an account
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	// APIKeyRequests is the number of external API requests an API key can make per APIKeyWindow
+	APIKeyRequests int
+	APIKeyWindow   time.Duration
+}
+
+// rateLimitKey builds the counter key of a budget, normalizing the subject so
+// differently cased emails share a budget
+func rateLimitKey(budget string, route string, subject string) string {
+	return budget + ":" + route + ":" + strings.ToLower(strings.TrimSpace(subject))
+}
+
+// rateLimitExceeded counts the request against the key's budget, setting the rate limit headers and
+// writing a 429 response when the budget is spent, the request is allowed if the counter can't be reached
+func (s *Service) rateLimitExceeded(w http.ResponseWriter, r *http.Request, key string, limit int, window time.Duration) bool {
+	if !s.Config.RateLimit.Enabled || limit <= 0 || window <= 0 || s.RateLimitDataSvc == nil {
+		return false
+	}
+	ctx := r.Context()
+
+	hits, windowEnd, err := s.RateLimitDataSvc.Hit(ctx, key, window)
+	if err != nil {
+		s.Logger.Ctx(ctx).Error("rateLimitExceeded error", zap.Error(err), zap.String("rate_limit_key", key))
+		return false
+	}
+
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(max(limit-hits, 0)))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(windowEnd.Unix(), 10))
+
+	if hits <= limit {
+		return false
+	}
+
+	retryAfter := int(math.Ceil(time.Until(windowEnd).Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+	s.Logger.Ctx(ctx).Warn("rate limit exceeded", zap.String("rate_limit_key", key),
+		zap.String("request_path", r.URL.Path))
+	s.Failure(w, r, http.StatusTooManyRequests, Errorf(ETOOMANYREQUESTS, "RATE_LIMIT_EXCEEDED"))
+
+	return true
+}
+
+// authRateLimit limits the requests each IP address can make to an auth route
+func (s *Service) authRateLimit(route string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := rateLimitKey("ip", route, s.clientIP(r))
+		if s.rateLimitExceeded(w, r, key, s.Config.RateLimit.AuthIPRequests, s.Config.RateLimit.AuthWindow) {
+			return
+		}
+
+		h(w, r)
+	}
+}
+
+// accountRateLimitExceeded limits the attempts made against a single account on an auth route,
+// regardless of how many IP addresses they come from
+func (s *Service) accountRateLimitExceeded(w http.ResponseWriter, r *http.Request, route string, account string) bool {
+	key := rateLimitKey("account", route, account)
+	return s.rateLimitExceeded(w, r, key, s.Config.RateLimit.AuthAccountRequests, s.Config.RateLimit.AuthWindow)
+}
+
+// resetAccountRateLimit clears an account's budget on an auth route after it authenticated successfully
+func (s *Service) resetAccountRateLimit(r *http.Request, route string, account string) {
+	if !s.Config.RateLimit.Enabled || s.RateLimitDataSvc == nil {
+		return
+	}
+	ctx := r.Context()
+
+	if err := s.RateLimitDataSvc.Reset(ctx, rateLimitKey("account", route, account)); err != nil {
+		s.Logger.Ctx(ctx).Error("resetAccountRateLimit error", zap.Error(err))
+	}
+}
+
+// apiKeyRateLimitExceeded limits the external API requests made with an API key
+func (s *Service) apiKeyRateLimitExceeded(w http.ResponseWriter, r *http.Request, key *thunderdome.APIKey) bool {
+	return s.rateLimitExceeded(w, r, rateLimitKey("apikey", "api", key.ID),
+		s.Config.RateLimit.APIKeyRequests, s.Config.RateLimit.APIKeyWindow)
+}
+
+// recordFailedPasswordLogin counts a failed password login towards locking the account,
+// emailing the user when the failure locks their account
+func (s *Service) recordFailedPasswordLogin(r *http.Request, email string) {
+	if !s.Config.RateLimit.Enabled || s.Config.RateLimit.LockoutThreshold <= 0 || s.Config.RateLimit.LockoutDuration <= 0 {
+		return
+	}
+	ctx := r.Context()
+
+	user, lockedUntil, err := s.AuthDataSvc.UserLoginFailed(ctx, email,
+		s.Config.RateLimit.LockoutThreshold, s.Config.RateLimit.LockoutDuration)
+	if err != nil {
+		if err.Error() != "USER_NOT_FOUND" {
+			s.Logger.Ctx(ctx).Error("recordFailedPasswordLogin error", zap.Error(err),
+				zap.String("user_email", sanitizeUserInputForLogs(email)))
+		}
+		return
+	}
+	if lockedUntil == nil {
+		return
+	}
+
+	s.recordAuditEvent(r, thunderdome.AuditEvent{
+		ActorID:    &user.ID,
+		Action:     thunderdome.AuditActionAccountLocked,
+		TargetType: thunderdome.AuditTargetTypeUser,
+		TargetID:   user.ID,
+		Metadata:   map[string]string{"lockedUntil": lockedUntil.UTC().Format(time.RFC3339)},
+	})
+
+	if emailErr := s.Email.SendAccountLocked(user.Name, user.Email, *lockedUntil); emailErr != nil {
+		s.Logger.Ctx(ctx).Error("recordFailedPasswordLogin error", zap.Error(emailErr),
+			zap.String("user_id", user.ID))
+	}
+}
+
+// recordFailedSecondFactor counts a failed MFA login towards locking the account the same as a failed password,
+// the password was already correct so the attempts would otherwise only be limited per login
+func (s *Service) recordFailedSecondFactor(r *http.Request, userID string) {
+	if !s.Config.RateLimit.Enabled || s.Config.RateLimit.LockoutThreshold <= 0 || s.Config.RateLimit.LockoutDuration <= 0 {
+		return
+	}
+	ctx := r.Context()
+
+	user, err := s.UserDataSvc.GetUserByID(ctx, userID)
+	if err != nil {
+		s.Logger.Ctx(ctx).Error("recordFailedSecondFactor error", zap.Error(err), zap.String("user_id", userID))
+		return
+	}
+
+	s.recordFailedPasswordLogin(r, user.Email)
+}
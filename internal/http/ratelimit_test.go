@@ -0,0 +1,221 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// fakeRateLimitDataSvc counts hits in memory with a window that never ends during the test
+type fakeRateLimitDataSvc struct {
+	hits map[string]int
+}
+
+func (f *fakeRateLimitDataSvc) Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	f.hits[key]++
+	return f.hits[key], time.Now().Add(window), nil
+}
+
+func (f *fakeRateLimitDataSvc) Reset(ctx context.Context, key string) error {
+	delete(f.hits, key)
+	return nil
+}
+
+func TestRateLimitKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  string
+		route   string
+		subject string
+		want    string
+	}{
+		{name: "ip", budget: "ip", route: "login", subject: "127.0.0.1", want: "ip:login:127.0.0.1"},
+		{name: "email normalized", budget: "account", route: "login", subject: " Thor@Example.com ", want: "account:login:thor@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rateLimitKey(tt.budget, tt.route, tt.subject); got != tt.want {
+				t.Errorf("rateLimitKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthRateLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		limit      int
+		requests   int
+		wantStatus int
+	}{
+		{name: "within budget", enabled: true, limit: 3, requests: 3, wantStatus: http.StatusOK},
+		{name: "budget spent", enabled: true, limit: 3, requests: 4, wantStatus: http.StatusTooManyRequests},
+		{name: "zero budget disables", enabled: true, limit: 0, requests: 10, wantStatus: http.StatusOK},
+		{name: "disabled", enabled: false, limit: 1, requests: 10, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				Config: &Config{RateLimit: RateLimitConfig{
+					Enabled:        tt.enabled,
+					AuthIPRequests: tt.limit,
+					AuthWindow:     time.Minute,
+				}},
+				Logger:           otelzap.New(zap.NewNop()),
+				RateLimitDataSvc: &fakeRateLimitDataSvc{hits: make(map[string]int)},
+			}
+			handler := s.authRateLimit("login", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			var rec *httptest.ResponseRecorder
+			for range tt.requests {
+				rec = httptest.NewRecorder()
+				handler(rec, httptest.NewRequest(http.MethodPost, "/api/auth", nil))
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusTooManyRequests && rec.Header().Get("Retry-After") == "" {
+				t.Error("expected Retry-After header")
+			}
+		})
+	}
+}
+
+func TestAuthRateLimitKeysOnClientIP(t *testing.T) {
+	tests := []struct {
+		name          string
+		remoteAddr    string
+		forwardedFor  []string
+		wantKey       string
+		wantSameLimit bool
+	}{
+		{
+			name:          "spoofed forwarded for from an untrusted peer",
+			remoteAddr:    "198.51.100.9:4321",
+			forwardedFor:  []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"},
+			wantKey:       "ip:login:198.51.100.9",
+			wantSameLimit: true,
+		},
+		{
+			name:          "forwarded for from a trusted proxy",
+			remoteAddr:    "10.0.0.2:4321",
+			forwardedFor:  []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"},
+			wantKey:       "ip:login:203.0.113.3",
+			wantSameLimit: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := &fakeRateLimitDataSvc{hits: make(map[string]int)}
+			s := &Service{
+				Config: &Config{
+					RateLimit: RateLimitConfig{
+						Enabled:        true,
+						AuthIPRequests: 2,
+						AuthWindow:     time.Minute,
+					},
+					TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+				},
+				Logger:           otelzap.New(zap.NewNop()),
+				RateLimitDataSvc: hits,
+			}
+			handler := s.authRateLimit("login", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			// each request claims a different client, only a trusted proxy's claim is honoured
+			var rec *httptest.ResponseRecorder
+			for _, forwardedFor := range tt.forwardedFor {
+				req := httptest.NewRequest(http.MethodPost, "/api/auth", nil)
+				req.RemoteAddr = tt.remoteAddr
+				req.Header.Set("X-Forwarded-For", forwardedFor)
+				rec = httptest.NewRecorder()
+				handler(rec, req)
+			}
+
+			if _, ok := hits.hits[tt.wantKey]; !ok {
+				t.Errorf("rate limit keys = %v, want %s", hits.hits, tt.wantKey)
+			}
+			if tt.wantSameLimit && (len(hits.hits) != 1 || rec.Code != http.StatusTooManyRequests) {
+				t.Errorf("keys = %v status = %d, want a single spent budget", hits.hits, rec.Code)
+			}
+			if !tt.wantSameLimit && (len(hits.hits) != len(tt.forwardedFor) || rec.Code != http.StatusOK) {
+				t.Errorf("keys = %v status = %d, want a budget per forwarded client", hits.hits, rec.Code)
+			}
+		})
+	}
+}
+
+// fakeMFAAuthDataSvc resolves every pending MFA session to the same user and rejects their passcodes
+type fakeMFAAuthDataSvc struct {
+	AuthDataSvc
+	userID       string
+	failedLogins []string
+}
+
+func (f *fakeMFAAuthDataSvc) MFASessionUserID(ctx context.Context, sessionID string) (string, error) {
+	return f.userID, nil
+}
+
+func (f *fakeMFAAuthDataSvc) MFATokenValidate(ctx context.Context, sessionID string, passcode string) error {
+	return errors.New("INVALID_AUTHENTICATOR_TOKEN")
+}
+
+func (f *fakeMFAAuthDataSvc) UserLoginFailed(ctx context.Context, userEmail string, threshold int, lockout time.Duration) (*thunderdome.User, *time.Time, error) {
+	f.failedLogins = append(f.failedLogins, userEmail)
+	return &thunderdome.User{ID: f.userID, Email: userEmail}, nil, nil
+}
+
+func TestMFALoginRateLimitPerUser(t *testing.T) {
+	const userID = "6d2c1f8e-0a4b-4c1e-9d7f-2b3a4c5d6e7f"
+	auth := &fakeMFAAuthDataSvc{userID: userID}
+	users := new(MockUserDataService)
+	users.On("GetUserByID", mock.Anything, userID).Return(&thunderdome.User{ID: userID, Email: "thor@example.com"}, nil)
+	s := &Service{
+		Config: &Config{RateLimit: RateLimitConfig{
+			Enabled:             true,
+			AuthAccountRequests: 2,
+			AuthWindow:          time.Minute,
+			LockoutThreshold:    5,
+			LockoutDuration:     time.Minute,
+		}},
+		Logger:           otelzap.New(zap.NewNop()),
+		AuthDataSvc:      auth,
+		UserDataSvc:      users,
+		RateLimitDataSvc: &fakeRateLimitDataSvc{hits: make(map[string]int)},
+	}
+	handler := s.handleMFALogin()
+
+	// each password login hands out a new pending session, the budget still belongs to the user
+	wantStatus := []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}
+	for i, sessionID := range []string{"session-1", "session-2", "session-3"} {
+		body := `{"passcode":"000000","sessionId":"` + sessionID + `"}`
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, "/api/auth/mfa", strings.NewReader(body)))
+
+		if rec.Code != wantStatus[i] {
+			t.Fatalf("attempt %d status = %d, want %d", i+1, rec.Code, wantStatus[i])
+		}
+	}
+
+	if len(auth.failedLogins) != 2 || auth.failedLogins[0] != "thor@example.com" {
+		t.Errorf("failed logins counted towards lockout = %v, want 2 for thor@example.com", auth.failedLogins)
+	}
+}
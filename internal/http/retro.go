@@ -18,6 +18,7 @@ type retroCreateRequestBody struct {
 	FacilitatorCode       string   `json:"facilitatorCode" example:"likeaboss"`
 	MaxVotes              int      `json:"maxVotes" validate:"required,min=1,max=9"`
 	BrainstormVisibility  string   `json:"brainstormVisibility" validate:"required,oneof=visible concealed hidden"`
+	Anonymity             string   `json:"anonymity" example:"anonymous" enums:"none,anonymous,untracked" validate:"omitempty,oneof=none anonymous untracked"`
 	PhaseTimeLimitMin     int      `json:"phaseTimeLimitMin" validate:"min=0,max=59" example:"10"`
 	PhaseAutoAdvance      bool     `json:"phaseAutoAdvance"`
 	AllowCumulativeVoting bool     `json:"allowCumulativeVoting"`
@@ -103,7 +104,7 @@ func (s *Service) handleRetroCreate() http.HandlerFunc {
 			return
 		}
 
-		newRetro, err = s.RetroDataSvc.CreateRetro(ctx, userID, teamID, nr.RetroName, nr.JoinCode, nr.FacilitatorCode, nr.MaxVotes, nr.BrainstormVisibility, nr.PhaseTimeLimitMin, nr.PhaseAutoAdvance, nr.AllowCumulativeVoting, nr.HideVotesDuringVoting, nr.SkipPrimeDirective, *nr.TemplateID)
+		newRetro, err = s.RetroDataSvc.CreateRetro(ctx, userID, teamID, nr.RetroName, nr.JoinCode, nr.FacilitatorCode, nr.MaxVotes, nr.BrainstormVisibility, nr.Anonymity, nr.PhaseTimeLimitMin, nr.PhaseAutoAdvance, nr.AllowCumulativeVoting, nr.HideVotesDuringVoting, nr.SkipPrimeDirective, *nr.TemplateID)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleRetroCreate error", zap.Error(err),
 				zap.String("entity_user_id", userID),
@@ -319,7 +320,9 @@ func (s *Service) handleGetRetros() http.HandlerFunc {
 type actionUpdateRequestBody struct {
 	ActionID  string `json:"id" swaggerignore:"true" validate:"required,uuid"`
 	Completed bool   `json:"completed" example:"false"`
+	Status    string `json:"status" example:"in_progress" enums:"open,in_progress,done,dropped" validate:"omitempty,oneof=open in_progress done dropped"`
 	Content   string `json:"content" example:"update documentation" validate:"required"`
+	DueDate   string `json:"dueDate" example:"2026-11-01" validate:"omitempty,datetime=2006-01-02"`
 }
 
 // handleRetroActionUpdate handles updating a retro action item
@@ -2,8 +2,10 @@ package retro
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
 
@@ -14,16 +16,21 @@ import (
 // CreateItem creates a retro item
 func (s *Service) CreateItem(ctx context.Context, RetroID string, UserID string, EventValue string) (any, []byte, error, bool) {
 	var rs struct {
-		Type    string `json:"type"`
-		Content string `json:"content"`
-		Phase   string `json:"phase"`
+		Type         string `json:"type"`
+		Content      string `json:"content"`
+		Phase        string `json:"phase"`
+		AuthorTag    string `json:"authorTag"`
+		AuthorSecret string `json:"authorSecret"`
 	}
 	err := json.Unmarshal([]byte(EventValue), &rs)
 	if err != nil {
 		return nil, nil, err, false
 	}
+	if !validAuthorToken(rs.AuthorTag) || !validAuthorToken(rs.AuthorSecret) {
+		return nil, nil, errors.New("INVALID_AUTHOR_TAG"), false
+	}
 
-	items, err := s.RetroService.CreateRetroItem(RetroID, UserID, rs.Type, rs.Content)
+	items, err := s.RetroService.CreateRetroItem(RetroID, UserID, rs.Type, rs.Content, rs.AuthorTag, rs.AuthorSecret)
 	if err != nil {
 		return nil, nil, err, false
 	}
@@ -193,16 +200,17 @@ func (s *Service) GroupItem(ctx context.Context, RetroID string, UserID string,
 // DeleteItem deletes a retro item
 func (s *Service) DeleteItem(ctx context.Context, RetroID string, UserID string, EventValue string) (any, []byte, error, bool) {
 	var rs struct {
-		ItemID string `json:"id"`
-		Phase  string `json:"phase"`
-		Type   string `json:"type"`
+		ItemID       string `json:"id"`
+		Phase        string `json:"phase"`
+		Type         string `json:"type"`
+		AuthorSecret string `json:"authorSecret"`
 	}
 	err := json.Unmarshal([]byte(EventValue), &rs)
 	if err != nil {
 		return nil, nil, err, false
 	}
 
-	items, err := s.RetroService.DeleteRetroItem(RetroID, UserID, rs.Type, rs.ItemID)
+	items, err := s.RetroService.DeleteRetroItem(RetroID, UserID, rs.Type, rs.ItemID, rs.AuthorSecret)
 	if err != nil {
 		return nil, nil, err, false
 	}
@@ -277,17 +285,47 @@ func (s *Service) GroupUserSubtractVote(ctx context.Context, RetroID string, Use
 	return nil, msg, nil, false
 }
 
+// HealthCheckScore handles a users anonymous score for a health check dimension,
+// only the response counts are sent to the retro, the results are revealed when the phase ends
+func (s *Service) HealthCheckScore(ctx context.Context, RetroID string, UserID string, EventValue string) (any, []byte, error, bool) {
+	var rs struct {
+		Dimension string `json:"dimension"`
+		Score     int    `json:"score"`
+	}
+	err := json.Unmarshal([]byte(EventValue), &rs)
+	if err != nil {
+		return nil, nil, err, false
+	}
+	if !validHealthCheckScore(rs.Score) {
+		return nil, nil, errors.New("INVALID_HEALTH_CHECK_SCORE"), false
+	}
+
+	results, err := s.RetroService.RetroHealthCheckScore(ctx, RetroID, UserID, rs.Dimension, rs.Score)
+	if err != nil {
+		return nil, nil, err, false
+	}
+
+	updatedResults, _ := json.Marshal(results)
+	msg := wshub.CreateSocketEvent("health_check_updated", string(updatedResults), "")
+
+	return nil, msg, nil, false
+}
+
 // CreateAction creates a retro action
 func (s *Service) CreateAction(ctx context.Context, RetroID string, UserID string, EventValue string) (any, []byte, error, bool) {
 	var rs struct {
 		Content string `json:"content"`
+		DueDate string `json:"dueDate"`
 	}
 	err := json.Unmarshal([]byte(EventValue), &rs)
 	if err != nil {
 		return nil, nil, err, false
 	}
+	if !validDueDate(rs.DueDate) {
+		return nil, nil, errors.New("INVALID_DUE_DATE"), false
+	}
 
-	items, err := s.RetroService.CreateRetroAction(RetroID, UserID, rs.Content)
+	items, err := s.RetroService.CreateRetroAction(RetroID, UserID, rs.Content, rs.DueDate)
 	if err != nil {
 		return nil, nil, err, false
 	}
@@ -303,14 +341,23 @@ func (s *Service) UpdateAction(ctx context.Context, RetroID string, UserID strin
 	var rs struct {
 		ActionID  string `json:"id"`
 		Completed bool   `json:"completed"`
+		Status    string `json:"status"`
 		Content   string `json:"content"`
+		DueDate   string `json:"dueDate"`
 	}
 	err := json.Unmarshal([]byte(EventValue), &rs)
 	if err != nil {
 		return nil, nil, err, false
 	}
+	status, err := actionStatus(rs.Status)
+	if err != nil {
+		return nil, nil, err, false
+	}
+	if !validDueDate(rs.DueDate) {
+		return nil, nil, errors.New("INVALID_DUE_DATE"), false
+	}
 
-	items, err := s.RetroService.UpdateRetroAction(RetroID, rs.ActionID, rs.Content, rs.Completed)
+	items, err := s.RetroService.UpdateRetroAction(RetroID, rs.ActionID, rs.Content, status, rs.Completed, rs.DueDate)
 	if err != nil {
 		return nil, nil, err, false
 	}
@@ -386,7 +433,34 @@ func (s *Service) DeleteAction(ctx context.Context, RetroID string, UserID strin
 	return nil, msg, nil, false
 }
 
-// AdvancePhase updates a retro phase
+// ReviewAction records the facilitator's review decision for an open action from the team's previous retros
+func (s *Service) ReviewAction(ctx context.Context, RetroID string, UserID string, EventValue string) (any, []byte, error, bool) {
+	var rs struct {
+		ActionID string `json:"id"`
+		Decision string `json:"decision"`
+	}
+	err := json.Unmarshal([]byte(EventValue), &rs)
+	if err != nil {
+		return nil, nil, err, false
+	}
+	switch rs.Decision {
+	case thunderdome.RetroActionReviewDone, thunderdome.RetroActionReviewKept, thunderdome.RetroActionReviewDropped:
+	default:
+		return nil, nil, errors.New("INVALID_REVIEW_DECISION"), false
+	}
+
+	actions, err := s.RetroService.ReviewRetroAction(ctx, RetroID, rs.ActionID, rs.Decision)
+	if err != nil {
+		return nil, nil, err, false
+	}
+
+	updatedActions, _ := json.Marshal(actions)
+	msg := wshub.CreateSocketEvent("review_actions_updated", string(updatedActions), "")
+
+	return nil, msg, nil, false
+}
+
+// AdvancePhase updates a retro phase, an empty phase advances to the next phase in the retro's phase sequence
 func (s *Service) AdvancePhase(ctx context.Context, RetroID string, UserID string, EventValue string) (any, []byte, error, bool) {
 	var rs struct {
 		Phase string `json:"phase"`
@@ -396,40 +470,39 @@ func (s *Service) AdvancePhase(ctx context.Context, RetroID string, UserID strin
 		return nil, nil, err, false
 	}
 
-	retro, err := s.RetroService.RetroAdvancePhase(RetroID, rs.Phase)
+	retro, err := s.RetroService.RetroAdvancePhase(ctx, RetroID, rs.Phase)
 	if err != nil {
 		return nil, nil, err, false
 	}
+	s.phaseTimer.RequestReload()
 
 	updatedItems, _ := json.Marshal(retro)
 	msg := wshub.CreateSocketEvent("phase_updated", string(updatedItems), "")
 
 	// if retro is completed send retro email to attendees
-	if rs.Phase == "completed" {
+	if retro.Phase == thunderdome.RetroPhaseCompleted {
 		go s.SendCompletedEmails(retro)
 	}
 
 	return nil, msg, nil, false
 }
 
-// PhaseTimeout advances a retro phase after time countdown
+// PhaseTimeout advances a retro phase after time countdown,
+// the phase is only advanced if its time limit has run out as the server phase timer normally advances it
 func (s *Service) PhaseTimeout(ctx context.Context, RetroID string, UserID string, EventValue string) (any, []byte, error, bool) {
-	var rs struct {
-		Phase string `json:"phase"`
-	}
-	err := json.Unmarshal([]byte(EventValue), &rs)
-	if err != nil {
-		return nil, nil, err, false
-	}
-
-	retro, err := s.RetroService.RetroAdvancePhase(RetroID, rs.Phase)
+	retro, err := s.RetroService.RetroPhaseTimeout(ctx, RetroID)
 	if err != nil {
 		return nil, nil, err, false
 	}
+	s.phaseTimer.RequestReload()
 
 	updatedItems, _ := json.Marshal(retro)
 	msg := wshub.CreateSocketEvent("phase_updated", string(updatedItems), "")
 
+	if retro.Phase == thunderdome.RetroPhaseCompleted {
+		go s.SendCompletedEmails(retro)
+	}
+
 	return nil, msg, nil, false
 }
 
@@ -443,10 +516,11 @@ func (s *Service) PhaseAllReady(ctx context.Context, RetroID string, UserID stri
 		return nil, nil, err, false
 	}
 
-	retro, err := s.RetroService.RetroAdvancePhase(RetroID, rs.Phase)
+	retro, err := s.RetroService.RetroAdvancePhase(ctx, RetroID, rs.Phase)
 	if err != nil {
 		return nil, nil, err, false
 	}
+	s.phaseTimer.RequestReload()
 
 	updatedItems, _ := json.Marshal(retro)
 	msg := wshub.CreateSocketEvent("phase_updated", string(updatedItems), "")
@@ -549,6 +623,7 @@ func (s *Service) EditRetro(ctx context.Context, RetroID string, UserID string,
 	if err != nil {
 		return nil, nil, err, false
 	}
+	s.phaseTimer.RequestReload()
 
 	updatedRetro, _ := json.Marshal(rb)
 	msg := wshub.CreateSocketEvent("retro_edited", string(updatedRetro), "")
@@ -596,3 +671,43 @@ func (s *Service) SendCompletedEmails(retro *thunderdome.Retro) {
 		}
 	}
 }
+
+// actionStatus validates an action's status, clients that don't send a status
+// leave it empty so the existing status is kept
+func actionStatus(status string) (string, error) {
+	switch status {
+	case "", thunderdome.RetroActionStatusOpen, thunderdome.RetroActionStatusInProgress,
+		thunderdome.RetroActionStatusDone, thunderdome.RetroActionStatusDropped:
+		return status, nil
+	default:
+		return "", errors.New("INVALID_ACTION_STATUS")
+	}
+}
+
+// validDueDate checks the action due date is empty or a YYYY-MM-DD date
+func validDueDate(dueDate string) bool {
+	if dueDate == "" {
+		return true
+	}
+	_, err := time.Parse(time.DateOnly, dueDate)
+
+	return err == nil
+}
+
+// validAuthorToken checks an anonymous item author tag or secret is empty or 32 to 64 hex characters
+func validAuthorToken(token string) bool {
+	if token == "" {
+		return true
+	}
+	if len(token) < 32 || len(token) > 64 {
+		return false
+	}
+	_, err := hex.DecodeString(token)
+
+	return err == nil
+}
+
+// validHealthCheckScore checks a health check score is from 1 to 5
+func validHealthCheckScore(score int) bool {
+	return score >= 1 && score <= 5
+}
@@ -0,0 +1,91 @@
+package retro
+
+import "testing"
+
+func TestActionStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty keeps the existing status", status: "", want: ""},
+		{name: "in progress", status: "in_progress", want: "in_progress"},
+		{name: "dropped", status: "dropped", want: "dropped"},
+		{name: "invalid status", status: "blocked", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := actionStatus(tt.status)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("actionStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("actionStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidDueDate(t *testing.T) {
+	tests := []struct {
+		dueDate string
+		want    bool
+	}{
+		{dueDate: "", want: true},
+		{dueDate: "2026-11-01", want: true},
+		{dueDate: "2026-13-01", want: false},
+		{dueDate: "11/01/2026", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dueDate, func(t *testing.T) {
+			if got := validDueDate(tt.dueDate); got != tt.want {
+				t.Errorf("validDueDate(%q) = %v, want %v", tt.dueDate, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidAuthorToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{name: "empty", token: "", want: true},
+		{name: "32 hex characters", token: "0123456789abcdef0123456789abcdef", want: true},
+		{name: "64 hex characters", token: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", want: true},
+		{name: "too short", token: "0123456789abcdef", want: false},
+		{name: "too long", token: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef00", want: false},
+		{name: "not hex", token: "zzzz456789abcdef0123456789abcdef", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validAuthorToken(tt.token); got != tt.want {
+				t.Errorf("validAuthorToken(%q) = %v, want %v", tt.token, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidHealthCheckScore(t *testing.T) {
+	tests := []struct {
+		score int
+		want  bool
+	}{
+		{score: 0, want: false},
+		{score: 1, want: true},
+		{score: 3, want: true},
+		{score: 5, want: true},
+		{score: 6, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := validHealthCheckScore(tt.score); got != tt.want {
+			t.Errorf("validHealthCheckScore(%d) = %v, want %v", tt.score, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package retro
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/deadline"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+const (
+	// how often phase deadlines are reloaded, picking up phase changes made on other instances
+	phaseTimerReloadInterval = 15 * time.Second
+	// how often loaded deadlines are checked for having run out
+	phaseTimerTickInterval = time.Second
+	// deadlines that ran out longer ago than this (e.g. while no instance was running) are not fired
+	phaseTimerLookback = 24 * time.Hour
+)
+
+// newPhaseTimer returns a scheduler that advances retros to the next phase once their phase time limit runs out
+func newPhaseTimer(svc *Service) *deadline.Scheduler[string] {
+	return deadline.New(deadline.Config[string]{
+		ReloadInterval: phaseTimerReloadInterval,
+		TickInterval:   phaseTimerTickInterval,
+		Load:           svc.loadPhaseDeadlines,
+		Fire:           svc.phaseTimeout,
+	})
+}
+
+// loadPhaseDeadlines gets the phase deadlines keyed by retro ID
+func (s *Service) loadPhaseDeadlines(ctx context.Context) (map[string]time.Time, error) {
+	deadlines, err := s.RetroService.GetRetroPhaseDeadlines(ctx, phaseTimerLookback)
+	if err != nil {
+		s.logger.Ctx(ctx).Error("retro phase timer load error", zap.Error(err))
+		return nil, err
+	}
+
+	retroDeadlines := make(map[string]time.Time, len(deadlines))
+	for _, pd := range deadlines {
+		retroDeadlines[pd.RetroID] = pd.Deadline
+	}
+
+	return retroDeadlines, nil
+}
+
+// phaseTimeout advances a retro whose phase deadline ran out,
+// the deadlines are reloaded after advancing as the next phase may also be timed
+func (s *Service) phaseTimeout(ctx context.Context, retroID string) bool {
+	retro, err := s.RetroService.RetroPhaseTimeout(ctx, retroID)
+	if err != nil {
+		// another instance (or a facilitator) already advanced the retro, or its deadline moved
+		if err.Error() != "RETRO_PHASE_NOT_EXPIRED" {
+			s.logger.Ctx(ctx).Error("retro phase timer advance error", zap.Error(err),
+				zap.String("retro_id", retroID))
+		}
+		return false
+	}
+
+	updatedRetro, _ := json.Marshal(retro)
+	msg := wshub.CreateSocketEvent("phase_updated", string(updatedRetro), "")
+	s.hub.Broadcast(wshub.Message{Data: msg, Room: retroID})
+
+	if retro.Phase == thunderdome.RetroPhaseCompleted {
+		go s.SendCompletedEmails(retro)
+	}
+
+	return true
+}
@@ -0,0 +1,108 @@
+package retro
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// fakeRetroDataSvc implements the phase timer's data service methods,
+// the embedded interface is nil so any other call panics
+type fakeRetroDataSvc struct {
+	RetroDataSvc
+	deadlines  []*thunderdome.RetroPhaseDeadline
+	timeoutErr error
+	timedOut   []string
+}
+
+func (f *fakeRetroDataSvc) GetRetroPhaseDeadlines(_ context.Context, _ time.Duration) ([]*thunderdome.RetroPhaseDeadline, error) {
+	return f.deadlines, nil
+}
+
+func (f *fakeRetroDataSvc) RetroPhaseTimeout(_ context.Context, retroID string) (*thunderdome.Retro, error) {
+	f.timedOut = append(f.timedOut, retroID)
+	if f.timeoutErr != nil {
+		return nil, f.timeoutErr
+	}
+	return &thunderdome.Retro{ID: retroID, Phase: "vote"}, nil
+}
+
+// publishedBackend records what the hub publishes to other instances
+type publishedBackend struct {
+	published chan wshub.Message
+}
+
+func (b *publishedBackend) Publish(_ context.Context, _ string, msg wshub.Message) error {
+	b.published <- msg
+	return nil
+}
+
+func (b *publishedBackend) Subscribe(_ string, _ func(wshub.Message)) {}
+
+func newPhaseTimerTestService(dataSvc RetroDataSvc) (*Service, *publishedBackend) {
+	logger := otelzap.New(zap.NewNop())
+	backend := &publishedBackend{published: make(chan wshub.Message, 1)}
+	hub := wshub.NewHub(logger, wshub.Config{Backend: backend, Channel: "retro"}, nil, nil, nil, nil)
+	go hub.Run()
+
+	return &Service{logger: logger, RetroService: dataSvc, hub: hub}, backend
+}
+
+func TestLoadPhaseDeadlines(t *testing.T) {
+	deadline := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	s, _ := newPhaseTimerTestService(&fakeRetroDataSvc{deadlines: []*thunderdome.RetroPhaseDeadline{
+		{RetroID: "retro-1", Phase: "brainstorm", Deadline: deadline},
+	}})
+
+	deadlines, err := s.loadPhaseDeadlines(context.Background())
+	if err != nil {
+		t.Fatalf("loadPhaseDeadlines() error = %v", err)
+	}
+	if len(deadlines) != 1 || !deadlines["retro-1"].Equal(deadline) {
+		t.Errorf("loadPhaseDeadlines() = %v, want retro-1 at %v", deadlines, deadline)
+	}
+}
+
+func TestPhaseTimeoutAdvancesRetro(t *testing.T) {
+	dataSvc := &fakeRetroDataSvc{}
+	s, backend := newPhaseTimerTestService(dataSvc)
+
+	if reload := s.phaseTimeout(context.Background(), "retro-1"); !reload {
+		t.Error("phaseTimeout() = false, want the deadlines reloaded for the next phase")
+	}
+
+	select {
+	case msg := <-backend.published:
+		if msg.Room != "retro-1" || !strings.Contains(string(msg.Data), "phase_updated") {
+			t.Errorf("broadcast %s to %s, want phase_updated to retro-1", msg.Data, msg.Room)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("phase_updated was not broadcast")
+	}
+}
+
+func TestPhaseTimeoutNotExpired(t *testing.T) {
+	// another instance or the facilitator advanced the retro first
+	dataSvc := &fakeRetroDataSvc{timeoutErr: errors.New("RETRO_PHASE_NOT_EXPIRED")}
+	s, backend := newPhaseTimerTestService(dataSvc)
+
+	if reload := s.phaseTimeout(context.Background(), "retro-1"); reload {
+		t.Error("phaseTimeout() = true, want no reload when the retro wasn't advanced")
+	}
+	if len(dataSvc.timedOut) != 1 {
+		t.Errorf("RetroPhaseTimeout called %d times, want 1", len(dataSvc.timedOut))
+	}
+
+	select {
+	case msg := <-backend.published:
+		t.Errorf("broadcast %s, want nothing broadcast", msg.Data)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
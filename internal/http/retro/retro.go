@@ -3,7 +3,9 @@ package retro
 import (
 	"context"
 	"net/http"
+	"time"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/deadline"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
@@ -24,6 +26,9 @@ type Config struct {
 
 	// Websocket Subdomain (for Websocket origin check)
 	WebsocketSubdomain string
+
+	// Broadcast backend for fanning out events to other instances
+	BroadcastBackend wshub.Backend
 }
 
 type AuthDataSvc interface {
@@ -44,25 +49,29 @@ type RetroDataSvc interface {
 	RetroFacilitatorRemove(retroID string, userID string) ([]string, error)
 	RetroRetreatUser(retroID string, userID string) []*thunderdome.RetroUser
 	RetroAbandon(retroID string, userID string) ([]*thunderdome.RetroUser, error)
-	RetroAdvancePhase(retroID string, phase string) (*thunderdome.Retro, error)
+	RetroAdvancePhase(ctx context.Context, retroID string, phase string) (*thunderdome.Retro, error)
+	GetRetroPhaseDeadlines(ctx context.Context, lookback time.Duration) ([]*thunderdome.RetroPhaseDeadline, error)
+	RetroPhaseTimeout(ctx context.Context, retroID string) (*thunderdome.Retro, error)
 	RetroDelete(retroID string) error
 	GetRetroUserActiveStatus(retroID string, userID string) error
 	GetRetroFacilitatorCode(retroID string) (string, error)
 	MarkUserReady(retroID string, userID string) ([]string, error)
 	UnmarkUserReady(retroID string, userID string) ([]string, error)
 
-	CreateRetroAction(retroID string, userID string, content string) ([]*thunderdome.RetroAction, error)
-	UpdateRetroAction(retroID string, actionID string, content string, completed bool) (Actions []*thunderdome.RetroAction, DeleteError error)
+	CreateRetroAction(retroID string, userID string, content string, dueDate string) ([]*thunderdome.RetroAction, error)
+	UpdateRetroAction(retroID string, actionID string, content string, status string, completed bool, dueDate string) (Actions []*thunderdome.RetroAction, DeleteError error)
+	ReviewRetroAction(ctx context.Context, retroID string, actionID string, decision string) ([]*thunderdome.RetroAction, error)
 	DeleteRetroAction(retroID string, userID string, actionID string) ([]*thunderdome.RetroAction, error)
 	RetroActionAssigneeAdd(retroID string, actionID string, userID string) ([]*thunderdome.RetroAction, error)
 	RetroActionAssigneeDelete(retroID string, actionID string, userID string) ([]*thunderdome.RetroAction, error)
 
-	CreateRetroItem(retroID string, userID string, itemType string, content string) ([]*thunderdome.RetroItem, error)
+	CreateRetroItem(retroID string, userID string, itemType string, content string, authorTag string, authorSecret string) ([]*thunderdome.RetroItem, error)
 	GroupRetroItem(retroID string, itemId string, groupId string) (thunderdome.RetroItem, error)
-	DeleteRetroItem(retroID string, userID string, itemType string, itemID string) ([]*thunderdome.RetroItem, error)
+	DeleteRetroItem(retroID string, userID string, itemType string, itemID string, authorSecret string) ([]*thunderdome.RetroItem, error)
 	GroupNameChange(retroID string, groupID string, name string) (thunderdome.RetroGroup, error)
 	GroupUserVote(retroID string, groupID string, userID string) ([]*thunderdome.RetroVote, error)
 	GroupUserSubtractVote(retroID string, groupID string, userID string) ([]*thunderdome.RetroVote, error)
+	RetroHealthCheckScore(ctx context.Context, retroID string, userID string, dimension string, score int) ([]*thunderdome.RetroHealthCheckResult, error)
 	ItemCommentAdd(retroID string, itemID string, userID string, comment string) ([]*thunderdome.RetroItem, error)
 	ItemCommentEdit(retroID string, commentID string, comment string) ([]*thunderdome.RetroItem, error)
 	ItemCommentDelete(retroID string, commentID string) ([]*thunderdome.RetroItem, error)
@@ -92,6 +101,7 @@ type Service struct {
 	TemplateService       RetroTemplateDataSvc
 	EmailService          EmailService
 	hub                   *wshub.Hub
+	phaseTimer            *deadline.Scheduler[string]
 }
 
 // New returns a new retro with websocket hub/client and event handlers
@@ -122,6 +132,8 @@ func New(
 		WriteWaitSec:       config.WriteWaitSec,
 		PongWaitSec:        config.PongWaitSec,
 		PingPeriodSec:      config.PingPeriodSec,
+		Backend:            config.BroadcastBackend,
+		Channel:            "retro",
 	}, map[string]func(context.Context, string, string, string) (any, []byte, error, bool){
 		"create_item":            s.CreateItem,
 		"user_ready":             s.UserMarkReady,
@@ -130,6 +142,7 @@ func New(
 		"group_name_change":      s.GroupNameChange,
 		"group_vote":             s.GroupUserVote,
 		"group_vote_subtract":    s.GroupUserSubtractVote,
+		"health_check_score":     s.HealthCheckScore,
 		"delete_item":            s.DeleteItem,
 		"item_comment_add":       s.ItemCommentAdd,
 		"item_comment_edit":      s.ItemCommentEdit,
@@ -139,6 +152,7 @@ func New(
 		"create_action":          s.CreateAction,
 		"update_action":          s.UpdateAction,
 		"delete_action":          s.DeleteAction,
+		"review_action":          s.ReviewAction,
 		"action_assignee_add":    s.ActionAddAssignee,
 		"action_assignee_remove": s.ActionRemoveAssignee,
 		"advance_phase":          s.AdvancePhase,
@@ -157,6 +171,7 @@ func New(
 			"remove_facilitator": {},
 			"edit_retro":         {},
 			"concede_retro":      {},
+			"review_action":      {},
 			"phase_time_ran_out": {},
 			"phase_all_ready":    {},
 		},
@@ -166,5 +181,8 @@ func New(
 
 	go s.hub.Run()
 
+	s.phaseTimer = newPhaseTimer(s)
+	go s.phaseTimer.Run(context.Background())
+
 	return s
 }
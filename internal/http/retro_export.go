@@ -0,0 +1,405 @@
+package http
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+type retroExportColumn struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+}
+
+type retroExportComment struct {
+	UserName string `json:"userName"`
+	Comment  string `json:"comment"`
+}
+
+type retroExportReaction struct {
+	Reaction string `json:"reaction"`
+	Count    int    `json:"count"`
+}
+
+type retroExportItem struct {
+	Content   string                `json:"content"`
+	Type      string                `json:"type"`
+	Column    string                `json:"column"`
+	Comments  []retroExportComment  `json:"comments"`
+	Reactions []retroExportReaction `json:"reactions"`
+}
+
+type retroExportGroup struct {
+	ID    string            `json:"id"`
+	Name  string            `json:"name"`
+	Votes int               `json:"votes"`
+	Items []retroExportItem `json:"items"`
+}
+
+type retroExportAction struct {
+	Content   string   `json:"content"`
+	Status    string   `json:"status"`
+	DueDate   string   `json:"dueDate"`
+	Completed bool     `json:"completed"`
+	Assignees []string `json:"assignees"`
+}
+
+type retroExport struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	TeamName    string              `json:"teamName,omitempty"`
+	Template    string              `json:"template"`
+	CreatedDate string              `json:"createdDate"`
+	ExportedAt  time.Time           `json:"exportedAt"`
+	Columns     []retroExportColumn `json:"columns"`
+	Groups      []retroExportGroup  `json:"groups"`
+	ActionItems []retroExportAction `json:"actionItems"`
+}
+
+// buildRetroExport organizes the retro's items into their groups ordered by votes, items within a group
+// follow the template column order, item authors are never included so anonymous retros stay anonymous
+func buildRetroExport(retro *thunderdome.Retro, exportedAt time.Time) *retroExport {
+	export := &retroExport{
+		ID:          retro.ID,
+		Name:        retro.Name,
+		TeamName:    retro.TeamName,
+		Template:    retro.Template.Name,
+		CreatedDate: retro.CreatedDate,
+		ExportedAt:  exportedAt,
+		Columns:     make([]retroExportColumn, 0),
+		Groups:      make([]retroExportGroup, 0),
+		ActionItems: make([]retroExportAction, 0, len(retro.ActionItems)),
+	}
+
+	columnOrder := make(map[string]int)
+	columnLabels := make(map[string]string)
+	if retro.Template.Format != nil {
+		for i, col := range retro.Template.Format.Columns {
+			export.Columns = append(export.Columns, retroExportColumn{Name: col.Name, Label: col.Label})
+			columnOrder[col.Name] = i
+			columnLabels[col.Name] = col.Label
+		}
+	}
+
+	userNames := make(map[string]string, len(retro.Users))
+	for _, u := range retro.Users {
+		userNames[u.ID] = u.Name
+	}
+
+	groupVotes := make(map[string]int)
+	for _, v := range retro.Votes {
+		groupVotes[v.GroupID] += v.Count
+	}
+
+	groupItems := make(map[string][]retroExportItem)
+	for _, item := range retro.Items {
+		ei := retroExportItem{
+			Content:   item.Content,
+			Type:      item.Type,
+			Column:    item.Type,
+			Comments:  make([]retroExportComment, 0, len(item.Comments)),
+			Reactions: make([]retroExportReaction, 0),
+		}
+		if label, ok := columnLabels[item.Type]; ok {
+			ei.Column = label
+		}
+		for _, c := range item.Comments {
+			name, ok := userNames[c.UserID]
+			if !ok {
+				name = c.UserID
+			}
+			ei.Comments = append(ei.Comments, retroExportComment{UserName: name, Comment: c.Comment})
+		}
+		reactionIndex := make(map[string]int)
+		for _, re := range item.Reactions {
+			i, ok := reactionIndex[re.Reaction]
+			if !ok {
+				reactionIndex[re.Reaction] = len(ei.Reactions)
+				ei.Reactions = append(ei.Reactions, retroExportReaction{Reaction: re.Reaction, Count: 1})
+				continue
+			}
+			ei.Reactions[i].Count++
+		}
+		groupItems[item.GroupID] = append(groupItems[item.GroupID], ei)
+	}
+
+	for _, group := range retro.Groups {
+		items := groupItems[group.ID]
+		// every item starts in its own group, so groups emptied while grouping are left out
+		if len(items) == 0 {
+			continue
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			return columnOrder[items[i].Type] < columnOrder[items[j].Type]
+		})
+		export.Groups = append(export.Groups, retroExportGroup{
+			ID:    group.ID,
+			Name:  group.Name,
+			Votes: groupVotes[group.ID],
+			Items: items,
+		})
+	}
+	sort.SliceStable(export.Groups, func(i, j int) bool {
+		return export.Groups[i].Votes > export.Groups[j].Votes
+	})
+
+	for _, action := range retro.ActionItems {
+		ea := retroExportAction{
+			Content:   action.Content,
+			Status:    action.Status,
+			DueDate:   action.DueDate,
+			Completed: action.Completed,
+			Assignees: make([]string, 0, len(action.Assignees)),
+		}
+		for _, a := range action.Assignees {
+			ea.Assignees = append(ea.Assignees, a.Name)
+		}
+		export.ActionItems = append(export.ActionItems, ea)
+	}
+
+	return export
+}
+
+// groupTitle is the group's name, or a placeholder when the group was never named
+func (g retroExportGroup) groupTitle() string {
+	if g.Name != "" {
+		return g.Name
+	}
+	return "Untitled group"
+}
+
+// markdownLine keeps multi-line content inside its list item by turning newlines into indented line breaks
+func markdownLine(content string, indent string) string {
+	content = strings.ReplaceAll(strings.TrimSpace(content), "\r\n", "\n")
+	return strings.ReplaceAll(content, "\n", "  \n"+indent)
+}
+
+// writeMarkdown writes the export as a Markdown document with a section per group and the action items as a task list
+func (e *retroExport) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", e.Name)
+	if e.TeamName != "" {
+		fmt.Fprintf(&b, "**Team:** %s  \n", e.TeamName)
+	}
+	if e.Template != "" {
+		fmt.Fprintf(&b, "**Template:** %s  \n", e.Template)
+	}
+	fmt.Fprintf(&b, "**Exported:** %s\n\n", e.ExportedAt.UTC().Format(time.RFC3339))
+
+	b.WriteString("## Feedback\n\n")
+	if len(e.Groups) == 0 {
+		b.WriteString("_No feedback items_\n\n")
+	}
+	for _, g := range e.Groups {
+		fmt.Fprintf(&b, "### %s (%d %s)\n\n", g.groupTitle(), g.Votes, pluralize(g.Votes, "vote", "votes"))
+		for _, item := range g.Items {
+			fmt.Fprintf(&b, "- **%s:** %s\n", item.Column, markdownLine(item.Content, "  "))
+			if len(item.Reactions) > 0 {
+				reactions := make([]string, 0, len(item.Reactions))
+				for _, re := range item.Reactions {
+					reactions = append(reactions, fmt.Sprintf("%s %d", re.Reaction, re.Count))
+				}
+				fmt.Fprintf(&b, "  - Reactions: %s\n", strings.Join(reactions, " "))
+			}
+			for _, c := range item.Comments {
+				fmt.Fprintf(&b, "  - %s: %s\n", c.UserName, markdownLine(c.Comment, "    "))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Action Items\n\n")
+	if len(e.ActionItems) == 0 {
+		b.WriteString("_No action items_\n")
+	}
+	for _, a := range e.ActionItems {
+		check := " "
+		if a.Completed {
+			check = "x"
+		}
+		fmt.Fprintf(&b, "- [%s] %s", check, markdownLine(a.Content, "  "))
+		if len(a.Assignees) > 0 {
+			fmt.Fprintf(&b, " (assigned to %s)", strings.Join(a.Assignees, ", "))
+		}
+		if a.DueDate != "" {
+			fmt.Fprintf(&b, " due %s", a.DueDate)
+		}
+		if a.Status != "" && a.Status != thunderdome.RetroActionStatusOpen {
+			fmt.Fprintf(&b, " _%s_", strings.ReplaceAll(a.Status, "_", " "))
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func pluralize(count int, singular string, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
+
+var retroExportHTMLTemplate = template.Must(template.New("retroExport").Funcs(template.FuncMap{
+	"status": func(status string) string {
+		return strings.ReplaceAll(status, "_", " ")
+	},
+	"pluralize": pluralize,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Name }}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2937; max-width: 960px; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; }
+h1 { margin-bottom: 0.25rem; }
+.meta { color: #6b7280; margin-bottom: 2rem; }
+.group { border: 1px solid #d1d5db; border-radius: 6px; padding: 0.75rem 1rem; margin-bottom: 1rem; break-inside: avoid; page-break-inside: avoid; }
+.group h3 { margin: 0 0 0.5rem; }
+.votes { color: #6b7280; font-weight: normal; font-size: 0.9em; }
+.column { font-weight: 600; }
+.content { white-space: pre-wrap; }
+.reactions, .comments { color: #4b5563; font-size: 0.9em; }
+ul { margin: 0.25rem 0; }
+table { width: 100%; border-collapse: collapse; }
+th, td { text-align: left; border-bottom: 1px solid #e5e7eb; padding: 0.4rem; vertical-align: top; }
+tr { break-inside: avoid; page-break-inside: avoid; }
+@media print { body { margin: 0; max-width: none; } @page { margin: 1.5cm; } }
+</style>
+</head>
+<body>
+<h1>{{ .Name }}</h1>
+<div class="meta">
+{{- if .TeamName }}Team: {{ .TeamName }} &middot; {{ end -}}
+{{- if .Template }}Template: {{ .Template }} &middot; {{ end -}}
+Exported: {{ .ExportedAt.UTC.Format "2006-01-02 15:04 MST" }}
+</div>
+<h2>Feedback</h2>
+{{- range .Groups }}
+<div class="group">
+<h3>{{ if .Name }}{{ .Name }}{{ else }}Untitled group{{ end }} <span class="votes">{{ .Votes }} {{ pluralize .Votes "vote" "votes" }}</span></h3>
+<ul>
+{{- range .Items }}
+<li><span class="column">{{ .Column }}:</span> <span class="content">{{ .Content }}</span>
+{{- if .Reactions }}
+<div class="reactions">{{ range .Reactions }}{{ .Reaction }} {{ .Count }} {{ end }}</div>
+{{- end }}
+{{- if .Comments }}
+<ul class="comments">
+{{- range .Comments }}
+<li><strong>{{ .UserName }}:</strong> <span class="content">{{ .Comment }}</span></li>
+{{- end }}
+</ul>
+{{- end }}
+</li>
+{{- end }}
+</ul>
+</div>
+{{- else }}
+<p><em>No feedback items</em></p>
+{{- end }}
+<h2>Action Items</h2>
+{{- if .ActionItems }}
+<table>
+<thead><tr><th>Action</th><th>Assignees</th><th>Due</th><th>Status</th></tr></thead>
+<tbody>
+{{- range .ActionItems }}
+<tr><td class="content">{{ .Content }}</td><td>{{ range $i, $a := .Assignees }}{{ if $i }}, {{ end }}{{ $a }}{{ end }}</td><td>{{ .DueDate }}</td><td>{{ status .Status }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- else }}
+<p><em>No action items</em></p>
+{{- end }}
+</body>
+</html>
+`))
+
+// writeHTML writes the export as a standalone print friendly HTML document, ready to be saved as PDF from the browser
+func (e *retroExport) writeHTML(w io.Writer) error {
+	return retroExportHTMLTemplate.Execute(w, e)
+}
+
+// handleRetroExport exports the retro's feedback and action items
+//
+//	@Summary		Export Retro
+//	@Description	Exports the retro template columns, grouped feedback items with vote counts, comments and reactions,
+//	@Description	and action items with their assignees, item authors are never included
+//	@Tags			retro
+//	@Produce		json
+//	@Produce		text/markdown
+//	@Produce		text/html
+//	@Param			retroId	path	string	true	"the retro ID to export"
+//	@Param			format	query	string	false	"the export format, defaults to markdown"	Enums(markdown, html, json)
+//	@Success		200		object	standardJsonResponse{data=retroExport}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		404		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/retros/{retroId}/export [get]
+func (s *Service) handleRetroExport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		retroID := r.PathValue("retroId")
+		idErr := validate.Var(retroID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userType := ctx.Value(contextKeyUserType).(string)
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = "markdown"
+		}
+		if format != "markdown" && format != "html" && format != "json" {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "format must be one of markdown, html, json"))
+			return
+		}
+
+		retro, err := s.RetroDataSvc.RetroGetByID(retroID, sessionUserID)
+		if err != nil {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "RETRO_NOT_FOUND"))
+			return
+		}
+
+		// don't allow exporting retro details if retro has JoinCode and user hasn't joined yet
+		if retro.JoinCode != "" {
+			userErr := s.RetroDataSvc.GetRetroUserActiveStatus(retroID, sessionUserID)
+			if userErr != nil && userErr.Error() != "DUPLICATE_RETRO_USER" && userType != thunderdome.AdminUserType {
+				s.Failure(w, r, http.StatusForbidden, Errorf(EUNAUTHORIZED, "USER_MUST_JOIN_RETRO"))
+				return
+			}
+		}
+
+		export := buildRetroExport(retro, time.Now().UTC())
+
+		if format == "json" {
+			s.Success(w, r, http.StatusOK, export, nil)
+			return
+		}
+
+		write, contentType, ext := export.writeMarkdown, "text/markdown; charset=utf-8", "md"
+		if format == "html" {
+			write, contentType, ext = export.writeHTML, "text/html; charset=utf-8", "html"
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"retro-%s.%s\"", retroID, ext))
+		w.WriteHeader(http.StatusOK)
+		if err := write(w); err != nil {
+			s.Logger.Ctx(ctx).Error("handleRetroExport error", zap.Error(err),
+				zap.String("retro_id", retroID), zap.String("session_user_id", sessionUserID))
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func TestBuildRetroExport(t *testing.T) {
+	retro := &thunderdome.Retro{
+		ID:   "retro",
+		Name: "Sprint 42 Retro",
+		Template: thunderdome.RetroTemplate{
+			Name: "Went Well / Improve",
+			Format: &thunderdome.RetroTemplateFormat{
+				Columns: []thunderdome.RetroTemplateFormatColumn{
+					{Name: "worked", Label: "Went Well"},
+					{Name: "improve", Label: "Needs Improvement"},
+				},
+			},
+		},
+		Users: []*thunderdome.RetroUser{
+			{ID: "u1", Name: "Ada"},
+			{ID: "u2", Name: "Grace"},
+		},
+		Groups: []*thunderdome.RetroGroup{
+			{ID: "g1", Name: "Deploys"},
+			{ID: "g2", Name: ""},
+			{ID: "g3", Name: "Emptied"},
+		},
+		Items: []*thunderdome.RetroItem{
+			{ID: "i1", UserID: "u1", GroupID: "g1", Type: "improve", Content: "Deploys are slow"},
+			{ID: "i2", UserID: "u2", GroupID: "g1", Type: "worked", Content: "No rollbacks",
+				Comments: []*thunderdome.RetroItemComment{{UserID: "u1", Comment: "Agreed"}},
+				Reactions: []*thunderdome.RetroItemReaction{
+					{UserID: "u1", Reaction: "🎉"},
+					{UserID: "u2", Reaction: "👍"},
+					{UserID: "u2", Reaction: "🎉"},
+				},
+			},
+			{ID: "i3", UserID: "u1", GroupID: "g2", Type: "worked", Content: "Pairing"},
+		},
+		Votes: []*thunderdome.RetroVote{
+			{UserID: "u1", GroupID: "g2", Count: 1},
+			{UserID: "u1", GroupID: "g1", Count: 2},
+			{UserID: "u2", GroupID: "g1", Count: 1},
+		},
+		ActionItems: []*thunderdome.RetroAction{
+			{Content: "Cache builds", Status: "in_progress", DueDate: "2026-11-01",
+				Assignees: []*thunderdome.User{{Name: "Grace"}}},
+			{Content: "Drop flaky test", Status: "done", Completed: true},
+		},
+	}
+
+	export := buildRetroExport(retro, time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{name: "columns", got: len(export.Columns), want: 2},
+		{name: "empty groups left out", got: len(export.Groups), want: 2},
+		{name: "groups ordered by votes", got: export.Groups[0].ID, want: "g1"},
+		{name: "group votes summed", got: export.Groups[0].Votes, want: 3},
+		{name: "items follow column order", got: export.Groups[0].Items[0].Content, want: "No rollbacks"},
+		{name: "item column label", got: export.Groups[0].Items[0].Column, want: "Went Well"},
+		{name: "comment user name", got: export.Groups[0].Items[0].Comments[0].UserName, want: "Ada"},
+		{name: "reactions counted", got: export.Groups[0].Items[0].Reactions[0], want: retroExportReaction{Reaction: "🎉", Count: 2}},
+		{name: "action assignees", got: export.ActionItems[0].Assignees[0], want: "Grace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+
+	var md bytes.Buffer
+	if err := export.writeMarkdown(&md); err != nil {
+		t.Fatalf("writeMarkdown() error = %v", err)
+	}
+	for _, want := range []string{
+		"# Sprint 42 Retro\n",
+		"### Deploys (3 votes)\n",
+		"### Untitled group (1 vote)\n",
+		"- **Went Well:** No rollbacks\n  - Reactions: 🎉 2 👍 1\n  - Ada: Agreed\n",
+		"- [ ] Cache builds (assigned to Grace) due 2026-11-01 _in progress_\n",
+		"- [x] Drop flaky test _done_\n",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown missing %q, got:\n%s", want, md.String())
+		}
+	}
+
+	var html bytes.Buffer
+	if err := export.writeHTML(&html); err != nil {
+		t.Fatalf("writeHTML() error = %v", err)
+	}
+	if !strings.Contains(html.String(), "<h3>Deploys <span class=\"votes\">3 votes</span></h3>") {
+		t.Errorf("html missing group heading, got:\n%s", html.String())
+	}
+	for _, author := range []string{"u1", "u2"} {
+		if strings.Contains(md.String(), author) || strings.Contains(html.String(), author) {
+			t.Errorf("export includes item author %q", author)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/retrophase"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 )
 
@@ -17,6 +18,12 @@ type retroTemplateFormatRequestBody struct {
 		Color string `json:"color" validate:"omitempty,oneof=red green blue yellow purple orange teal"`
 		Icon  string `json:"icon" validate:"omitempty,oneof=smiley frown angry question"`
 	} `json:"columns" validate:"required,min=2,max=5,dive,required"`
+	Phases []struct {
+		Name         string   `json:"name" validate:"required,oneof=review checkin healthcheck intro brainstorm group vote action completed"`
+		TimeLimitMin int      `json:"timeLimitMin" validate:"min=0,max=59"`
+		Prompt       string   `json:"prompt" validate:"max=256"`
+		Dimensions   []string `json:"dimensions" validate:"omitempty,max=10,dive,required,max=64"`
+	} `json:"phases" validate:"omitempty,max=9,dive"`
 }
 
 type retroTemplateRequestBody struct {
@@ -95,6 +102,9 @@ func (s *Service) handleRetroTemplateCreate() http.HandlerFunc {
 		}
 
 		inputErr := validate.Struct(template)
+		if inputErr == nil {
+			inputErr = retrophase.Validate(retroTemplateBuildFormatFromRequest(template.Format).Phases)
+		}
 		if inputErr != nil {
 			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
 			return
@@ -163,6 +173,9 @@ func (s *Service) handleRetroTemplateUpdate() http.HandlerFunc {
 		}
 
 		inputErr := validate.Struct(template)
+		if inputErr == nil {
+			inputErr = retrophase.Validate(retroTemplateBuildFormatFromRequest(template.Format).Phases)
+		}
 		if inputErr != nil {
 			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
 			return
@@ -425,6 +438,9 @@ func (s *Service) handleTeamRetroTemplateCreate() http.HandlerFunc {
 		}
 
 		inputErr := validate.Struct(template)
+		if inputErr == nil {
+			inputErr = retrophase.Validate(retroTemplateBuildFormatFromRequest(template.Format).Phases)
+		}
 		if inputErr != nil {
 			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
 			return
@@ -492,6 +508,9 @@ func (s *Service) handleOrganizationRetroTemplateCreate() http.HandlerFunc {
 		}
 
 		inputErr := validate.Struct(template)
+		if inputErr == nil {
+			inputErr = retrophase.Validate(retroTemplateBuildFormatFromRequest(template.Format).Phases)
+		}
 		if inputErr != nil {
 			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
 			return
@@ -567,6 +586,9 @@ func (s *Service) handleTeamRetroTemplateUpdate() http.HandlerFunc {
 		}
 
 		inputErr := validate.Struct(template)
+		if inputErr == nil {
+			inputErr = retrophase.Validate(retroTemplateBuildFormatFromRequest(template.Format).Phases)
+		}
 		if inputErr != nil {
 			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
 			return
@@ -641,6 +663,9 @@ func (s *Service) handleOrganizationRetroTemplateUpdate() http.HandlerFunc {
 		}
 
 		inputErr := validate.Struct(template)
+		if inputErr == nil {
+			inputErr = retrophase.Validate(retroTemplateBuildFormatFromRequest(template.Format).Phases)
+		}
 		if inputErr != nil {
 			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
 			return
@@ -0,0 +1,403 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+const (
+	scimSchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimSchemaGroup        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimSchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimSchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	scimSchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimContentType        = "application/scim+json"
+	// scimMaxResults is the most resources returned in one list response
+	scimMaxResults = 100
+)
+
+type scimMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+type scimListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    any      `json:"Resources"`
+}
+
+type scimErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+type scimPatchOperation struct {
+	Op    string          `json:"op" validate:"required"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+type scimPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []scimPatchOperation `json:"Operations" validate:"required,min=1,dive"`
+}
+
+type scimTokenRequestBody struct {
+	Name string `json:"name" validate:"required,min=1,max=256"`
+}
+
+// scimRespond writes a SCIM resource response
+func (s *Service) scimRespond(w http.ResponseWriter, code int, body any) {
+	response, _ := json.Marshal(body)
+
+	w.Header().Set("Content-Type", scimContentType)
+	w.WriteHeader(code)
+	w.Write(response)
+}
+
+// scimFailure writes a SCIM error response, the scimType is optional
+func (s *Service) scimFailure(w http.ResponseWriter, code int, scimType string, detail string) {
+	s.scimRespond(w, code, scimErrorResponse{
+		Schemas:  []string{scimSchemaError},
+		Status:   strconv.Itoa(code),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+// scimDecode reads and validates a SCIM request body, writing the failure and returning false when invalid
+func (s *Service) scimDecode(w http.ResponseWriter, r *http.Request, v any) bool {
+	body, bodyErr := io.ReadAll(r.Body)
+	if bodyErr != nil {
+		s.scimFailure(w, http.StatusBadRequest, "invalidSyntax", bodyErr.Error())
+		return false
+	}
+
+	if jsonErr := json.Unmarshal(body, v); jsonErr != nil {
+		s.scimFailure(w, http.StatusBadRequest, "invalidSyntax", jsonErr.Error())
+		return false
+	}
+
+	if inputErr := validate.Struct(v); inputErr != nil {
+		s.scimFailure(w, http.StatusBadRequest, "invalidValue", inputErr.Error())
+		return false
+	}
+
+	return true
+}
+
+// scimLocation returns the absolute URL of a SCIM resource
+func (s *Service) scimLocation(resourceType string, id string) string {
+	return s.appOrigin() + s.Config.PathPrefix + "/api/scim/v2/" + resourceType + "/" + id
+}
+
+// scimList builds a list response of a page of resources
+func scimList(resources any, total int, startIndex int, count int) scimListResponse {
+	return scimListResponse{
+		Schemas:      []string{scimSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: count,
+		Resources:    resources,
+	}
+}
+
+// scimPagination gets the 1-based startIndex and count query params of a list request
+// as a limit and offset, count is capped at scimMaxResults
+func scimPagination(r *http.Request) (startIndex int, limit int, offset int) {
+	query := r.URL.Query()
+
+	startIndex, err := strconv.Atoi(query.Get("startIndex"))
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+
+	limit, err = strconv.Atoi(query.Get("count"))
+	if err != nil || limit > scimMaxResults {
+		limit = scimMaxResults
+	}
+	if limit < 0 {
+		limit = 0
+	}
+
+	return startIndex, limit, startIndex - 1
+}
+
+// parseSCIMFilter parses a SCIM filter of the form `attribute eq "value"`, the only filter identity providers
+// use to find existing resources. The attribute is returned lower case as SCIM attributes are case-insensitive
+func parseSCIMFilter(filter string) (string, string, error) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return "", "", nil
+	}
+
+	attribute, rest, found := strings.Cut(filter, " ")
+	if !found {
+		return "", "", errors.New("unsupported filter")
+	}
+	operator, value, found := strings.Cut(strings.TrimSpace(rest), " ")
+	if !found || !strings.EqualFold(operator, "eq") {
+		return "", "", errors.New("unsupported filter operator, only eq is supported")
+	}
+
+	value, err := strconv.Unquote(strings.TrimSpace(value))
+	if err != nil {
+		return "", "", errors.New("filter value must be a quoted string")
+	}
+
+	return strings.ToLower(attribute), value, nil
+}
+
+// scimBool decodes a SCIM boolean value, some identity providers send booleans as strings e.g. "False"
+func scimBool(raw json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b, nil
+	}
+
+	var str string
+	if err := json.Unmarshal(raw, &str); err != nil {
+		return false, fmt.Errorf("invalid boolean value %s", raw)
+	}
+
+	return strconv.ParseBool(strings.ToLower(str))
+}
+
+// scimString decodes a SCIM string value
+func scimString(raw json.RawMessage) (string, error) {
+	var str string
+	if err := json.Unmarshal(raw, &str); err != nil {
+		return "", fmt.Errorf("invalid string value %s", raw)
+	}
+
+	return str, nil
+}
+
+// scimOnly validates the SCIM bearer token of the request, adding the token's organization to the context
+func (s *Service) scimOnly(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.Config.OrganizationsEnabled {
+			s.scimFailure(w, http.StatusNotFound, "", "ORGANIZATIONS_DISABLED")
+			return
+		}
+		ctx := r.Context()
+
+		scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			s.scimFailure(w, http.StatusUnauthorized, "", "INVALID_SCIM_TOKEN")
+			return
+		}
+
+		orgID, err := s.SCIMDataSvc.TokenOrganization(ctx, strings.TrimSpace(token))
+		if err != nil && err.Error() == "INVALID_SCIM_TOKEN" {
+			s.scimFailure(w, http.StatusUnauthorized, "", "INVALID_SCIM_TOKEN")
+			return
+		} else if err != nil {
+			s.Logger.Ctx(ctx).Error("scimOnly error", zap.Error(err))
+			s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+
+		ctx = context.WithValue(ctx, contextKeySCIMOrgID, orgID)
+
+		h(w, r.WithContext(ctx))
+	}
+}
+
+// recordSCIMAuditEvent records a change made by the organization's identity provider
+func (s *Service) recordSCIMAuditEvent(r *http.Request, action string, targetType string, targetID string, metadata map[string]string) {
+	orgID := r.Context().Value(contextKeySCIMOrgID).(string)
+
+	s.recordAuditEvent(r, thunderdome.AuditEvent{
+		Action:         action,
+		ActorType:      thunderdome.AuditActorTypeSCIM,
+		TargetType:     targetType,
+		TargetID:       targetID,
+		OrganizationID: &orgID,
+		Metadata:       metadata,
+	})
+}
+
+// handleOrganizationSCIMTokens gets a list of the organization's SCIM tokens
+//
+//	@Summary		Get Organization SCIM Tokens
+//	@Description	Get a list of the tokens the organization's identity provider can use to call the SCIM API
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId	path	string	true	"organization id"
+//	@Success		200		object	standardJsonResponse{data=[]thunderdome.SCIMToken}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/scim-tokens [get]
+func (s *Service) handleOrganizationSCIMTokens() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.Config.OrganizationsEnabled {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ORGANIZATIONS_DISABLED"))
+			return
+		}
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		orgID := r.PathValue("orgId")
+		idErr := validate.Var(orgID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		tokens, err := s.SCIMDataSvc.TokenList(ctx, orgID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleOrganizationSCIMTokens error", zap.Error(err),
+				zap.String("organization_id", orgID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, tokens, nil)
+	}
+}
+
+// handleOrganizationSCIMTokenCreate handles creating an organization SCIM token
+//
+//	@Summary		Create Organization SCIM Token
+//	@Description	Creates a token for the organization's identity provider to call the SCIM API with,
+//	@Description	the token is only returned once
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId	path	string					true	"organization id"
+//	@Param			token	body	scimTokenRequestBody	true	"new scim token object"
+//	@Success		200		object	standardJsonResponse{data=thunderdome.SCIMToken}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/scim-tokens [post]
+func (s *Service) handleOrganizationSCIMTokenCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.Config.OrganizationsEnabled {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ORGANIZATIONS_DISABLED"))
+			return
+		}
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		orgID := r.PathValue("orgId")
+		idErr := validate.Var(orgID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		var t = scimTokenRequestBody{}
+		body, bodyErr := io.ReadAll(r.Body)
+		if bodyErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+			return
+		}
+
+		jsonErr := json.Unmarshal(body, &t)
+		if jsonErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+			return
+		}
+
+		inputErr := validate.Struct(t)
+		if inputErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+			return
+		}
+
+		token, err := s.SCIMDataSvc.TokenCreate(ctx, orgID, sessionUserID, t.Name)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleOrganizationSCIMTokenCreate error", zap.Error(err),
+				zap.String("organization_id", orgID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.recordAuditEvent(r, thunderdome.AuditEvent{
+			Action:     thunderdome.AuditActionSCIMTokenCreate,
+			TargetType: thunderdome.AuditTargetTypeSCIMToken,
+			TargetID:   token.ID,
+			Metadata:   map[string]string{"name": token.Name, "prefix": token.Prefix},
+		})
+
+		s.Success(w, r, http.StatusOK, token, nil)
+	}
+}
+
+// handleOrganizationSCIMTokenDelete handles deleting an organization SCIM token
+//
+//	@Summary		Delete Organization SCIM Token
+//	@Description	Deletes an organization SCIM token, the identity provider using it can no longer provision users
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId	path	string	true	"organization id"
+//	@Param			tokenId	path	string	true	"scim token id"
+//	@Success		200		object	standardJsonResponse{}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		404		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/scim-tokens/{tokenId} [delete]
+func (s *Service) handleOrganizationSCIMTokenDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.Config.OrganizationsEnabled {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ORGANIZATIONS_DISABLED"))
+			return
+		}
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		orgID := r.PathValue("orgId")
+		idErr := validate.Var(orgID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		tokenID := r.PathValue("tokenId")
+		idErr = validate.Var(tokenID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		err := s.SCIMDataSvc.TokenDelete(ctx, orgID, tokenID)
+		if err != nil && err.Error() == "SCIM_TOKEN_NOT_FOUND" {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "SCIM_TOKEN_NOT_FOUND"))
+			return
+		} else if err != nil {
+			s.Logger.Ctx(ctx).Error("handleOrganizationSCIMTokenDelete error", zap.Error(err),
+				zap.String("organization_id", orgID), zap.String("scim_token_id", tokenID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.recordAuditEvent(r, thunderdome.AuditEvent{
+			Action:     thunderdome.AuditActionSCIMTokenDelete,
+			TargetType: thunderdome.AuditTargetTypeSCIMToken,
+			TargetID:   tokenID,
+		})
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
@@ -0,0 +1,528 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+type scimMember struct {
+	Value   string `json:"value" validate:"required,uuid"`
+	Display string `json:"display,omitempty"`
+}
+
+type scimGroupResource struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	DisplayName string       `json:"displayName" validate:"required,max=256"`
+	Members     []scimMember `json:"members" validate:"dive"`
+	Meta        *scimMeta    `json:"meta,omitempty"`
+}
+
+// memberIDs gets the user IDs of the group's members
+func (g *scimGroupResource) memberIDs() []string {
+	ids := make([]string, 0, len(g.Members))
+	for _, m := range g.Members {
+		ids = append(ids, m.Value)
+	}
+
+	return ids
+}
+
+// scimGroupTeam splits a group display name of the form "Department/Team" into the department
+// and team names, a display name without a department maps to an organization team
+func scimGroupTeam(displayName string) (string, string) {
+	displayName = strings.TrimSpace(displayName)
+
+	department, team, found := strings.Cut(displayName, "/")
+	department, team = strings.TrimSpace(department), strings.TrimSpace(team)
+	if !found || department == "" || team == "" {
+		return "", displayName
+	}
+
+	return department, team
+}
+
+// scimGroup converts a provisioned group to a SCIM group resource
+func (s *Service) scimGroup(g *thunderdome.SCIMGroup) scimGroupResource {
+	members := make([]scimMember, 0, len(g.Members))
+	for _, m := range g.Members {
+		members = append(members, scimMember{Value: m.UserID, Display: m.Name})
+	}
+
+	return scimGroupResource{
+		Schemas:     []string{scimSchemaGroup},
+		ID:          g.ID,
+		ExternalID:  g.ExternalID,
+		DisplayName: g.DisplayName,
+		Members:     members,
+		Meta: &scimMeta{
+			ResourceType: "Group",
+			Created:      g.CreatedDate,
+			LastModified: g.UpdatedDate,
+			Location:     s.scimLocation("Groups", g.ID),
+		},
+	}
+}
+
+// scimGroupFromPath validates the {groupId} path value and gets the organization's provisioned group,
+// writing the failure and returning false when the group isn't found
+func (s *Service) scimGroupFromPath(w http.ResponseWriter, r *http.Request) (*thunderdome.SCIMGroup, bool) {
+	ctx := r.Context()
+	orgID := ctx.Value(contextKeySCIMOrgID).(string)
+	groupID := r.PathValue("groupId")
+	if validate.Var(groupID, "required,uuid") != nil {
+		s.scimFailure(w, http.StatusNotFound, "", "SCIM_GROUP_NOT_FOUND")
+		return nil, false
+	}
+
+	group, err := s.SCIMDataSvc.GroupGet(ctx, orgID, groupID)
+	if err != nil && err.Error() == "SCIM_GROUP_NOT_FOUND" {
+		s.scimFailure(w, http.StatusNotFound, "", "SCIM_GROUP_NOT_FOUND")
+		return nil, false
+	} else if err != nil {
+		s.Logger.Ctx(ctx).Error("scimGroupFromPath error", zap.Error(err),
+			zap.String("organization_id", orgID), zap.String("group_id", groupID))
+		s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+		return nil, false
+	}
+
+	return group, true
+}
+
+// scimRespondGroup writes the current state of a provisioned group
+func (s *Service) scimRespondGroup(w http.ResponseWriter, r *http.Request, code int, groupID string) {
+	ctx := r.Context()
+	orgID := ctx.Value(contextKeySCIMOrgID).(string)
+
+	group, err := s.SCIMDataSvc.GroupGet(ctx, orgID, groupID)
+	if err != nil {
+		s.Logger.Ctx(ctx).Error("scimRespondGroup error", zap.Error(err),
+			zap.String("organization_id", orgID), zap.String("group_id", groupID))
+		s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+
+	s.scimRespond(w, code, s.scimGroup(group))
+}
+
+// handleSCIMGroups gets a list of the organization's provisioned groups
+//
+//	@Summary		List SCIM Groups
+//	@Description	Lists the groups provisioned into the token's organization, supports filtering by displayName or externalId with eq
+//	@Tags			scim
+//	@Produce		json
+//	@Param			filter		query	string	false	"filter e.g. displayName eq \"Engineering/Platform\""
+//	@Param			startIndex	query	int		false	"1-based index of the first result"
+//	@Param			count		query	int		false	"max number of results"
+//	@Success		200			object	scimListResponse{Resources=[]scimGroupResource}
+//	@Failure		400			object	scimErrorResponse
+//	@Failure		401			object	scimErrorResponse
+//	@Security		SCIMBearerAuth
+//	@Router			/scim/v2/Groups [get]
+func (s *Service) handleSCIMGroups() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		orgID := ctx.Value(contextKeySCIMOrgID).(string)
+		startIndex, limit, offset := scimPagination(r)
+
+		attribute, value, filterErr := parseSCIMFilter(r.URL.Query().Get("filter"))
+		if filterErr != nil {
+			s.scimFailure(w, http.StatusBadRequest, "invalidFilter", filterErr.Error())
+			return
+		}
+		var displayName, externalID string
+		switch attribute {
+		case "":
+		case "displayname":
+			displayName = value
+		case "externalid":
+			externalID = value
+		default:
+			s.scimFailure(w, http.StatusBadRequest, "invalidFilter", "unsupported filter attribute "+attribute)
+			return
+		}
+
+		groups, count, err := s.SCIMDataSvc.GroupList(ctx, orgID, displayName, externalID, limit, offset)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleSCIMGroups error", zap.Error(err), zap.String("organization_id", orgID))
+			s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+
+		resources := make([]scimGroupResource, 0, len(groups))
+		for _, g := range groups {
+			resources = append(resources, s.scimGroup(g))
+		}
+
+		s.scimRespond(w, http.StatusOK, scimList(resources, count, startIndex, len(resources)))
+	}
+}
+
+// handleSCIMGroup gets a provisioned group
+//
+//	@Summary		Get SCIM Group
+//	@Description	Gets a group provisioned into the token's organization with its members
+//	@Tags			scim
+//	@Produce		json
+//	@Param			groupId	path	string	true	"group id"
+//	@Success		200		object	scimGroupResource
+//	@Failure		401		object	scimErrorResponse
+//	@Failure		404		object	scimErrorResponse
+//	@Security		SCIMBearerAuth
+//	@Router			/scim/v2/Groups/{groupId} [get]
+func (s *Service) handleSCIMGroup() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		group, ok := s.scimGroupFromPath(w, r)
+		if !ok {
+			return
+		}
+
+		s.scimRespond(w, http.StatusOK, s.scimGroup(group))
+	}
+}
+
+// handleSCIMGroupCreate provisions a group into the organization
+//
+//	@Summary		Create SCIM Group
+//	@Description	Maps a group to an organization team of the same name, a displayName of the form "Department/Team"
+//	@Description	maps to a team within the department. The department and team are created when they don't exist
+//	@Tags			scim
+//	@Produce		json
+//	@Param			group	body	scimGroupResource	true	"scim group"
+//	@Success		201		object	scimGroupResource
+//	@Failure		400		object	scimErrorResponse
+//	@Failure		401		object	scimErrorResponse
+//	@Failure		409		object	scimErrorResponse
+//	@Security		SCIMBearerAuth
+//	@Router			/scim/v2/Groups [post]
+func (s *Service) handleSCIMGroupCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		orgID := ctx.Value(contextKeySCIMOrgID).(string)
+
+		var g = scimGroupResource{}
+		if !s.scimDecode(w, r, &g) {
+			return
+		}
+		department, team := scimGroupTeam(g.DisplayName)
+
+		group, err := s.SCIMDataSvc.GroupCreate(ctx, orgID, strings.TrimSpace(g.DisplayName), g.ExternalID, department, team)
+		if err != nil && err.Error() == "SCIM_GROUP_EXISTS" {
+			s.scimFailure(w, http.StatusConflict, "uniqueness", "SCIM_GROUP_EXISTS")
+			return
+		} else if err != nil {
+			s.Logger.Ctx(ctx).Error("handleSCIMGroupCreate error", zap.Error(err), zap.String("organization_id", orgID))
+			s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+
+		if len(g.Members) > 0 {
+			if err := s.SCIMDataSvc.GroupMembersAdd(ctx, orgID, group.ID, g.memberIDs()); err != nil {
+				s.Logger.Ctx(ctx).Error("handleSCIMGroupCreate error", zap.Error(err),
+					zap.String("organization_id", orgID), zap.String("group_id", group.ID))
+				s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+				return
+			}
+		}
+
+		s.recordSCIMAuditEvent(r, thunderdome.AuditActionSCIMGroupCreate, thunderdome.AuditTargetTypeSCIMGroup, group.ID,
+			map[string]string{"displayName": group.DisplayName, "teamId": group.TeamID})
+
+		s.scimRespondGroup(w, r, http.StatusCreated, group.ID)
+	}
+}
+
+// handleSCIMGroupReplace replaces a provisioned group
+//
+//	@Summary		Replace SCIM Group
+//	@Description	Updates the displayName and externalId of a provisioned group, renaming its team,
+//	@Description	and sets its provisioned members. Team members that weren't provisioned are kept
+//	@Tags			scim
+//	@Produce		json
+//	@Param			groupId	path	string				true	"group id"
+//	@Param			group	body	scimGroupResource	true	"scim group"
+//	@Success		200		object	scimGroupResource
+//	@Failure		400		object	scimErrorResponse
+//	@Failure		401		object	scimErrorResponse
+//	@Failure		404		object	scimErrorResponse
+//	@Security		SCIMBearerAuth
+//	@Router			/scim/v2/Groups/{groupId} [put]
+func (s *Service) handleSCIMGroupReplace() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		orgID := ctx.Value(contextKeySCIMOrgID).(string)
+
+		group, ok := s.scimGroupFromPath(w, r)
+		if !ok {
+			return
+		}
+
+		var g = scimGroupResource{}
+		if !s.scimDecode(w, r, &g) {
+			return
+		}
+
+		if !s.scimUpdateGroup(w, r, group, strings.TrimSpace(g.DisplayName), g.ExternalID) {
+			return
+		}
+
+		if err := s.SCIMDataSvc.GroupMembersReplace(ctx, orgID, group.ID, g.memberIDs()); err != nil {
+			s.Logger.Ctx(ctx).Error("handleSCIMGroupReplace error", zap.Error(err),
+				zap.String("organization_id", orgID), zap.String("group_id", group.ID))
+			s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+
+		s.scimRespondGroup(w, r, http.StatusOK, group.ID)
+	}
+}
+
+// scimUpdateGroup saves a group's display name and external ID when they changed,
+// writing the failure and returning false when the update fails
+func (s *Service) scimUpdateGroup(w http.ResponseWriter, r *http.Request, group *thunderdome.SCIMGroup, displayName string, externalID string) bool {
+	if displayName == group.DisplayName && externalID == group.ExternalID {
+		return true
+	}
+	ctx := r.Context()
+	orgID := ctx.Value(contextKeySCIMOrgID).(string)
+
+	// the department is decided when the group is created, a rename only renames the team
+	_, team := scimGroupTeam(displayName)
+	if err := s.SCIMDataSvc.GroupUpdate(ctx, orgID, group.ID, displayName, externalID, team); err != nil {
+		s.Logger.Ctx(ctx).Error("scimUpdateGroup error", zap.Error(err),
+			zap.String("organization_id", orgID), zap.String("group_id", group.ID))
+		s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+		return false
+	}
+
+	s.recordSCIMAuditEvent(r, thunderdome.AuditActionSCIMGroupUpdate, thunderdome.AuditTargetTypeSCIMGroup, group.ID,
+		map[string]string{"displayName": displayName})
+
+	return true
+}
+
+// scimGroupPatch is the result of applying patch operations to a group
+type scimGroupPatch struct {
+	DisplayName   string
+	ExternalID    string
+	AddMembers    []string
+	RemoveMembers []string
+	// ReplaceMembers is nil unless the members were replaced
+	ReplaceMembers []string
+}
+
+// applySCIMGroupPatch applies patch operations to a group's displayName, externalId and members
+func applySCIMGroupPatch(group *thunderdome.SCIMGroup, operations []scimPatchOperation) (*scimGroupPatch, error) {
+	patch := &scimGroupPatch{DisplayName: group.DisplayName, ExternalID: group.ExternalID}
+
+	members := func(value json.RawMessage) ([]string, error) {
+		var m []scimMember
+		if len(value) == 0 {
+			return []string{}, nil
+		}
+		if err := json.Unmarshal(value, &m); err != nil {
+			return nil, fmt.Errorf("members value must be a list of members")
+		}
+		ids := make([]string, 0, len(m))
+		for _, member := range m {
+			if err := validate.Var(member.Value, "required,uuid"); err != nil {
+				return nil, fmt.Errorf("invalid member value %s", member.Value)
+			}
+			ids = append(ids, member.Value)
+		}
+		return ids, nil
+	}
+
+	for _, o := range operations {
+		op := strings.ToLower(o.Op)
+		path := strings.TrimSpace(o.Path)
+		lowerPath := strings.ToLower(path)
+
+		switch {
+		case op != "add" && op != "replace" && op != "remove":
+			return nil, fmt.Errorf("unsupported patch operation %s", o.Op)
+		case lowerPath == "members" && op == "replace":
+			ids, err := members(o.Value)
+			if err != nil {
+				return nil, err
+			}
+			patch.ReplaceMembers, patch.AddMembers, patch.RemoveMembers = ids, nil, nil
+		case lowerPath == "members" && op == "add":
+			ids, err := members(o.Value)
+			if err != nil {
+				return nil, err
+			}
+			patch.AddMembers = append(patch.AddMembers, ids...)
+		case lowerPath == "members" && op == "remove":
+			ids, err := members(o.Value)
+			if err != nil {
+				return nil, err
+			}
+			if len(o.Value) == 0 {
+				// removing the members attribute removes all members
+				patch.ReplaceMembers, patch.AddMembers, patch.RemoveMembers = []string{}, nil, nil
+				continue
+			}
+			patch.RemoveMembers = append(patch.RemoveMembers, ids...)
+		case strings.HasPrefix(lowerPath, "members[") && strings.HasSuffix(path, "]") && op == "remove":
+			attribute, id, err := parseSCIMFilter(path[len("members[") : len(path)-1])
+			if err != nil || attribute != "value" || validate.Var(id, "required,uuid") != nil {
+				return nil, fmt.Errorf("unsupported members filter %s", path)
+			}
+			patch.RemoveMembers = append(patch.RemoveMembers, id)
+		case lowerPath == "displayname" && op != "remove":
+			name, err := scimString(o.Value)
+			if err != nil {
+				return nil, err
+			}
+			patch.DisplayName = strings.TrimSpace(name)
+		case lowerPath == "externalid":
+			patch.ExternalID = ""
+			if op != "remove" {
+				id, err := scimString(o.Value)
+				if err != nil {
+					return nil, err
+				}
+				patch.ExternalID = id
+			}
+		case path == "" && op != "remove":
+			// without a path the value is an object of the attributes to set
+			var attributes map[string]json.RawMessage
+			if err := json.Unmarshal(o.Value, &attributes); err != nil {
+				return nil, fmt.Errorf("patch value must be an object when no path is given")
+			}
+			for attribute, value := range attributes {
+				switch strings.ToLower(attribute) {
+				case "displayname":
+					name, err := scimString(value)
+					if err != nil {
+						return nil, err
+					}
+					patch.DisplayName = strings.TrimSpace(name)
+				case "externalid":
+					id, err := scimString(value)
+					if err != nil {
+						return nil, err
+					}
+					patch.ExternalID = id
+				case "members":
+					ids, err := members(value)
+					if err != nil {
+						return nil, err
+					}
+					if op == "replace" {
+						patch.ReplaceMembers, patch.AddMembers, patch.RemoveMembers = ids, nil, nil
+					} else {
+						patch.AddMembers = append(patch.AddMembers, ids...)
+					}
+				}
+			}
+		}
+	}
+
+	if patch.DisplayName == "" || len(patch.DisplayName) > 256 {
+		return nil, fmt.Errorf("displayName must be between 1 and 256 characters")
+	}
+
+	return patch, nil
+}
+
+// handleSCIMGroupPatch updates a provisioned group
+//
+//	@Summary		Patch SCIM Group
+//	@Description	Applies add, replace and remove operations to the displayName, externalId and members of a provisioned group
+//	@Tags			scim
+//	@Produce		json
+//	@Param			groupId	path	string				true	"group id"
+//	@Param			patch	body	scimPatchRequest	true	"scim patch operations"
+//	@Success		200		object	scimGroupResource
+//	@Failure		400		object	scimErrorResponse
+//	@Failure		401		object	scimErrorResponse
+//	@Failure		404		object	scimErrorResponse
+//	@Security		SCIMBearerAuth
+//	@Router			/scim/v2/Groups/{groupId} [patch]
+func (s *Service) handleSCIMGroupPatch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		orgID := ctx.Value(contextKeySCIMOrgID).(string)
+
+		group, ok := s.scimGroupFromPath(w, r)
+		if !ok {
+			return
+		}
+
+		var p = scimPatchRequest{}
+		if !s.scimDecode(w, r, &p) {
+			return
+		}
+
+		patch, err := applySCIMGroupPatch(group, p.Operations)
+		if err != nil {
+			s.scimFailure(w, http.StatusBadRequest, "invalidValue", err.Error())
+			return
+		}
+
+		if !s.scimUpdateGroup(w, r, group, patch.DisplayName, patch.ExternalID) {
+			return
+		}
+
+		if patch.ReplaceMembers != nil {
+			err = s.SCIMDataSvc.GroupMembersReplace(ctx, orgID, group.ID, patch.ReplaceMembers)
+		}
+		if err == nil && len(patch.AddMembers) > 0 {
+			err = s.SCIMDataSvc.GroupMembersAdd(ctx, orgID, group.ID, patch.AddMembers)
+		}
+		if err == nil && len(patch.RemoveMembers) > 0 {
+			err = s.SCIMDataSvc.GroupMembersRemove(ctx, orgID, group.ID, patch.RemoveMembers)
+		}
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleSCIMGroupPatch error", zap.Error(err),
+				zap.String("organization_id", orgID), zap.String("group_id", group.ID))
+			s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+
+		s.scimRespondGroup(w, r, http.StatusOK, group.ID)
+	}
+}
+
+// handleSCIMGroupDelete removes a provisioned group
+//
+//	@Summary		Delete SCIM Group
+//	@Description	Removes the mapping of a group to its team, the team and its members are kept
+//	@Tags			scim
+//	@Param			groupId	path	string	true	"group id"
+//	@Success		204
+//	@Failure		401	object	scimErrorResponse
+//	@Failure		404	object	scimErrorResponse
+//	@Security		SCIMBearerAuth
+//	@Router			/scim/v2/Groups/{groupId} [delete]
+func (s *Service) handleSCIMGroupDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		orgID := ctx.Value(contextKeySCIMOrgID).(string)
+
+		group, ok := s.scimGroupFromPath(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.SCIMDataSvc.GroupDelete(ctx, orgID, group.ID); err != nil {
+			s.Logger.Ctx(ctx).Error("handleSCIMGroupDelete error", zap.Error(err),
+				zap.String("organization_id", orgID), zap.String("group_id", group.ID))
+			s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+
+		s.recordSCIMAuditEvent(r, thunderdome.AuditActionSCIMGroupDelete, thunderdome.AuditTargetTypeSCIMGroup, group.ID,
+			map[string]string{"displayName": group.DisplayName})
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,456 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const (
+	testSCIMOrgID      = "6d2c1f8e-0a4b-4c1e-9d7f-2b3a4c5d6e7f"
+	testSCIMOtherOrgID = "1f2e3d4c-5b6a-4978-8a9b-0c1d2e3f4a5b"
+	testSCIMUserID     = "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"
+)
+
+// MockSCIMDataSvc mocks the token and user operations of the SCIM data service
+type MockSCIMDataSvc struct {
+	SCIMDataSvc
+	mock.Mock
+}
+
+func (m *MockSCIMDataSvc) TokenOrganization(ctx context.Context, token string) (string, error) {
+	args := m.Called(ctx, token)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockSCIMDataSvc) UserList(ctx context.Context, orgID string, email string, externalID string, limit int, offset int) ([]*thunderdome.SCIMUser, int, error) {
+	args := m.Called(ctx, orgID, email, externalID, limit, offset)
+	users, _ := args.Get(0).([]*thunderdome.SCIMUser)
+	return users, args.Int(1), args.Error(2)
+}
+
+func (m *MockSCIMDataSvc) UserGet(ctx context.Context, orgID string, userID string) (*thunderdome.SCIMUser, error) {
+	args := m.Called(ctx, orgID, userID)
+	user, _ := args.Get(0).(*thunderdome.SCIMUser)
+	return user, args.Error(1)
+}
+
+func (m *MockSCIMDataSvc) UserLink(ctx context.Context, orgID string, userID string, externalID string, created bool) error {
+	args := m.Called(ctx, orgID, userID, externalID, created)
+	return args.Error(0)
+}
+
+func (m *MockSCIMDataSvc) UserSetActive(ctx context.Context, orgID string, userID string, active bool) error {
+	args := m.Called(ctx, orgID, userID, active)
+	return args.Error(0)
+}
+
+type scimTest struct {
+	service *Service
+	scim    *MockSCIMDataSvc
+	users   *MockUserDataService
+	orgs    *MockOrganizationDataService
+}
+
+func newSCIMTest() *scimTest {
+	scim := new(MockSCIMDataSvc)
+	users := new(MockUserDataService)
+	orgs := new(MockOrganizationDataService)
+
+	return &scimTest{
+		service: &Service{
+			Config:              &Config{OrganizationsEnabled: true},
+			Logger:              otelzap.New(zap.NewNop()),
+			SCIMDataSvc:         scim,
+			UserDataSvc:         users,
+			OrganizationDataSvc: orgs,
+		},
+		scim:  scim,
+		users: users,
+		orgs:  orgs,
+	}
+}
+
+// request builds a request authenticated with the organization's SCIM token
+func (st *scimTest) request(method string, body string) *http.Request {
+	req := httptest.NewRequest(method, "/api/scim/v2/Users", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer org-token")
+	st.scim.On("TokenOrganization", mock.Anything, "org-token").Return(testSCIMOrgID, nil)
+
+	return req
+}
+
+func TestParseSCIMFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    string
+		attribute string
+		value     string
+		wantErr   bool
+	}{
+		{name: "empty", filter: ""},
+		{name: "userName", filter: `userName eq "thor@example.com"`, attribute: "username", value: "thor@example.com"},
+		{name: "operator case", filter: `externalId EQ "abc"`, attribute: "externalid", value: "abc"},
+		{name: "value with spaces", filter: `displayName eq "Engineering / Platform"`, attribute: "displayname", value: "Engineering / Platform"},
+		{name: "unsupported operator", filter: `userName co "thor"`, wantErr: true},
+		{name: "unquoted value", filter: `userName eq thor`, wantErr: true},
+		{name: "missing value", filter: `userName`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attribute, value, err := parseSCIMFilter(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSCIMFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attribute != tt.attribute || value != tt.value {
+				t.Errorf("parseSCIMFilter() = %q, %q, want %q, %q", attribute, value, tt.attribute, tt.value)
+			}
+		})
+	}
+}
+
+func TestSCIMBool(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    bool
+		wantErr bool
+	}{
+		{name: "bool", raw: `false`, want: false},
+		{name: "string", raw: `"True"`, want: true},
+		{name: "invalid", raw: `"nope"`, wantErr: true},
+		{name: "number", raw: `1`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scimBool(json.RawMessage(tt.raw))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("scimBool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("scimBool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSCIMGroupTeam(t *testing.T) {
+	tests := []struct {
+		name        string
+		displayName string
+		department  string
+		team        string
+	}{
+		{name: "team", displayName: "Platform", team: "Platform"},
+		{name: "department team", displayName: "Engineering / Platform", department: "Engineering", team: "Platform"},
+		{name: "empty department", displayName: "/Platform", team: "/Platform"},
+		{name: "nested", displayName: "Engineering/Platform/Core", department: "Engineering", team: "Platform/Core"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			department, team := scimGroupTeam(tt.displayName)
+			if department != tt.department || team != tt.team {
+				t.Errorf("scimGroupTeam() = %q, %q, want %q, %q", department, team, tt.department, tt.team)
+			}
+		})
+	}
+}
+
+func TestApplySCIMUserPatch(t *testing.T) {
+	user := &thunderdome.SCIMUser{ExternalID: "ext", Name: "Thor", Active: true}
+
+	tests := []struct {
+		name       string
+		operations string
+		externalID string
+		userName   string
+		active     bool
+		wantErr    bool
+	}{
+		{
+			name:       "deactivate with string boolean",
+			operations: `[{"op":"Replace","path":"active","value":"False"}]`,
+			externalID: "ext", userName: "Thor", active: false,
+		},
+		{
+			name:       "pathless value",
+			operations: `[{"op":"replace","value":{"active":false,"externalId":"new","displayName":"Thor Odinson"}}]`,
+			externalID: "new", userName: "Thor Odinson", active: false,
+		},
+		{
+			name:       "ignores unknown attributes",
+			operations: `[{"op":"add","path":"title","value":"God of Thunder"}]`,
+			externalID: "ext", userName: "Thor", active: true,
+		},
+		{
+			name:       "unsupported op",
+			operations: `[{"op":"move","path":"active","value":false}]`,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ops []scimPatchOperation
+			if err := json.Unmarshal([]byte(tt.operations), &ops); err != nil {
+				t.Fatal(err)
+			}
+			externalID, name, active, err := applySCIMUserPatch(user, ops)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applySCIMUserPatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if externalID != tt.externalID || name != tt.userName || active != tt.active {
+				t.Errorf("applySCIMUserPatch() = %q, %q, %v, want %q, %q, %v",
+					externalID, name, active, tt.externalID, tt.userName, tt.active)
+			}
+		})
+	}
+}
+
+func TestApplySCIMGroupPatch(t *testing.T) {
+	group := &thunderdome.SCIMGroup{DisplayName: "Platform", ExternalID: "ext"}
+	member := "8a8b3d6e-0b1f-4d47-9c4e-3f0e7a1b2c3d"
+
+	tests := []struct {
+		name       string
+		operations string
+		want       scimGroupPatch
+		wantErr    bool
+	}{
+		{
+			name:       "add members",
+			operations: `[{"op":"add","path":"members","value":[{"value":"` + member + `"}]}]`,
+			want:       scimGroupPatch{DisplayName: "Platform", ExternalID: "ext", AddMembers: []string{member}},
+		},
+		{
+			name:       "remove member by filter",
+			operations: `[{"op":"remove","path":"members[value eq \"` + member + `\"]"}]`,
+			want:       scimGroupPatch{DisplayName: "Platform", ExternalID: "ext", RemoveMembers: []string{member}},
+		},
+		{
+			name:       "remove all members",
+			operations: `[{"op":"remove","path":"members"}]`,
+			want:       scimGroupPatch{DisplayName: "Platform", ExternalID: "ext", ReplaceMembers: []string{}},
+		},
+		{
+			name:       "rename",
+			operations: `[{"op":"replace","value":{"displayName":"Core"}}]`,
+			want:       scimGroupPatch{DisplayName: "Core", ExternalID: "ext"},
+		},
+		{
+			name:       "invalid member",
+			operations: `[{"op":"add","path":"members","value":[{"value":"thor"}]}]`,
+			wantErr:    true,
+		},
+		{
+			name:       "empty display name",
+			operations: `[{"op":"replace","path":"displayName","value":" "}]`,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ops []scimPatchOperation
+			if err := json.Unmarshal([]byte(tt.operations), &ops); err != nil {
+				t.Fatal(err)
+			}
+			got, err := applySCIMGroupPatch(group, ops)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applySCIMGroupPatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.DisplayName != tt.want.DisplayName || got.ExternalID != tt.want.ExternalID ||
+				!slices.Equal(got.AddMembers, tt.want.AddMembers) ||
+				!slices.Equal(got.RemoveMembers, tt.want.RemoveMembers) ||
+				(got.ReplaceMembers == nil) != (tt.want.ReplaceMembers == nil) ||
+				!slices.Equal(got.ReplaceMembers, tt.want.ReplaceMembers) {
+				t.Errorf("applySCIMGroupPatch() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSCIMOnly(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		tokenOrg      string
+		tokenErr      error
+		wantStatus    int
+		wantDetail    string
+	}{
+		{name: "missing token", wantStatus: http.StatusUnauthorized, wantDetail: "INVALID_SCIM_TOKEN"},
+		{name: "not a bearer token", authorization: "Basic org-token", wantStatus: http.StatusUnauthorized, wantDetail: "INVALID_SCIM_TOKEN"},
+		{
+			name:          "invalid token",
+			authorization: "Bearer unknown-token",
+			tokenErr:      errors.New("INVALID_SCIM_TOKEN"),
+			wantStatus:    http.StatusUnauthorized,
+			wantDetail:    "INVALID_SCIM_TOKEN",
+		},
+		{
+			name:          "another organization's token",
+			authorization: "Bearer other-org-token",
+			tokenOrg:      testSCIMOtherOrgID,
+			wantStatus:    http.StatusNotFound,
+			wantDetail:    "SCIM_USER_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newSCIMTest()
+			if tt.tokenOrg != "" || tt.tokenErr != nil {
+				_, token, _ := strings.Cut(tt.authorization, " ")
+				st.scim.On("TokenOrganization", mock.Anything, token).Return(tt.tokenOrg, tt.tokenErr)
+			}
+			// the user is provisioned into testSCIMOrgID, lookups are scoped to the token's organization
+			st.scim.On("UserGet", mock.Anything, testSCIMOtherOrgID, testSCIMUserID).
+				Return(nil, errors.New("SCIM_USER_NOT_FOUND"))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/scim/v2/Users/"+testSCIMUserID, nil)
+			req.SetPathValue("userId", testSCIMUserID)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			st.service.scimOnly(st.service.handleSCIMUser())(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantDetail)
+			st.scim.AssertNotCalled(t, "UserGet", mock.Anything, testSCIMOrgID, mock.Anything)
+		})
+	}
+}
+
+func TestHandleSCIMUserCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		user        *thunderdome.User
+		created     bool
+		role        string
+		roleErr     error
+		wantStatus  int
+		wantLinked  bool
+		wantCreated bool
+	}{
+		{
+			name:        "new user",
+			user:        &thunderdome.User{ID: testSCIMUserID, Type: thunderdome.RegisteredUserType},
+			created:     true,
+			wantStatus:  http.StatusCreated,
+			wantLinked:  true,
+			wantCreated: true,
+		},
+		{
+			name:       "existing organization member",
+			user:       &thunderdome.User{ID: testSCIMUserID, Type: thunderdome.RegisteredUserType},
+			role:       thunderdome.EntityMemberUserType,
+			wantStatus: http.StatusCreated,
+			wantLinked: true,
+		},
+		{
+			name:       "existing user outside the organization",
+			user:       &thunderdome.User{ID: testSCIMUserID, Type: thunderdome.RegisteredUserType},
+			roleErr:    errors.New("USER_ROLE_NOT_FOUND"),
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "existing admin",
+			user:       &thunderdome.User{ID: testSCIMUserID, Type: thunderdome.AdminUserType},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "existing guest",
+			user:       &thunderdome.User{ID: testSCIMUserID, Type: thunderdome.GuestUserType},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newSCIMTest()
+			st.scim.On("UserList", mock.Anything, testSCIMOrgID, "thor@example.com", "", 1, 0).Return(nil, 0, nil)
+			st.users.On("ProvisionUser", mock.Anything, "thor", "thor@example.com").Return(tt.user, tt.created, nil)
+			st.orgs.On("OrganizationUserRole", mock.Anything, testSCIMUserID, testSCIMOrgID).Return(tt.role, tt.roleErr)
+			st.scim.On("UserLink", mock.Anything, testSCIMOrgID, testSCIMUserID, "ext-1", tt.wantCreated).Return(nil)
+			st.scim.On("UserGet", mock.Anything, testSCIMOrgID, testSCIMUserID).
+				Return(&thunderdome.SCIMUser{ID: testSCIMUserID, Email: "thor@example.com", Active: true}, nil)
+
+			w := httptest.NewRecorder()
+			req := st.request(http.MethodPost, `{"userName":"thor@example.com","externalId":"ext-1"}`)
+
+			st.service.scimOnly(st.service.handleSCIMUserCreate())(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantLinked {
+				st.scim.AssertCalled(t, "UserLink", mock.Anything, testSCIMOrgID, testSCIMUserID, "ext-1", tt.wantCreated)
+			} else {
+				st.scim.AssertNotCalled(t, "UserLink", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestHandleSCIMUserDeactivate(t *testing.T) {
+	tests := []struct {
+		name        string
+		owned       bool
+		wantDisable bool
+		wantRemove  bool
+	}{
+		{name: "owned user is disabled", owned: true, wantDisable: true},
+		{name: "linked user is removed from the organization", owned: false, wantRemove: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newSCIMTest()
+			st.scim.On("UserGet", mock.Anything, testSCIMOrgID, testSCIMUserID).
+				Return(&thunderdome.SCIMUser{ID: testSCIMUserID, Email: "thor@example.com", Active: true, Owned: tt.owned}, nil)
+			st.scim.On("UserSetActive", mock.Anything, testSCIMOrgID, testSCIMUserID, false).Return(nil)
+			st.users.On("DisableUser", mock.Anything, testSCIMUserID).Return(nil)
+			st.orgs.On("OrganizationRemoveUser", mock.Anything, testSCIMOrgID, testSCIMUserID).Return(nil)
+
+			w := httptest.NewRecorder()
+			req := st.request(http.MethodPatch, `{"Operations":[{"op":"replace","path":"active","value":false}]}`)
+			req.SetPathValue("userId", testSCIMUserID)
+
+			st.service.scimOnly(st.service.handleSCIMUserPatch())(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			st.scim.AssertCalled(t, "UserSetActive", mock.Anything, testSCIMOrgID, testSCIMUserID, false)
+			if tt.wantDisable {
+				st.users.AssertCalled(t, "DisableUser", mock.Anything, testSCIMUserID)
+			} else {
+				st.users.AssertNotCalled(t, "DisableUser", mock.Anything, mock.Anything)
+			}
+			if tt.wantRemove {
+				st.orgs.AssertCalled(t, "OrganizationRemoveUser", mock.Anything, testSCIMOrgID, testSCIMUserID)
+			} else {
+				st.orgs.AssertNotCalled(t, "OrganizationRemoveUser", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,546 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+type scimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type scimEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type scimUserResource struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	UserName    string      `json:"userName" validate:"required"`
+	Name        *scimName   `json:"name,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Emails      []scimEmail `json:"emails,omitempty"`
+	Active      *bool       `json:"active,omitempty"`
+	Meta        *scimMeta   `json:"meta,omitempty"`
+}
+
+// email gets the user's email, the userName when it is an email otherwise the primary email
+func (u *scimUserResource) email() string {
+	if validate.Var(u.UserName, "email") == nil {
+		return u.UserName
+	}
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+
+	return ""
+}
+
+// displayName gets the name to use for the user, falling back from the display name
+// to the formatted name, the given and family names and finally the userName
+func (u *scimUserResource) displayName() string {
+	name := strings.TrimSpace(u.DisplayName)
+	if name == "" && u.Name != nil {
+		name = strings.TrimSpace(u.Name.Formatted)
+		if name == "" {
+			name = strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+		}
+	}
+	if name == "" {
+		name, _, _ = strings.Cut(u.UserName, "@")
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	return name
+}
+
+// scimUser converts a provisioned user to a SCIM user resource
+func (s *Service) scimUser(u *thunderdome.SCIMUser) scimUserResource {
+	active := u.Active
+
+	return scimUserResource{
+		Schemas:     []string{scimSchemaUser},
+		ID:          u.ID,
+		ExternalID:  u.ExternalID,
+		UserName:    u.Email,
+		Name:        &scimName{Formatted: u.Name},
+		DisplayName: u.Name,
+		Emails:      []scimEmail{{Value: u.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &scimMeta{
+			ResourceType: "User",
+			Created:      u.CreatedDate,
+			LastModified: u.UpdatedDate,
+			Location:     s.scimLocation("Users", u.ID),
+		},
+	}
+}
+
+// scimUserFromPath validates the {userId} path value and gets the organization's provisioned user,
+// writing the failure and returning false when the user isn't found
+func (s *Service) scimUserFromPath(w http.ResponseWriter, r *http.Request) (*thunderdome.SCIMUser, bool) {
+	ctx := r.Context()
+	orgID := ctx.Value(contextKeySCIMOrgID).(string)
+	userID := r.PathValue("userId")
+	if validate.Var(userID, "required,uuid") != nil {
+		s.scimFailure(w, http.StatusNotFound, "", "SCIM_USER_NOT_FOUND")
+		return nil, false
+	}
+
+	user, err := s.SCIMDataSvc.UserGet(ctx, orgID, userID)
+	if err != nil && err.Error() == "SCIM_USER_NOT_FOUND" {
+		s.scimFailure(w, http.StatusNotFound, "", "SCIM_USER_NOT_FOUND")
+		return nil, false
+	} else if err != nil {
+		s.Logger.Ctx(ctx).Error("scimUserFromPath error", zap.Error(err),
+			zap.String("organization_id", orgID), zap.String("user_id", userID))
+		s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+		return nil, false
+	}
+
+	return user, true
+}
+
+// scimSetUserActive activates or deactivates a provisioned user when their active state changes,
+// the user's account is only enabled or disabled when the organization owns the user,
+// otherwise a deactivated user is only removed from the organization
+func (s *Service) scimSetUserActive(r *http.Request, user *thunderdome.SCIMUser, active bool) error {
+	if user.Active == active {
+		return nil
+	}
+	ctx := r.Context()
+	orgID := ctx.Value(contextKeySCIMOrgID).(string)
+
+	if err := s.SCIMDataSvc.UserSetActive(ctx, orgID, user.ID, active); err != nil {
+		return err
+	}
+
+	var err error
+	switch {
+	case user.Owned && active:
+		err = s.UserDataSvc.EnableUser(ctx, user.ID)
+	case user.Owned:
+		err = s.UserDataSvc.DisableUser(ctx, user.ID)
+	case !active:
+		err = s.OrganizationDataSvc.OrganizationRemoveUser(ctx, orgID, user.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	action := thunderdome.AuditActionSCIMUserEnable
+	if !active {
+		action = thunderdome.AuditActionSCIMUserDisable
+	}
+	s.recordSCIMAuditEvent(r, action, thunderdome.AuditTargetTypeUser, user.ID,
+		map[string]string{"owned": strconv.FormatBool(user.Owned)})
+	user.Active = active
+
+	return nil
+}
+
+// scimUpdateUser saves the name, external ID and active state of a provisioned user
+// and writes the updated user resource
+func (s *Service) scimUpdateUser(w http.ResponseWriter, r *http.Request, user *thunderdome.SCIMUser, externalID string, name string, active bool) {
+	ctx := r.Context()
+	orgID := ctx.Value(contextKeySCIMOrgID).(string)
+
+	if externalID != user.ExternalID || name != user.Name {
+		if err := s.SCIMDataSvc.UserUpdate(ctx, orgID, user.ID, externalID, name); err != nil {
+			s.Logger.Ctx(ctx).Error("scimUpdateUser error", zap.Error(err),
+				zap.String("organization_id", orgID), zap.String("user_id", user.ID))
+			s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+		s.recordSCIMAuditEvent(r, thunderdome.AuditActionSCIMUserUpdate, thunderdome.AuditTargetTypeUser, user.ID,
+			map[string]string{"name": name, "externalId": externalID})
+	}
+
+	if err := s.scimSetUserActive(r, user, active); err != nil {
+		s.Logger.Ctx(ctx).Error("scimUpdateUser error", zap.Error(err),
+			zap.String("organization_id", orgID), zap.String("user_id", user.ID))
+		s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+
+	updated, err := s.SCIMDataSvc.UserGet(ctx, orgID, user.ID)
+	if err != nil {
+		s.Logger.Ctx(ctx).Error("scimUpdateUser error", zap.Error(err),
+			zap.String("organization_id", orgID), zap.String("user_id", user.ID))
+		s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+
+	s.scimRespond(w, http.StatusOK, s.scimUser(updated))
+}
+
+// handleSCIMUsers gets a list of the organization's provisioned users
+//
+//	@Summary		List SCIM Users
+//	@Description	Lists the users provisioned into the token's organization, supports filtering by userName or externalId with eq
+//	@Tags			scim
+//	@Produce		json
+//	@Param			filter		query	string	false	"filter e.g. userName eq \"user@example.com\""
+//	@Param			startIndex	query	int		false	"1-based index of the first result"
+//	@Param			count		query	int		false	"max number of results"
+//	@Success		200			object	scimListResponse{Resources=[]scimUserResource}
+//	@Failure		400			object	scimErrorResponse
+//	@Failure		401			object	scimErrorResponse
+//	@Security		SCIMBearerAuth
+//	@Router			/scim/v2/Users [get]
+func (s *Service) handleSCIMUsers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		orgID := ctx.Value(contextKeySCIMOrgID).(string)
+		startIndex, limit, offset := scimPagination(r)
+
+		attribute, value, filterErr := parseSCIMFilter(r.URL.Query().Get("filter"))
+		if filterErr != nil {
+			s.scimFailure(w, http.StatusBadRequest, "invalidFilter", filterErr.Error())
+			return
+		}
+		var email, externalID string
+		switch attribute {
+		case "":
+		case "username", "emails.value":
+			email = value
+		case "externalid":
+			externalID = value
+		default:
+			s.scimFailure(w, http.StatusBadRequest, "invalidFilter", "unsupported filter attribute "+attribute)
+			return
+		}
+
+		users, count, err := s.SCIMDataSvc.UserList(ctx, orgID, email, externalID, limit, offset)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleSCIMUsers error", zap.Error(err), zap.String("organization_id", orgID))
+			s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+
+		resources := make([]scimUserResource, 0, len(users))
+		for _, u := range users {
+			resources = append(resources, s.scimUser(u))
+		}
+
+		s.scimRespond(w, http.StatusOK, scimList(resources, count, startIndex, len(resources)))
+	}
+}
+
+// handleSCIMUser gets a provisioned user
+//
+//	@Summary		Get SCIM User
+//	@Description	Gets a user provisioned into the token's organization
+//	@Tags			scim
+//	@Produce		json
+//	@Param			userId	path	string	true	"user id"
+//	@Success		200		object	scimUserResource
+//	@Failure		401		object	scimErrorResponse
+//	@Failure		404		object	scimErrorResponse
+//	@Security		SCIMBearerAuth
+//	@Router			/scim/v2/Users/{userId} [get]
+func (s *Service) handleSCIMUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := s.scimUserFromPath(w, r)
+		if !ok {
+			return
+		}
+
+		s.scimRespond(w, http.StatusOK, s.scimUser(user))
+	}
+}
+
+// handleSCIMUserCreate provisions a user into the organization
+//
+//	@Summary		Create SCIM User
+//	@Description	Provisions a user into the token's organization as a member, creating the user without a password
+//	@Description	when no user has the email. The user signs in through the identity provider.
+//	@Description	An existing user is only provisioned when they're already a member of the organization, admins never are
+//	@Tags			scim
+//	@Produce		json
+//	@Param			user	body	scimUserResource	true	"scim user"
+//	@Success		201		object	scimUserResource
+//	@Failure		400		object	scimErrorResponse
+//	@Failure		401		object	scimErrorResponse
+//	@Failure		409		object	scimErrorResponse
+//	@Security		SCIMBearerAuth
+//	@Router			/scim/v2/Users [post]
+func (s *Service) handleSCIMUserCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		orgID := ctx.Value(contextKeySCIMOrgID).(string)
+
+		var u = scimUserResource{}
+		if !s.scimDecode(w, r, &u) {
+			return
+		}
+		email := u.email()
+		if validate.Var(email, "required,email") != nil {
+			s.scimFailure(w, http.StatusBadRequest, "invalidValue", "userName or emails must contain a valid email")
+			return
+		}
+
+		_, existing, err := s.SCIMDataSvc.UserList(ctx, orgID, email, "", 1, 0)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleSCIMUserCreate error", zap.Error(err), zap.String("organization_id", orgID))
+			s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+		if existing > 0 {
+			s.scimFailure(w, http.StatusConflict, "uniqueness", "SCIM_USER_EXISTS")
+			return
+		}
+
+		user, created, err := s.UserDataSvc.ProvisionUser(ctx, u.displayName(), email)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleSCIMUserCreate error", zap.Error(err), zap.String("organization_id", orgID))
+			s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+		// existing users are only linked when the organization already has them as members,
+		// so provisioning can't take over accounts the organization doesn't own
+		if !created {
+			if user.Type != thunderdome.RegisteredUserType {
+				s.scimFailure(w, http.StatusConflict, "uniqueness", "SCIM_USER_EXISTS")
+				return
+			}
+			_, roleErr := s.OrganizationDataSvc.OrganizationUserRole(ctx, user.ID, orgID)
+			if roleErr != nil && roleErr.Error() == "USER_ROLE_NOT_FOUND" {
+				s.scimFailure(w, http.StatusConflict, "uniqueness", "SCIM_USER_EXISTS")
+				return
+			} else if roleErr != nil {
+				s.Logger.Ctx(ctx).Error("handleSCIMUserCreate error", zap.Error(roleErr),
+					zap.String("organization_id", orgID), zap.String("user_id", user.ID))
+				s.scimFailure(w, http.StatusInternalServerError, "", roleErr.Error())
+				return
+			}
+		}
+
+		if err := s.SCIMDataSvc.UserLink(ctx, orgID, user.ID, u.ExternalID, created); err != nil {
+			s.Logger.Ctx(ctx).Error("handleSCIMUserCreate error", zap.Error(err),
+				zap.String("organization_id", orgID), zap.String("user_id", user.ID))
+			s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+
+		s.recordSCIMAuditEvent(r, thunderdome.AuditActionSCIMUserCreate, thunderdome.AuditTargetTypeUser, user.ID,
+			map[string]string{"externalId": u.ExternalID, "created": strconv.FormatBool(created)})
+
+		provisioned, err := s.SCIMDataSvc.UserGet(ctx, orgID, user.ID)
+		if err == nil && u.Active != nil {
+			err = s.scimSetUserActive(r, provisioned, *u.Active)
+		}
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleSCIMUserCreate error", zap.Error(err),
+				zap.String("organization_id", orgID), zap.String("user_id", user.ID))
+			s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+
+		s.scimRespond(w, http.StatusCreated, s.scimUser(provisioned))
+	}
+}
+
+// handleSCIMUserReplace replaces a provisioned user
+//
+//	@Summary		Replace SCIM User
+//	@Description	Updates the name, externalId and active state of a provisioned user, an inactive user is removed from
+//	@Description	the organization. The name is only changed and an inactive user only disabled when the organization
+//	@Description	created the user and no other organization provisions them. The userName can't be changed
+//	@Tags			scim
+//	@Produce		json
+//	@Param			userId	path	string				true	"user id"
+//	@Param			user	body	scimUserResource	true	"scim user"
+//	@Success		200		object	scimUserResource
+//	@Failure		400		object	scimErrorResponse
+//	@Failure		401		object	scimErrorResponse
+//	@Failure		404		object	scimErrorResponse
+//	@Security		SCIMBearerAuth
+//	@Router			/scim/v2/Users/{userId} [put]
+func (s *Service) handleSCIMUserReplace() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := s.scimUserFromPath(w, r)
+		if !ok {
+			return
+		}
+
+		var u = scimUserResource{}
+		if !s.scimDecode(w, r, &u) {
+			return
+		}
+
+		active := true
+		if u.Active != nil {
+			active = *u.Active
+		}
+
+		s.scimUpdateUser(w, r, user, u.ExternalID, u.displayName(), active)
+	}
+}
+
+// handleSCIMUserPatch updates a provisioned user
+//
+//	@Summary		Patch SCIM User
+//	@Description	Applies add, replace and remove operations to the active, displayName, name and externalId
+//	@Description	attributes of a provisioned user, other attributes are ignored
+//	@Tags			scim
+//	@Produce		json
+//	@Param			userId	path	string				true	"user id"
+//	@Param			patch	body	scimPatchRequest	true	"scim patch operations"
+//	@Success		200		object	scimUserResource
+//	@Failure		400		object	scimErrorResponse
+//	@Failure		401		object	scimErrorResponse
+//	@Failure		404		object	scimErrorResponse
+//	@Security		SCIMBearerAuth
+//	@Router			/scim/v2/Users/{userId} [patch]
+func (s *Service) handleSCIMUserPatch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := s.scimUserFromPath(w, r)
+		if !ok {
+			return
+		}
+
+		var patch = scimPatchRequest{}
+		if !s.scimDecode(w, r, &patch) {
+			return
+		}
+
+		externalID, name, active, err := applySCIMUserPatch(user, patch.Operations)
+		if err != nil {
+			s.scimFailure(w, http.StatusBadRequest, "invalidValue", err.Error())
+			return
+		}
+
+		s.scimUpdateUser(w, r, user, externalID, name, active)
+	}
+}
+
+// applySCIMUserPatch applies patch operations to a provisioned user's externalId, name and active state,
+// operations on attributes Thunderdome doesn't store are ignored
+func applySCIMUserPatch(user *thunderdome.SCIMUser, operations []scimPatchOperation) (string, string, bool, error) {
+	externalID, name, active := user.ExternalID, user.Name, user.Active
+
+	apply := func(op string, path string, value json.RawMessage) error {
+		var err error
+		switch strings.ToLower(path) {
+		case "active":
+			if op == "remove" {
+				return nil
+			}
+			active, err = scimBool(value)
+		case "externalid":
+			if op == "remove" {
+				externalID = ""
+				return nil
+			}
+			externalID, err = scimString(value)
+		case "displayname", "name.formatted":
+			if op == "remove" {
+				return nil
+			}
+			var v string
+			if v, err = scimString(value); err == nil && strings.TrimSpace(v) != "" {
+				name = strings.TrimSpace(v)
+			}
+		}
+
+		return err
+	}
+
+	for _, o := range operations {
+		op := strings.ToLower(o.Op)
+		if op != "add" && op != "replace" && op != "remove" {
+			return "", "", false, fmt.Errorf("unsupported patch operation %s", o.Op)
+		}
+
+		if o.Path != "" {
+			if err := apply(op, o.Path, o.Value); err != nil {
+				return "", "", false, err
+			}
+			continue
+		}
+
+		// without a path the value is an object of the attributes to set
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(o.Value, &attributes); err != nil {
+			return "", "", false, fmt.Errorf("patch value must be an object when no path is given")
+		}
+		for attribute, value := range attributes {
+			if strings.EqualFold(attribute, "name") {
+				var n scimName
+				if err := json.Unmarshal(value, &n); err != nil {
+					return "", "", false, err
+				}
+				if n.Formatted != "" {
+					name = n.Formatted
+				}
+				continue
+			}
+			if err := apply(op, attribute, value); err != nil {
+				return "", "", false, err
+			}
+		}
+	}
+
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	return externalID, name, active, nil
+}
+
+// handleSCIMUserDelete deprovisions a user from the organization
+//
+//	@Summary		Delete SCIM User
+//	@Description	Removes a provisioned user from the token's organization along with its departments and teams
+//	@Tags			scim
+//	@Param			userId	path	string	true	"user id"
+//	@Success		204
+//	@Failure		401	object	scimErrorResponse
+//	@Failure		404	object	scimErrorResponse
+//	@Security		SCIMBearerAuth
+//	@Router			/scim/v2/Users/{userId} [delete]
+func (s *Service) handleSCIMUserDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		orgID := ctx.Value(contextKeySCIMOrgID).(string)
+
+		user, ok := s.scimUserFromPath(w, r)
+		if !ok {
+			return
+		}
+
+		err := s.SCIMDataSvc.UserUnlink(ctx, orgID, user.ID)
+		if err == nil {
+			err = s.OrganizationDataSvc.OrganizationRemoveUser(ctx, orgID, user.ID)
+		}
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleSCIMUserDelete error", zap.Error(err),
+				zap.String("organization_id", orgID), zap.String("user_id", user.ID))
+			s.scimFailure(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+
+		s.recordSCIMAuditEvent(r, thunderdome.AuditActionSCIMUserDelete, thunderdome.AuditTargetTypeUser, user.ID, nil)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,451 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+type serviceAccountRequestBody struct {
+	Name        string `json:"name" validate:"required,min=1,max=64"`
+	Description string `json:"description" validate:"max=256"`
+}
+
+// serviceAccountOrganizationID checks organizations are enabled and validates the {orgId} path value,
+// responding with the failure and returning false when either check fails
+func (s *Service) serviceAccountOrganizationID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if !s.Config.OrganizationsEnabled {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ORGANIZATIONS_DISABLED"))
+		return "", false
+	}
+
+	orgID := r.PathValue("orgId")
+	if idErr := validate.Var(orgID, "required,uuid"); idErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+		return "", false
+	}
+
+	return orgID, true
+}
+
+// organizationServiceAccount validates the {orgId} and {serviceAccountId} path values and gets the service account,
+// responding with the failure and returning false when either is invalid or the account isn't owned by the organization
+func (s *Service) organizationServiceAccount(w http.ResponseWriter, r *http.Request) (*thunderdome.OrganizationServiceAccount, bool) {
+	orgID, ok := s.serviceAccountOrganizationID(w, r)
+	if !ok {
+		return nil, false
+	}
+	accountID := r.PathValue("serviceAccountId")
+	if idErr := validate.Var(accountID, "required,uuid"); idErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+		return nil, false
+	}
+
+	account, err := s.OrganizationDataSvc.OrganizationServiceAccountGet(r.Context(), orgID, accountID)
+	if err != nil && err.Error() == "SERVICE_ACCOUNT_NOT_FOUND" {
+		s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "SERVICE_ACCOUNT_NOT_FOUND"))
+		return nil, false
+	} else if err != nil {
+		s.serviceAccountFailure(w, r, "organizationServiceAccount", orgID, accountID, err)
+		return nil, false
+	}
+
+	return account, true
+}
+
+// serviceAccountAPIKeyID validates the {keyID} path value,
+// responding with the failure and returning false when it is invalid
+func (s *Service) serviceAccountAPIKeyID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	keyID := r.PathValue("keyID")
+	if keyIDErr := validate.Var(keyID, "required"); keyIDErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, keyIDErr.Error()))
+		return "", false
+	}
+
+	return keyID, true
+}
+
+// serviceAccountDecode reads and validates a request body, writing the failure and returning false when invalid
+func (s *Service) serviceAccountDecode(w http.ResponseWriter, r *http.Request, v any) bool {
+	body, bodyErr := io.ReadAll(r.Body)
+	if bodyErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, bodyErr.Error()))
+		return false
+	}
+
+	if jsonErr := json.Unmarshal(body, v); jsonErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, jsonErr.Error()))
+		return false
+	}
+
+	if inputErr := validate.Struct(v); inputErr != nil {
+		s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, inputErr.Error()))
+		return false
+	}
+
+	return true
+}
+
+// serviceAccountFailure logs the error of a service account handler and responds with it
+func (s *Service) serviceAccountFailure(w http.ResponseWriter, r *http.Request, handler string, orgID string, accountID string, err error, fields ...zap.Field) {
+	ctx := r.Context()
+	sessionUserID, _ := ctx.Value(contextKeyUserID).(string)
+
+	s.Logger.Ctx(ctx).Error(handler+" error", append([]zap.Field{zap.Error(err),
+		zap.String("organization_id", orgID), zap.String("service_account_id", accountID),
+		zap.String("session_user_id", sessionUserID)}, fields...)...)
+	s.Failure(w, r, http.StatusInternalServerError, err)
+}
+
+// handleOrganizationServiceAccounts gets a list of the organization's service accounts
+//
+//	@Summary		Get Organization Service Accounts
+//	@Description	Get a list of organization service accounts
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId	path	string	true	"organization id"
+//	@Success		200		object	standardJsonResponse{data=[]thunderdome.OrganizationServiceAccount}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/service-accounts [get]
+func (s *Service) handleOrganizationServiceAccounts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orgID, ok := s.serviceAccountOrganizationID(w, r)
+		if !ok {
+			return
+		}
+		ctx := r.Context()
+
+		accounts, err := s.OrganizationDataSvc.OrganizationServiceAccountList(ctx, orgID)
+		if err != nil {
+			s.serviceAccountFailure(w, r, "handleOrganizationServiceAccounts", orgID, "", err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, accounts, nil)
+	}
+}
+
+// handleOrganizationServiceAccountCreate handles creating an organization service account
+//
+//	@Summary		Create Organization Service Account
+//	@Description	Creates a non-login service account owned by the organization, it is added to the organization as a member
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId	path	string						true	"organization id"
+//	@Param			account	body	serviceAccountRequestBody	true	"new service account object"
+//	@Success		200		object	standardJsonResponse{data=thunderdome.OrganizationServiceAccount}
+//	@Failure		400		object	standardJsonResponse{}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/service-accounts [post]
+func (s *Service) handleOrganizationServiceAccountCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orgID, ok := s.serviceAccountOrganizationID(w, r)
+		if !ok {
+			return
+		}
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+
+		var sa = serviceAccountRequestBody{}
+		if !s.serviceAccountDecode(w, r, &sa) {
+			return
+		}
+
+		account, err := s.OrganizationDataSvc.OrganizationServiceAccountCreate(ctx, orgID, sessionUserID, sa.Name, sa.Description)
+		if err != nil {
+			s.serviceAccountFailure(w, r, "handleOrganizationServiceAccountCreate", orgID, "", err)
+			return
+		}
+
+		s.recordAuditEvent(r, thunderdome.AuditEvent{
+			Action:     thunderdome.AuditActionServiceAccountCreate,
+			TargetType: thunderdome.AuditTargetTypeServiceAccount,
+			TargetID:   account.ID,
+			Metadata:   map[string]string{"name": account.Name},
+		})
+
+		s.Success(w, r, http.StatusOK, account, nil)
+	}
+}
+
+// handleOrganizationServiceAccountUpdate handles updating an organization service account
+//
+//	@Summary		Update Organization Service Account
+//	@Description	Updates the name and description of an organization service account
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId				path	string						true	"organization id"
+//	@Param			serviceAccountId	path	string						true	"service account id"
+//	@Param			account				body	serviceAccountRequestBody	true	"updated service account object"
+//	@Success		200					object	standardJsonResponse{data=thunderdome.OrganizationServiceAccount}
+//	@Failure		400					object	standardJsonResponse{}
+//	@Failure		403					object	standardJsonResponse{}
+//	@Failure		404					object	standardJsonResponse{}
+//	@Failure		500					object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/service-accounts/{serviceAccountId} [put]
+func (s *Service) handleOrganizationServiceAccountUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		account, ok := s.organizationServiceAccount(w, r)
+		if !ok {
+			return
+		}
+		ctx := r.Context()
+
+		var sa = serviceAccountRequestBody{}
+		if !s.serviceAccountDecode(w, r, &sa) {
+			return
+		}
+
+		updated, err := s.OrganizationDataSvc.OrganizationServiceAccountUpdate(ctx, account.OrganizationID, account.ID, sa.Name, sa.Description)
+		if err != nil {
+			s.serviceAccountFailure(w, r, "handleOrganizationServiceAccountUpdate", account.OrganizationID, account.ID, err)
+			return
+		}
+
+		s.recordAuditEvent(r, thunderdome.AuditEvent{
+			Action:     thunderdome.AuditActionServiceAccountUpdate,
+			TargetType: thunderdome.AuditTargetTypeServiceAccount,
+			TargetID:   account.ID,
+			Metadata:   map[string]string{"name": updated.Name},
+		})
+
+		s.Success(w, r, http.StatusOK, updated, nil)
+	}
+}
+
+// handleOrganizationServiceAccountDelete handles deleting an organization service account
+//
+//	@Summary		Delete Organization Service Account
+//	@Description	Deletes an organization service account along with its API keys and team memberships
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId				path	string	true	"organization id"
+//	@Param			serviceAccountId	path	string	true	"service account id"
+//	@Success		200					object	standardJsonResponse{}
+//	@Failure		403					object	standardJsonResponse{}
+//	@Failure		404					object	standardJsonResponse{}
+//	@Failure		500					object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/service-accounts/{serviceAccountId} [delete]
+func (s *Service) handleOrganizationServiceAccountDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		account, ok := s.organizationServiceAccount(w, r)
+		if !ok {
+			return
+		}
+		ctx := r.Context()
+
+		err := s.OrganizationDataSvc.OrganizationServiceAccountDelete(ctx, account.OrganizationID, account.ID)
+		if err != nil {
+			s.serviceAccountFailure(w, r, "handleOrganizationServiceAccountDelete", account.OrganizationID, account.ID, err)
+			return
+		}
+
+		s.recordAuditEvent(r, thunderdome.AuditEvent{
+			Action:     thunderdome.AuditActionServiceAccountDelete,
+			TargetType: thunderdome.AuditTargetTypeServiceAccount,
+			TargetID:   account.ID,
+			Metadata:   map[string]string{"name": account.Name},
+		})
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+// handleOrganizationServiceAccountAPIKeys handles getting the API keys of an organization service account
+//
+//	@Summary		Get Organization Service Account API Keys
+//	@Description	Get a list of the API keys of an organization service account
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId				path	string	true	"organization id"
+//	@Param			serviceAccountId	path	string	true	"service account id"
+//	@Success		200					object	standardJsonResponse{data=[]thunderdome.APIKey}
+//	@Failure		403					object	standardJsonResponse{}
+//	@Failure		404					object	standardJsonResponse{}
+//	@Failure		500					object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/service-accounts/{serviceAccountId}/apikeys [get]
+func (s *Service) handleOrganizationServiceAccountAPIKeys() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		account, ok := s.organizationServiceAccount(w, r)
+		if !ok {
+			return
+		}
+		ctx := r.Context()
+
+		apiKeys, keysErr := s.ApiKeyDataSvc.GetUserAPIKeys(ctx, account.ID)
+		if keysErr != nil {
+			s.serviceAccountFailure(w, r, "handleOrganizationServiceAccountAPIKeys", account.OrganizationID, account.ID, keysErr)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, apiKeys, nil)
+	}
+}
+
+// handleOrganizationServiceAccountAPIKeyGenerate handles generating an API key for an organization service account
+//
+//	@Summary		Generate Organization Service Account API Key
+//	@Description	Generates an API key for the service account, the key is always restricted to the organization or one of its teams
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId				path	string						true	"organization id"
+//	@Param			serviceAccountId	path	string						true	"service account id"
+//	@Param			key					body	apikeyGenerateRequestBody	true	"new APIKey key object"
+//	@Success		200					object	standardJsonResponse{data=thunderdome.APIKey}
+//	@Failure		400					object	standardJsonResponse{}
+//	@Failure		403					object	standardJsonResponse{}
+//	@Failure		404					object	standardJsonResponse{}
+//	@Failure		500					object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/service-accounts/{serviceAccountId}/apikeys [post]
+func (s *Service) handleOrganizationServiceAccountAPIKeyGenerate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		account, ok := s.organizationServiceAccount(w, r)
+		if !ok {
+			return
+		}
+		ctx := r.Context()
+
+		var k = apikeyGenerateRequestBody{}
+		if !s.serviceAccountDecode(w, r, &k) {
+			return
+		}
+
+		restrictions, restrictionsErr := k.restrictions(time.Now())
+		if restrictionsErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, restrictionsErr.Error()))
+			return
+		}
+		// service account keys never reach beyond the owning organization
+		if restrictions.OrganizationID != nil && *restrictions.OrganizationID != account.OrganizationID {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "ORGANIZATION_NOT_FOUND"))
+			return
+		}
+		if restrictions.TeamID != nil {
+			team, err := s.TeamDataSvc.TeamGetByID(ctx, *restrictions.TeamID)
+			if err != nil || team.OrganizationID != account.OrganizationID {
+				s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, "TEAM_NOT_FOUND"))
+				return
+			}
+		} else {
+			restrictions.OrganizationID = &account.OrganizationID
+		}
+
+		if account.APIKeyCount >= s.Config.UserAPIKeyLimit {
+			s.Failure(w, r, http.StatusForbidden, Errorf(EINVALID, "USER_APIKEY_LIMIT_REACHED"))
+			return
+		}
+
+		apiKey, keyErr := s.ApiKeyDataSvc.GenerateAPIKey(ctx, account.ID, k.Name, restrictions)
+		if keyErr != nil {
+			s.serviceAccountFailure(w, r, "handleOrganizationServiceAccountAPIKeyGenerate", account.OrganizationID, account.ID, keyErr)
+			return
+		}
+
+		s.recordAPIKeyAuditEvent(r, thunderdome.AuditActionAPIKeyCreate, apiKey.ID, account.ID, map[string]string{
+			"name":   apiKey.Name,
+			"scopes": strings.Join(apiKey.Scopes, " "),
+		})
+
+		s.Success(w, r, http.StatusOK, apiKey, nil)
+	}
+}
+
+// handleOrganizationServiceAccountAPIKeyUpdate handles updating an organization service account API key
+//
+//	@Summary		Update Organization Service Account API Key
+//	@Description	Updates the active status of an organization service account API key
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId				path	string					true	"organization id"
+//	@Param			serviceAccountId	path	string					true	"service account id"
+//	@Param			keyID				path	string					true	"the API Key ID to update"
+//	@Param			key					body	apikeyUpdateRequestBody	true	"APIKey key object to update"
+//	@Success		200					object	standardJsonResponse{data=[]thunderdome.APIKey}
+//	@Failure		403					object	standardJsonResponse{}
+//	@Failure		404					object	standardJsonResponse{}
+//	@Failure		500					object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/service-accounts/{serviceAccountId}/apikeys/{keyID} [put]
+func (s *Service) handleOrganizationServiceAccountAPIKeyUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		account, ok := s.organizationServiceAccount(w, r)
+		if !ok {
+			return
+		}
+		keyID, ok := s.serviceAccountAPIKeyID(w, r)
+		if !ok {
+			return
+		}
+		ctx := r.Context()
+
+		var k = apikeyUpdateRequestBody{}
+		if !s.serviceAccountDecode(w, r, &k) {
+			return
+		}
+
+		keys, keysErr := s.ApiKeyDataSvc.UpdateUserAPIKey(ctx, account.ID, keyID, k.Active)
+		if keysErr != nil {
+			s.serviceAccountFailure(w, r, "handleOrganizationServiceAccountAPIKeyUpdate", account.OrganizationID, account.ID, keysErr, zap.String("apikey_id", keyID))
+			return
+		}
+
+		s.recordAPIKeyAuditEvent(r, thunderdome.AuditActionAPIKeyUpdate, keyID, account.ID, map[string]string{
+			"active": strconv.FormatBool(k.Active),
+		})
+
+		s.Success(w, r, http.StatusOK, keys, nil)
+	}
+}
+
+// handleOrganizationServiceAccountAPIKeyDelete handles deleting an organization service account API key
+//
+//	@Summary		Delete Organization Service Account API Key
+//	@Description	Deletes an organization service account API key
+//	@Tags			organization
+//	@Produce		json
+//	@Param			orgId				path	string	true	"organization id"
+//	@Param			serviceAccountId	path	string	true	"service account id"
+//	@Param			keyID				path	string	true	"the API Key ID to delete"
+//	@Success		200					object	standardJsonResponse{data=[]thunderdome.APIKey}
+//	@Failure		403					object	standardJsonResponse{}
+//	@Failure		404					object	standardJsonResponse{}
+//	@Failure		500					object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/organizations/{orgId}/service-accounts/{serviceAccountId}/apikeys/{keyID} [delete]
+func (s *Service) handleOrganizationServiceAccountAPIKeyDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		account, ok := s.organizationServiceAccount(w, r)
+		if !ok {
+			return
+		}
+		keyID, ok := s.serviceAccountAPIKeyID(w, r)
+		if !ok {
+			return
+		}
+		ctx := r.Context()
+
+		keys, keysErr := s.ApiKeyDataSvc.DeleteUserAPIKey(ctx, account.ID, keyID)
+		if keysErr != nil {
+			s.serviceAccountFailure(w, r, "handleOrganizationServiceAccountAPIKeyDelete", account.OrganizationID, account.ID, keysErr, zap.String("apikey_id", keyID))
+			return
+		}
+
+		s.recordAPIKeyAuditEvent(r, thunderdome.AuditActionAPIKeyDelete, keyID, account.ID, nil)
+
+		s.Success(w, r, http.StatusOK, keys, nil)
+	}
+}
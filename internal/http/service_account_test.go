@@ -0,0 +1,283 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const (
+	testServiceAccountOrgID      = "6d2c1f8e-0a4b-4c1e-9d7f-2b3a4c5d6e7f"
+	testServiceAccountOtherOrgID = "1f2e3d4c-5b6a-4978-8a9b-0c1d2e3f4a5b"
+	testServiceAccountID         = "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"
+	testServiceAccountTeamID     = "b2c3d4e5-f6a7-4b8c-9d0e-1f2a3b4c5d6e"
+	testServiceAccountAdminID    = "c3d4e5f6-a7b8-4c9d-8e1f-2a3b4c5d6e7f"
+)
+
+type MockAPIKeyDataSvc struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyDataSvc) GenerateAPIKey(ctx context.Context, userID string, keyName string, restrictions thunderdome.APIKeyRestrictions) (*thunderdome.APIKey, error) {
+	args := m.Called(ctx, userID, keyName, restrictions)
+	key, _ := args.Get(0).(*thunderdome.APIKey)
+	return key, args.Error(1)
+}
+
+func (m *MockAPIKeyDataSvc) GetUserAPIKeys(ctx context.Context, userID string) ([]*thunderdome.APIKey, error) {
+	args := m.Called(ctx, userID)
+	keys, _ := args.Get(0).([]*thunderdome.APIKey)
+	return keys, args.Error(1)
+}
+
+func (m *MockAPIKeyDataSvc) GetAPIKeyUser(ctx context.Context, apiKey string) (*thunderdome.User, *thunderdome.APIKey, error) {
+	args := m.Called(ctx, apiKey)
+	user, _ := args.Get(0).(*thunderdome.User)
+	key, _ := args.Get(1).(*thunderdome.APIKey)
+	return user, key, args.Error(2)
+}
+
+func (m *MockAPIKeyDataSvc) GetAPIKeys(ctx context.Context, limit int, offset int) []*thunderdome.UserAPIKey {
+	args := m.Called(ctx, limit, offset)
+	keys, _ := args.Get(0).([]*thunderdome.UserAPIKey)
+	return keys
+}
+
+func (m *MockAPIKeyDataSvc) UpdateUserAPIKey(ctx context.Context, userID string, keyID string, active bool) ([]*thunderdome.APIKey, error) {
+	args := m.Called(ctx, userID, keyID, active)
+	keys, _ := args.Get(0).([]*thunderdome.APIKey)
+	return keys, args.Error(1)
+}
+
+func (m *MockAPIKeyDataSvc) DeleteUserAPIKey(ctx context.Context, userID string, keyID string) ([]*thunderdome.APIKey, error) {
+	args := m.Called(ctx, userID, keyID)
+	keys, _ := args.Get(0).([]*thunderdome.APIKey)
+	return keys, args.Error(1)
+}
+
+type serviceAccountTest struct {
+	service *Service
+	orgs    *MockOrganizationDataService
+	teams   *MockTeamDataSvc
+	apiKeys *MockAPIKeyDataSvc
+}
+
+func newServiceAccountTest() *serviceAccountTest {
+	orgs := new(MockOrganizationDataService)
+	teams := new(MockTeamDataSvc)
+	apiKeys := new(MockAPIKeyDataSvc)
+
+	return &serviceAccountTest{
+		service: &Service{
+			Config:              &Config{OrganizationsEnabled: true, UserAPIKeyLimit: 5},
+			Logger:              otelzap.New(zap.NewNop()),
+			OrganizationDataSvc: orgs,
+			TeamDataSvc:         teams,
+			ApiKeyDataSvc:       apiKeys,
+		},
+		orgs:    orgs,
+		teams:   teams,
+		apiKeys: apiKeys,
+	}
+}
+
+func (st *serviceAccountTest) account(orgID string) {
+	if orgID == testServiceAccountOrgID {
+		st.orgs.On("OrganizationServiceAccountGet", mock.Anything, orgID, testServiceAccountID).
+			Return(&thunderdome.OrganizationServiceAccount{
+				ID:             testServiceAccountID,
+				OrganizationID: testServiceAccountOrgID,
+				Name:           "CI",
+				APIKeyCount:    1,
+			}, nil)
+		return
+	}
+
+	st.orgs.On("OrganizationServiceAccountGet", mock.Anything, orgID, testServiceAccountID).
+		Return(nil, errors.New("SERVICE_ACCOUNT_NOT_FOUND"))
+}
+
+// serve calls the handler as the organization admin with the path values set as the router would
+func (st *serviceAccountTest) serve(handler http.HandlerFunc, method string, body string, pathValues map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	for name, value := range pathValues {
+		req.SetPathValue(name, value)
+	}
+	ctx := context.WithValue(req.Context(), contextKeyUserID, testServiceAccountAdminID)
+	ctx = context.WithValue(ctx, contextKeyUserType, thunderdome.RegisteredUserType)
+	w := httptest.NewRecorder()
+
+	handler(w, req.WithContext(ctx))
+
+	return w
+}
+
+func accountPath(orgID string) map[string]string {
+	return map[string]string{"orgId": orgID, "serviceAccountId": testServiceAccountID}
+}
+
+func TestHandleOrganizationServiceAccountCreate(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "creates the account", body: `{"name":"CI","description":"deploys"}`, expectedStatus: http.StatusOK},
+		{name: "name required", body: `{"description":"deploys"}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newServiceAccountTest()
+			st.orgs.On("OrganizationServiceAccountCreate", mock.Anything, testServiceAccountOrgID,
+				testServiceAccountAdminID, "CI", "deploys").
+				Return(&thunderdome.OrganizationServiceAccount{ID: testServiceAccountID, Name: "CI"}, nil)
+
+			w := st.serve(st.service.handleOrganizationServiceAccountCreate(), http.MethodPost, tt.body,
+				map[string]string{"orgId": testServiceAccountOrgID})
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				st.orgs.AssertNotCalled(t, "OrganizationServiceAccountCreate",
+					mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			var response struct {
+				Data thunderdome.OrganizationServiceAccount `json:"data"`
+			}
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, testServiceAccountID, response.Data.ID)
+			st.orgs.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleOrganizationServiceAccountRotateAPIKey(t *testing.T) {
+	st := newServiceAccountTest()
+	st.account(testServiceAccountOrgID)
+	// keys without a team are restricted to the owning organization
+	st.apiKeys.On("GenerateAPIKey", mock.Anything, testServiceAccountID, "deploy",
+		thunderdome.APIKeyRestrictions{OrganizationID: ptr(testServiceAccountOrgID)}).
+		Return(&thunderdome.APIKey{ID: "newkey.secret", Name: "deploy"}, nil)
+	st.apiKeys.On("DeleteUserAPIKey", mock.Anything, testServiceAccountID, "oldkey.secret").
+		Return([]*thunderdome.APIKey{{ID: "newkey.secret"}}, nil)
+
+	w := st.serve(st.service.handleOrganizationServiceAccountAPIKeyGenerate(), http.MethodPost,
+		`{"name":"deploy"}`, accountPath(testServiceAccountOrgID))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	pathValues := accountPath(testServiceAccountOrgID)
+	pathValues["keyID"] = "oldkey.secret"
+	w = st.serve(st.service.handleOrganizationServiceAccountAPIKeyDelete(), http.MethodDelete, "", pathValues)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	st.apiKeys.AssertExpectations(t)
+}
+
+func TestHandleOrganizationServiceAccountDelete(t *testing.T) {
+	st := newServiceAccountTest()
+	st.account(testServiceAccountOrgID)
+	st.orgs.On("OrganizationServiceAccountDelete", mock.Anything, testServiceAccountOrgID, testServiceAccountID).
+		Return(nil)
+
+	w := st.serve(st.service.handleOrganizationServiceAccountDelete(), http.MethodDelete, "",
+		accountPath(testServiceAccountOrgID))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	st.orgs.AssertExpectations(t)
+}
+
+func TestOrganizationServiceAccountOtherOrganization(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(s *Service) http.HandlerFunc
+		method  string
+		body    string
+	}{
+		{name: "update", handler: (*Service).handleOrganizationServiceAccountUpdate, method: http.MethodPut, body: `{"name":"CI"}`},
+		{name: "delete", handler: (*Service).handleOrganizationServiceAccountDelete, method: http.MethodDelete},
+		{name: "list keys", handler: (*Service).handleOrganizationServiceAccountAPIKeys, method: http.MethodGet},
+		{name: "generate key", handler: (*Service).handleOrganizationServiceAccountAPIKeyGenerate, method: http.MethodPost, body: `{"name":"deploy"}`},
+		{name: "update key", handler: (*Service).handleOrganizationServiceAccountAPIKeyUpdate, method: http.MethodPut, body: `{"active":false}`},
+		{name: "delete key", handler: (*Service).handleOrganizationServiceAccountAPIKeyDelete, method: http.MethodDelete},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newServiceAccountTest()
+			st.account(testServiceAccountOtherOrgID)
+			pathValues := accountPath(testServiceAccountOtherOrgID)
+			pathValues["keyID"] = "key.secret"
+
+			w := st.serve(tt.handler(st.service), tt.method, tt.body, pathValues)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			st.orgs.AssertNotCalled(t, "OrganizationServiceAccountUpdate",
+				mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			st.orgs.AssertNotCalled(t, "OrganizationServiceAccountDelete", mock.Anything, mock.Anything, mock.Anything)
+			assert.Empty(t, st.apiKeys.Calls)
+		})
+	}
+}
+
+func TestHandleOrganizationServiceAccountAPIKeyOtherOrganization(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantError string
+	}{
+		{
+			name:      "organization restriction",
+			body:      `{"name":"deploy","organizationId":"` + testServiceAccountOtherOrgID + `"}`,
+			wantError: "ORGANIZATION_NOT_FOUND",
+		},
+		{
+			name:      "team restriction",
+			body:      `{"name":"deploy","teamId":"` + testServiceAccountTeamID + `"}`,
+			wantError: "TEAM_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newServiceAccountTest()
+			st.account(testServiceAccountOrgID)
+			st.teams.On("TeamGetByID", mock.Anything, testServiceAccountTeamID).
+				Return(&thunderdome.Team{ID: testServiceAccountTeamID, OrganizationID: testServiceAccountOtherOrgID}, nil)
+
+			w := st.serve(st.service.handleOrganizationServiceAccountAPIKeyGenerate(), http.MethodPost, tt.body,
+				accountPath(testServiceAccountOrgID))
+
+			var response standardJsonResponse
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.wantError, response.Error)
+			assert.Empty(t, st.apiKeys.Calls)
+		})
+	}
+}
+
+func TestHandleOrganizationRemoveUserServiceAccount(t *testing.T) {
+	st := newServiceAccountTest()
+	st.orgs.On("OrganizationRemoveUser", mock.Anything, testServiceAccountOrgID, testServiceAccountID).
+		Return(errors.New("SERVICE_ACCOUNT_MEMBER"))
+
+	w := st.serve(st.service.handleOrganizationRemoveUser(), http.MethodDelete, "",
+		map[string]string{"orgId": testServiceAccountOrgID, "userId": testServiceAccountID})
+
+	var response standardJsonResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "SERVICE_ACCOUNT_MEMBER", response.Error)
+}
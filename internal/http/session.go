@@ -0,0 +1,195 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"go.uber.org/zap"
+)
+
+// createSessionCookie sets the cookie of a newly created session, recording the IP address and user agent
+// the session was created from so it can be recognized in the user's session list
+func (s *Service) createSessionCookie(w http.ResponseWriter, r *http.Request, sessionID string) error {
+	s.recordSessionClient(r, sessionID)
+
+	return s.Cookie.CreateSessionCookie(w, sessionID)
+}
+
+// recordSessionClient records the IP address and user agent of the request on the session,
+// failures are logged and never fail the request
+func (s *Service) recordSessionClient(r *http.Request, sessionID string) {
+	ctx := r.Context()
+
+	if err := s.AuthDataSvc.TouchSession(ctx, sessionID, s.clientIP(r), r.UserAgent()); err != nil {
+		s.Logger.Ctx(ctx).Warn("recordSessionClient error", zap.Error(err))
+	}
+}
+
+// handleUserSessions handles getting the active sessions of a user
+//
+//	@Summary		Get User Sessions
+//	@Description	get list of active sessions for the user, the session making the request is marked as current
+//	@Tags			user
+//	@Produce		json
+//	@Param			userId	path	string	true	"the user ID to get sessions for"
+//	@Success		200		object	standardJsonResponse{data=[]thunderdome.UserSession}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/sessions [get]
+func (s *Service) handleUserSessions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userID := r.PathValue("userId")
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		currentSessionID, _ := s.Cookie.ValidateSessionCookie(w, r)
+
+		sessions, err := s.AuthDataSvc.GetUserSessions(ctx, userID, currentSessionID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleUserSessions error", zap.Error(err),
+				zap.String("entity_user_id", userID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.Success(w, r, http.StatusOK, sessions, nil)
+	}
+}
+
+// handleUserSessionDelete handles revoking one of the user's sessions
+//
+//	@Summary		Revoke User Session
+//	@Description	Revokes an active session of the user, logging that device out
+//	@Tags			user
+//	@Produce		json
+//	@Param			userId		path	string	true	"the user ID"
+//	@Param			sessionId	path	string	true	"the session ID to revoke"
+//	@Success		200			object	standardJsonResponse{}
+//	@Failure		403			object	standardJsonResponse{}
+//	@Failure		404			object	standardJsonResponse{}
+//	@Failure		500			object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/sessions/{sessionId} [delete]
+func (s *Service) handleUserSessionDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userID := r.PathValue("userId")
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		sessionID := r.PathValue("sessionId")
+		idErr = validate.Var(sessionID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		err := s.AuthDataSvc.DeleteUserSession(ctx, userID, sessionID)
+		if err != nil && err.Error() == "SESSION_NOT_FOUND" {
+			s.Failure(w, r, http.StatusNotFound, Errorf(ENOTFOUND, "SESSION_NOT_FOUND"))
+			return
+		} else if err != nil {
+			s.Logger.Ctx(ctx).Error("handleUserSessionDelete error", zap.Error(err),
+				zap.String("entity_user_id", userID), zap.String("user_session_id", sessionID),
+				zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.recordAuditEvent(r, thunderdome.AuditEvent{
+			Action:     thunderdome.AuditActionSessionRevoke,
+			TargetType: thunderdome.AuditTargetTypeSession,
+			TargetID:   sessionID,
+			Metadata:   map[string]string{"userId": userID},
+		})
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+// handleUserSessionsDelete handles revoking all the user's sessions other than the current session
+//
+//	@Summary		Revoke Other User Sessions
+//	@Description	Revokes all active sessions of the user except the session making the request
+//	@Tags			user
+//	@Produce		json
+//	@Param			userId	path	string	true	"the user ID"
+//	@Success		200		object	standardJsonResponse{}
+//	@Failure		403		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/users/{userId}/sessions [delete]
+func (s *Service) handleUserSessionsDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userID := r.PathValue("userId")
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+		currentSessionID, _ := s.Cookie.ValidateSessionCookie(w, r)
+
+		revoked, err := s.AuthDataSvc.DeleteUserSessions(ctx, userID, currentSessionID)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleUserSessionsDelete error", zap.Error(err),
+				zap.String("entity_user_id", userID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.recordUserAuditEvent(r, thunderdome.AuditActionSessionRevoke, userID, map[string]string{
+			"revoked": strconv.Itoa(revoked),
+		})
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
+
+// handleAdminUserSessionsDelete handles revoking all sessions of a user
+//
+//	@Summary		Revoke User Sessions
+//	@Description	Revokes all active sessions of a user, forcing them to log in again
+//	@Tags			admin
+//	@Produce		json
+//	@Param			userId	path	string	true	"the user ID to revoke sessions for"
+//	@Success		200		object	standardJsonResponse{}
+//	@Failure		500		object	standardJsonResponse{}
+//	@Security		ApiKeyAuth
+//	@Router			/admin/users/{userId}/sessions [delete]
+func (s *Service) handleAdminUserSessionsDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		sessionUserID := ctx.Value(contextKeyUserID).(string)
+		userID := r.PathValue("userId")
+		idErr := validate.Var(userID, "required,uuid")
+		if idErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
+			return
+		}
+
+		revoked, err := s.AuthDataSvc.DeleteUserSessions(ctx, userID, "")
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleAdminUserSessionsDelete error", zap.Error(err),
+				zap.String("entity_user_id", userID), zap.String("session_user_id", sessionUserID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.recordUserAuditEvent(r, thunderdome.AuditActionAdminUserSessionsRevoke, userID, map[string]string{
+			"revoked": strconv.Itoa(revoked),
+		})
+
+		s.Success(w, r, http.StatusOK, nil, nil)
+	}
+}
@@ -0,0 +1,188 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+const (
+	testSessionUserID  = "6d2c1f8e-0a4b-4c1e-9d7f-2b3a4c5d6e7f"
+	testSessionOtherID = "1f2e3d4c-5b6a-4978-8a9b-0c1d2e3f4a5b"
+	testSessionRowID   = "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"
+)
+
+// fakeSessionAuthDataSvc records the session calls made by the handlers
+type fakeSessionAuthDataSvc struct {
+	AuthDataSvc
+	touched        []string
+	currentSession string
+	deleted        []string
+	exceptSession  string
+}
+
+func (f *fakeSessionAuthDataSvc) TouchSession(ctx context.Context, sessionID string, ipAddress string, userAgent string) error {
+	f.touched = append(f.touched, sessionID, ipAddress, userAgent)
+	return nil
+}
+
+func (f *fakeSessionAuthDataSvc) GetUserSessions(ctx context.Context, userID string, currentSessionID string) ([]*thunderdome.UserSession, error) {
+	f.currentSession = currentSessionID
+	return []*thunderdome.UserSession{{ID: testSessionRowID, Current: true}}, nil
+}
+
+func (f *fakeSessionAuthDataSvc) DeleteUserSession(ctx context.Context, userID string, id string) error {
+	f.deleted = append(f.deleted, userID+"/"+id)
+	// mirrors the data layer, sessions only match when they belong to the user
+	if userID != testSessionUserID {
+		return errors.New("SESSION_NOT_FOUND")
+	}
+	return nil
+}
+
+func (f *fakeSessionAuthDataSvc) DeleteUserSessions(ctx context.Context, userID string, exceptSessionID string) (int, error) {
+	f.exceptSession = exceptSessionID
+	return 2, nil
+}
+
+// sessionCookieManager returns a fixed current session and records created session cookies
+type sessionCookieManager struct {
+	CookieManager
+	created string
+}
+
+func (c *sessionCookieManager) CreateSessionCookie(w http.ResponseWriter, sessionID string) error {
+	c.created = sessionID
+	return nil
+}
+
+func (c *sessionCookieManager) ValidateSessionCookie(w http.ResponseWriter, r *http.Request) (string, error) {
+	return "current-session", nil
+}
+
+func newSessionTestService() (*Service, *fakeSessionAuthDataSvc, *sessionCookieManager) {
+	auth := &fakeSessionAuthDataSvc{}
+	cookie := &sessionCookieManager{}
+
+	return &Service{
+		Config: &Config{
+			TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+		},
+		Cookie:      cookie,
+		Logger:      otelzap.New(zap.NewNop()),
+		AuthDataSvc: auth,
+	}, auth, cookie
+}
+
+func sessionRequest(method string, userID string, userType string, sessionID string) *http.Request {
+	req := httptest.NewRequest(method, "/api/users/"+userID+"/sessions", nil)
+	req.SetPathValue("userId", userID)
+	if sessionID != "" {
+		req.SetPathValue("sessionId", sessionID)
+	}
+	ctx := context.WithValue(req.Context(), contextKeyUserID, testSessionUserID)
+	ctx = context.WithValue(ctx, contextKeyUserType, userType)
+
+	return req.WithContext(ctx)
+}
+
+func TestCreateSessionCookieRecordsClient(t *testing.T) {
+	s, auth, cookie := newSessionTestService()
+	req := httptest.NewRequest(http.MethodPost, "/api/auth", nil)
+	req.RemoteAddr = "10.0.0.2:4321"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	req.Header.Set("User-Agent", "Firefox")
+
+	if err := s.createSessionCookie(httptest.NewRecorder(), req, "new-session"); err != nil {
+		t.Fatalf("createSessionCookie() error = %v", err)
+	}
+	if cookie.created != "new-session" {
+		t.Errorf("session cookie = %q, want new-session", cookie.created)
+	}
+	want := []string{"new-session", "203.0.113.7", "Firefox"}
+	if len(auth.touched) != 3 || auth.touched[0] != want[0] || auth.touched[1] != want[1] || auth.touched[2] != want[2] {
+		t.Errorf("TouchSession() called with %v, want %v", auth.touched, want)
+	}
+}
+
+func TestHandleUserSessionsMarksCurrent(t *testing.T) {
+	s, auth, _ := newSessionTestService()
+	w := httptest.NewRecorder()
+
+	s.entityUserOnly(s.handleUserSessions())(w, sessionRequest(http.MethodGet, testSessionUserID, thunderdome.RegisteredUserType, ""))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if auth.currentSession != "current-session" {
+		t.Errorf("GetUserSessions() current session = %q, want current-session", auth.currentSession)
+	}
+}
+
+func TestHandleUserSessionsDeleteKeepsCurrent(t *testing.T) {
+	s, auth, _ := newSessionTestService()
+	w := httptest.NewRecorder()
+
+	s.entityUserOnly(s.handleUserSessionsDelete())(w, sessionRequest(http.MethodDelete, testSessionUserID, thunderdome.RegisteredUserType, ""))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if auth.exceptSession != "current-session" {
+		t.Errorf("DeleteUserSessions() kept %q, want current-session", auth.exceptSession)
+	}
+}
+
+func TestHandleUserSessionDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		pathUserID  string
+		userType    string
+		wantStatus  int
+		wantDeleted []string
+	}{
+		{
+			name:        "own session",
+			pathUserID:  testSessionUserID,
+			userType:    thunderdome.RegisteredUserType,
+			wantStatus:  http.StatusOK,
+			wantDeleted: []string{testSessionUserID + "/" + testSessionRowID},
+		},
+		{
+			name:       "another user's session",
+			pathUserID: testSessionOtherID,
+			userType:   thunderdome.RegisteredUserType,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:        "session not belonging to the user",
+			pathUserID:  testSessionOtherID,
+			userType:    thunderdome.AdminUserType,
+			wantStatus:  http.StatusNotFound,
+			wantDeleted: []string{testSessionOtherID + "/" + testSessionRowID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, auth, _ := newSessionTestService()
+			w := httptest.NewRecorder()
+
+			s.entityUserOnly(s.handleUserSessionDelete())(w, sessionRequest(http.MethodDelete, tt.pathUserID, tt.userType, testSessionRowID))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if len(auth.deleted) != len(tt.wantDeleted) || (len(tt.wantDeleted) > 0 && auth.deleted[0] != tt.wantDeleted[0]) {
+				t.Errorf("DeleteUserSession() calls = %v, want %v", auth.deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
@@ -25,6 +25,9 @@ type Config struct {
 
 	// Websocket Subdomain (for Websocket origin check)
 	WebsocketSubdomain string
+
+	// Broadcast backend for fanning out events to other instances
+	BroadcastBackend wshub.Backend
 }
 
 type AuthDataSvc interface {
@@ -117,6 +120,8 @@ func New(
 		WriteWaitSec:       config.WriteWaitSec,
 		PongWaitSec:        config.PongWaitSec,
 		PingPeriodSec:      config.PingPeriodSec,
+		Backend:            config.BroadcastBackend,
+		Channel:            "storyboard",
 	}, map[string]func(context.Context, string, string, string) (any, []byte, error, bool){
 		"add_goal":              sb.AddGoal,
 		"revise_goal":           sb.ReviseGoal,
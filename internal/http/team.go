@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -354,6 +355,7 @@ func (s *Service) handleTeamInviteUser() http.HandlerFunc {
 					s.Failure(w, r, http.StatusInternalServerError, err)
 					return
 				}
+				s.recordUserAuditEvent(r, thunderdome.AuditActionTeamUserAdd, user.ID, map[string]string{"teamId": teamID, "role": u.Role})
 				s.Success(w, r, http.StatusOK, nil, userAddMeta{Invited: false, Added: true})
 				return
 			} else if userErr != nil && !errors.Is(userErr, sql.ErrNoRows) {
@@ -371,6 +373,13 @@ func (s *Service) handleTeamInviteUser() http.HandlerFunc {
 			s.Failure(w, r, http.StatusInternalServerError, inviteErr)
 			return
 		}
+
+		s.recordAuditEvent(r, thunderdome.AuditEvent{
+			Action:     thunderdome.AuditActionTeamUserInvite,
+			TargetType: thunderdome.AuditTargetTypeInvite,
+			TargetID:   inviteID,
+			Metadata:   map[string]string{"teamId": teamID, "email": userEmail, "role": u.Role},
+		})
 		team, teamErr := s.TeamDataSvc.TeamGetByID(ctx, teamID)
 		if teamErr != nil {
 			s.Logger.Ctx(ctx).Error("handleTeamInviteUser error", zap.Error(teamErr),
@@ -454,6 +463,8 @@ func (s *Service) handleTeamUpdateUser() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionTeamUserUpdate, userID, map[string]string{"teamId": teamID, "role": u.Role})
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
@@ -497,6 +508,8 @@ func (s *Service) handleTeamRemoveUser() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionTeamUserRemove, userID, map[string]string{"teamId": teamID})
+
 		s.Success(w, r, http.StatusOK, nil, nil)
 	}
 }
@@ -892,21 +905,31 @@ func (s *Service) handleDeleteTeamUserInvite() http.HandlerFunc {
 //
 //	@Summary		Get Team Metrics
 //	@Description	Get metrics for a specific team such as user count, poker game count, etc.
-//	@Tags			admin
+//	@Description	the team's retro health check results over time, and the team's estimation metrics:
+//	@Description	points estimated per period, vote spread and consensus per story, average rounds to consensus,
+//	@Description	final points distribution per estimation scale, and skip rate from the team's poker games
+//	@Tags			team
 //	@Produce		json
-//	@Param			teamID	path	string	true	"Team ID"
-//	@Success		200		object	standardJsonResponse{data=thunderdome.TeamMetrics}
-//	@Failure		400		object	standardJsonResponse{}
-//	@Failure		404		object	standardJsonResponse{}
-//	@Failure		500		object	standardJsonResponse{}
+//	@Param			orgId			path	string	false	"the organization ID"
+//	@Param			departmentId	path	string	false	"the department ID"
+//	@Param			teamId			path	string	true	"the team ID"
+//	@Param			from			query	string	false	"estimation metrics start date (YYYY-MM-DD), defaults to 90 days before to"
+//	@Param			to				query	string	false	"estimation metrics end date inclusive (YYYY-MM-DD), defaults to today"
+//	@Param			period			query	string	false	"period to group estimated points by"	Enums(week, month)
+//	@Success		200				object	standardJsonResponse{data=thunderdome.TeamMetrics}
+//	@Failure		400				object	standardJsonResponse{}
+//	@Failure		404				object	standardJsonResponse{}
+//	@Failure		500				object	standardJsonResponse{}
 //	@Security		ApiKeyAuth
-//	@Router			/admin/teams/{teamID}/metrics [get]
+//	@Router			/teams/{teamId}/metrics [get]
+//	@Router			/organizations/{orgId}/teams/{teamId}/metrics [get]
+//	@Router			/organizations/{orgId}/departments/{departmentId}/teams/{teamId}/metrics [get]
 func (s *Service) handleTeamMetrics() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		sessionUserID := ctx.Value(contextKeyUserID).(string)
 
-		teamID := r.PathValue("teamID")
+		teamID := r.PathValue("teamId")
 		idErr := validate.Var(teamID, "required,uuid")
 		if idErr != nil {
 			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, idErr.Error()))
@@ -918,6 +941,13 @@ func (s *Service) handleTeamMetrics() http.HandlerFunc {
 			return
 		}
 
+		query := r.URL.Query()
+		from, to, period, rangeErr := estimationMetricsRange(query.Get("from"), query.Get("to"), query.Get("period"))
+		if rangeErr != nil {
+			s.Failure(w, r, http.StatusBadRequest, Errorf(EINVALID, rangeErr.Error()))
+			return
+		}
+
 		metrics, err := s.TeamDataSvc.GetTeamMetrics(ctx, teamID)
 		if err != nil {
 			s.Logger.Ctx(ctx).Error("handleTeamMetrics error", zap.Error(err),
@@ -931,6 +961,54 @@ func (s *Service) handleTeamMetrics() http.HandlerFunc {
 			return
 		}
 
+		metrics.Estimation, err = s.TeamDataSvc.GetTeamEstimationMetrics(ctx, teamID, from, to, period)
+		if err != nil {
+			s.Logger.Ctx(ctx).Error("handleTeamMetrics estimation metrics error", zap.Error(err),
+				zap.String("session_user_id", sessionUserID),
+				zap.String("team_id", teamID))
+			s.Failure(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
 		s.Success(w, r, http.StatusOK, metrics, nil)
 	}
 }
+
+// estimationMetricsRange resolves the from and to dates (YYYY-MM-DD, inclusive) and period query params
+// into a time range ending at the start of the day after to, defaulting to the last 90 days grouped by week
+func estimationMetricsRange(from string, to string, period string) (time.Time, time.Time, string, error) {
+	if period == "" {
+		period = thunderdome.EstimationPeriodWeek
+	}
+	if period != thunderdome.EstimationPeriodWeek && period != thunderdome.EstimationPeriodMonth {
+		return time.Time{}, time.Time{}, "", errors.New("period must be one of week, month")
+	}
+
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	if to != "" {
+		parsed, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return time.Time{}, time.Time{}, "", errors.New("to must be a date formatted as YYYY-MM-DD")
+		}
+		end = parsed
+	}
+	end = end.AddDate(0, 0, 1)
+
+	start := end.AddDate(0, 0, -90)
+	if from != "" {
+		parsed, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return time.Time{}, time.Time{}, "", errors.New("from must be a date formatted as YYYY-MM-DD")
+		}
+		start = parsed
+	}
+
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, "", errors.New("from must not be after to")
+	}
+	if end.Sub(start) > 366*24*time.Hour {
+		return time.Time{}, time.Time{}, "", errors.New("date range must not exceed 366 days")
+	}
+
+	return start, end, period, nil
+}
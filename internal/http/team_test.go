@@ -0,0 +1,79 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+func TestHandleTeamMetrics(t *testing.T) {
+	const teamID = "6d2c1f8e-0a4b-4c1e-9d7f-2b3a4c5d6e7f"
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	// to is inclusive so the range ends at the start of the next day
+	to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMocks     func(m *MockTeamDataSvc)
+		expectedStatus int
+	}{
+		{
+			name:  "includes estimation metrics",
+			query: "?from=2026-09-01&to=2026-09-30&period=month",
+			setupMocks: func(m *MockTeamDataSvc) {
+				m.On("GetTeamMetrics", mock.Anything, teamID).
+					Return(&thunderdome.TeamMetrics{TeamID: teamID}, nil)
+				m.On("GetTeamEstimationMetrics", mock.Anything, teamID, from, to, thunderdome.EstimationPeriodMonth).
+					Return(&thunderdome.TeamEstimationMetrics{TeamID: teamID, StoryCount: 3}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid estimation period",
+			query:          "?period=day",
+			setupMocks:     func(m *MockTeamDataSvc) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTeamDataSvc := new(MockTeamDataSvc)
+			tt.setupMocks(mockTeamDataSvc)
+
+			s := &Service{
+				TeamDataSvc: mockTeamDataSvc,
+				Logger:      otelzap.New(zap.NewNop()),
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/teams/"+teamID+"/metrics"+tt.query, nil)
+			req.SetPathValue("teamId", teamID)
+			req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, "c3d4e5f6-a7b8-4c9d-8e1f-2a3b4c5d6e7f"))
+
+			rr := httptest.NewRecorder()
+			s.handleTeamMetrics()(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var resp struct {
+					Data thunderdome.TeamMetrics `json:"data"`
+				}
+				_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+				if assert.NotNil(t, resp.Data.Estimation) {
+					assert.Equal(t, 3, resp.Data.Estimation.StoryCount)
+				}
+			}
+			mockTeamDataSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -3,11 +3,15 @@ package http
 import (
 	"context"
 	"net/http"
+	"net/netip"
 	"time"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/directory"
 	"github.com/StevenWeathers/thunderdome-planning-poker/internal/webhook/subscription"
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/wshub"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 	"github.com/go-playground/validator/v10"
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 )
 
@@ -19,6 +23,7 @@ const (
 	contextKeyOrgRole         contextKey = "orgRole"
 	contextKeyDepartmentRole  contextKey = "departmentRole"
 	contextKeyUserProjectRole contextKey = "userProjectRole"
+	contextKeySCIMOrgID       contextKey = "scimOrgId"
 )
 
 var validate *validator.Validate
@@ -35,6 +40,9 @@ type WebsocketConfig struct {
 
 	// Websocket subdomain (allow websockets to be routed via a subdomain)
 	WebsocketSubdomain string
+
+	// Broadcast backend for fanning out websocket events to other instances, nil keeps events in process
+	BroadcastBackend wshub.Backend
 }
 
 type AuthProvider struct {
@@ -42,6 +50,12 @@ type AuthProvider struct {
 	thunderdome.AuthProviderConfig
 }
 
+// OIDCAuth is the OpenID Connect authentication configuration of one or more providers
+type OIDCAuth struct {
+	Enabled   bool
+	Providers []thunderdome.AuthProviderConfig
+}
+
 // Config contains configuration values used by the APIs
 type Config struct {
 	Port                  string
@@ -55,6 +69,9 @@ type Config struct {
 	PathPrefix string
 	// SecureProtocol whether the application is accessed through HTTPS
 	SecureProtocol bool
+	// TrustedProxies are the reverse proxies whose X-Forwarded-For and X-Real-Ip headers are trusted,
+	// requests from other peers are identified by their remote address
+	TrustedProxies []netip.Prefix
 	// Whether the external API is enabled
 	ExternalAPIEnabled bool
 	// Whether the external API requires user verified email
@@ -86,14 +103,6 @@ type Config struct {
 	CleanupStoryboardsDaysOld int
 	CleanupGuestsDaysOld      int
 	RequireTeams              bool
-	AuthLdapUrl               string
-	AuthLdapUseTls            bool
-	AuthLdapBindname          string
-	AuthLdapBindpass          string
-	AuthLdapBasedn            string
-	AuthLdapFilter            string
-	AuthLdapMailAttr          string
-	AuthLdapCnAttr            string
 	AuthHeaderUsernameHeader  string
 	AuthHeaderEmailHeader     string
 	AllowGuests               bool
@@ -101,8 +110,10 @@ type Config struct {
 	ShowActiveCountries       bool
 	SubscriptionsEnabled      bool
 
+	RateLimit RateLimitConfig
+
 	GoogleAuth AuthProvider
-	OIDCAuth   AuthProvider
+	OIDCAuth   OIDCAuth
 	WebsocketConfig
 }
 
@@ -130,6 +141,11 @@ type Service struct {
 	ColorLegendTemplateDataSvc ColorLegendTemplateDataSvc
 	SubscriptionSvc            *subscription.Service
 	ProjectDataSvc             ProjectDataSvc
+	AuditDataSvc               AuditDataSvc
+	RateLimitDataSvc           RateLimitDataSvc
+	SCIMDataSvc                SCIMDataSvc
+	LdapDirectory              LdapDirectory
+	webAuthn                   *webauthn.WebAuthn
 }
 
 // standardJsonResponse structure used for all restful APIs response body
@@ -164,6 +180,40 @@ type CookieManager interface {
 	DeleteAuthStateCookie(w http.ResponseWriter) error
 }
 
+// RateLimitDataSvc represents the interface for rate limit counter data operations
+type RateLimitDataSvc interface {
+	Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error)
+	Reset(ctx context.Context, key string) error
+}
+
+// LdapDirectory represents the interface for LDAP directory authentication
+type LdapDirectory interface {
+	Authenticate(ctx context.Context, username string, password string) (*directory.Entry, error)
+	SyncUserGroups(ctx context.Context, userID string, entry *directory.Entry) (string, error)
+}
+
+// SCIMDataSvc represents the interface for organization SCIM provisioning data operations
+type SCIMDataSvc interface {
+	TokenList(ctx context.Context, orgID string) ([]*thunderdome.SCIMToken, error)
+	TokenCreate(ctx context.Context, orgID string, createdBy string, name string) (*thunderdome.SCIMToken, error)
+	TokenDelete(ctx context.Context, orgID string, tokenID string) error
+	TokenOrganization(ctx context.Context, token string) (string, error)
+	UserList(ctx context.Context, orgID string, email string, externalID string, limit int, offset int) ([]*thunderdome.SCIMUser, int, error)
+	UserGet(ctx context.Context, orgID string, userID string) (*thunderdome.SCIMUser, error)
+	UserLink(ctx context.Context, orgID string, userID string, externalID string, created bool) error
+	UserUpdate(ctx context.Context, orgID string, userID string, externalID string, name string) error
+	UserSetActive(ctx context.Context, orgID string, userID string, active bool) error
+	UserUnlink(ctx context.Context, orgID string, userID string) error
+	GroupList(ctx context.Context, orgID string, displayName string, externalID string, limit int, offset int) ([]*thunderdome.SCIMGroup, int, error)
+	GroupGet(ctx context.Context, orgID string, groupID string) (*thunderdome.SCIMGroup, error)
+	GroupCreate(ctx context.Context, orgID string, displayName string, externalID string, departmentName string, teamName string) (*thunderdome.SCIMGroup, error)
+	GroupUpdate(ctx context.Context, orgID string, groupID string, displayName string, externalID string, teamName string) error
+	GroupDelete(ctx context.Context, orgID string, groupID string) error
+	GroupMembersAdd(ctx context.Context, orgID string, groupID string, userIDs []string) error
+	GroupMembersRemove(ctx context.Context, orgID string, groupID string, userIDs []string) error
+	GroupMembersReplace(ctx context.Context, orgID string, groupID string, userIDs []string) error
+}
+
 type AdminDataSvc interface {
 	GetAppStats(ctx context.Context) (*thunderdome.ApplicationStats, error)
 	ListSupportTickets(ctx context.Context, limit, offset int) ([]*thunderdome.SupportTicket, int, error)
@@ -182,20 +232,26 @@ type AlertDataSvc interface {
 }
 
 type APIKeyDataSvc interface {
-	GenerateAPIKey(ctx context.Context, userID string, keyName string) (*thunderdome.APIKey, error)
+	GenerateAPIKey(ctx context.Context, userID string, keyName string, restrictions thunderdome.APIKeyRestrictions) (*thunderdome.APIKey, error)
 	GetUserAPIKeys(ctx context.Context, userID string) ([]*thunderdome.APIKey, error)
-	GetAPIKeyUser(ctx context.Context, apiKey string) (*thunderdome.User, error)
+	GetAPIKeyUser(ctx context.Context, apiKey string) (*thunderdome.User, *thunderdome.APIKey, error)
 	GetAPIKeys(ctx context.Context, limit int, offset int) []*thunderdome.UserAPIKey
 	UpdateUserAPIKey(ctx context.Context, userID string, keyID string, active bool) ([]*thunderdome.APIKey, error)
 	DeleteUserAPIKey(ctx context.Context, userID string, keyID string) ([]*thunderdome.APIKey, error)
 }
 
+type AuditDataSvc interface {
+	CreateEvent(ctx context.Context, event thunderdome.AuditEvent) error
+	ListEvents(ctx context.Context, filter thunderdome.AuditEventFilter, limit int, offset int) ([]*thunderdome.AuditEvent, int, error)
+}
+
 type AuthDataSvc interface {
 	AuthUser(ctx context.Context, email string, password string) (*thunderdome.User, *thunderdome.Credential, string, error)
 	OauthCreateNonce(ctx context.Context) (string, error)
 	OauthValidateNonce(ctx context.Context, nonceId string) error
 	OauthAuthUser(ctx context.Context, provider string, sub string, email string, emailVerified bool, name string, pictureUrl string) (*thunderdome.User, string, error)
 	OauthUpsertUser(ctx context.Context, provider string, sub string, email string, emailVerified bool, name string, pictureUrl string) (*thunderdome.User, string, error)
+	SyncGroupMemberships(ctx context.Context, userID string, memberships []thunderdome.GroupMembership, userType string) error
 	UserResetRequest(ctx context.Context, email string) (resetID string, userName string, resetErr error)
 	UserResetPassword(ctx context.Context, resetID string, password string) (userName string, email string, resetErr error)
 	UserUpdatePassword(ctx context.Context, userID string, password string) (name string, email string, resetErr error)
@@ -205,10 +261,25 @@ type AuthDataSvc interface {
 	MFASetupValidate(ctx context.Context, userID string, secret string, passcode string) error
 	MFARemove(ctx context.Context, userID string) error
 	MFATokenValidate(ctx context.Context, sessionId string, passcode string) error
+	UserLoginFailed(ctx context.Context, userEmail string, threshold int, lockout time.Duration) (*thunderdome.User, *time.Time, error)
+	MFAStatus(ctx context.Context, userID string) (*thunderdome.MFAStatus, error)
+	MFARecoveryCodesGenerate(ctx context.Context, userID string) ([]string, error)
+	MFARecoveryCodeValidate(ctx context.Context, sessionID string, code string) error
+	MFASessionUserID(ctx context.Context, sessionID string) (string, error)
+	WebAuthnCredentialList(ctx context.Context, userID string) ([]*thunderdome.WebAuthnCredential, error)
+	WebAuthnCredentialCreate(ctx context.Context, userID string, name string, credentialID []byte, credential []byte) (*thunderdome.WebAuthnCredential, error)
+	WebAuthnCredentialUse(ctx context.Context, userID string, credentialID []byte, credential []byte) error
+	WebAuthnCredentialDelete(ctx context.Context, userID string, id string) error
+	WebAuthnChallengeSave(ctx context.Context, userID string, ceremony string, sessionData []byte) error
+	WebAuthnChallengeConsume(ctx context.Context, userID string, ceremony string) ([]byte, error)
 	CreateSession(ctx context.Context, userId string, enabled bool) (string, error)
 	EnableSession(ctx context.Context, sessionId string) error
 	GetSessionUserByID(ctx context.Context, sessionId string) (*thunderdome.User, error)
 	DeleteSession(ctx context.Context, sessionId string) error
+	TouchSession(ctx context.Context, sessionID string, ipAddress string, userAgent string) error
+	GetUserSessions(ctx context.Context, userID string, currentSessionID string) ([]*thunderdome.UserSession, error)
+	DeleteUserSession(ctx context.Context, userID string, id string) error
+	DeleteUserSessions(ctx context.Context, userID string, exceptSessionID string) (int, error)
 }
 
 type CheckinDataSvc interface {
@@ -230,8 +301,8 @@ type CheckinDataSvc interface {
 type JiraDataSvc interface {
 	FindInstancesByUserID(ctx context.Context, userId string) ([]thunderdome.JiraInstance, error)
 	GetInstanceByID(ctx context.Context, instanceId string) (thunderdome.JiraInstance, error)
-	CreateInstance(ctx context.Context, userId string, host string, clientMail string, accessToken string, jiraDataCenter bool) (thunderdome.JiraInstance, error)
-	UpdateInstance(ctx context.Context, instanceId string, host string, clientMail string, accessToken string) (thunderdome.JiraInstance, error)
+	CreateInstance(ctx context.Context, userId string, host string, clientMail string, accessToken string, jiraDataCenter bool, fieldMapping thunderdome.JiraFieldMapping) (thunderdome.JiraInstance, error)
+	UpdateInstance(ctx context.Context, instanceId string, host string, clientMail string, accessToken string, fieldMapping thunderdome.JiraFieldMapping) (thunderdome.JiraInstance, error)
 	DeleteInstance(ctx context.Context, instanceId string) error
 }
 
@@ -278,6 +349,11 @@ type OrganizationDataSvc interface {
 	DepartmentDeleteUserInvite(ctx context.Context, inviteID string) error
 	DepartmentGetUserInvites(ctx context.Context, deptID string) ([]thunderdome.DepartmentUserInvite, error)
 	DepartmentGetUserPendingInvites(ctx context.Context, email string) ([]thunderdome.DepartmentUserInvite, error)
+	OrganizationServiceAccountList(ctx context.Context, orgID string) ([]*thunderdome.OrganizationServiceAccount, error)
+	OrganizationServiceAccountGet(ctx context.Context, orgID string, accountID string) (*thunderdome.OrganizationServiceAccount, error)
+	OrganizationServiceAccountCreate(ctx context.Context, orgID string, createdBy string, name string, description string) (*thunderdome.OrganizationServiceAccount, error)
+	OrganizationServiceAccountUpdate(ctx context.Context, orgID string, accountID string, name string, description string) (*thunderdome.OrganizationServiceAccount, error)
+	OrganizationServiceAccountDelete(ctx context.Context, orgID string, accountID string) error
 }
 
 type TeamDataSvc interface {
@@ -309,6 +385,7 @@ type TeamDataSvc interface {
 	TeamList(ctx context.Context, limit int, offset int) ([]*thunderdome.Team, int)
 	TeamIsSubscribed(ctx context.Context, teamID string) (bool, error)
 	GetTeamMetrics(ctx context.Context, teamID string) (*thunderdome.TeamMetrics, error)
+	GetTeamEstimationMetrics(ctx context.Context, teamID string, from time.Time, to time.Time, period string) (*thunderdome.TeamEstimationMetrics, error)
 	TeamUserRolesByUserID(ctx context.Context, userID string, teamID string) (*thunderdome.UserTeamRoleInfo, error)
 }
 
@@ -332,6 +409,7 @@ type UserDataSvc interface {
 	SearchRegisteredUsersByEmail(ctx context.Context, email string, limit int, offset int) ([]*thunderdome.User, int, error)
 	CreateUser(ctx context.Context, userName string, email string, userPassword string) (newUser *thunderdome.User, verifyID string, registerErr error)
 	CreateUserGuest(ctx context.Context, userName string) (*thunderdome.User, error)
+	ProvisionUser(ctx context.Context, userName string, userEmail string) (*thunderdome.User, bool, error)
 	CreateUserRegistered(ctx context.Context, userName string, email string, userPassword string, activeuserID string) (newUser *thunderdome.User, verifyID string, registerErr error)
 	UpdateUserAccount(ctx context.Context, userID string, userName string, email string, userAvatar string, notificationsEnabled bool, country string, locale string, company string, jobTitle string, theme string) error
 	UpdateUserProfile(ctx context.Context, userID string, userName string, userAvatar string, notificationsEnabled bool, country string, locale string, company string, jobTitle string, theme string) error
@@ -351,11 +429,11 @@ type UserDataSvc interface {
 
 type PokerDataSvc interface {
 	// CreateGame creates a new poker game
-	CreateGame(ctx context.Context, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, hideVoterIdentity bool) (*thunderdome.Poker, error)
+	CreateGame(ctx context.Context, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, hideVoterIdentity bool, votingTimeLimitSec int) (*thunderdome.Poker, error)
 	// TeamCreateGame creates a new poker game for a team
-	TeamCreateGame(ctx context.Context, teamID string, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, hideVoterIdentity bool) (*thunderdome.Poker, error)
+	TeamCreateGame(ctx context.Context, teamID string, facilitatorID string, name string, estimationScaleID string, pointValuesAllowed []string, stories []*thunderdome.Story, autoFinishVoting bool, pointAverageRounding string, joinCode string, facilitatorCode string, hideVoterIdentity bool, votingTimeLimitSec int) (*thunderdome.Poker, error)
 	// UpdateGame updates an existing poker game
-	UpdateGame(pokerID string, name string, pointValuesAllowed []string, autoFinishVoting bool, pointAverageRounding string, hideVoterIdentity bool, joinCode string, facilitatorCode string, teamID string) error
+	UpdateGame(pokerID string, name string, pointValuesAllowed []string, autoFinishVoting bool, pointAverageRounding string, hideVoterIdentity bool, joinCode string, facilitatorCode string, teamID string, votingTimeLimitSec *int) error
 	// GetFacilitatorCode retrieves the facilitator code for a poker game
 	GetFacilitatorCode(pokerID string) (string, error)
 	// GetGameByID retrieves a poker game by its ID
@@ -402,8 +480,16 @@ type PokerDataSvc interface {
 	SetVote(pokerID string, userID string, storyID string, voteValue string) (stories []*thunderdome.Story, allUsersVoted bool)
 	// RetractVote retracts a user's vote for a story in a poker game
 	RetractVote(pokerID string, userID string, storyID string) ([]*thunderdome.Story, error)
+	// GetStoryVotingDeadlines gets the voting deadlines of stories being voted on with a time limit
+	GetStoryVotingDeadlines(ctx context.Context, lookback time.Duration) ([]*thunderdome.StoryVotingDeadline, error)
+	// TimeoutStoryVoting ends voting for a story whose voting deadline has run out
+	TimeoutStoryVoting(ctx context.Context, pokerID string, storyID string) ([]*thunderdome.Story, error)
 	// EndStoryVoting ends voting for a story in a poker game
-	EndStoryVoting(pokerID string, storyID string) ([]*thunderdome.Story, error)
+	EndStoryVoting(pokerID string, storyID string, endReason string, endedBy string) ([]*thunderdome.Story, error)
+	// RevoteStory starts a fresh voting round on a story in a poker game
+	RevoteStory(pokerID string, storyID string, userID string) ([]*thunderdome.Story, error)
+	// GetStoryVoteRounds gets the voting round history of a poker game's stories
+	GetStoryVoteRounds(ctx context.Context, pokerID string) ([]*thunderdome.StoryVoteRound, error)
 	// SkipStory skips a story in a poker game
 	SkipStory(pokerID string, storyID string) ([]*thunderdome.Story, error)
 	// UpdateStory updates an existing story in a poker game
@@ -469,7 +555,7 @@ type PokerDataSvc interface {
 }
 
 type RetroDataSvc interface {
-	CreateRetro(ctx context.Context, ownerID, teamID string, retroName, joinCode, facilitatorCode string, maxVotes int, brainstormVisibility string, phaseTimeLimitMin int, phaseAutoAdvance bool, allowCumulativeVoting bool, hideVotesDuringVoting bool, skipPrimeDirective bool, templateID string) (*thunderdome.Retro, error)
+	CreateRetro(ctx context.Context, ownerID, teamID string, retroName, joinCode, facilitatorCode string, maxVotes int, brainstormVisibility string, anonymity string, phaseTimeLimitMin int, phaseAutoAdvance bool, allowCumulativeVoting bool, hideVotesDuringVoting bool, skipPrimeDirective bool, templateID string) (*thunderdome.Retro, error)
 	EditRetro(retroID string, retroName string, joinCode string, facilitatorCode string, maxVotes int, brainstormVisibility string, phaseAutoAdvance bool, hideVotesDuringVoting bool, phaseTimeLimitMin int) error
 	RetroGetByID(retroID string, userID string) (*thunderdome.Retro, error)
 	RetroGetByUser(userID string, limit int, offset int) ([]*thunderdome.Retro, int, error)
@@ -481,7 +567,9 @@ type RetroDataSvc interface {
 	RetroFacilitatorRemove(retroID string, userID string) ([]string, error)
 	RetroRetreatUser(retroID string, userID string) []*thunderdome.RetroUser
 	RetroAbandon(retroID string, userID string) ([]*thunderdome.RetroUser, error)
-	RetroAdvancePhase(retroID string, phase string) (*thunderdome.Retro, error)
+	RetroAdvancePhase(ctx context.Context, retroID string, phase string) (*thunderdome.Retro, error)
+	GetRetroPhaseDeadlines(ctx context.Context, lookback time.Duration) ([]*thunderdome.RetroPhaseDeadline, error)
+	RetroPhaseTimeout(ctx context.Context, retroID string) (*thunderdome.Retro, error)
 	RetroDelete(retroID string) error
 	GetRetroUserActiveStatus(retroID string, userID string) error
 	GetRetros(limit int, offset int) ([]*thunderdome.Retro, int, error)
@@ -491,8 +579,9 @@ type RetroDataSvc interface {
 	MarkUserReady(retroID string, userID string) ([]string, error)
 	UnmarkUserReady(retroID string, userID string) ([]string, error)
 
-	CreateRetroAction(retroID string, userID string, content string) ([]*thunderdome.RetroAction, error)
-	UpdateRetroAction(retroID string, actionID string, content string, completed bool) (Actions []*thunderdome.RetroAction, DeleteError error)
+	CreateRetroAction(retroID string, userID string, content string, dueDate string) ([]*thunderdome.RetroAction, error)
+	UpdateRetroAction(retroID string, actionID string, content string, status string, completed bool, dueDate string) (Actions []*thunderdome.RetroAction, DeleteError error)
+	ReviewRetroAction(ctx context.Context, retroID string, actionID string, decision string) ([]*thunderdome.RetroAction, error)
 	DeleteRetroAction(retroID string, userID string, actionID string) ([]*thunderdome.RetroAction, error)
 	GetRetroActions(retroID string) []*thunderdome.RetroAction
 	GetTeamRetroActions(teamID string, limit int, offset int, completed bool) ([]*thunderdome.RetroAction, int, error)
@@ -503,15 +592,16 @@ type RetroDataSvc interface {
 	RetroActionAssigneeAdd(retroID string, actionID string, userID string) ([]*thunderdome.RetroAction, error)
 	RetroActionAssigneeDelete(retroID string, actionID string, userID string) ([]*thunderdome.RetroAction, error)
 
-	CreateRetroItem(retroID string, userID string, itemType string, content string) ([]*thunderdome.RetroItem, error)
+	CreateRetroItem(retroID string, userID string, itemType string, content string, authorTag string, authorSecret string) ([]*thunderdome.RetroItem, error)
 	GroupRetroItem(retroID string, itemId string, groupId string) (thunderdome.RetroItem, error)
-	DeleteRetroItem(retroID string, userID string, itemType string, itemID string) ([]*thunderdome.RetroItem, error)
+	DeleteRetroItem(retroID string, userID string, itemType string, itemID string, authorSecret string) ([]*thunderdome.RetroItem, error)
 	GetRetroItems(retroID string) []*thunderdome.RetroItem
 	GetRetroGroups(retroID string) []*thunderdome.RetroGroup
 	GroupNameChange(retroID string, groupID string, name string) (thunderdome.RetroGroup, error)
 	GetRetroVotes(retroID string) []*thunderdome.RetroVote
 	GroupUserVote(retroID string, groupID string, userID string) ([]*thunderdome.RetroVote, error)
 	GroupUserSubtractVote(retroID string, groupID string, userID string) ([]*thunderdome.RetroVote, error)
+	RetroHealthCheckScore(ctx context.Context, retroID string, userID string, dimension string, score int) ([]*thunderdome.RetroHealthCheckResult, error)
 	ItemCommentAdd(retroID string, itemID string, userID string, comment string) ([]*thunderdome.RetroItem, error)
 	ItemCommentEdit(retroID string, commentID string, comment string) ([]*thunderdome.RetroItem, error)
 	ItemCommentDelete(retroID string, commentID string) ([]*thunderdome.RetroItem, error)
@@ -632,6 +722,7 @@ type EmailService interface {
 	SendForgotPassword(userName string, userEmail string, resetID string) error
 	SendPasswordReset(userName string, userEmail string) error
 	SendPasswordUpdate(userName string, userEmail string) error
+	SendAccountLocked(userName string, userEmail string, lockedUntil time.Time) error
 	SendDeleteConfirmation(userName string, userEmail string) error
 	SendTeamInvite(TeamName string, userEmail string, inviteID string) error
 	SendOrganizationInvite(organizationName string, userEmail string, inviteID string) error
@@ -684,4 +775,30 @@ type ProjectDataSvc interface {
 	AssociatePoker(ctx context.Context, projectID string, pokerID string) error
 	ListPokerGames(ctx context.Context, projectId string, limit int, offset int) ([]*thunderdome.Poker, error)
 	RemovePokerGame(ctx context.Context, projectID string, pokerID string) error
+
+	// Item types, statuses, and priorities
+	GetItemTypes(ctx context.Context, scope thunderdome.ItemScope) ([]*thunderdome.ItemType, error)
+	GetProjectItemTypes(ctx context.Context, projectID string) ([]*thunderdome.ItemType, error)
+	CreateItemType(ctx context.Context, itemType *thunderdome.ItemType) (*thunderdome.ItemType, error)
+	UpdateItemType(ctx context.Context, itemType *thunderdome.ItemType) (*thunderdome.ItemType, error)
+	DeleteItemType(ctx context.Context, scope thunderdome.ItemScope, typeID string) error
+	GetItemStatuses(ctx context.Context, scope thunderdome.ItemScope) ([]*thunderdome.ItemStatus, error)
+	GetProjectItemStatuses(ctx context.Context, projectID string) ([]*thunderdome.ItemStatus, error)
+	CreateItemStatus(ctx context.Context, status *thunderdome.ItemStatus) (*thunderdome.ItemStatus, error)
+	UpdateItemStatus(ctx context.Context, status *thunderdome.ItemStatus) (*thunderdome.ItemStatus, error)
+	DeleteItemStatus(ctx context.Context, scope thunderdome.ItemScope, statusID string) error
+	GetItemPriorities(ctx context.Context, scope thunderdome.ItemScope) ([]*thunderdome.ItemPriority, error)
+	GetProjectItemPriorities(ctx context.Context, projectID string) ([]*thunderdome.ItemPriority, error)
+	CreateItemPriority(ctx context.Context, priority *thunderdome.ItemPriority) (*thunderdome.ItemPriority, error)
+	UpdateItemPriority(ctx context.Context, priority *thunderdome.ItemPriority) (*thunderdome.ItemPriority, error)
+	DeleteItemPriority(ctx context.Context, scope thunderdome.ItemScope, priorityID string) error
+
+	// Project items
+	GetProjectItems(ctx context.Context, projectID string, filter thunderdome.ProjectItemFilter, limit int, offset int) ([]*thunderdome.ProjectItem, int, error)
+	GetProjectItemByID(ctx context.Context, projectID string, itemID string) (*thunderdome.ProjectItem, error)
+	CreateProjectItem(ctx context.Context, item *thunderdome.ProjectItem) (*thunderdome.ProjectItem, error)
+	UpdateProjectItem(ctx context.Context, item *thunderdome.ProjectItem) (*thunderdome.ProjectItem, error)
+	TransitionProjectItem(ctx context.Context, projectID string, itemID string, statusID string) (*thunderdome.ProjectItem, error)
+	MoveProjectItem(ctx context.Context, projectID string, itemID string, placeBefore string) (*thunderdome.ProjectItem, error)
+	DeleteProjectItem(ctx context.Context, projectID string, itemID string) error
 }
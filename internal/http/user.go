@@ -232,6 +232,8 @@ func (s *Service) handleUserDelete() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionUserDelete, userID, nil)
+
 		// don't attempt to send email to guest users
 		if user.Email != "" {
 			emailErr := s.Email.SendDeleteConfirmation(user.Name, user.Email)
@@ -438,6 +440,14 @@ func (s *Service) handleUserOrganizationInvite() http.HandlerFunc {
 			return
 		}
 
+		s.recordAuditEvent(r, thunderdome.AuditEvent{
+			Action:         thunderdome.AuditActionOrganizationUserAdd,
+			TargetType:     thunderdome.AuditTargetTypeUser,
+			TargetID:       userID,
+			OrganizationID: &orgID,
+			Metadata:       map[string]string{"inviteId": orgInvite.InviteID, "role": orgInvite.Role},
+		})
+
 		organization, orgErr := s.OrganizationDataSvc.OrganizationGetByID(ctx, orgID)
 		if orgErr != nil {
 			s.Logger.Ctx(ctx).Error("handleUserOrganizationInvite error getting organization",
@@ -690,6 +700,16 @@ func (s *Service) handleUserDepartmentInvite() http.HandlerFunc {
 			return
 		}
 
+		s.recordAuditEvent(r, thunderdome.AuditEvent{
+			Action:         thunderdome.AuditActionDepartmentUserAdd,
+			TargetType:     thunderdome.AuditTargetTypeUser,
+			TargetID:       userID,
+			OrganizationID: &org.ID,
+			Metadata: map[string]string{
+				"departmentId": deptInvite.DepartmentID, "inviteId": deptInvite.InviteID, "role": deptInvite.Role,
+			},
+		})
+
 		result := thunderdome.UserDepartment{
 			Department: *dept,
 			Role:       deptInvite.Role,
@@ -892,6 +912,10 @@ func (s *Service) handleUserTeamInvite() http.HandlerFunc {
 			return
 		}
 
+		s.recordUserAuditEvent(r, thunderdome.AuditActionTeamUserAdd, userID, map[string]string{
+			"teamId": teamInvite.TeamID, "inviteId": teamInvite.InviteID, "role": teamInvite.Role,
+		})
+
 		result := thunderdome.UserTeam{
 			Team: *team,
 			Role: teamInvite.Role,
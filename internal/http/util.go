@@ -2,9 +2,7 @@ package http
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"html/template"
 	"io/fs"
@@ -15,7 +13,6 @@ import (
 
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 
-	"github.com/go-ldap/ldap/v3"
 	"go.uber.org/zap"
 )
 
@@ -164,55 +161,13 @@ func (s *Service) authAndCreateUserLdap(ctx context.Context, userName string, us
 	var sessionID string
 	var sessErr error
 
-	l, err := ldap.DialURL(s.Config.AuthLdapUrl)
+	entry, err := s.LdapDirectory.Authenticate(ctx, userName, userPassword)
 	if err != nil {
-		s.Logger.Ctx(ctx).Error("Failed connecting to ldap server at " + s.Config.AuthLdapUrl)
-		return authedUser, sessionID, err
-	}
-	defer l.Close()
-	if s.Config.AuthLdapUseTls {
-		err = l.StartTLS(&tls.Config{InsecureSkipVerify: true})
-		if err != nil {
-			s.Logger.Ctx(ctx).Error("Failed securing ldap connection", zap.Error(err))
-			return authedUser, sessionID, err
-		}
-	}
-
-	if s.Config.AuthLdapBindname != "" {
-		err = l.Bind(s.Config.AuthLdapBindname, s.Config.AuthLdapBindpass)
-		if err != nil {
-			s.Logger.Ctx(ctx).Error("Failed binding for authentication", zap.Error(err))
-			return authedUser, sessionID, err
-		}
-	}
-
-	searchRequest := ldap.NewSearchRequest(s.Config.AuthLdapBasedn,
-		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
-		fmt.Sprintf(s.Config.AuthLdapFilter, ldap.EscapeFilter(userName)),
-		[]string{"dn", s.Config.AuthLdapMailAttr, s.Config.AuthLdapCnAttr},
-		nil,
-	)
-
-	sr, err := l.Search(searchRequest)
-	if err != nil {
-		s.Logger.Ctx(ctx).Error("Failed performing ldap search query", zap.String("username", sanitizeUserInputForLogs(userName)), zap.Error(err))
-		return authedUser, sessionID, err
-	}
-
-	if len(sr.Entries) != 1 {
-		s.Logger.Ctx(ctx).Error("User does not exist or too many entries returned", zap.String("username", sanitizeUserInputForLogs(userName)))
-		return authedUser, sessionID, errors.New("user not found")
-	}
-
-	userdn := sr.Entries[0].DN
-	useremail := sr.Entries[0].GetAttributeValue(s.Config.AuthLdapMailAttr)
-	usercn := sr.Entries[0].GetAttributeValue(s.Config.AuthLdapCnAttr)
-
-	err = l.Bind(userdn, userPassword)
-	if err != nil {
-		s.Logger.Ctx(ctx).Error("Failed authenticating user", zap.String("username", sanitizeUserInputForLogs(userName)))
+		s.Logger.Ctx(ctx).Error("Failed authenticating ldap user", zap.String("username", sanitizeUserInputForLogs(userName)), zap.Error(err))
 		return authedUser, sessionID, err
 	}
+	useremail := entry.Email
+	usercn := entry.Name
 
 	authedUser, err = s.UserDataSvc.GetUserByEmail(ctx, useremail)
 
@@ -230,21 +185,23 @@ func (s *Service) authAndCreateUserLdap(ctx context.Context, userName string, us
 			return authedUser, sessionID, err
 		}
 		authedUser.Verified = true
-		sessionID, err = s.AuthDataSvc.CreateSession(ctx, authedUser.ID, true)
-		if err != nil {
-			s.Logger.Ctx(ctx).Error("Failed creating user session", zap.Error(err))
-			return authedUser, sessionID, err
-		}
-	} else {
-		if authedUser.Disabled {
-			return nil, "", fmt.Errorf("user is disabled")
-		}
+	} else if authedUser.Disabled {
+		return nil, "", fmt.Errorf("user is disabled")
+	}
 
-		sessionID, sessErr = s.AuthDataSvc.CreateSession(ctx, authedUser.ID, true)
-		if sessErr != nil {
-			s.Logger.Ctx(ctx).Error("Failed creating user session", zap.Error(err))
-			return nil, "", err
-		}
+	userType, err := s.LdapDirectory.SyncUserGroups(ctx, authedUser.ID, entry)
+	if err != nil {
+		s.Logger.Ctx(ctx).Error("Failed syncing ldap user groups", zap.Error(err), zap.String("user_id", authedUser.ID))
+		return nil, "", err
+	}
+	if userType != "" && (authedUser.Type == thunderdome.RegisteredUserType || authedUser.Type == thunderdome.AdminUserType) {
+		authedUser.Type = userType
+	}
+
+	sessionID, sessErr = s.AuthDataSvc.CreateSession(ctx, authedUser.ID, true)
+	if sessErr != nil {
+		s.Logger.Ctx(ctx).Error("Failed creating user session", zap.Error(sessErr))
+		return nil, "", sessErr
 	}
 
 	return authedUser, sessionID, nil
@@ -374,6 +331,15 @@ func retroTemplateBuildFormatFromRequest(requestFormat retroTemplateFormatReques
 		})
 	}
 
+	for _, phase := range requestFormat.Phases {
+		tf.Phases = append(tf.Phases, thunderdome.RetroPhase{
+			Name:         phase.Name,
+			TimeLimitMin: phase.TimeLimitMin,
+			Prompt:       phase.Prompt,
+			Dimensions:   phase.Dimensions,
+		})
+	}
+
 	return tf
 }
 
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strings"
 
+	"github.com/StevenWeathers/thunderdome-planning-poker/internal/groupmap"
 	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
 
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
@@ -165,6 +167,12 @@ func (s *Service) HandleOAuth2Callback() http.HandlerFunc {
 			return
 		}
 
+		if !emailDomainAllowed(claims.Email, s.config.AllowedEmailDomains) {
+			logger.Warn("oauth user email domain not allowed", zap.String("provider", s.config.ProviderName))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
 		var user *thunderdome.User
 		var sessionID string
 		var userErr error
@@ -184,8 +192,8 @@ func (s *Service) HandleOAuth2Callback() http.HandlerFunc {
 
 		if userErr != nil {
 			logger.Error("error authenticating oauth user", zap.Error(userErr))
-			ue := err.Error()
-			if ue == "USER_DISABLED" {
+			ue := userErr.Error()
+			if ue == "USER_DISABLED" || ue == "EMAIL_NOT_VERIFIED" {
 				w.WriteHeader(http.StatusUnauthorized)
 			} else {
 				w.WriteHeader(http.StatusInternalServerError)
@@ -193,6 +201,29 @@ func (s *Service) HandleOAuth2Callback() http.HandlerFunc {
 			return
 		}
 
+		if s.config.GroupsClaim != "" {
+			var rawClaims map[string]any
+			if err := idToken.Claims(&rawClaims); err != nil {
+				logger.Error("error extracting groups claim from id_token", zap.Error(err))
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			groups := claimGroups(rawClaims, s.config.GroupsClaim)
+			userType := groupmap.UserType(groups, s.config.AdminGroups)
+
+			if err := s.authDataSvc.SyncGroupMemberships(
+				ctx, user.ID, groupmap.Memberships(groups, s.config.GroupMappings), userType,
+			); err != nil {
+				logger.Error("error syncing oauth user groups", zap.Error(err),
+					zap.String("userId", user.ID))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if userType != "" && (user.Type == thunderdome.RegisteredUserType || user.Type == thunderdome.AdminUserType) {
+				user.Type = userType
+			}
+		}
+
 		if scErr := s.cookie.CreateSessionCookie(w, sessionID); scErr != nil {
 			logger.Error("error creating oauth user session cookie", zap.Error(scErr),
 				zap.String("userId", user.ID))
@@ -218,6 +249,52 @@ func (s *Service) HandleOAuth2Callback() http.HandlerFunc {
 			return
 		}
 
+		if s.config.OnLogin != nil {
+			s.config.OnLogin(r, user, sessionID)
+		}
+
 		http.Redirect(w, r, s.config.UIRedirectURL, http.StatusFound)
 	}
 }
+
+// emailDomainAllowed checks the email is in one of the allowed domains, any email is allowed when no domains are configured
+func emailDomainAllowed(email string, allowedDomains []string) bool {
+	if len(allowedDomains) == 0 {
+		return true
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at == -1 {
+		return false
+	}
+	domain := email[at+1:]
+
+	for _, d := range allowedDomains {
+		if strings.EqualFold(domain, strings.TrimPrefix(strings.TrimSpace(d), "@")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// claimGroups gets the user's groups from the groups claim, which identity providers send as either
+// a list of strings or a single string
+func claimGroups(claims map[string]any, groupsClaim string) []string {
+	groups := make([]string, 0)
+
+	switch v := claims[groupsClaim].(type) {
+	case string:
+		if v != "" {
+			groups = append(groups, v)
+		}
+	case []any:
+		for _, g := range v {
+			if group, ok := g.(string); ok && group != "" {
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	return groups
+}
@@ -0,0 +1,49 @@
+package oauth
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEmailDomainAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		domains []string
+		want    bool
+	}{
+		{name: "no domains", email: "thor@asgard.example.com", want: true},
+		{name: "allowed", email: "thor@Asgard.example.com", domains: []string{"midgard.example.com", "asgard.example.com"}, want: true},
+		{name: "allowed with at prefix", email: "thor@asgard.example.com", domains: []string{"@asgard.example.com"}, want: true},
+		{name: "subdomain not allowed", email: "thor@mail.asgard.example.com", domains: []string{"asgard.example.com"}, want: false},
+		{name: "missing domain", email: "thor", domains: []string{"asgard.example.com"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := emailDomainAllowed(tt.email, tt.domains); got != tt.want {
+				t.Errorf("emailDomainAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimGroups(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]any
+		want   []string
+	}{
+		{name: "list", claims: map[string]any{"groups": []any{"engineering", "", 7, "admins"}}, want: []string{"engineering", "admins"}},
+		{name: "single", claims: map[string]any{"groups": "engineering"}, want: []string{"engineering"}},
+		{name: "missing", claims: map[string]any{"roles": []any{"engineering"}}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := claimGroups(tt.claims, "groups"); !slices.Equal(got, tt.want) {
+				t.Errorf("claimGroups() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,6 +16,8 @@ type Config struct {
 	CallbackRedirectURL string
 	UIRedirectURL       string
 	InternalOnlyOidc    bool
+	// OnLogin is called after a user successfully logs in with the new session, e.g. to record an audit event
+	OnLogin func(r *http.Request, user *thunderdome.User, sessionID string)
 }
 
 // CookieManager is an interface for managing cookies
@@ -40,6 +42,7 @@ type AuthDataSvc interface {
 	OauthValidateNonce(ctx context.Context, nonceId string) error
 	OauthAuthUser(ctx context.Context, provider string, sub string, email string, emailVerified bool, name string, pictureUrl string) (*thunderdome.User, string, error)
 	OauthUpsertUser(ctx context.Context, provider string, sub string, email string, emailVerified bool, name string, pictureUrl string) (*thunderdome.User, string, error)
+	SyncGroupMemberships(ctx context.Context, userID string, memberships []thunderdome.GroupMembership, userType string) error
 }
 
 // SubscriptionDataSvc is an interface for the subscription data service
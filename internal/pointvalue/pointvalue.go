@@ -0,0 +1,41 @@
+// Package pointvalue reads the numeric value of the point values stories are estimated with
+package pointvalue
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Parse parses a numeric point value including fractions such as 1/2,
+// non-numeric values such as ? or t-shirt sizes are reported as not ok
+func Parse(points string) (float64, bool) {
+	points = strings.TrimSpace(points)
+	if numerator, denominator, found := strings.Cut(points, "/"); found {
+		n, nErr := strconv.ParseFloat(numerator, 64)
+		d, dErr := strconv.ParseFloat(denominator, 64)
+		if nErr != nil || dErr != nil || d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+
+	value, err := strconv.ParseFloat(points, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// Less orders numeric point values numerically ahead of non-numeric values, which are ordered lexically
+func Less(a string, b string) bool {
+	av, aOk := Parse(a)
+	bv, bOk := Parse(b)
+	switch {
+	case aOk && bOk:
+		return av < bv
+	case aOk != bOk:
+		return aOk
+	default:
+		return a < b
+	}
+}
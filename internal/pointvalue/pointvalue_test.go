@@ -0,0 +1,51 @@
+package pointvalue
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		points string
+		want   float64
+		wantOk bool
+	}{
+		{name: "whole number", points: "8", want: 8, wantOk: true},
+		{name: "fraction", points: "1/2", want: 0.5, wantOk: true},
+		{name: "decimal", points: "0.5", want: 0.5, wantOk: true},
+		{name: "surrounding space", points: " 5 ", want: 5, wantOk: true},
+		{name: "unknown", points: "?", want: 0, wantOk: false},
+		{name: "t-shirt size", points: "XL", want: 0, wantOk: false},
+		{name: "zero denominator", points: "1/0", want: 0, wantOk: false},
+		{name: "empty", points: "", want: 0, wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.points)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Parse(%q) = (%v, %v), want (%v, %v)", tt.points, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "1/2", b: "1", want: true},
+		{a: "13", b: "8", want: false},
+		{a: "8", b: "?", want: true},
+		{a: "?", b: "8", want: false},
+		{a: "L", b: "M", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if got := Less(tt.a, tt.b); got != tt.want {
+				t.Errorf("Less(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
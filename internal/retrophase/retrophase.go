@@ -0,0 +1,176 @@
+// Package retrophase resolves the sequence of phases a retro moves through from its template
+package retrophase
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+// MaxTimeLimitMin is the longest a timed phase can run
+const MaxTimeLimitMin = 59
+
+// MaxHealthCheckDimensions is the most dimensions a health check phase can score
+const MaxHealthCheckDimensions = 10
+
+// DefaultHealthCheckDimensions are scored by health check phases that don't define their own dimensions
+var DefaultHealthCheckDimensions = []string{"Delivery", "Fun", "Learning"}
+
+// defaultPhases is the phase sequence of templates that don't define their own
+var defaultPhases = []string{
+	thunderdome.RetroPhaseReview,
+	thunderdome.RetroPhasePrimeDirective,
+	thunderdome.RetroPhaseBrainstorm,
+	thunderdome.RetroPhaseGroup,
+	thunderdome.RetroPhaseVote,
+	thunderdome.RetroPhaseAction,
+	thunderdome.RetroPhaseCompleted,
+}
+
+var knownPhases = map[string]bool{
+	thunderdome.RetroPhaseReview:         true,
+	thunderdome.RetroPhaseCheckIn:        true,
+	thunderdome.RetroPhaseHealthCheck:    true,
+	thunderdome.RetroPhasePrimeDirective: true,
+	thunderdome.RetroPhaseBrainstorm:     true,
+	thunderdome.RetroPhaseGroup:          true,
+	thunderdome.RetroPhaseVote:           true,
+	thunderdome.RetroPhaseAction:         true,
+	thunderdome.RetroPhaseCompleted:      true,
+}
+
+// Validate checks a template's phase sequence, an empty sequence uses the default phases.
+// Phases must be known and unique, include brainstorm, and completed can only be the last phase,
+// health check dimensions must be unique
+func Validate(phases []thunderdome.RetroPhase) error {
+	if len(phases) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(phases))
+	for i, p := range phases {
+		if !knownPhases[p.Name] {
+			return errors.New("INVALID_RETRO_PHASE")
+		}
+		if seen[p.Name] {
+			return errors.New("DUPLICATE_RETRO_PHASE")
+		}
+		if p.TimeLimitMin < 0 || p.TimeLimitMin > MaxTimeLimitMin {
+			return errors.New("INVALID_RETRO_PHASE_TIME_LIMIT")
+		}
+		if p.Name == thunderdome.RetroPhaseCompleted && i != len(phases)-1 {
+			return errors.New("RETRO_COMPLETED_PHASE_MUST_BE_LAST")
+		}
+		if err := validateDimensions(p.Dimensions); err != nil {
+			return err
+		}
+		seen[p.Name] = true
+	}
+	if !seen[thunderdome.RetroPhaseBrainstorm] {
+		return errors.New("RETRO_BRAINSTORM_PHASE_REQUIRED")
+	}
+
+	return nil
+}
+
+// Sequence resolves a new retro's phases from its template's phases, or the default phases when the template
+// doesn't define any. The review phase is left out when the team has no open actions to review and the prime
+// directive when it's skipped, completed is always the last phase. A brainstormTimeLimitMin above 0 overrides
+// the template's brainstorm time limit, and a health check without dimensions scores the default dimensions
+func Sequence(templatePhases []thunderdome.RetroPhase, brainstormTimeLimitMin int, skipPrimeDirective bool, hasOpenActions bool) []thunderdome.RetroPhase {
+	phases := templatePhases
+	if len(phases) == 0 {
+		phases = make([]thunderdome.RetroPhase, 0, len(defaultPhases))
+		for _, name := range defaultPhases {
+			phases = append(phases, thunderdome.RetroPhase{Name: name})
+		}
+	}
+
+	sequence := make([]thunderdome.RetroPhase, 0, len(phases)+1)
+	for _, p := range phases {
+		switch {
+		case p.Name == thunderdome.RetroPhaseReview && !hasOpenActions:
+			continue
+		case p.Name == thunderdome.RetroPhasePrimeDirective && skipPrimeDirective:
+			continue
+		case p.Name == thunderdome.RetroPhaseCompleted:
+			continue
+		case p.Name == thunderdome.RetroPhaseBrainstorm && brainstormTimeLimitMin > 0:
+			p.TimeLimitMin = brainstormTimeLimitMin
+		case p.Name == thunderdome.RetroPhaseHealthCheck && len(p.Dimensions) == 0:
+			p.Dimensions = slices.Clone(DefaultHealthCheckDimensions)
+		}
+		sequence = append(sequence, p)
+	}
+
+	return append(sequence, thunderdome.RetroPhase{Name: thunderdome.RetroPhaseCompleted})
+}
+
+// Next gets the phase after the current phase, false when the current phase is the last or not in the sequence
+func Next(phases []thunderdome.RetroPhase, current string) (string, bool) {
+	i := index(phases, current)
+	if i == -1 || i == len(phases)-1 {
+		return "", false
+	}
+
+	return phases[i+1].Name, true
+}
+
+// Contains reports whether the phase is part of the sequence
+func Contains(phases []thunderdome.RetroPhase, phase string) bool {
+	return index(phases, phase) != -1
+}
+
+// Before reports whether phase a comes before phase b in the sequence
+func Before(phases []thunderdome.RetroPhase, a string, b string) bool {
+	i, j := index(phases, a), index(phases, b)
+	return i != -1 && j != -1 && i < j
+}
+
+// TimeLimit gets the phase's time limit in minutes, 0 when the phase isn't timed
+func TimeLimit(phases []thunderdome.RetroPhase, phase string) int {
+	i := index(phases, phase)
+	if i == -1 {
+		return 0
+	}
+
+	return phases[i].TimeLimitMin
+}
+
+// HealthCheckDimensions gets the dimensions scored in the sequence's health check phase,
+// nil when the sequence has no health check
+func HealthCheckDimensions(phases []thunderdome.RetroPhase) []string {
+	i := index(phases, thunderdome.RetroPhaseHealthCheck)
+	if i == -1 {
+		return nil
+	}
+
+	return phases[i].Dimensions
+}
+
+func validateDimensions(dimensions []string) error {
+	if len(dimensions) > MaxHealthCheckDimensions {
+		return errors.New("TOO_MANY_HEALTH_CHECK_DIMENSIONS")
+	}
+	seen := make(map[string]bool, len(dimensions))
+	for _, d := range dimensions {
+		key := strings.ToLower(strings.TrimSpace(d))
+		if key == "" || len(d) > 64 {
+			return errors.New("INVALID_HEALTH_CHECK_DIMENSION")
+		}
+		if seen[key] {
+			return errors.New("DUPLICATE_HEALTH_CHECK_DIMENSION")
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
+
+func index(phases []thunderdome.RetroPhase, phase string) int {
+	return slices.IndexFunc(phases, func(p thunderdome.RetroPhase) bool {
+		return p.Name == phase
+	})
+}
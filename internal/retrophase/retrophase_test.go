@@ -0,0 +1,173 @@
+package retrophase
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/StevenWeathers/thunderdome-planning-poker/thunderdome"
+)
+
+func names(phases []thunderdome.RetroPhase) []string {
+	n := make([]string, 0, len(phases))
+	for _, p := range phases {
+		n = append(n, p.Name)
+	}
+	return n
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		phases  []thunderdome.RetroPhase
+		wantErr string
+	}{
+		{name: "default phases", phases: nil},
+		{
+			name:   "skip grouping with check-in",
+			phases: []thunderdome.RetroPhase{{Name: "checkin", Prompt: "One word"}, {Name: "brainstorm", TimeLimitMin: 5}, {Name: "vote"}, {Name: "action"}},
+		},
+		{name: "unknown phase", phases: []thunderdome.RetroPhase{{Name: "brainstorm"}, {Name: "party"}}, wantErr: "INVALID_RETRO_PHASE"},
+		{name: "duplicate phase", phases: []thunderdome.RetroPhase{{Name: "brainstorm"}, {Name: "brainstorm"}}, wantErr: "DUPLICATE_RETRO_PHASE"},
+		{name: "time limit too long", phases: []thunderdome.RetroPhase{{Name: "brainstorm", TimeLimitMin: 60}}, wantErr: "INVALID_RETRO_PHASE_TIME_LIMIT"},
+		{name: "completed not last", phases: []thunderdome.RetroPhase{{Name: "completed"}, {Name: "brainstorm"}}, wantErr: "RETRO_COMPLETED_PHASE_MUST_BE_LAST"},
+		{name: "brainstorm missing", phases: []thunderdome.RetroPhase{{Name: "vote"}}, wantErr: "RETRO_BRAINSTORM_PHASE_REQUIRED"},
+		{
+			name:   "health check dimensions",
+			phases: []thunderdome.RetroPhase{{Name: "healthcheck", Dimensions: []string{"Delivery", "Fun"}}, {Name: "brainstorm"}},
+		},
+		{
+			name:    "duplicate health check dimension",
+			phases:  []thunderdome.RetroPhase{{Name: "healthcheck", Dimensions: []string{"Fun", "fun "}}, {Name: "brainstorm"}},
+			wantErr: "DUPLICATE_HEALTH_CHECK_DIMENSION",
+		},
+		{
+			name:    "empty health check dimension",
+			phases:  []thunderdome.RetroPhase{{Name: "healthcheck", Dimensions: []string{" "}}, {Name: "brainstorm"}},
+			wantErr: "INVALID_HEALTH_CHECK_DIMENSION",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.phases)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate() error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSequence(t *testing.T) {
+	custom := []thunderdome.RetroPhase{
+		{Name: "checkin", TimeLimitMin: 3},
+		{Name: "review"},
+		{Name: "brainstorm", TimeLimitMin: 10},
+		{Name: "vote"},
+		{Name: "action"},
+	}
+
+	tests := []struct {
+		name               string
+		templatePhases     []thunderdome.RetroPhase
+		brainstormLimit    int
+		skipPrimeDirective bool
+		hasOpenActions     bool
+		want               []string
+		wantBrainstormMin  int
+	}{
+		{
+			name: "default phases",
+			want: []string{"intro", "brainstorm", "group", "vote", "action", "completed"},
+		},
+		{
+			name:               "default phases with open actions and no prime directive",
+			skipPrimeDirective: true,
+			hasOpenActions:     true,
+			brainstormLimit:    7,
+			want:               []string{"review", "brainstorm", "group", "vote", "action", "completed"},
+			wantBrainstormMin:  7,
+		},
+		{
+			name:              "template phases",
+			templatePhases:    custom,
+			want:              []string{"checkin", "brainstorm", "vote", "action", "completed"},
+			wantBrainstormMin: 10,
+		},
+		{
+			name:              "retro brainstorm limit overrides template",
+			templatePhases:    custom,
+			brainstormLimit:   4,
+			hasOpenActions:    true,
+			want:              []string{"checkin", "review", "brainstorm", "vote", "action", "completed"},
+			wantBrainstormMin: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Sequence(tt.templatePhases, tt.brainstormLimit, tt.skipPrimeDirective, tt.hasOpenActions)
+			if !slices.Equal(names(got), tt.want) {
+				t.Fatalf("Sequence() = %v, want %v", names(got), tt.want)
+			}
+			if limit := TimeLimit(got, "brainstorm"); limit != tt.wantBrainstormMin {
+				t.Errorf("brainstorm time limit = %d, want %d", limit, tt.wantBrainstormMin)
+			}
+		})
+	}
+}
+
+func TestHealthCheckDimensions(t *testing.T) {
+	tests := []struct {
+		name           string
+		templatePhases []thunderdome.RetroPhase
+		want           []string
+	}{
+		{name: "no health check", want: nil},
+		{
+			name:           "default dimensions",
+			templatePhases: []thunderdome.RetroPhase{{Name: "healthcheck"}, {Name: "brainstorm"}},
+			want:           DefaultHealthCheckDimensions,
+		},
+		{
+			name:           "template dimensions",
+			templatePhases: []thunderdome.RetroPhase{{Name: "healthcheck", Dimensions: []string{"Delivery", "Fun", "Learning"}}, {Name: "brainstorm"}},
+			want:           []string{"Delivery", "Fun", "Learning"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HealthCheckDimensions(Sequence(tt.templatePhases, 0, false, false))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("HealthCheckDimensions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	phases := Sequence([]thunderdome.RetroPhase{{Name: "brainstorm"}, {Name: "vote"}}, 0, false, false)
+
+	tests := []struct {
+		current string
+		want    string
+		wantOk  bool
+	}{
+		{current: "brainstorm", want: "vote", wantOk: true},
+		{current: "vote", want: "completed", wantOk: true},
+		{current: "completed"},
+		{current: "group"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current, func(t *testing.T) {
+			got, ok := Next(phases, tt.current)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Next() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
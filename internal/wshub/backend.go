@@ -0,0 +1,13 @@
+package wshub
+
+import "context"
+
+// Backend fans out hub broadcasts to the hubs of every application instance,
+// allowing users in the same room to be connected to different instances.
+type Backend interface {
+	// Publish sends the message to the hubs subscribed to the channel on other instances,
+	// the publishing hub delivers to its own connections directly.
+	Publish(ctx context.Context, channel string, msg Message) error
+	// Subscribe registers the deliver func to receive messages published to the channel by other instances.
+	Subscribe(channel string, deliver func(Message))
+}
@@ -14,6 +14,10 @@ type Config struct {
 	AppDomain string
 	// Websocket Subdomain (for Websocket origin check)
 	WebsocketSubdomain string
+	// Backend fans out broadcasts to other instances, nil keeps broadcasts in process
+	Backend Backend
+	// Channel identifies the hub's messages on the Backend (e.g. poker, retro)
+	Channel string
 }
 
 // WriteWait returns the write wait duration.
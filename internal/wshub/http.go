@@ -88,7 +88,7 @@ func (h *Hub) ProcessAPIEventHandler(ctx context.Context, userID, roomID, eventT
 			return nil, eventErr
 		}
 
-		if h.RoomExists(roomID) {
+		if h.shouldBroadcast(roomID) {
 			h.Broadcast(Message{Data: msg, Room: roomID})
 		}
 
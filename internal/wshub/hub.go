@@ -2,20 +2,28 @@ package wshub
 
 import (
 	"context"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
 )
 
 const (
 	// Maximum message size allowed from peer.
 	maxMessageSize = 1024 * 1024
+	// publishQueueSize is how many broadcasts can wait to be published to the Backend.
+	publishQueueSize = 256
+	// publishTimeout bounds how long publishing a single broadcast to the Backend can take.
+	publishTimeout = 5 * time.Second
 )
 
 // Message represents a message sent to the websocket hub.
 type Message struct {
 	Data []byte `json:"data"`
 	Room string `json:"room"`
+	// UserID limits delivery to the user's connections in the room when set
+	UserID string `json:"userId,omitempty"`
 }
 
 type roomExistsRequest struct {
@@ -25,11 +33,12 @@ type roomExistsRequest struct {
 
 // Hub maintains the set of active connections and broadcasts messages to the connections.
 type Hub struct {
-	rooms                     map[string]map[Connection]struct{}
+	rooms                     map[string]map[Connection]string
 	broadcast                 chan Message
 	register                  chan Subscription
 	unregister                chan Subscription
 	roomExists                chan roomExistsRequest
+	publish                   chan Message
 	logger                    *otelzap.Logger
 	config                    *Config
 	eventHandlers             map[string]func(context.Context, string, string, string) (any, []byte, error, bool)
@@ -47,11 +56,11 @@ func NewHub(
 	confirmFacilitator func(roomID string, userID string) error,
 	retreatUser func(roomID string, userID string) string,
 ) *Hub {
-	return &Hub{
+	h := &Hub{
 		broadcast:                 make(chan Message),
 		register:                  make(chan Subscription),
 		unregister:                make(chan Subscription),
-		rooms:                     make(map[string]map[Connection]struct{}),
+		rooms:                     make(map[string]map[Connection]string),
 		roomExists:                make(chan roomExistsRequest),
 		logger:                    logger,
 		config:                    &config,
@@ -60,6 +69,14 @@ func NewHub(
 		confirmFacilitator:        confirmFacilitator,
 		retreatUser:               retreatUser,
 	}
+
+	if config.Backend != nil {
+		h.publish = make(chan Message, publishQueueSize)
+		config.Backend.Subscribe(config.Channel, h.deliver)
+		go h.runPublisher()
+	}
+
+	return h
 }
 
 // Run starts the hub.
@@ -68,9 +85,9 @@ func (h *Hub) Run() {
 		select {
 		case sub := <-h.register:
 			if _, ok := h.rooms[sub.RoomID]; !ok {
-				h.rooms[sub.RoomID] = make(map[Connection]struct{})
+				h.rooms[sub.RoomID] = make(map[Connection]string)
 			}
-			h.rooms[sub.RoomID][sub.Conn] = struct{}{}
+			h.rooms[sub.RoomID][sub.Conn] = sub.UserID
 
 		case sub := <-h.unregister:
 			if _, ok := h.rooms[sub.RoomID]; ok {
@@ -85,7 +102,10 @@ func (h *Hub) Run() {
 
 		case m := <-h.broadcast:
 			if connections, ok := h.rooms[m.Room]; ok {
-				for conn := range connections {
+				for conn, userID := range connections {
+					if m.UserID != "" && m.UserID != userID {
+						continue
+					}
 					select {
 					case conn.Send() <- m.Data:
 					default:
@@ -115,11 +135,45 @@ func (h *Hub) Unregister(sub Subscription) {
 	h.unregister <- sub
 }
 
-// Broadcast sends a message to all connections in the room.
+// Broadcast sends a message to all connections in the room,
+// including those connected to other instances when a Backend is configured.
+// Publishing to the Backend happens in the background so a slow Backend doesn't hold up event handlers.
 func (h *Hub) Broadcast(msg Message) {
+	if h.publish != nil {
+		select {
+		case h.publish <- msg:
+		default:
+			h.logger.Error("hub backend publish queue full, dropping message",
+				zap.String("hub_channel", h.config.Channel), zap.String("room_id", msg.Room))
+		}
+	}
+
 	h.broadcast <- msg
 }
 
+// runPublisher publishes queued broadcasts to the Backend one at a time, keeping them in order.
+func (h *Hub) runPublisher() {
+	for msg := range h.publish {
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		if err := h.config.Backend.Publish(ctx, h.config.Channel, msg); err != nil {
+			h.logger.Error("hub backend publish error", zap.Error(err),
+				zap.String("hub_channel", h.config.Channel), zap.String("room_id", msg.Room))
+		}
+		cancel()
+	}
+}
+
+// deliver sends a message to the connections in the room on this instance only.
+func (h *Hub) deliver(msg Message) {
+	h.broadcast <- msg
+}
+
+// shouldBroadcast reports whether a message for the room has any recipients,
+// with a Backend configured the room may only have connections on other instances.
+func (h *Hub) shouldBroadcast(room string) bool {
+	return h.config.Backend != nil || h.RoomExists(room)
+}
+
 // RoomExists checks if a room exists in the hub.
 func (h *Hub) RoomExists(room string) bool {
 	response := make(chan bool)
@@ -2,7 +2,11 @@ package wshub
 
 import (
 	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
@@ -44,3 +48,127 @@ func TestCreateWebsocketUpgrader(t *testing.T) {
 	assert.Equal(t, 1024, upgrader.WriteBufferSize)
 	assert.NotNil(t, upgrader.CheckOrigin)
 }
+
+// fakeBus connects the fake Backends of several hubs, as if each hub ran on its own instance
+type fakeBus struct {
+	mu        sync.Mutex
+	instances []*fakeBackend
+}
+
+// fakeBackend is an in memory Backend for one instance, it doesn't receive its own messages
+type fakeBackend struct {
+	bus      *fakeBus
+	channel  string
+	deliver  func(Message)
+	stalled  bool
+	attempts atomic.Int32
+}
+
+func (b *fakeBus) backend(stalled bool) *fakeBackend {
+	backend := &fakeBackend{bus: b, stalled: stalled}
+	b.mu.Lock()
+	b.instances = append(b.instances, backend)
+	b.mu.Unlock()
+	return backend
+}
+
+func (f *fakeBackend) Publish(ctx context.Context, channel string, msg Message) error {
+	f.attempts.Add(1)
+	if f.stalled {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	f.bus.mu.Lock()
+	instances := slices.Clone(f.bus.instances)
+	f.bus.mu.Unlock()
+	for _, other := range instances {
+		if other != f && other.channel == channel && other.deliver != nil {
+			other.deliver(msg)
+		}
+	}
+	return nil
+}
+
+func (f *fakeBackend) Subscribe(channel string, deliver func(Message)) {
+	f.channel = channel
+	f.deliver = deliver
+}
+
+func newBackendHub(backend Backend) *Hub {
+	hub := NewHub(otelzap.New(zap.NewNop()), Config{Backend: backend, Channel: "retro"}, nil, nil, nil, nil)
+	go hub.Run()
+	return hub
+}
+
+func receive(t *testing.T, conn Connection) []byte {
+	t.Helper()
+	select {
+	case data := <-conn.send:
+		return data
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+		return nil
+	}
+}
+
+// TestBroadcastReachesOtherInstances tests a broadcast on one hub is delivered to the room on another hub
+func TestBroadcastReachesOtherInstances(t *testing.T) {
+	bus := &fakeBus{}
+	hubA := newBackendHub(bus.backend(false))
+	hubB := newBackendHub(bus.backend(false))
+
+	connA := Connection{send: make(chan []byte, 1)}
+	connB := Connection{send: make(chan []byte, 1)}
+	hubA.Register(Subscription{Conn: connA, RoomID: "room-1", UserID: "user-a"})
+	hubB.Register(Subscription{Conn: connB, RoomID: "room-1", UserID: "user-b"})
+
+	hubA.Broadcast(Message{Room: "room-1", Data: []byte("phase_updated")})
+
+	assert.Equal(t, "phase_updated", string(receive(t, connA)))
+	assert.Equal(t, "phase_updated", string(receive(t, connB)))
+}
+
+// TestBroadcastDoesNotWaitForBackend tests a stalled Backend doesn't hold up local delivery
+func TestBroadcastDoesNotWaitForBackend(t *testing.T) {
+	bus := &fakeBus{}
+	backend := bus.backend(true)
+	hub := newBackendHub(backend)
+
+	conn := Connection{send: make(chan []byte, 2)}
+	hub.Register(Subscription{Conn: conn, RoomID: "room-1", UserID: "user-a"})
+
+	done := make(chan struct{})
+	go func() {
+		hub.Broadcast(Message{Room: "room-1", Data: []byte("first")})
+		hub.Broadcast(Message{Room: "room-1", Data: []byte("second")})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Broadcast blocked on the stalled backend")
+	}
+	assert.Equal(t, "first", string(receive(t, conn)))
+	assert.Equal(t, "second", string(receive(t, conn)))
+	assert.Eventually(t, func() bool { return backend.attempts.Load() >= 1 }, time.Second, 10*time.Millisecond)
+}
+
+// TestBroadcastToUser tests a message for a user is only delivered to that user's connections, on any instance
+func TestBroadcastToUser(t *testing.T) {
+	bus := &fakeBus{}
+	hubA := newBackendHub(bus.backend(false))
+	hubB := newBackendHub(bus.backend(false))
+
+	connA := Connection{send: make(chan []byte, 1)}
+	connB := Connection{send: make(chan []byte, 1)}
+	hubA.Register(Subscription{Conn: connA, RoomID: "room-1", UserID: "user-a"})
+	hubB.Register(Subscription{Conn: connB, RoomID: "room-1", UserID: "user-b"})
+
+	hubA.Broadcast(Message{Room: "room-1", UserID: "user-b", Data: []byte("jira_estimate_push_failed")})
+	hubA.Broadcast(Message{Room: "room-1", Data: []byte("plan_finalized")})
+
+	assert.Equal(t, "plan_finalized", string(receive(t, connA)))
+	assert.Equal(t, "jira_estimate_push_failed", string(receive(t, connB)))
+	assert.Equal(t, "plan_finalized", string(receive(t, connB)))
+}
@@ -67,7 +67,7 @@ func (s *Subscription) ReadPump(
 
 		if hub.retreatUser != nil {
 			userLeaveEvent := CreateSocketEvent("user_left", UpdatedUsers, s.UserID)
-			if hub.RoomExists(s.RoomID) {
+			if hub.shouldBroadcast(s.RoomID) {
 				hub.Broadcast(Message{Data: userLeaveEvent, Room: s.RoomID})
 			}
 		}
@@ -131,7 +131,7 @@ func (s *Subscription) ReadPump(
 			}
 		}
 
-		if !badEvent && hub.RoomExists(s.RoomID) {
+		if !badEvent && hub.shouldBroadcast(s.RoomID) {
 			hub.Broadcast(Message{Data: msg, Room: s.RoomID})
 		}
 
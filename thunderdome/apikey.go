@@ -4,28 +4,70 @@ import (
 	"time"
 )
 
+// API key scopes, a key without any scopes has the full access of its owner
+const (
+	APIKeyScopePokerRead         = "poker:read"
+	APIKeyScopePokerWrite        = "poker:write"
+	APIKeyScopeRetroRead         = "retro:read"
+	APIKeyScopeRetroWrite        = "retro:write"
+	APIKeyScopeStoryboardRead    = "storyboard:read"
+	APIKeyScopeStoryboardWrite   = "storyboard:write"
+	APIKeyScopeProjectRead       = "project:read"
+	APIKeyScopeProjectWrite      = "project:write"
+	APIKeyScopeTeamRead          = "team:read"
+	APIKeyScopeTeamAdmin         = "team:admin"
+	APIKeyScopeOrganizationRead  = "organization:read"
+	APIKeyScopeOrganizationAdmin = "organization:admin"
+	APIKeyScopeUserRead          = "user:read"
+	APIKeyScopeUserWrite         = "user:write"
+)
+
+// APIKeyScopes are the scopes that can be granted to an API key
+var APIKeyScopes = []string{
+	APIKeyScopePokerRead, APIKeyScopePokerWrite,
+	APIKeyScopeRetroRead, APIKeyScopeRetroWrite,
+	APIKeyScopeStoryboardRead, APIKeyScopeStoryboardWrite,
+	APIKeyScopeProjectRead, APIKeyScopeProjectWrite,
+	APIKeyScopeTeamRead, APIKeyScopeTeamAdmin,
+	APIKeyScopeOrganizationRead, APIKeyScopeOrganizationAdmin,
+	APIKeyScopeUserRead, APIKeyScopeUserWrite,
+}
+
+// APIKeyRestrictions limits what an API key can be used for
+type APIKeyRestrictions struct {
+	Scopes         []string   `json:"scopes"`
+	ExpiresAt      *time.Time `json:"expiresAt"`
+	TeamID         *string    `json:"teamId"`
+	OrganizationID *string    `json:"organizationId"`
+}
+
 // APIKey structure
 type APIKey struct {
-	ID          string    `json:"id"`
-	Prefix      string    `json:"prefix"`
-	UserID      string    `json:"userId"`
-	Name        string    `json:"name"`
-	Key         string    `json:"apiKey"`
-	Active      bool      `json:"active"`
-	CreatedDate time.Time `json:"createdDate"`
-	UpdatedDate time.Time `json:"updatedDate"`
+	ID     string `json:"id"`
+	Prefix string `json:"prefix"`
+	UserID string `json:"userId"`
+	Name   string `json:"name"`
+	Key    string `json:"apiKey"`
+	Active bool   `json:"active"`
+	APIKeyRestrictions
+	LastUsed    *time.Time `json:"lastUsed"`
+	CreatedDate time.Time  `json:"createdDate"`
+	UpdatedDate time.Time  `json:"updatedDate"`
 }
 
 // UserAPIKey structure
 type UserAPIKey struct {
-	ID          string    `json:"id"`
-	Prefix      string    `json:"prefix"`
-	UserID      string    `json:"userId"`
-	UserEmail   string    `json:"userEmail"`
-	UserName    string    `json:"userName"`
-	Name        string    `json:"name"`
-	Key         string    `json:"apiKey"`
-	Active      bool      `json:"active"`
-	CreatedDate time.Time `json:"createdDate"`
-	UpdatedDate time.Time `json:"updatedDate"`
+	ID        string `json:"id"`
+	Prefix    string `json:"prefix"`
+	UserID    string `json:"userId"`
+	UserEmail string `json:"userEmail"`
+	UserName  string `json:"userName"`
+	UserType  string `json:"userType"`
+	Name      string `json:"name"`
+	Key       string `json:"apiKey"`
+	Active    bool   `json:"active"`
+	APIKeyRestrictions
+	LastUsed    *time.Time `json:"lastUsed"`
+	CreatedDate time.Time  `json:"createdDate"`
+	UpdatedDate time.Time  `json:"updatedDate"`
 }
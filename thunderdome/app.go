@@ -43,7 +43,7 @@ type AppConfig struct {
 	HeaderAuthEnabled           bool
 	GoogleAuthEnabled           bool
 	OIDCAuthEnabled             bool
-	OIDCProviderName            string
+	OIDCProviders               []string
 	FeaturePoker                bool
 	FeatureRetro                bool
 	FeatureStoryboard           bool
@@ -0,0 +1,96 @@
+package thunderdome
+
+import (
+	"time"
+)
+
+// Audit event actions
+const (
+	AuditActionLogin                   = "auth.login"
+	AuditActionLoginFailed             = "auth.login_failed"
+	AuditActionAccountLocked           = "auth.account_locked"
+	AuditActionLogout                  = "auth.logout"
+	AuditActionPasswordReset           = "auth.password_reset"
+	AuditActionPasswordUpdate          = "auth.password_update"
+	AuditActionMFAEnable               = "auth.mfa_enable"
+	AuditActionMFARemove               = "auth.mfa_remove"
+	AuditActionMFARecoveryCodesReset   = "auth.mfa_recovery_codes_reset"
+	AuditActionSessionRevoke           = "auth.session_revoke"
+	AuditActionUserDelete              = "user.delete"
+	AuditActionAdminUserCreate         = "admin.user_create"
+	AuditActionAdminUserPromote        = "admin.user_promote"
+	AuditActionAdminUserDemote         = "admin.user_demote"
+	AuditActionAdminUserDisable        = "admin.user_disable"
+	AuditActionAdminUserEnable         = "admin.user_enable"
+	AuditActionAdminUserPasswordUpdate = "admin.user_password_update"
+	AuditActionAdminUserSessionsRevoke = "admin.user_sessions_revoke"
+	AuditActionAPIKeyCreate            = "apikey.create"
+	AuditActionAPIKeyUpdate            = "apikey.update"
+	AuditActionAPIKeyDelete            = "apikey.delete"
+	AuditActionOrganizationUserAdd     = "organization.user_add"
+	AuditActionOrganizationUserInvite  = "organization.user_invite"
+	AuditActionOrganizationUserUpdate  = "organization.user_update"
+	AuditActionOrganizationUserRemove  = "organization.user_remove"
+	AuditActionDepartmentUserAdd       = "department.user_add"
+	AuditActionDepartmentUserInvite    = "department.user_invite"
+	AuditActionDepartmentUserUpdate    = "department.user_update"
+	AuditActionDepartmentUserRemove    = "department.user_remove"
+	AuditActionTeamUserAdd             = "team.user_add"
+	AuditActionTeamUserInvite          = "team.user_invite"
+	AuditActionTeamUserUpdate          = "team.user_update"
+	AuditActionTeamUserRemove          = "team.user_remove"
+	AuditActionServiceAccountCreate    = "service_account.create"
+	AuditActionServiceAccountUpdate    = "service_account.update"
+	AuditActionServiceAccountDelete    = "service_account.delete"
+	AuditActionSCIMTokenCreate         = "scim.token_create"
+	AuditActionSCIMTokenDelete         = "scim.token_delete"
+	AuditActionSCIMUserCreate          = "scim.user_create"
+	AuditActionSCIMUserUpdate          = "scim.user_update"
+	AuditActionSCIMUserDisable         = "scim.user_disable"
+	AuditActionSCIMUserEnable          = "scim.user_enable"
+	AuditActionSCIMUserDelete          = "scim.user_delete"
+	AuditActionSCIMGroupCreate         = "scim.group_create"
+	AuditActionSCIMGroupUpdate         = "scim.group_update"
+	AuditActionSCIMGroupDelete         = "scim.group_delete"
+)
+
+// Audit event target types
+const (
+	AuditTargetTypeUser           = "user"
+	AuditTargetTypeAPIKey         = "apikey"
+	AuditTargetTypeInvite         = "invite"
+	AuditTargetTypeServiceAccount = "service_account"
+	AuditTargetTypeSession        = "session"
+	AuditTargetTypeSCIMToken      = "scim_token"
+	AuditTargetTypeSCIMGroup      = "scim_group"
+)
+
+// AuditActorTypeSCIM is the actor type of changes made by an organization's identity provider through SCIM
+const AuditActorTypeSCIM = "SCIM"
+
+// AuditEvent is an append only record of an authentication or administrative action
+type AuditEvent struct {
+	ID             string            `json:"id"`
+	Action         string            `json:"action"`
+	ActorID        *string           `json:"actorId"`
+	ActorName      string            `json:"actorName"`
+	ActorType      string            `json:"actorType"`
+	TargetType     string            `json:"targetType"`
+	TargetID       string            `json:"targetId"`
+	OrganizationID *string           `json:"organizationId"`
+	Metadata       map[string]string `json:"metadata"`
+	IPAddress      string            `json:"ipAddress"`
+	UserAgent      string            `json:"userAgent"`
+	CreatedDate    time.Time         `json:"createdDate"`
+}
+
+// AuditEventFilter narrows an audit event query, empty values are not filtered on
+type AuditEventFilter struct {
+	Action         string
+	ActorID        string
+	TargetType     string
+	TargetID       string
+	OrganizationID string
+	From           *time.Time
+	To             *time.Time
+}
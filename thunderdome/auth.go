@@ -11,6 +11,32 @@ type AuthProviderConfig struct {
 	ClientSecret           string   `mapstructure:"client_secret"`
 	RequestedScopes        []string `mapstructure:"requestedScopes"`
 	RequestedIDTokenClaims []string `mapstructure:"requestedIDTokenClaims"`
+	// GroupsClaim is the ID token claim listing the user's groups, group mapping is disabled when empty
+	GroupsClaim   string         `mapstructure:"groups_claim"`
+	GroupMappings []GroupMapping `mapstructure:"group_mappings"`
+	// AdminGroups are the groups whose members are application admins, when empty the user type is not managed
+	AdminGroups []string `mapstructure:"admin_groups"`
+	// AllowedEmailDomains restricts logins to users with an email in one of the domains, any domain is allowed when empty
+	AllowedEmailDomains []string `mapstructure:"allowed_email_domains"`
+}
+
+// GroupMapping maps an identity provider group to a role in an organization, department or team,
+// the most specific of team, department and organization is the mapped entity
+type GroupMapping struct {
+	Group          string `mapstructure:"group"`
+	OrganizationID string `mapstructure:"organization_id"`
+	DepartmentID   string `mapstructure:"department_id"`
+	TeamID         string `mapstructure:"team_id"`
+	Role           string `mapstructure:"role"`
+}
+
+// GroupMembership is the role a user should have in a mapped organization, department or team,
+// an empty role means the user should not be a member
+type GroupMembership struct {
+	OrganizationID string
+	DepartmentID   string
+	TeamID         string
+	Role           string
 }
 
 type Credential struct {
@@ -32,3 +58,38 @@ type Identity struct {
 	CreatedDate time.Time `json:"created_date"`
 	UpdatedDate time.Time `json:"updated_date"`
 }
+
+// UserSession is an authenticated session of a user, the session secret is never exposed
+type UserSession struct {
+	ID          string    `json:"id"`
+	IPAddress   string    `json:"ipAddress"`
+	UserAgent   string    `json:"userAgent"`
+	Current     bool      `json:"current"`
+	CreatedDate time.Time `json:"createdDate"`
+	LastSeen    time.Time `json:"lastSeen"`
+	ExpireDate  time.Time `json:"expireDate"`
+}
+
+// Second factor methods a user can complete an MFA login with
+const (
+	MFAMethodTOTP         = "totp"
+	MFAMethodWebAuthn     = "webauthn"
+	MFAMethodRecoveryCode = "recovery_code"
+)
+
+// MFAStatus is a summary of the second factors a user has configured
+type MFAStatus struct {
+	TOTPEnabled            bool `json:"totpEnabled"`
+	WebAuthnCredentials    int  `json:"webauthnCredentials"`
+	RecoveryCodesRemaining int  `json:"recoveryCodesRemaining"`
+}
+
+// WebAuthnCredential is a registered security key or passkey of a user
+type WebAuthnCredential struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	CreatedDate time.Time  `json:"createdDate"`
+	LastUsed    *time.Time `json:"lastUsed"`
+	// Credential is the serialized authenticator credential
+	Credential []byte `json:"-"`
+}
@@ -5,12 +5,32 @@ import (
 )
 
 type JiraInstance struct {
-	ID             string    `json:"id"`
-	UserID         string    `json:"user_id"`
-	Host           string    `json:"host"`
-	ClientMail     string    `json:"client_mail"`
-	AccessToken    string    `json:"access_token"`
-	JiraDataCenter bool      `json:"jira_data_center"` // Checkbox for enabling Jira Data Center
-	CreatedDate    time.Time `json:"created_date"`
-	UpdatedDate    time.Time `json:"updated_date"`
+	ID             string `json:"id"`
+	UserID         string `json:"user_id"`
+	Host           string `json:"host"`
+	ClientMail     string `json:"client_mail"`
+	AccessToken    string `json:"access_token"`
+	JiraDataCenter bool   `json:"jira_data_center"` // Checkbox for enabling Jira Data Center
+	JiraFieldMapping
+	CreatedDate time.Time `json:"created_date"`
+	UpdatedDate time.Time `json:"updated_date"`
+}
+
+// JiraFieldMapping maps the site specific Jira fields to poker story fields
+type JiraFieldMapping struct {
+	// StoryPointsField is the field ID finalized poker estimates are written to (e.g. customfield_10016),
+	// estimates are not written back to Jira when empty
+	StoryPointsField string `json:"story_points_field"`
+	// AcceptanceCriteriaField is the field ID acceptance criteria are imported from (e.g. customfield_10050)
+	AcceptanceCriteriaField string `json:"acceptance_criteria_field"`
+	// LinkTemplate builds the story link from the {host} and issue {key} placeholders, defaults to {host}/browse/{key}
+	LinkTemplate string `json:"link_template"`
+}
+
+// JiraField is an issue field available on a Jira instance
+type JiraField struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Custom bool   `json:"custom"`
+	Type   string `json:"type"`
 }
@@ -27,6 +27,7 @@ type OrganizationUser struct {
 	ID           string `json:"id"`
 	Name         string `json:"name"`
 	Email        string `json:"email"`
+	Type         string `json:"rank"`
 	Role         string `json:"role"`
 	Avatar       string `json:"avatar"`
 	GravatarHash string `json:"gravatarHash"`
@@ -85,3 +86,16 @@ type OrganizationMetrics struct {
 	EstimationScaleCount int    `json:"estimation_scale_count"`
 	RetroTemplateCount   int    `json:"retro_template_count"`
 }
+
+// OrganizationServiceAccount is a non-login user owned by an organization that can hold API keys
+// and be added to the organization's teams
+type OrganizationServiceAccount struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organizationId"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	CreatedBy      *string   `json:"createdBy"`
+	APIKeyCount    int       `json:"apiKeyCount"`
+	CreatedDate    time.Time `json:"createdDate"`
+	UpdatedDate    time.Time `json:"updatedDate"`
+}
@@ -30,6 +30,7 @@ type Poker struct {
 	Facilitators         []string         `json:"leaders"`
 	PointAverageRounding string           `json:"pointAverageRounding"`
 	HideVoterIdentity    bool             `json:"hideVoterIdentity"`
+	VotingTimeLimitSec   int              `json:"votingTimeLimitSec"`
 	JoinCode             string           `json:"joinCode"`
 	FacilitatorCode      string           `json:"leaderCode,omitempty"`
 	TeamID               string           `json:"teamId"`
@@ -50,21 +51,37 @@ type Vote struct {
 
 // Story aka Story structure
 type Story struct {
-	ID                 string    `json:"id"`
-	Name               string    `json:"name"`
-	Type               string    `json:"type"`
-	ReferenceID        string    `json:"referenceId"`
-	Link               string    `json:"link"`
-	Description        string    `json:"description"`
-	AcceptanceCriteria string    `json:"acceptanceCriteria"`
-	Priority           int32     `json:"priority"`
-	Votes              []*Vote   `json:"votes"`
-	Points             string    `json:"points"`
-	Active             bool      `json:"active"`
-	Skipped            bool      `json:"skipped"`
-	VoteStartTime      time.Time `json:"voteStartTime"`
-	VoteEndTime        time.Time `json:"voteEndTime"`
-	Position           int32     `json:"position"`
+	ID                 string            `json:"id"`
+	Name               string            `json:"name"`
+	Type               string            `json:"type"`
+	ReferenceID        string            `json:"referenceId"`
+	Link               string            `json:"link"`
+	Description        string            `json:"description"`
+	AcceptanceCriteria string            `json:"acceptanceCriteria"`
+	Priority           int32             `json:"priority"`
+	Votes              []*Vote           `json:"votes"`
+	Points             string            `json:"points"`
+	Active             bool              `json:"active"`
+	Skipped            bool              `json:"skipped"`
+	VoteStartTime      time.Time         `json:"voteStartTime"`
+	VoteEndTime        time.Time         `json:"voteEndTime"`
+	VoteDeadline       *time.Time        `json:"voteDeadline"`
+	VoteEndReason      *string           `json:"voteEndReason"`
+	Position           int32             `json:"position"`
+	ProjectItemID      *string           `json:"projectItemId"`
+	VoteRounds         []*StoryVoteRound `json:"voteRounds,omitempty"`
+}
+
+// StoryVoteRound is a completed round of voting on a story, kept so revotes don't lose earlier rounds
+type StoryVoteRound struct {
+	ID          string    `json:"id"`
+	StoryID     string    `json:"storyId"`
+	RoundNumber int       `json:"roundNumber"`
+	Votes       []*Vote   `json:"votes"`
+	StartTime   time.Time `json:"startTime"`
+	EndTime     time.Time `json:"endTime"`
+	EndedBy     *string   `json:"endedBy"`
+	EndReason   string    `json:"endReason"`
 }
 
 type EstimationScale struct {
@@ -91,6 +108,7 @@ type PokerSettings struct {
 	AutoFinishVoting     bool      `json:"autoFinishVoting"`
 	PointAverageRounding string    `json:"pointAverageRounding"`
 	HideVoterIdentity    bool      `json:"hideVoterIdentity"`
+	VotingTimeLimitSec   *int      `json:"votingTimeLimitSec"`
 	EstimationScaleID    *string   `json:"estimationScaleId"`
 	JoinCode             string    `json:"joinCode"`
 	FacilitatorCode      string    `json:"facilitatorCode"`
@@ -103,3 +121,22 @@ type PokerEndGameEvent struct {
 	EndReason string    `json:"endReason"`
 	EndTime   time.Time `json:"endTime"`
 }
+
+// Story voting end reasons recorded when a story's voting round ends,
+// a round in progress when another story is activated ends as switched
+const (
+	StoryVoteEndTimeout   = "timeout"
+	StoryVoteEndAllVoted  = "all_voted"
+	StoryVoteEndManual    = "manual"
+	StoryVoteEndRevote    = "revote"
+	StoryVoteEndSwitched  = "switched"
+	StoryVoteEndSkipped   = "skipped"
+	StoryVoteEndFinalized = "finalized"
+)
+
+// StoryVotingDeadline is when a story's voting round runs out of time and voting is ended
+type StoryVotingDeadline struct {
+	PokerID  string    `json:"pokerId"`
+	StoryID  string    `json:"storyId"`
+	Deadline time.Time `json:"deadline"`
+}
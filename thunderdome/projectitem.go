@@ -0,0 +1,93 @@
+package thunderdome
+
+import (
+	"time"
+)
+
+// ItemScope identifies the organization, department, or team an item type, status, or priority belongs to,
+// all nil represents a global scope
+type ItemScope struct {
+	OrganizationID *string `json:"organizationId"`
+	DepartmentID   *string `json:"departmentId"`
+	TeamID         *string `json:"teamId"`
+}
+
+// ItemType represents a kind of project item such as epic, story, or bug
+type ItemType struct {
+	ID          string  `json:"id" db:"id"`
+	Name        string  `json:"name" db:"name"`
+	TypeKey     string  `json:"typeKey" db:"type_key"`
+	Description string  `json:"description" db:"description"`
+	Color       *string `json:"color" db:"color"`
+	IsActive    bool    `json:"isActive" db:"is_active"`
+	ItemScope
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// ItemStatus represents a workflow status a project item can be in
+type ItemStatus struct {
+	ID          string  `json:"id" db:"id"`
+	Name        string  `json:"name" db:"name"`
+	StatusKey   string  `json:"statusKey" db:"status_key"`
+	Description string  `json:"description" db:"description"`
+	Color       *string `json:"color" db:"color"`
+	IsInitial   bool    `json:"isInitial" db:"is_initial"`
+	IsFinal     bool    `json:"isFinal" db:"is_final"`
+	IsActive    bool    `json:"isActive" db:"is_active"`
+	SortOrder   int     `json:"sortOrder" db:"sort_order"`
+	ItemScope
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// ItemPriority represents a priority a project item can be assigned, lower levels are higher priority
+type ItemPriority struct {
+	ID            string  `json:"id" db:"id"`
+	Name          string  `json:"name" db:"name"`
+	PriorityKey   string  `json:"priorityKey" db:"priority_key"`
+	Description   string  `json:"description" db:"description"`
+	Color         *string `json:"color" db:"color"`
+	PriorityLevel int     `json:"priorityLevel" db:"priority_level"`
+	IsActive      bool    `json:"isActive" db:"is_active"`
+	ItemScope
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// ProjectItem represents a work item within a project, keyed by project key and number (e.g. PROJ-42)
+type ProjectItem struct {
+	ID                    string     `json:"id" db:"id"`
+	ProjectID             string     `json:"projectId" db:"project_id"`
+	ParentID              *string    `json:"parentId" db:"parent_id"`
+	ItemKey               string     `json:"itemKey" db:"item_key"`
+	Title                 string     `json:"title" db:"title"`
+	Description           string     `json:"description" db:"description"`
+	TypeID                string     `json:"typeId" db:"type_id"`
+	TypeKey               string     `json:"typeKey"`
+	StatusID              string     `json:"statusId" db:"status_id"`
+	StatusKey             string     `json:"statusKey"`
+	StatusIsFinal         bool       `json:"statusIsFinal"`
+	PriorityID            *string    `json:"priorityId" db:"priority_id"`
+	PriorityKey           *string    `json:"priorityKey"`
+	StoryPoints           *string    `json:"storyPoints" db:"story_points"`
+	Rank                  string     `json:"rank" db:"rank"`
+	StartDate             *time.Time `json:"startDate" db:"start_date"`
+	EndDate               *time.Time `json:"endDate" db:"end_date"`
+	CreatedBy             string     `json:"createdBy" db:"created_by"`
+	ExternalReferenceID   *string    `json:"externalReferenceId" db:"external_reference_id"`
+	ExternalReferenceLink *string    `json:"externalReferenceLink" db:"external_reference_link"`
+	CreatedAt             time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt             time.Time  `json:"updatedAt" db:"updated_at"`
+}
+
+// ProjectItemFilter narrows a project item listing, empty values are ignored
+type ProjectItemFilter struct {
+	IDs        []string
+	TypeID     string
+	StatusID   string
+	PriorityID string
+	ParentID   string
+	// Open excludes items in a final status when true
+	Open bool
+}
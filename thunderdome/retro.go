@@ -15,6 +15,7 @@ type RetroUser struct {
 	ID           string `json:"id"`
 	Name         string `json:"name"`
 	Email        string `json:"email"`
+	Type         string `json:"rank"`
 	Active       bool   `json:"active"`
 	Avatar       string `json:"avatar"`
 	GravatarHash string `json:"gravatarHash"`
@@ -31,10 +32,12 @@ type Retro struct {
 	Groups                []*RetroGroup  `json:"groups"`
 	Items                 []*RetroItem   `json:"items"`
 	ActionItems           []*RetroAction `json:"actionItems"`
+	ReviewActions         []*RetroAction `json:"reviewActions"`
 	Votes                 []*RetroVote   `json:"votes"`
 	ReadyUsers            []string       `json:"readyUsers"`
 	Facilitators          []string       `json:"facilitators"`
 	Phase                 string         `json:"phase" db:"phase"`
+	Phases                []RetroPhase   `json:"phases" db:"phases"`
 	PhaseTimeLimitMin     int            `json:"phase_time_limit_min" db:"phase_time_limit_min"`
 	PhaseTimeStart        time.Time      `json:"phase_time_start" db:"phase_time_start"`
 	PhaseAutoAdvance      bool           `json:"phase_auto_advance" db:"phase_auto_advance"`
@@ -42,6 +45,7 @@ type Retro struct {
 	FacilitatorCode       string         `json:"facilitatorCode" db:"facilitator_code"`
 	MaxVotes              int            `json:"maxVotes" db:"max_votes"`
 	BrainstormVisibility  string         `json:"brainstormVisibility" db:"brainstorm_visibility"`
+	Anonymity             string         `json:"anonymity" db:"anonymity"`
 	AllowCumulativeVoting bool           `json:"allowCumulativeVoting" db:"allow_cumulative_voting"`
 	HideVotesDuringVoting bool           `json:"hideVotesDuringVoting" db:"hide_votes_during_voting"`
 	Template              RetroTemplate  `json:"template"`
@@ -49,12 +53,18 @@ type Retro struct {
 	TeamName              string         `json:"teamName"`
 	CreatedDate           string         `json:"createdDate" db:"created_date"`
 	UpdatedDate           string         `json:"updatedDate" db:"updated_date"`
+
+	// HealthCheck is the aggregated health check results and HealthCheckScores the requesting user's own scores
+	HealthCheck       []*RetroHealthCheckResult `json:"healthCheck"`
+	HealthCheckScores map[string]int            `json:"healthCheckScores"`
 }
 
-// RetroItem can be a pro (went well/worked), con (needs improvement), or a question
+// RetroItem can be a pro (went well/worked), con (needs improvement), or a question,
+// UserID is empty in anonymous retros where only the author's browser can recognize its items by AuthorTag
 type RetroItem struct {
 	ID        string               `json:"id" db:"id"`
 	UserID    string               `json:"userId" db:"user_id"`
+	AuthorTag string               `json:"authorTag,omitempty" db:"author_tag"`
 	GroupID   string               `json:"groupId" db:"group_id"`
 	Content   string               `json:"content" db:"content"`
 	Type      string               `json:"type" db:"type"`
@@ -62,13 +72,22 @@ type RetroItem struct {
 	Reactions []*RetroItemReaction `json:"reactions"`
 }
 
+// Retro item anonymity modes, anonymous retros never send item authors to clients
+// and untracked retros don't store them either
+const (
+	RetroAnonymityNone      = "none"
+	RetroAnonymityAnonymous = "anonymous"
+	RetroAnonymityUntracked = "untracked"
+)
+
 // RetroGroup is a grouping of retro items
 type RetroGroup struct {
 	ID   string `json:"id" db:"id"`
 	Name string `json:"name" db:"name"`
 }
 
-// RetroAction is an action the team can take based on retro feedback
+// RetroAction is an action the team can take based on retro feedback,
+// Completed is true once the action is closed as done or dropped
 type RetroAction struct {
 	RetroID   string                `json:"retroId,omitempty"`
 	TeamID    string                `json:"teamId,omitempty"`
@@ -76,10 +95,29 @@ type RetroAction struct {
 	ID        string                `json:"id" db:"id"`
 	Content   string                `json:"content" db:"content"`
 	Completed bool                  `json:"completed" db:"completed"`
+	Status    string                `json:"status" db:"status"`
+	DueDate   string                `json:"dueDate" db:"due_date"`
 	Comments  []*RetroActionComment `json:"comments"`
 	Assignees []*User               `json:"assignees"`
+	// ReviewDecision is the decision made for a previous retro's action in the review phase, empty until reviewed
+	ReviewDecision string `json:"reviewDecision,omitempty"`
 }
 
+// Retro action statuses
+const (
+	RetroActionStatusOpen       = "open"
+	RetroActionStatusInProgress = "in_progress"
+	RetroActionStatusDone       = "done"
+	RetroActionStatusDropped    = "dropped"
+)
+
+// Retro action review decisions made for a previous retro's open actions
+const (
+	RetroActionReviewDone    = "done"
+	RetroActionReviewKept    = "kept"
+	RetroActionReviewDropped = "dropped"
+)
+
 // RetroActionComment A retro action comment by a user
 type RetroActionComment struct {
 	ID          string `json:"id"`
@@ -135,3 +173,41 @@ type RetroSettings struct {
 	CreatedAt             time.Time `json:"createdAt"`
 	UpdatedAt             time.Time `json:"updatedAt"`
 }
+
+// RetroPhaseDeadline is when a retro's timed phase runs out and the retro advances to the next phase
+type RetroPhaseDeadline struct {
+	RetroID  string    `json:"retroId" db:"id"`
+	Phase    string    `json:"phase" db:"phase"`
+	Deadline time.Time `json:"deadline"`
+}
+
+// Retro phases, templates can define their own sequence of these phases
+const (
+	RetroPhaseReview         = "review"
+	RetroPhaseCheckIn        = "checkin"
+	RetroPhaseHealthCheck    = "healthcheck"
+	RetroPhasePrimeDirective = "intro"
+	RetroPhaseBrainstorm     = "brainstorm"
+	RetroPhaseGroup          = "group"
+	RetroPhaseVote           = "vote"
+	RetroPhaseAction         = "action"
+	RetroPhaseCompleted      = "completed"
+)
+
+// RetroPhase is a phase in a retro's phase sequence, a TimeLimitMin of 0 means the phase isn't timed,
+// Prompt is the question shown during a check-in phase and Dimensions are what's scored in a health check phase
+type RetroPhase struct {
+	Name         string   `json:"name"`
+	TimeLimitMin int      `json:"timeLimitMin"`
+	Prompt       string   `json:"prompt,omitempty"`
+	Dimensions   []string `json:"dimensions,omitempty"`
+}
+
+// RetroHealthCheckResult is the aggregate of the anonymous 1-5 scores for a health check dimension,
+// Distribution is the number of responses for each score from 1 to 5
+type RetroHealthCheckResult struct {
+	Dimension    string  `json:"dimension"`
+	Average      float64 `json:"average"`
+	Responses    int     `json:"responses"`
+	Distribution [5]int  `json:"distribution"`
+}
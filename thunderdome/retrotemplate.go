@@ -26,7 +26,9 @@ type RetroTemplateFormatColumn struct {
 	Icon  string `json:"icon"`
 }
 
-// RetroTemplateFormat is the format of a retro template
+// RetroTemplateFormat is the format of a retro template,
+// templates without Phases use the default phase sequence
 type RetroTemplateFormat struct {
 	Columns []RetroTemplateFormatColumn `json:"columns"`
+	Phases  []RetroPhase                `json:"phases,omitempty"`
 }
@@ -0,0 +1,48 @@
+package thunderdome
+
+import "time"
+
+// SCIMToken is an organization scoped bearer token used by an identity provider to call the SCIM API
+type SCIMToken struct {
+	ID             string     `json:"id"`
+	OrganizationID string     `json:"organizationId"`
+	Name           string     `json:"name"`
+	Prefix         string     `json:"prefix"`
+	Token          string     `json:"token,omitempty"`
+	CreatedBy      *string    `json:"createdBy"`
+	LastUsed       *time.Time `json:"lastUsed"`
+	CreatedDate    time.Time  `json:"createdDate"`
+}
+
+// SCIMUser is a user provisioned into an organization by its identity provider
+type SCIMUser struct {
+	ID         string `json:"id"`
+	ExternalID string `json:"externalId"`
+	Name       string `json:"name"`
+	Email      string `json:"email"`
+	Active     bool   `json:"active"`
+	// Owned is true when the organization's provisioning created the user and no other organization provisions them,
+	// only then do the identity provider's changes apply to the user's account rather than their membership
+	Owned       bool      `json:"owned"`
+	CreatedDate time.Time `json:"createdDate"`
+	UpdatedDate time.Time `json:"updatedDate"`
+}
+
+// SCIMGroup is an identity provider group mapped to an organization team,
+// optionally within a department
+type SCIMGroup struct {
+	ID           string             `json:"id"`
+	DisplayName  string             `json:"displayName"`
+	ExternalID   string             `json:"externalId"`
+	TeamID       string             `json:"teamId"`
+	DepartmentID *string            `json:"departmentId"`
+	Members      []*SCIMGroupMember `json:"members"`
+	CreatedDate  time.Time          `json:"createdDate"`
+	UpdatedDate  time.Time          `json:"updatedDate"`
+}
+
+// SCIMGroupMember is a provisioned user that belongs to a SCIM group's team
+type SCIMGroupMember struct {
+	UserID string `json:"userId"`
+	Name   string `json:"name"`
+}
@@ -4,6 +4,7 @@ package thunderdome
 type StoryboardUser struct {
 	ID           string `json:"id"`
 	Name         string `json:"name"`
+	Type         string `json:"rank"`
 	Active       bool   `json:"active"`
 	Avatar       string `json:"avatar"`
 	Abandoned    bool   `json:"abandoned"`
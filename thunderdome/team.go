@@ -23,6 +23,7 @@ type TeamUser struct {
 	ID           string `json:"id"`
 	Name         string `json:"name"`
 	Email        string `json:"email"`
+	Type         string `json:"rank"`
 	Role         string `json:"role"`
 	Avatar       string `json:"avatar"`
 	GravatarHash string `json:"gravatarHash"`
@@ -54,6 +55,22 @@ type TeamMetrics struct {
 	TeamCheckinCount     int    `json:"team_checkin_count"`
 	EstimationScaleCount int    `json:"estimation_scale_count"`
 	RetroTemplateCount   int    `json:"retro_template_count"`
+
+	// HealthCheck is the team's retro health check results over time, oldest first
+	HealthCheck []*TeamHealthCheck `json:"health_check"`
+	// Estimation is the team's poker estimation accuracy and velocity over the requested date range
+	Estimation *TeamEstimationMetrics `json:"estimation"`
+}
+
+// TeamHealthCheck is a team's average score for a health check dimension in a retro,
+// kept when the retro is deleted so the team's health can be charted over time
+type TeamHealthCheck struct {
+	RetroID     string    `json:"retro_id"`
+	RetroName   string    `json:"retro_name"`
+	Dimension   string    `json:"dimension"`
+	Average     float64   `json:"average"`
+	Responses   int       `json:"responses"`
+	CreatedDate time.Time `json:"created_date"`
 }
 
 // UserTeamRoleInfo represents a team's structure and a user's roles (if any) for that team.
@@ -67,3 +84,60 @@ type UserTeamRoleInfo struct {
 	OrganizationRole *string `db:"organization_role" json:"organizationRole"`
 	AssociationLevel string  `db:"association_level" json:"associationLevel"`
 }
+
+const (
+	EstimationPeriodWeek  = "week"
+	EstimationPeriodMonth = "month"
+)
+
+// TeamEstimationMetrics represents the estimation accuracy and velocity of a team's poker games over a date range
+type TeamEstimationMetrics struct {
+	TeamID                   string                         `json:"teamId"`
+	From                     time.Time                      `json:"from"`
+	To                       time.Time                      `json:"to"`
+	Period                   string                         `json:"period"`
+	StoryCount               int                            `json:"storyCount"`
+	EstimatedCount           int                            `json:"estimatedCount"`
+	SkippedCount             int                            `json:"skippedCount"`
+	SkipRate                 float64                        `json:"skipRate"`
+	ConsensusRate            float64                        `json:"consensusRate"`
+	AverageRoundsToConsensus float64                        `json:"averageRoundsToConsensus"`
+	Periods                  []*EstimationPeriodMetrics     `json:"periods"`
+	PointDistribution        []*EstimationScaleDistribution `json:"pointDistribution"`
+	Stories                  []*StoryEstimationMetrics      `json:"stories"`
+}
+
+// EstimationPeriodMetrics represents the points estimated within a week or month
+type EstimationPeriodMetrics struct {
+	PeriodStart    time.Time `json:"periodStart"`
+	Points         float64   `json:"points"`
+	EstimatedCount int       `json:"estimatedCount"`
+	SkippedCount   int       `json:"skippedCount"`
+}
+
+// EstimationScaleDistribution represents how often each final point value was used with an estimation scale
+type EstimationScaleDistribution struct {
+	EstimationScaleID   string                   `json:"estimationScaleId"`
+	EstimationScaleName string                   `json:"estimationScaleName"`
+	Points              []*EstimationPointsCount `json:"points"`
+}
+
+// EstimationPointsCount represents the number of stories that finished with a point value
+type EstimationPointsCount struct {
+	Points     string `json:"points"`
+	StoryCount int    `json:"storyCount"`
+}
+
+// StoryEstimationMetrics represents the vote spread and consensus of a single estimated story
+type StoryEstimationMetrics struct {
+	StoryID   string    `json:"storyId"`
+	PokerID   string    `json:"pokerId"`
+	Name      string    `json:"name"`
+	Points    string    `json:"points"`
+	Skipped   bool      `json:"skipped"`
+	VoteCount int       `json:"voteCount"`
+	Spread    *float64  `json:"spread"`
+	Consensus bool      `json:"consensus"`
+	Rounds    int       `json:"rounds"`
+	VoteEnd   time.Time `json:"voteEnd"`
+}
@@ -8,7 +8,8 @@ const (
 	GuestUserType        = "GUEST"
 	RegisteredUserType   = "REGISTERED"
 	AdminUserType        = "ADMIN"
-	EntityMemberUserType = "MEMBER" // used for organizations, teams, etc
+	ServiceUserType      = "SERVICE" // non-login users owned by an organization for integrations
+	EntityMemberUserType = "MEMBER"  // used for organizations, teams, etc
 )
 
 type UserUICookie struct {
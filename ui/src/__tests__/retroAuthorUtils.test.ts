@@ -0,0 +1,26 @@
+import { beforeEach, describe, expect, it } from 'vitest';
+
+import { createAuthorTag, getAuthorSecret, isOwnAuthorTag } from '../retroAuthorUtils';
+
+describe('Retro Author Utils', () => {
+  beforeEach(() => {
+    localStorage.clear();
+  });
+
+  it('should create distinct hex tags and secrets', () => {
+    const { authorTag, authorSecret } = createAuthorTag();
+
+    expect(authorTag).toMatch(/^[0-9a-f]{64}$/);
+    expect(authorSecret).toMatch(/^[0-9a-f]{64}$/);
+    expect(authorTag).not.toEqual(authorSecret);
+  });
+
+  it('should recognize only tags created by this browser', () => {
+    const { authorTag, authorSecret } = createAuthorTag();
+
+    expect(isOwnAuthorTag(authorTag)).toBe(true);
+    expect(getAuthorSecret(authorTag)).toEqual(authorSecret);
+    expect(isOwnAuthorTag('0'.repeat(64))).toBe(false);
+    expect(isOwnAuthorTag(undefined)).toBe(false);
+  });
+});
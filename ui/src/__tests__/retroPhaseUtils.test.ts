@@ -0,0 +1,61 @@
+import { describe, expect, it } from 'vitest';
+
+import { getNextPhase, getPhaseTimeLimit, groupHealthCheckTrend } from '../retroPhaseUtils';
+
+describe('Retro Phase Utils', () => {
+  const phases = [
+    { name: 'checkin', timeLimitMin: 3, prompt: 'One word for the sprint' },
+    { name: 'brainstorm', timeLimitMin: 10 },
+    { name: 'vote', timeLimitMin: 0 },
+    { name: 'completed', timeLimitMin: 0 },
+  ];
+
+  it('should follow the phase sequence', () => {
+    expect(getNextPhase(phases, 'checkin')).toEqual('brainstorm');
+    expect(getNextPhase(phases, 'brainstorm')).toEqual('vote');
+    expect(getNextPhase(phases, 'completed')).toEqual('');
+    expect(getNextPhase(phases, 'group')).toEqual('');
+  });
+
+  it('should get the phase time limit', () => {
+    expect(getPhaseTimeLimit(phases, 'brainstorm')).toEqual(10);
+    expect(getPhaseTimeLimit(phases, 'vote')).toEqual(0);
+    expect(getPhaseTimeLimit(phases, 'group')).toEqual(0);
+    expect(getPhaseTimeLimit(undefined, 'brainstorm')).toEqual(0);
+  });
+
+  it('should group the health check trend by dimension', () => {
+    const trend = [
+      {
+        retro_id: 'a',
+        retro_name: 'Sprint 1',
+        dimension: 'Fun',
+        average: 3.5,
+        responses: 4,
+        created_date: '2026-09-01',
+      },
+      {
+        retro_id: 'a',
+        retro_name: 'Sprint 1',
+        dimension: 'Delivery',
+        average: 2,
+        responses: 4,
+        created_date: '2026-09-01',
+      },
+      {
+        retro_id: 'b',
+        retro_name: 'Sprint 2',
+        dimension: 'Fun',
+        average: 4.25,
+        responses: 3,
+        created_date: '2026-09-15',
+      },
+    ];
+    const series = groupHealthCheckTrend(trend);
+
+    expect(series.map(s => s.dimension)).toEqual(['Fun', 'Delivery']);
+    expect(series[0].points.map(p => p.average)).toEqual([3.5, 4.25]);
+    expect(series[1].points[0].retroName).toEqual('Sprint 1');
+    expect(groupHealthCheckTrend(undefined)).toEqual([]);
+  });
+});
@@ -0,0 +1,17 @@
+import { describe, expect, it } from 'vitest';
+
+import { base64urlToBuffer, bufferToBase64url } from '../webauthnUtils';
+
+describe('WebAuthn Utils', () => {
+  it('should round trip bytes through base64url', () => {
+    const bytes = new Uint8Array([0, 251, 255, 62, 63, 1, 2]);
+    const encoded = bufferToBase64url(bytes.buffer);
+
+    expect(encoded).not.toMatch(/[+/=]/);
+    expect(new Uint8Array(base64urlToBuffer(encoded))).toEqual(bytes);
+  });
+
+  it('should decode unpadded base64url', () => {
+    expect(new Uint8Array(base64urlToBuffer('-_8'))).toEqual(new Uint8Array([251, 255]));
+  });
+});
@@ -9,12 +9,14 @@ const baseAction = {
   retroId: 'retro-1',
   content: 'Ship the modal fix',
   completed: false,
+  status: 'open',
+  dueDate: '',
   assignees: [],
   comments: [],
 };
 
 describe('ActionItemEdit component', () => {
-  it('renders the current action content and completion state', async () => {
+  it('renders the current action content, status and due date', async () => {
     render(ActionItemEdit, {
       toggleEdit: vi.fn(),
       handleEdit: vi.fn(),
@@ -23,10 +25,11 @@ describe('ActionItemEdit component', () => {
     });
 
     await expect.element(page.getByRole('textbox')).toHaveValue('Ship the modal fix');
-    await expect.element(page.getByLabelText('Completed')).not.toBeChecked();
+    await expect.element(page.getByLabelText('Status')).toHaveValue('open');
+    await expect.element(page.getByLabelText('Due Date')).toHaveValue('');
   });
 
-  it('submits updated content and completion state', async () => {
+  it('submits updated content, status and due date', async () => {
     const handleEdit = vi.fn();
 
     render(ActionItemEdit, {
@@ -37,12 +40,14 @@ describe('ActionItemEdit component', () => {
     });
 
     const textbox = page.getByRole('textbox');
-    const completed = page.getByLabelText('Completed');
+    const status = page.getByLabelText('Status');
+    const dueDate = page.getByLabelText('Due Date');
     const saveButton = page.getByRole('button', { name: 'Save' });
 
     await userEvent.clear(textbox);
     await userEvent.fill(textbox, 'Updated action');
-    await completed.click();
+    await userEvent.selectOptions(status, 'done');
+    await userEvent.fill(dueDate, '2026-11-01');
     await saveButton.click();
 
     expect(handleEdit).toHaveBeenCalledWith({
@@ -50,6 +55,8 @@ describe('ActionItemEdit component', () => {
       retroId: 'retro-1',
       content: 'Updated action',
       completed: true,
+      status: 'done',
+      dueDate: '2026-11-01',
     });
   });
 });
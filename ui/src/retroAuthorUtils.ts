@@ -0,0 +1,44 @@
+// Anonymous retros never send item authors to clients, instead the browser tags each item it creates with a random
+// tag and keeps the matching secret so it can recognize and delete its own items
+
+const storageKey = 'retroAuthorTags';
+const maxStoredTags = 1000;
+
+const randomHex = (bytes: number): string =>
+  Array.from(crypto.getRandomValues(new Uint8Array(bytes)), b => b.toString(16).padStart(2, '0')).join('');
+
+const loadTags = (): Record<string, string> => {
+  try {
+    return JSON.parse(localStorage.getItem(storageKey) || '{}');
+  } catch {
+    return {};
+  }
+};
+
+// createAuthorTag creates and remembers a new item author tag and secret
+export const createAuthorTag = (): { authorTag: string; authorSecret: string } => {
+  const authorTag = randomHex(32);
+  const authorSecret = randomHex(32);
+
+  const tags = loadTags();
+  tags[authorTag] = authorSecret;
+  // drop the oldest tags, object keys keep insertion order
+  const keys = Object.keys(tags);
+  for (const key of keys.slice(0, Math.max(0, keys.length - maxStoredTags))) {
+    delete tags[key];
+  }
+  localStorage.setItem(storageKey, JSON.stringify(tags));
+
+  return { authorTag, authorSecret };
+};
+
+// getAuthorSecret gets the secret for an item author tag created by this browser
+export const getAuthorSecret = (authorTag?: string): string | undefined => {
+  if (!authorTag) {
+    return undefined;
+  }
+  return loadTags()[authorTag];
+};
+
+// isOwnAuthorTag checks if the item author tag was created by this browser
+export const isOwnAuthorTag = (authorTag?: string): boolean => getAuthorSecret(authorTag) !== undefined;
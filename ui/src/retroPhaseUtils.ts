@@ -0,0 +1,68 @@
+import type { RetroPhase, TeamHealthCheck } from './types/retro';
+
+// Retros move through the phase sequence resolved from their template when they're created,
+// templates without phases use the default sequence
+
+// retroPhaseNames are the phases a template can use, in their default order
+export const retroPhaseNames = [
+  'checkin',
+  'healthcheck',
+  'review',
+  'intro',
+  'brainstorm',
+  'group',
+  'vote',
+  'action',
+  'completed',
+];
+
+export const retroPhaseLabels: Record<string, string> = {
+  checkin: 'Check-in',
+  healthcheck: 'Health Check',
+  review: 'Review Actions',
+  intro: 'Prime Directive',
+  brainstorm: 'Brainstorm',
+  group: 'Group',
+  vote: 'Vote',
+  action: 'Action Items',
+  completed: 'Done',
+};
+
+// getNextPhase gets the phase after the current phase, or an empty string when there isn't one
+export const getNextPhase = (phases: Array<RetroPhase> = [], current: string): string => {
+  const index = phases.findIndex(p => p.name === current);
+  if (index === -1 || index === phases.length - 1) {
+    return '';
+  }
+  return phases[index + 1].name;
+};
+
+// getPhaseTimeLimit gets the phase's time limit in minutes, 0 when the phase isn't timed
+export const getPhaseTimeLimit = (phases: Array<RetroPhase> = [], phase: string): number =>
+  phases.find(p => p.name === phase)?.timeLimitMin ?? 0;
+
+// defaultHealthCheckDimensions are scored when a health check phase doesn't define its own dimensions
+export const defaultHealthCheckDimensions = ['Delivery', 'Fun', 'Learning'];
+
+export type HealthCheckTrendSeries = {
+  dimension: string;
+  points: Array<{ retroName: string; date: string; average: number; responses: number }>;
+};
+
+// groupHealthCheckTrend groups a team's health check results by dimension, keeping each dimension's
+// results in the order they were recorded, oldest first
+export const groupHealthCheckTrend = (trend: Array<TeamHealthCheck> = []): Array<HealthCheckTrendSeries> => {
+  const series = new Map<string, HealthCheckTrendSeries>();
+  for (const result of trend) {
+    if (!series.has(result.dimension)) {
+      series.set(result.dimension, { dimension: result.dimension, points: [] });
+    }
+    series.get(result.dimension)!.points.push({
+      retroName: result.retro_name,
+      date: result.created_date,
+      average: result.average,
+      responses: result.responses,
+    });
+  }
+  return [...series.values()];
+};
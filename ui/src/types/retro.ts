@@ -1,6 +1,8 @@
 export type Retro = {
   actionItems: Array<RetroAction>;
+  reviewActions: Array<RetroAction>;
   brainstormVisibility: string;
+  anonymity: RetroAnonymity;
   createdDate: string;
   facilitatorCode: string;
   facilitators: Array<string>;
@@ -13,22 +15,32 @@ export type Retro = {
   name: string;
   ownerId: string;
   phase: string;
+  phases: Array<RetroPhase>;
   updatedDate: string;
   users: Array<RetroUser>;
   votes: Array<RetroVote>;
+  healthCheck: Array<RetroHealthCheckResult> | null;
+  healthCheckScores: Record<string, number> | null;
 };
 
 export type RetroAction = {
   comments: Array<RetroActionComment>;
   completed: boolean;
+  status: RetroActionStatus;
+  dueDate: string;
   content: string;
   assignees: Array<RetroUser>;
   id: string;
   retroId: string;
   teamId?: string;
   teamName?: string;
+  reviewDecision?: RetroActionReviewDecision;
 };
 
+export type RetroActionStatus = 'open' | 'in_progress' | 'done' | 'dropped';
+
+export type RetroActionReviewDecision = 'done' | 'kept' | 'dropped';
+
 export type RetroActionComment = {
   comment: string;
   created_date: string;
@@ -71,8 +83,11 @@ export type RetroItem = {
   reactions?: Array<RetroItemReaction>;
   type: string;
   userId: string;
+  authorTag?: string;
 };
 
+export type RetroAnonymity = 'none' | 'anonymous' | 'untracked';
+
 export type RetroUser = {
   active: boolean;
   avatar: string;
@@ -95,4 +110,28 @@ export type RetroTemplateColumn = {
 };
 export type RetroTemplateFormat = {
   columns: RetroTemplateColumn[];
+  phases?: RetroPhase[];
+};
+
+export type RetroPhase = {
+  name: string;
+  timeLimitMin: number;
+  prompt?: string;
+  dimensions?: string[];
+};
+
+export type RetroHealthCheckResult = {
+  dimension: string;
+  average: number;
+  responses: number;
+  distribution: [number, number, number, number, number];
+};
+
+export type TeamHealthCheck = {
+  retro_id: string;
+  retro_name: string;
+  dimension: string;
+  average: number;
+  responses: number;
+  created_date: string;
 };
@@ -0,0 +1,78 @@
+// WebAuthn options and credentials travel as JSON with binary fields base64url encoded,
+// these helpers convert them to and from the ArrayBuffers the browser credentials API works with
+
+export const base64urlToBuffer = (value: string): ArrayBuffer => {
+  const base64 = value.replace(/-/g, '+').replace(/_/g, '/');
+  const padded = base64 + '='.repeat((4 - (base64.length % 4)) % 4);
+  const binary = atob(padded);
+  const bytes = new Uint8Array(binary.length);
+  for (let i = 0; i < binary.length; i++) {
+    bytes[i] = binary.charCodeAt(i);
+  }
+  return bytes.buffer;
+};
+
+export const bufferToBase64url = (value: ArrayBuffer): string => {
+  const bytes = new Uint8Array(value);
+  let binary = '';
+  for (let i = 0; i < bytes.length; i++) {
+    binary += String.fromCharCode(bytes[i]);
+  }
+  return btoa(binary).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '');
+};
+
+export const webAuthnSupported = (): boolean =>
+  typeof window !== 'undefined' && typeof window.PublicKeyCredential !== 'undefined';
+
+const toDescriptors = (descriptors: any[] = []) =>
+  descriptors.map((d: any) => ({ ...d, id: base64urlToBuffer(d.id) }));
+
+// createCredential runs navigator.credentials.create with the options from register/begin
+export const createCredential = async (options: any) => {
+  const publicKey = options.publicKey;
+  const credential = (await navigator.credentials.create({
+    publicKey: {
+      ...publicKey,
+      challenge: base64urlToBuffer(publicKey.challenge),
+      user: { ...publicKey.user, id: base64urlToBuffer(publicKey.user.id) },
+      excludeCredentials: toDescriptors(publicKey.excludeCredentials),
+    },
+  })) as PublicKeyCredential;
+  const response = credential.response as AuthenticatorAttestationResponse;
+
+  return {
+    id: credential.id,
+    rawId: bufferToBase64url(credential.rawId),
+    type: credential.type,
+    response: {
+      clientDataJSON: bufferToBase64url(response.clientDataJSON),
+      attestationObject: bufferToBase64url(response.attestationObject),
+      transports: response.getTransports ? response.getTransports() : [],
+    },
+  };
+};
+
+// getCredential runs navigator.credentials.get with the options from login/begin
+export const getCredential = async (options: any) => {
+  const publicKey = options.publicKey;
+  const credential = (await navigator.credentials.get({
+    publicKey: {
+      ...publicKey,
+      challenge: base64urlToBuffer(publicKey.challenge),
+      allowCredentials: toDescriptors(publicKey.allowCredentials),
+    },
+  })) as PublicKeyCredential;
+  const response = credential.response as AuthenticatorAssertionResponse;
+
+  return {
+    id: credential.id,
+    rawId: bufferToBase64url(credential.rawId),
+    type: credential.type,
+    response: {
+      clientDataJSON: bufferToBase64url(response.clientDataJSON),
+      authenticatorData: bufferToBase64url(response.authenticatorData),
+      signature: bufferToBase64url(response.signature),
+      userHandle: response.userHandle ? bufferToBase64url(response.userHandle) : undefined,
+    },
+  };
+};